@@ -0,0 +1,27 @@
+/*
+Package archimate exports a software architecture design to the ArchiMate 3.1
+Open Exchange File Format so that it can be imported in tools such as Archi.
+
+The C4 model elements are mapped to ArchiMate concepts as follows:
+
+	Person              -> Business Actor
+	Software System     -> Application Component
+	Container           -> Application Component (Node for data stores)
+	Component           -> Application Component
+	Deployment Node     -> Node
+	Infrastructure Node -> Node
+
+Containers whose element style uses the cylinder shape are considered data
+stores and are mapped to nodes. The hierarchy of the model is described using
+composition relationships. Synchronous relationships are mapped to serving
+relationships (going from the destination to the source as ArchiMate
+describes the serving element rather than the using element), asynchronous
+relationships to flow relationships and any relationship that cannot be
+represented with these two kinds to association relationships.
+
+The technology, URL and tags of elements and relationships as well as their
+properties are exported as ArchiMate properties. Each view is exported as a
+diagram where elements rendered as boundaries contain the nodes of their
+children. Elements are positioned using the same layout as the svg package.
+*/
+package archimate
@@ -0,0 +1,554 @@
+package archimate
+
+import (
+	"encoding/xml"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"goa.design/model/mdl"
+	"goa.design/model/svg"
+)
+
+type (
+	// model is the root of an Open Exchange document.
+	model struct {
+		XMLName             xml.Name             `xml:"model"`
+		Namespace           string               `xml:"xmlns,attr"`
+		XSI                 string               `xml:"xmlns:xsi,attr"`
+		SchemaLocation      string               `xml:"xsi:schemaLocation,attr"`
+		Identifier          string               `xml:"identifier,attr"`
+		Name                langString           `xml:"name"`
+		Documentation       *langString          `xml:"documentation,omitempty"`
+		Elements            *elements            `xml:"elements,omitempty"`
+		Relationships       *relationships       `xml:"relationships,omitempty"`
+		PropertyDefinitions *propertyDefinitions `xml:"propertyDefinitions,omitempty"`
+		Views               *views               `xml:"views,omitempty"`
+	}
+
+	// elements lists the ArchiMate elements of a model. The schema does not
+	// allow empty containers so the lists below are omitted when empty.
+	elements struct {
+		Elements []*element `xml:"element"`
+	}
+
+	// relationships lists the ArchiMate relationships of a model.
+	relationships struct {
+		Relationships []*relationship `xml:"relationship"`
+	}
+
+	// propertyDefinitions lists the property definitions of a model.
+	propertyDefinitions struct {
+		Definitions []*propertyDefinition `xml:"propertyDefinition"`
+	}
+
+	// views lists the diagrams of a model.
+	views struct {
+		Diagrams []*diagram `xml:"diagrams>view"`
+	}
+
+	// properties lists the properties of an element or relationship.
+	properties struct {
+		Properties []*property `xml:"property"`
+	}
+
+	// langString is a string in a given language.
+	langString struct {
+		Lang  string `xml:"xml:lang,attr,omitempty"`
+		Value string `xml:",chardata"`
+	}
+
+	// element is an ArchiMate element.
+	element struct {
+		Identifier    string      `xml:"identifier,attr"`
+		Type          string      `xml:"xsi:type,attr"`
+		Name          langString  `xml:"name"`
+		Documentation *langString `xml:"documentation,omitempty"`
+		Properties    *properties `xml:"properties,omitempty"`
+	}
+
+	// relationship is an ArchiMate relationship.
+	relationship struct {
+		Identifier    string      `xml:"identifier,attr"`
+		Source        string      `xml:"source,attr"`
+		Target        string      `xml:"target,attr"`
+		Type          string      `xml:"xsi:type,attr"`
+		Name          *langString `xml:"name,omitempty"`
+		Documentation *langString `xml:"documentation,omitempty"`
+		Properties    *properties `xml:"properties,omitempty"`
+	}
+
+	// property is the value of a property of an element or relationship.
+	property struct {
+		Ref   string     `xml:"propertyDefinitionRef,attr"`
+		Value langString `xml:"value"`
+	}
+
+	// propertyDefinition defines a property.
+	propertyDefinition struct {
+		Identifier string     `xml:"identifier,attr"`
+		Type       string     `xml:"type,attr"`
+		Name       langString `xml:"name"`
+	}
+
+	// diagram is an ArchiMate view.
+	diagram struct {
+		Identifier    string        `xml:"identifier,attr"`
+		Type          string        `xml:"xsi:type,attr"`
+		Name          langString    `xml:"name"`
+		Documentation *langString   `xml:"documentation,omitempty"`
+		Nodes         []*node       `xml:"node"`
+		Connections   []*connection `xml:"connection"`
+	}
+
+	// node is a diagram node, it either references an element or is a
+	// labeled container (used for the enterprise boundary).
+	node struct {
+		Identifier string      `xml:"identifier,attr"`
+		ElementRef string      `xml:"elementRef,attr,omitempty"`
+		Type       string      `xml:"xsi:type,attr"`
+		X          int         `xml:"x,attr"`
+		Y          int         `xml:"y,attr"`
+		W          int         `xml:"w,attr"`
+		H          int         `xml:"h,attr"`
+		Label      *langString `xml:"label,omitempty"`
+		Nodes      []*node     `xml:"node"`
+	}
+
+	// connection is a diagram connection between two nodes.
+	connection struct {
+		Identifier      string `xml:"identifier,attr"`
+		RelationshipRef string `xml:"relationshipRef,attr"`
+		Type            string `xml:"xsi:type,attr"`
+		Source          string `xml:"source,attr"`
+		Target          string `xml:"target,attr"`
+	}
+
+	// exporter keeps the state needed to build the document.
+	exporter struct {
+		design *mdl.Design
+		doc    *model
+		// elements indexes the ArchiMate elements by model element ID.
+		elements map[string]*element
+		// relationships indexes the ArchiMate relationships by model
+		// relationship ID.
+		relationships map[string]*relationship
+		// definitions indexes the property definitions by name.
+		definitions map[string]*propertyDefinition
+	}
+)
+
+const (
+	businessActor        = "BusinessActor"
+	applicationComponent = "ApplicationComponent"
+	technologyNode       = "Node"
+)
+
+// scale is the factor applied to the positions and sizes computed by the svg
+// package layout. The default size of elements in the graphical editor is
+// much larger than the default size of ArchiMate elements.
+const scale = 0.5
+
+// Render returns the Open Exchange document for the given design. layouts
+// contains the positions of the elements of each view indexed by view key as
+// saved by the graphical editor, it may be nil.
+func Render(d *mdl.Design, layouts map[string]svg.Layout) ([]byte, error) {
+	e := &exporter{
+		design: d,
+		doc: &model{
+			Namespace:           "http://www.opengroup.org/xsd/archimate/3.0/",
+			XSI:                 "http://www.w3.org/2001/XMLSchema-instance",
+			SchemaLocation:      "http://www.opengroup.org/xsd/archimate/3.0/ http://www.opengroup.org/xsd/archimate/3.1/archimate3_Diagram.xsd",
+			Identifier:          "id-model",
+			Name:                text(d.Name),
+			Documentation:       optText(d.Description),
+			Elements:            &elements{},
+			Relationships:       &relationships{},
+			PropertyDefinitions: &propertyDefinitions{},
+			Views:               &views{},
+		},
+		elements:      make(map[string]*element),
+		relationships: make(map[string]*relationship),
+		definitions:   make(map[string]*propertyDefinition),
+	}
+	if d.Model != nil {
+		e.addElements(d.Model)
+		d.Model.IterateRelationships(e.addRelationship)
+	}
+	if d.Views != nil {
+		for i, v := range d.Views.All() {
+			e.addDiagram(i+1, v, layouts[v.Props().Key])
+		}
+	}
+	if len(e.doc.Elements.Elements) == 0 {
+		e.doc.Elements = nil
+	}
+	if len(e.doc.Relationships.Relationships) == 0 {
+		e.doc.Relationships = nil
+	}
+	if len(e.doc.PropertyDefinitions.Definitions) == 0 {
+		e.doc.PropertyDefinitions = nil
+	}
+	if len(e.doc.Views.Diagrams) == 0 {
+		e.doc.Views = nil
+	}
+	b, err := xml.MarshalIndent(e.doc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), append(b, '\n')...), nil
+}
+
+// addElements adds the ArchiMate elements and composition relationships
+// corresponding to the elements of m.
+func (e *exporter) addElements(m *mdl.Model) {
+	var styles *mdl.Styles
+	if e.design.Views != nil {
+		styles = e.design.Views.Styles
+	}
+	for _, p := range m.People {
+		e.addElement(p.ID, businessActor, p.Name, p.Description, "", p.URL, p.Tags, p.Properties)
+	}
+	for _, s := range m.Systems {
+		e.addElement(s.ID, applicationComponent, s.Name, s.Description, "", s.URL, s.Tags, s.Properties)
+		for _, c := range s.Containers {
+			kind := applicationComponent
+			if st := styles.ElementStyle(c.Tags); st != nil && st.Shape == mdl.ShapeCylinder {
+				kind = technologyNode
+			}
+			e.addElement(c.ID, kind, c.Name, c.Description, c.Technology, c.URL, c.Tags, c.Properties)
+			e.compose(s.ID, c.ID)
+			for _, cmp := range c.Components {
+				e.addElement(cmp.ID, applicationComponent, cmp.Name, cmp.Description, cmp.Technology, cmp.URL, cmp.Tags, cmp.Properties)
+				e.compose(c.ID, cmp.ID)
+				for _, ce := range cmp.CodeElements {
+					e.addElement(ce.ID, applicationComponent, ce.Name, ce.Description, ce.Technology, ce.URL, ce.Tags, ce.Properties)
+					e.compose(cmp.ID, ce.ID)
+				}
+			}
+		}
+	}
+	var addNodes func(nodes []*mdl.DeploymentNode, parent string)
+	addNodes = func(nodes []*mdl.DeploymentNode, parent string) {
+		for _, n := range nodes {
+			el := e.addElement(n.ID, technologyNode, n.Name, n.Description, n.Technology, n.URL, n.Tags, n.Properties)
+			if env := e.property("Environment", n.Environment); env != nil {
+				if el.Properties == nil {
+					el.Properties = &properties{}
+				}
+				el.Properties.Properties = append(el.Properties.Properties, env...)
+			}
+			if parent != "" {
+				e.compose(parent, n.ID)
+			}
+			for _, inf := range n.InfrastructureNodes {
+				e.addElement(inf.ID, technologyNode, inf.Name, inf.Description, inf.Technology, inf.URL, inf.Tags, inf.Properties)
+				e.compose(n.ID, inf.ID)
+			}
+			addNodes(n.Children, n.ID)
+		}
+	}
+	addNodes(m.DeploymentNodes, "")
+}
+
+// addElement adds an ArchiMate element with the given type and attributes.
+func (e *exporter) addElement(id, kind, name, desc, tech, u, tags string, props map[string]string) *element {
+	el := &element{
+		Identifier:    "id-" + id,
+		Type:          kind,
+		Name:          text(name),
+		Documentation: optText(desc),
+		Properties:    e.properties(tech, u, tags, props),
+	}
+	e.elements[id] = el
+	e.doc.Elements.Elements = append(e.doc.Elements.Elements, el)
+	return el
+}
+
+// compose adds a composition relationship between the elements with the given
+// IDs. It adds an association relationship instead if the elements are not
+// of the same type as ArchiMate only allows composition between elements of
+// the same layer.
+func (e *exporter) compose(parentID, childID string) {
+	parent, child := e.elements[parentID], e.elements[childID]
+	kind := "Composition"
+	if parent.Type != child.Type {
+		kind = "Association"
+	}
+	e.doc.Relationships.Relationships = append(e.doc.Relationships.Relationships, &relationship{
+		Identifier: "id-" + parentID + "-" + childID,
+		Source:     parent.Identifier,
+		Target:     child.Identifier,
+		Type:       kind,
+	})
+}
+
+// addRelationship adds the ArchiMate relationship corresponding to r.
+// Relationships between container instances are not exported, they are
+// represented in the diagrams using the relationship they are based on.
+func (e *exporter) addRelationship(r *mdl.Relationship) {
+	src, dst := e.elements[r.SourceID], e.elements[r.DestinationID]
+	if src == nil || dst == nil {
+		return
+	}
+	kind, reverse := relationshipType(src.Type, dst.Type, r.InteractionStyle == mdl.InteractionAsynchronous)
+	if reverse {
+		src, dst = dst, src
+	}
+	rel := &relationship{
+		Identifier: "id-" + r.ID,
+		Source:     src.Identifier,
+		Target:     dst.Identifier,
+		Type:       kind,
+		Name:       optText(r.Description),
+		Properties: e.properties(r.Technology, r.URL, r.Tags, r.Properties),
+	}
+	e.relationships[r.ID] = rel
+	e.doc.Relationships.Relationships = append(e.doc.Relationships.Relationships, rel)
+}
+
+// addDiagram adds the diagram corresponding to v. index is used to build
+// unique node and connection identifiers.
+func (e *exporter) addDiagram(index int, v mdl.View, layout svg.Layout) {
+	props := v.Props()
+	vid := "id-v" + strconv.Itoa(index)
+	name := props.Title
+	if name == "" {
+		name = props.Key
+	}
+	dg := &diagram{
+		Identifier:    vid,
+		Type:          "Diagram",
+		Name:          text(name),
+		Documentation: optText(props.Description),
+	}
+	vl := svg.LayoutView(e.design, v, layout)
+
+	// Translate the diagram so it starts at the origin.
+	minX, minY := math.Inf(1), math.Inf(1)
+	for _, b := range vl.Elements {
+		minX, minY = math.Min(minX, b.X), math.Min(minY, b.Y)
+	}
+	for _, b := range vl.Boundaries {
+		minX, minY = math.Min(minX, b.X), math.Min(minY, b.Y)
+	}
+	newNode := func(id, ref string, b svg.Box) *node {
+		n := &node{
+			Identifier: vid + "-" + id,
+			X:          int(math.Round((b.X-minX)*scale)) + 20,
+			Y:          int(math.Round((b.Y-minY)*scale)) + 20,
+			W:          int(math.Round(b.Width * scale)),
+			H:          int(math.Round(b.Height * scale)),
+		}
+		if el, ok := e.elements[ref]; ok {
+			n.Type, n.ElementRef = "Element", el.Identifier
+		} else {
+			n.Type = "Container"
+		}
+		return n
+	}
+
+	// Boundaries are sorted outermost first so parents are always created
+	// before their children.
+	nodes := make(map[string]*node)
+	add := func(n *node, parentID string) {
+		if p, ok := nodes[parentID]; ok {
+			p.Nodes = append(p.Nodes, n)
+		} else {
+			dg.Nodes = append(dg.Nodes, n)
+		}
+	}
+	for i, b := range vl.Boundaries {
+		nid := b.ID
+		if strings.HasPrefix(nid, "group:") {
+			// Group names are not valid XML identifiers.
+			nid = "g" + strconv.Itoa(i)
+		}
+		n := newNode(nid, b.ID, b.Box)
+		if n.ElementRef == "" {
+			n.Label = optText(b.Name)
+		}
+		nodes[b.ID] = n
+		add(n, b.ParentID)
+	}
+	for _, ev := range props.ElementViews {
+		b, ok := vl.Elements[ev.ID]
+		if !ok {
+			continue
+		}
+		ref := ev.ID
+		if ci, ok := e.design.Model.Element(ev.ID).(*mdl.ContainerInstance); ok {
+			ref = ci.ContainerID
+		}
+		n := newNode(ev.ID, ref, *b)
+		if n.ElementRef == "" {
+			continue
+		}
+		nodes[ev.ID] = n
+		add(n, e.boundary(ev.ID, vl))
+	}
+
+	for _, rv := range props.RelationshipViews {
+		r := e.design.Model.Relationship(rv.ID)
+		if r == nil {
+			continue
+		}
+		rel, ok := e.relationships[r.ID]
+		if !ok {
+			rel, ok = e.relationships[r.LinkedRelationshipID]
+		}
+		src, dst := nodes[r.SourceID], nodes[r.DestinationID]
+		if !ok || src == nil || dst == nil {
+			continue
+		}
+		if rel.Source != src.ElementRef {
+			src, dst = dst, src
+		}
+		dg.Connections = append(dg.Connections, &connection{
+			Identifier:      vid + "-" + rv.ID,
+			RelationshipRef: rel.Identifier,
+			Type:            "Relationship",
+			Source:          src.Identifier,
+			Target:          dst.Identifier,
+		})
+	}
+
+	e.doc.Views.Diagrams = append(e.doc.Views.Diagrams, dg)
+}
+
+// boundary returns the ID of the innermost boundary of the view layout that
+// contains the element with the given ID or the empty string if there isn't
+// any.
+func (e *exporter) boundary(id string, vl *svg.ViewLayout) string {
+	bounds := make(map[string]bool, len(vl.Boundaries))
+	for _, b := range vl.Boundaries {
+		bounds[b.ID] = true
+	}
+	m := e.design.Model
+	if g := m.Group(id); g != "" {
+		if gid := "group:" + idOf(m.Parent(id)) + "/" + g; bounds[gid] {
+			return gid
+		}
+	}
+	for p := m.Parent(id); p != nil; p = m.Parent(idOf(p)) {
+		if bounds[idOf(p)] {
+			return idOf(p)
+		}
+	}
+	if bounds["__enterprise__"] {
+		switch el := m.Element(id).(type) {
+		case *mdl.Person:
+			if el.Location == mdl.LocationInternal {
+				return "__enterprise__"
+			}
+		case *mdl.SoftwareSystem:
+			if el.Location == mdl.LocationInternal {
+				return "__enterprise__"
+			}
+		}
+	}
+	return ""
+}
+
+// properties returns the ArchiMate properties corresponding to the given
+// technology, URL, tags and properties, it returns nil if there are none.
+func (e *exporter) properties(tech, u, tags string, props map[string]string) *properties {
+	var res []*property
+	res = append(res, e.property("Technology", tech)...)
+	res = append(res, e.property("URL", u)...)
+	res = append(res, e.property("Tags", tags)...)
+	for _, n := range sortedKeys(props) {
+		res = append(res, e.property(n, props[n])...)
+	}
+	if len(res) == 0 {
+		return nil
+	}
+	return &properties{Properties: res}
+}
+
+// property returns the property with the given name and value creating the
+// property definition if needed. It returns nil if value is empty.
+func (e *exporter) property(name, value string) []*property {
+	if value == "" {
+		return nil
+	}
+	def, ok := e.definitions[name]
+	if !ok {
+		def = &propertyDefinition{
+			Identifier: "propid-" + strconv.Itoa(len(e.definitions)+1),
+			Type:       "string",
+			Name:       text(name),
+		}
+		e.definitions[name] = def
+		e.doc.PropertyDefinitions.Definitions = append(e.doc.PropertyDefinitions.Definitions, def)
+	}
+	return []*property{{Ref: def.Identifier, Value: text(value)}}
+}
+
+// relationshipType returns the type of the ArchiMate relationship between
+// elements of type src and dst. reverse is true if the ArchiMate relationship
+// goes from dst to src.
+func relationshipType(src, dst string, async bool) (kind string, reverse bool) {
+	if async && src == dst && src != businessActor {
+		return "Flow", false
+	}
+	switch dst {
+	case applicationComponent:
+		if src == applicationComponent || src == businessActor {
+			return "Serving", true
+		}
+	case technologyNode:
+		if src == technologyNode || src == applicationComponent {
+			return "Serving", true
+		}
+	}
+	return "Association", false
+}
+
+// idOf returns the ID of the given model element.
+func idOf(e interface{}) string {
+	switch el := e.(type) {
+	case *mdl.Person:
+		return el.ID
+	case *mdl.SoftwareSystem:
+		return el.ID
+	case *mdl.Container:
+		return el.ID
+	case *mdl.Component:
+		return el.ID
+	case *mdl.CodeElement:
+		return el.ID
+	case *mdl.DeploymentNode:
+		return el.ID
+	case *mdl.InfrastructureNode:
+		return el.ID
+	case *mdl.ContainerInstance:
+		return el.ID
+	}
+	return ""
+}
+
+// sortedKeys returns the keys of m sorted alphabetically.
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// text returns s as an english string.
+func text(s string) langString {
+	return langString{Lang: "en", Value: s}
+}
+
+// optText returns s as an english string or nil if s is empty.
+func optText(s string) *langString {
+	if s == "" {
+		return nil
+	}
+	t := text(s)
+	return &t
+}
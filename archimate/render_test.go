@@ -0,0 +1,159 @@
+package archimate
+
+import (
+	"strings"
+	"testing"
+
+	. "goa.design/model/dsl"
+	"goa.design/model/internal/testutil"
+	"goa.design/model/mdl"
+	"goa.design/model/svg"
+)
+
+// hotel defines the design used to test the ArchiMate exporter.
+func hotel() {
+	Design("Hotel", "Books rooms.", func() {
+		Enterprise("Resorts")
+		var Payments = SoftwareSystem("Payments", "Charges <cards> & refunds.", func() {
+			External()
+			URL("https://payments.example.com")
+		})
+		var Booking = SoftwareSystem("Booking", "Books rooms.", func() {
+			Prop("tier", "1")
+			Container("Web", "Serves the booking site.", "Go", func() {
+				Uses("Queue", "Publishes bookings", "AMQP", Asynchronous)
+				Uses("Database", "Reads and writes", "SQL")
+				Uses(Payments, "Charges cards", "HTTPS")
+				Component("Search", "Finds rooms.", "Go", func() {
+					Uses("Booking/Web/Checkout", "Starts checkout", Asynchronous)
+				})
+				Component("Checkout", "Books rooms.", "Go")
+			})
+			Container("Queue", "Carries bookings.", "RabbitMQ")
+			Group("Storage", func() {
+				Container("Database", "Stores bookings.", "PostgreSQL", func() {
+					Tag("Database")
+				})
+			})
+		})
+		Person("Guest", "Books rooms.", func() {
+			Uses(Booking, "Books rooms with")
+			Uses("Booking/Web", "Visits", "HTTPS")
+			InteractsWith("Receptionist", "Calls")
+		})
+		Person("Receptionist", "Welcomes guests.")
+		DeploymentEnvironment("Production", func() {
+			DeploymentNode("Cloud", "", "AWS", func() {
+				InfrastructureNode("Load Balancer", "Routes traffic.", "ELB")
+				DeploymentNode("Cluster", "", "Kubernetes", func() {
+					ContainerInstance("Booking/Web")
+				})
+				DeploymentNode("RDS", "", "Amazon RDS", func() {
+					ContainerInstance("Booking/Database")
+				})
+			})
+		})
+		Views(func() {
+			SystemLandscapeView("landscape", "Resorts landscape.", func() {
+				AddAll()
+			})
+			SystemContextView(Booking, "context", "Booking context.", func() {
+				AddAll()
+			})
+			ContainerView(Booking, "containers", func() {
+				AddAll()
+			})
+			ComponentView("Booking/Web", "components", func() {
+				AddAll()
+			})
+			DeploymentView(Booking, "Production", "production", "Production deployment.", func() {
+				AddAll()
+			})
+			Styles(func() {
+				ElementStyle("Database", func() {
+					Shape(ShapeCylinder)
+				})
+			})
+		})
+	})
+}
+
+func TestRender(t *testing.T) {
+	d := testutil.RunDSL(t, hotel)
+	layouts := map[string]svg.Layout{
+		"context": {
+			elementID(d, "Guest"):        {X: 100, Y: 100},
+			elementID(d, "Receptionist"): {X: 100, Y: 600},
+			elementID(d, "Booking"):      {X: 500, Y: 100},
+			elementID(d, "Payments"):     {X: 900, Y: 100},
+		},
+	}
+	b, err := Render(d, layouts)
+	if err != nil {
+		t.Fatalf("failed to render: %s", err)
+	}
+	testutil.AssertGolden(t, "hotel.xml", string(b))
+}
+
+func TestRenderEdgeCases(t *testing.T) {
+	d := testutil.RunDSL(t, hotel)
+	b, err := Render(d, nil)
+	if err != nil {
+		t.Fatalf("failed to render: %s", err)
+	}
+	got := string(b)
+	id := func(name string) string { return "id-" + elementID(d, name) }
+	tests := []struct {
+		name, want string
+	}{
+		{"escaped documentation", `<documentation xml:lang="en">Charges &lt;cards&gt; &amp; refunds.</documentation>`},
+		{"cylinder container is a node", `<name xml:lang="en">Database</name>`},
+		{"container composition", `source="` + id("Booking") + `" target="` + id("Web") + `" xsi:type="Composition"`},
+		{"node association", `source="` + id("Booking") + `" target="` + id("Database") + `" xsi:type="Association"`},
+		{"person served by system", `source="` + id("Booking") + `" target="` + id("Guest") + `" xsi:type="Serving"`},
+		{"person to person association", `source="` + id("Guest") + `" target="` + id("Receptionist") + `" xsi:type="Association"`},
+		{"asynchronous flow", `source="` + id("Web") + `" target="` + id("Queue") + `" xsi:type="Flow"`},
+		{"node serves application", `source="` + id("Database") + `" target="` + id("Web") + `" xsi:type="Serving"`},
+		{"environment property", `<name xml:lang="en">Environment</name>`},
+		{"enterprise boundary", `<label xml:lang="en">Resorts</label>`},
+		{"group boundary", `<label xml:lang="en">Storage</label>`},
+		{"instance relationship", `relationshipRef="id-1pvctpm"`},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			if !strings.Contains(got, tt.want) {
+				t.Errorf("document does not contain %q:\n%s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestRenderEmpty(t *testing.T) {
+	b, err := Render(&mdl.Design{Name: "Empty", Model: &mdl.Model{}, Views: &mdl.Views{}}, nil)
+	if err != nil {
+		t.Fatalf("failed to render: %s", err)
+	}
+	testutil.AssertGolden(t, "empty.xml", string(b))
+}
+
+// elementID returns the ID of the person, software system or container with
+// the given name.
+func elementID(d *mdl.Design, name string) string {
+	for _, p := range d.Model.People {
+		if p.Name == name {
+			return p.ID
+		}
+	}
+	for _, s := range d.Model.Systems {
+		if s.Name == name {
+			return s.ID
+		}
+		for _, c := range s.Containers {
+			if c.Name == name {
+				return c.ID
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,367 @@
+package backstage
+
+import (
+	"bytes"
+	"sort"
+	"strconv"
+	"strings"
+
+	"goa.design/model/mdl"
+	"gopkg.in/yaml.v3"
+)
+
+// exporter renders a design as catalog entities.
+type exporter struct {
+	// d is the design being exported.
+	d *mdl.Design
+	// refs indexes the references of the exported entities by element ID.
+	refs map[string]string
+	// names records the entity names in use per kind.
+	names map[string]map[string]bool
+}
+
+// Catalog renders the software systems, containers and components of the
+// given design as a multi-document catalog-info.yaml file.
+func Catalog(d *mdl.Design) ([]byte, error) {
+	ex := &exporter{
+		d:     d,
+		refs:  make(map[string]string),
+		names: make(map[string]map[string]bool),
+	}
+	var (
+		entities []*Entity
+		sources  = make(map[string]*Entity)
+	)
+	add := func(id string, e *Entity) {
+		entities = append(entities, e)
+		sources[id] = e
+	}
+	for _, s := range d.Model.Systems {
+		kind := "System"
+		if len(s.Containers) == 0 {
+			// Software systems created from components or resources that
+			// do not belong to a system.
+			switch {
+			case hasTag(s.Tags, "Resource"):
+				kind = "Resource"
+			case strings.HasPrefix(s.Properties["entity"], "component:"):
+				kind = "Component"
+			}
+		}
+		add(s.ID, ex.entity(s.ID, kind, s.Name, s.Description, s.Tags, s.URL, s.Properties))
+	}
+	for _, s := range d.Model.Systems {
+		sys := ex.refs[s.ID]
+		for _, c := range s.Containers {
+			kind := "Component"
+			if ex.isResource(c) {
+				kind = "Resource"
+			}
+			e := ex.entity(c.ID, kind, c.Name, c.Description, c.Tags, c.URL, c.Properties)
+			e.Spec.System = shortRef(sys, "System")
+			add(c.ID, e)
+			for _, cmp := range c.Components {
+				e := ex.entity(cmp.ID, "Component", cmp.Name, cmp.Description, cmp.Tags, cmp.URL, cmp.Properties)
+				e.Spec.System = shortRef(sys, "System")
+				e.Spec.SubcomponentOf = shortRef(ex.refs[c.ID], "Component")
+				add(cmp.ID, e)
+			}
+		}
+	}
+	d.Model.IterateRelationships(func(r *mdl.Relationship) {
+		if r.Properties["api"] != "" {
+			return // exported as API below
+		}
+		src, ok := sources[r.SourceID]
+		if !ok || src.Kind == "System" || r.LinkedRelationshipID != "" || implied(d.Model, r) {
+			return
+		}
+		dest, ok := sources[r.DestinationID]
+		if !ok || dest.Kind == "System" || dest == src {
+			return
+		}
+		ref := shortRef(ex.refs[r.DestinationID], "")
+		for _, dep := range src.Spec.DependsOn {
+			if dep == ref {
+				return
+			}
+		}
+		src.Spec.DependsOn = append(src.Spec.DependsOn, ref)
+	})
+	apis := make(map[string]*Entity)
+	d.Model.IterateRelationships(func(r *mdl.Relationship) {
+		ref := r.Properties["api"]
+		if ref == "" || r.LinkedRelationshipID != "" {
+			return
+		}
+		src, ok := sources[r.SourceID]
+		if !ok || src.Kind != "Component" {
+			return // only components consume APIs
+		}
+		api, ok := apis[ref]
+		if !ok {
+			api = ex.api(r)
+			apis[ref] = api
+		}
+		src.Spec.ConsumesAPIs = appendRef(src.Spec.ConsumesAPIs, api.Metadata.Name)
+		dest, ok := sources[r.DestinationID]
+		if !ok {
+			return
+		}
+		switch dest.Kind {
+		case "Component":
+			dest.Spec.ProvidesAPIs = appendRef(dest.Spec.ProvidesAPIs, api.Metadata.Name)
+			if api.Spec.System == "" {
+				api.Spec.System = dest.Spec.System
+			}
+			if api.Spec.Owner == "" {
+				api.Spec.Owner = dest.Spec.Owner
+			}
+		case "System":
+			api.Spec.System = shortRef(ex.refs[r.DestinationID], "System")
+		}
+	})
+	for _, ref := range sortedRefs(apis) {
+		entities = append(entities, apis[ref])
+	}
+	for _, e := range entities {
+		sort.Strings(e.Spec.DependsOn)
+		sort.Strings(e.Spec.ConsumesAPIs)
+		sort.Strings(e.Spec.ProvidesAPIs)
+		if e.Kind == "API" && e.Spec.Owner == "" {
+			e.Spec.Owner = "unknown"
+		}
+	}
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	for _, e := range entities {
+		if err := enc.Encode(e); err != nil {
+			return nil, err
+		}
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// entity returns the entity of the given kind corresponding to the element
+// with the given ID and attributes.
+func (ex *exporter) entity(id, kind, name, desc, tags, url string, props map[string]string) *Entity {
+	e := &Entity{
+		APIVersion: APIVersion,
+		Kind:       kind,
+		Metadata: &Metadata{
+			Name:        ex.name(kind, name, props["entity"]),
+			Description: desc,
+			Tags:        entityTags(tags),
+		},
+		Spec: &Spec{
+			Owner:  props["owner"],
+			Type:   props["type"],
+			Domain: props["domain"],
+		},
+	}
+	if e.Metadata.Name != name {
+		e.Metadata.Title = name
+	}
+	if url != "" {
+		e.Metadata.Links = []*Link{{URL: url}}
+	}
+	if t := ex.d.Model.Owner(id); t != nil {
+		e.Spec.Owner = slug(t.Name, "_.")
+	}
+	if e.Spec.Owner == "" {
+		e.Spec.Owner = "unknown"
+	}
+	switch kind {
+	case "Component":
+		e.Spec.Lifecycle = props["lifecycle"]
+		if e.Spec.Lifecycle == "" {
+			e.Spec.Lifecycle = "production"
+		}
+		if e.Spec.Type == "" {
+			e.Spec.Type = "service"
+		}
+	case "Resource":
+		if e.Spec.Type == "" {
+			e.Spec.Type = "database"
+		}
+	}
+	if kind != "System" {
+		e.Spec.Domain = ""
+	}
+	ex.refs[id] = normalize(e.Metadata.Name, kind)
+	return e
+}
+
+// api returns the API entity recorded in the properties of the given
+// relationship, see apiProperties.
+func (ex *exporter) api(r *mdl.Relationship) *Entity {
+	var (
+		props = r.Properties
+		title = strings.TrimPrefix(r.Description, "Uses ")
+	)
+	e := &Entity{
+		APIVersion: APIVersion,
+		Kind:       "API",
+		Metadata: &Metadata{
+			Name:        ex.name("API", title, props["api"]),
+			Description: props["api.description"],
+		},
+		Spec: &Spec{
+			Type:      r.Technology,
+			Lifecycle: props["api.lifecycle"],
+			Owner:     props["api.owner"],
+		},
+	}
+	if e.Metadata.Name != title {
+		e.Metadata.Title = title
+	}
+	if e.Spec.Type == "" {
+		e.Spec.Type = "openapi"
+	}
+	if e.Spec.Lifecycle == "" {
+		e.Spec.Lifecycle = "production"
+	}
+	if def := props["api.definition"]; def != "" {
+		var v interface{}
+		if err := yaml.Unmarshal([]byte(def), &v); err == nil {
+			e.Spec.Definition = v
+		}
+	}
+	return e
+}
+
+// isResource returns true if the given container is tagged "Resource" or is
+// rendered as a cylinder.
+func (ex *exporter) isResource(c *mdl.Container) bool {
+	if hasTag(c.Tags, "Resource") {
+		return true
+	}
+	if ex.d.Views == nil {
+		return false
+	}
+	st := ex.d.Views.Styles.ElementStyle(c.Tags)
+	return st != nil && st.Shape == mdl.ShapeCylinder
+}
+
+// name returns a unique entity name for the entity of the given kind. The
+// name is the name recorded in the "entity" property of the element if any,
+// a valid entity name derived from the element name otherwise.
+func (ex *exporter) name(kind, elemName, ref string) string {
+	names, ok := ex.names[kind]
+	if !ok {
+		names = make(map[string]bool)
+		ex.names[kind] = names
+	}
+	name := slug(elemName, "_.")
+	if ref != "" {
+		if i := strings.LastIndexAny(ref, ":/"); i >= 0 {
+			ref = ref[i+1:]
+		}
+		name = ref
+	}
+	if name == "" {
+		name = strings.ToLower(kind)
+	}
+	res := name
+	for i := 2; names[res]; i++ {
+		res = name + "-" + strconv.Itoa(i)
+	}
+	names[res] = true
+	return res
+}
+
+// appendRef appends the reference to the API with the given name to refs
+// unless it is already present.
+func appendRef(refs []string, name string) []string {
+	for _, r := range refs {
+		if r == name {
+			return refs
+		}
+	}
+	return append(refs, name)
+}
+
+// entityTags returns the valid catalog tags corresponding to the custom tags
+// in the given comma separated list of element tags.
+func entityTags(tags string) []string {
+	var res []string
+	seen := make(map[string]bool)
+	for _, t := range strings.Split(tags, ",") {
+		switch t = strings.TrimSpace(t); t {
+		case "", "Element", "Person", "Software System", "Container", "Component", "Resource":
+			continue
+		}
+		if t = slug(t, "+#"); t != "" && !seen[t] {
+			seen[t] = true
+			res = append(res, t)
+		}
+	}
+	sort.Strings(res)
+	return res
+}
+
+// slug returns a lowercase version of s containing only letters, digits,
+// dashes and the given extra characters. Other characters are replaced with
+// dashes. The result is at most 63 characters long.
+func slug(s, extra string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(s) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || strings.ContainsRune(extra, r) {
+			if dash && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			dash = false
+			b.WriteRune(r)
+			continue
+		}
+		dash = true
+	}
+	res := b.String()
+	if len(res) > 63 {
+		res = strings.TrimRight(res[:63], "-_.")
+	}
+	return res
+}
+
+// implied returns true if r is implied by a relationship with the same
+// description between descendants of its source and destination.
+func implied(m *mdl.Model, r *mdl.Relationship) bool {
+	res := false
+	m.IterateRelationships(func(o *mdl.Relationship) {
+		if o == r || o.Description != r.Description || o.SourceID == r.SourceID && o.DestinationID == r.DestinationID {
+			return
+		}
+		if isDescendant(m, o.SourceID, r.SourceID) && isDescendant(m, o.DestinationID, r.DestinationID) {
+			res = true
+		}
+	})
+	return res
+}
+
+// isDescendant returns true if the element with ID id is the element with ID
+// ancestor or one of its descendants.
+func isDescendant(m *mdl.Model, id, ancestor string) bool {
+	for el := m.Element(id); el != nil; el = m.Parent(id) {
+		if id = idOf(el); id == ancestor {
+			return true
+		}
+	}
+	return false
+}
+
+// hasTag returns true if the given comma separated list of tags contains
+// tag.
+func hasTag(tags, tag string) bool {
+	for _, t := range strings.Split(tags, ",") {
+		if strings.TrimSpace(t) == tag {
+			return true
+		}
+	}
+	return false
+}
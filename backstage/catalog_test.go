@@ -0,0 +1,154 @@
+package backstage
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestCatalogRoundTrip(t *testing.T) {
+	entities, err := Load(filepath.Join("testdata", "catalog-info.yaml"))
+	if err != nil {
+		t.Fatalf("failed to load catalog: %s", err)
+	}
+	d, err := Design("Catalog", entities)
+	if err != nil {
+		t.Fatalf("failed to build design: %s", err)
+	}
+	b, err := Catalog(d)
+	if err != nil {
+		t.Fatalf("failed to render catalog: %s", err)
+	}
+	got, err := Parse(b)
+	if err != nil {
+		t.Fatalf("failed to parse rendered catalog: %s\n%s", err, b)
+	}
+	byRef := make(map[string]*Entity)
+	for _, e := range got {
+		byRef[e.Ref()] = e
+	}
+	entity := func(t *testing.T, ref string) *Entity {
+		t.Helper()
+		e, ok := byRef[ref]
+		if !ok {
+			t.Fatalf("entity %q not found in:\n%s", ref, b)
+		}
+		return e
+	}
+
+	t.Run("consumer", func(t *testing.T) {
+		web := entity(t, "component:default/web")
+		if want := []string{"events", "orders-api"}; !reflect.DeepEqual(web.Spec.ConsumesAPIs, want) {
+			t.Errorf("got consumesApis %v, want %v", web.Spec.ConsumesAPIs, want)
+		}
+		if want := []string{"resource:orders-db"}; !reflect.DeepEqual(web.Spec.DependsOn, want) {
+			t.Errorf("got dependsOn %v, want %v", web.Spec.DependsOn, want)
+		}
+	})
+
+	t.Run("provider", func(t *testing.T) {
+		orders := entity(t, "component:default/orders")
+		if want := []string{"events", "orders-api"}; !reflect.DeepEqual(orders.Spec.ProvidesAPIs, want) {
+			t.Errorf("got providesApis %v, want %v", orders.Spec.ProvidesAPIs, want)
+		}
+		if orders.Spec.Lifecycle != "experimental" {
+			t.Errorf("got lifecycle %q, want experimental", orders.Spec.Lifecycle)
+		}
+	})
+
+	t.Run("apis", func(t *testing.T) {
+		tests := []struct {
+			ref, title, typ, desc, definition string
+		}{
+			{"api:default/orders-api", "Orders API", "openapi", "Creates and lists orders.", "$text: ./openapi.yaml"},
+			{"api:default/events", "", "asyncapi", "Order events.", "|\n    asyncapi: 2.0.0"},
+		}
+		for _, tt := range tests {
+			api := entity(t, tt.ref)
+			if api.Metadata.Title != tt.title {
+				t.Errorf("%s: got title %q, want %q", tt.ref, api.Metadata.Title, tt.title)
+			}
+			if api.Metadata.Description != tt.desc {
+				t.Errorf("%s: got description %q, want %q", tt.ref, api.Metadata.Description, tt.desc)
+			}
+			if api.Spec.Type != tt.typ {
+				t.Errorf("%s: got type %q, want %q", tt.ref, api.Spec.Type, tt.typ)
+			}
+			if api.Spec.Owner != "team-orders" || api.Spec.System != "shop" {
+				t.Errorf("%s: got owner %q and system %q, want team-orders and shop", tt.ref, api.Spec.Owner, api.Spec.System)
+			}
+			def, err := yaml.Marshal(api.Spec.Definition)
+			if err != nil {
+				t.Fatalf("%s: failed to marshal definition: %s", tt.ref, err)
+			}
+			if got := string(def); got != tt.definition+"\n" {
+				t.Errorf("%s: got definition %q, want %q", tt.ref, got, tt.definition+"\n")
+			}
+		}
+	})
+
+	t.Run("idempotent", func(t *testing.T) {
+		d2, err := Design("Catalog", got)
+		if err != nil {
+			t.Fatalf("failed to build design from rendered catalog: %s", err)
+		}
+		b2, err := Catalog(d2)
+		if err != nil {
+			t.Fatalf("failed to render catalog: %s", err)
+		}
+		if string(b2) != string(b) {
+			t.Errorf("second round trip differs:\n%s\nvs.\n%s", b2, b)
+		}
+	})
+}
+
+func TestAPIWithoutProvider(t *testing.T) {
+	entities, err := Parse([]byte(`
+kind: System
+metadata:
+  name: billing
+---
+kind: Component
+metadata:
+  name: checkout
+spec:
+  consumesApis: [invoices]
+---
+kind: API
+metadata:
+  name: invoices
+spec:
+  type: grpc
+  system: billing
+`))
+	if err != nil {
+		t.Fatalf("failed to parse catalog: %s", err)
+	}
+	d, err := Design("Catalog", entities)
+	if err != nil {
+		t.Fatalf("failed to build design: %s", err)
+	}
+	b, err := Catalog(d)
+	if err != nil {
+		t.Fatalf("failed to render catalog: %s", err)
+	}
+	got, err := Parse(b)
+	if err != nil {
+		t.Fatalf("failed to parse rendered catalog: %s", err)
+	}
+	var found bool
+	for _, e := range got {
+		if e.Kind != "API" {
+			continue
+		}
+		found = true
+		if e.Metadata.Name != "invoices" || e.Spec.System != "billing" || e.Spec.Type != "grpc" {
+			t.Errorf("got API %q of system %q and type %q, want invoices, billing and grpc", e.Metadata.Name, e.Spec.System, e.Spec.Type)
+		}
+	}
+	if !found {
+		t.Errorf("API entity not exported:\n%s", b)
+	}
+}
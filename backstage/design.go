@@ -0,0 +1,477 @@
+package backstage
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"goa.design/model/mdl"
+	"gopkg.in/yaml.v3"
+)
+
+// importer builds a design from catalog entities.
+type importer struct {
+	// m is the model being built.
+	m *mdl.Model
+	// entities indexes the entities by reference.
+	entities map[string]*Entity
+	// elements indexes the model elements by entity reference.
+	elements map[string]interface{}
+	// visiting records the entities being mapped to detect cycles.
+	visiting map[string]bool
+	// names records the element names in use per parent ID.
+	names map[string]map[string]bool
+	// seq is used to generate element IDs.
+	seq int
+}
+
+// Design returns the design with the given name that describes the given
+// catalog entities. The design includes a system landscape view and one
+// container view per software system that has containers.
+func Design(name string, entities []*Entity) (*mdl.Design, error) {
+	im := &importer{
+		m:        &mdl.Model{},
+		entities: make(map[string]*Entity),
+		elements: make(map[string]interface{}),
+		visiting: make(map[string]bool),
+		names:    make(map[string]map[string]bool),
+	}
+	for _, e := range entities {
+		ref := e.Ref()
+		if _, ok := im.entities[ref]; ok {
+			return nil, fmt.Errorf("duplicate entity %q", shortRef(ref, ""))
+		}
+		im.entities[ref] = e
+	}
+	for _, e := range entities {
+		if e.Kind == "System" {
+			im.system(e)
+		}
+	}
+	for _, e := range entities {
+		if e.Kind == "Component" || e.Kind == "Resource" {
+			if _, err := im.element(e); err != nil {
+				return nil, err
+			}
+		}
+	}
+	for _, e := range entities {
+		if e.Kind == "Component" || e.Kind == "Resource" {
+			im.relationships(e)
+		}
+	}
+	im.impliedRelationships()
+	return &mdl.Design{Name: name, Model: im.m, Views: im.views()}, nil
+}
+
+// system returns the software system corresponding to the given System
+// entity creating it if needed.
+func (im *importer) system(e *Entity) *mdl.SoftwareSystem {
+	if s, ok := im.elements[e.Ref()].(*mdl.SoftwareSystem); ok {
+		return s
+	}
+	s := &mdl.SoftwareSystem{
+		ID:          im.newID(),
+		Name:        im.name("", e),
+		Description: e.Metadata.Description,
+		Tags:        tags(e, "Element", "Software System"),
+		URL:         url(e),
+		Properties:  im.properties(e),
+	}
+	im.m.Systems = append(im.m.Systems, s)
+	im.elements[e.Ref()] = s
+	return s
+}
+
+// element returns the model element corresponding to the given Component or
+// Resource entity creating it if needed.
+func (im *importer) element(e *Entity) (interface{}, error) {
+	ref := e.Ref()
+	if el, ok := im.elements[ref]; ok {
+		return el, nil
+	}
+	if im.visiting[ref] {
+		return nil, fmt.Errorf("%s: subcomponentOf cycle", shortRef(ref, ""))
+	}
+	im.visiting[ref] = true
+	defer delete(im.visiting, ref)
+
+	var parent interface{}
+	if e.Spec.SubcomponentOf != "" {
+		if pe, ok := im.entities[normalize(e.Spec.SubcomponentOf, "Component")]; ok {
+			p, err := im.element(pe)
+			if err != nil {
+				return nil, err
+			}
+			parent = p
+		}
+	}
+	if parent == nil && e.Spec.System != "" {
+		sref := normalize(e.Spec.System, "System")
+		se, ok := im.entities[sref]
+		if !ok {
+			// Create system for dangling reference.
+			se = &Entity{
+				Kind:     "System",
+				Metadata: &Metadata{Name: shortRef(sref, "System")},
+				Spec:     &Spec{},
+			}
+			im.entities[sref] = se
+		}
+		parent = im.system(se)
+	}
+
+	defaults := func(kind string) []string {
+		if e.Kind == "Resource" {
+			return []string{"Element", kind, "Resource"}
+		}
+		return []string{"Element", kind}
+	}
+	var el interface{}
+	switch p := parent.(type) {
+	case *mdl.SoftwareSystem:
+		c := &mdl.Container{
+			ID:          im.newID(),
+			Name:        im.name(p.ID, e),
+			Description: e.Metadata.Description,
+			Tags:        tags(e, defaults("Container")...),
+			URL:         url(e),
+			Properties:  im.properties(e),
+		}
+		p.Containers = append(p.Containers, c)
+		el = c
+	case *mdl.Container, *mdl.Component:
+		ct, ok := p.(*mdl.Container)
+		if !ok {
+			// The model does not support nested components, add to the
+			// container of the parent component instead.
+			ct = im.m.Parent(idOf(p)).(*mdl.Container)
+		}
+		c := &mdl.Component{
+			ID:          im.newID(),
+			Name:        im.name(ct.ID, e),
+			Description: e.Metadata.Description,
+			Tags:        tags(e, defaults("Component")...),
+			URL:         url(e),
+			Properties:  im.properties(e),
+		}
+		ct.Components = append(ct.Components, c)
+		el = c
+	default:
+		s := im.system(e)
+		s.Tags = tags(e, defaults("Software System")...)
+		el = s
+	}
+	im.elements[ref] = el
+	return el, nil
+}
+
+// relationships adds the relationships corresponding to the dependsOn and
+// consumesApis relations of the given entity.
+func (im *importer) relationships(e *Entity) {
+	src := im.elements[e.Ref()]
+	for _, dep := range e.Spec.DependsOn {
+		if dest, ok := im.elements[normalize(dep, "Component")]; ok {
+			im.addRelationship(src, dest, "Uses", "", mdl.InteractionUndefined, nil)
+		}
+	}
+	for _, a := range e.Spec.ConsumesAPIs {
+		aref := normalize(a, "API")
+		api, ok := im.entities[aref]
+		if !ok {
+			continue
+		}
+		desc := "Uses " + title(api)
+		style := mdl.InteractionUndefined
+		if strings.EqualFold(api.Spec.Type, "asyncapi") {
+			style = mdl.InteractionAsynchronous
+		}
+		props := apiProperties(api)
+		var found bool
+		for _, ref := range sortedRefs(im.entities) {
+			p := im.entities[ref]
+			for _, pa := range p.Spec.ProvidesAPIs {
+				if normalize(pa, "API") != aref {
+					continue
+				}
+				if dest, ok := im.elements[ref]; ok {
+					im.addRelationship(src, dest, desc, api.Spec.Type, style, props)
+					found = true
+				}
+			}
+		}
+		if !found && api.Spec.System != "" {
+			if dest, ok := im.elements[normalize(api.Spec.System, "System")]; ok {
+				im.addRelationship(src, dest, desc, api.Spec.Type, style, props)
+			}
+		}
+	}
+}
+
+// addRelationship adds a relationship from src to dest unless the elements
+// are the same, one is the ancestor of the other or the relationship already
+// exists. props records the API the relationship was created from if any.
+func (im *importer) addRelationship(src, dest interface{}, desc, tech string, style mdl.InteractionStyleKind, props map[string]string) {
+	srcID, destID := idOf(src), idOf(dest)
+	if isDescendant(im.m, destID, srcID) || isDescendant(im.m, srcID, destID) {
+		return
+	}
+	rels := relationships(src)
+	for _, r := range *rels {
+		if r.DestinationID == destID && r.Description == desc {
+			return
+		}
+	}
+	tags := "Relationship"
+	if style == mdl.InteractionAsynchronous {
+		tags += ",Asynchronous"
+	}
+	*rels = append(*rels, &mdl.Relationship{
+		ID:               im.newID(),
+		Description:      desc,
+		Tags:             tags,
+		SourceID:         srcID,
+		DestinationID:    destID,
+		Technology:       tech,
+		InteractionStyle: style,
+		Properties:       props,
+	})
+}
+
+// impliedRelationships adds the relationships implied by the relationships
+// of containers and components to the parents of their source and
+// destination so that they show in the views.
+func (im *importer) impliedRelationships() {
+	var rels []*mdl.Relationship
+	im.m.IterateRelationships(func(r *mdl.Relationship) { rels = append(rels, r) })
+	for _, r := range rels {
+		for _, src := range im.lineage(r.SourceID) {
+			for _, dest := range im.lineage(r.DestinationID) {
+				im.addRelationship(src, dest, r.Description, r.Technology, r.InteractionStyle, nil)
+			}
+		}
+	}
+}
+
+// views returns a system landscape view listing all the software systems and
+// a container view for each software system that has containers.
+func (im *importer) views() *mdl.Views {
+	var ids []string
+	for _, s := range im.m.Systems {
+		ids = append(ids, s.ID)
+	}
+	vs := &mdl.Views{
+		LandscapeViews: []*mdl.LandscapeView{{ViewProps: im.viewProps("SystemLandscape", "Software systems of the catalog.", ids)}},
+		Styles: &mdl.Styles{Elements: []*mdl.ElementStyle{
+			{Tag: "Resource", Shape: mdl.ShapeCylinder},
+		}},
+	}
+	for _, s := range im.m.Systems {
+		if len(s.Containers) == 0 {
+			continue
+		}
+		var (
+			ids     []string
+			related = make(map[string]bool)
+		)
+		for _, c := range s.Containers {
+			ids = append(ids, c.ID)
+			for _, r := range c.Relationships {
+				if _, ok := im.m.Element(r.DestinationID).(*mdl.SoftwareSystem); ok {
+					related[r.DestinationID] = true
+				}
+			}
+		}
+		// Add the software systems that use the containers of s.
+		im.m.IterateRelationships(func(r *mdl.Relationship) {
+			if _, ok := im.m.Element(r.SourceID).(*mdl.SoftwareSystem); !ok || r.SourceID == s.ID {
+				return
+			}
+			if p, ok := im.m.Parent(r.DestinationID).(*mdl.SoftwareSystem); ok && p == s {
+				related[r.SourceID] = true
+			}
+		})
+		ids = append(ids, sortedKeys(related)...)
+		key := strings.Join(strings.Fields(s.Name), "") + "Containers"
+		desc := "Containers of " + s.Name + "."
+		vs.ContainerViews = append(vs.ContainerViews, &mdl.ContainerView{
+			ViewProps:        im.viewProps(key, desc, ids),
+			SoftwareSystemID: s.ID,
+		})
+	}
+	return vs
+}
+
+// viewProps returns the properties of a view with the given key and
+// description that includes the elements with the given IDs and all the
+// relationships between them.
+func (im *importer) viewProps(key, desc string, ids []string) *mdl.ViewProps {
+	props := &mdl.ViewProps{Key: key, Description: desc}
+	in := make(map[string]bool)
+	for _, id := range ids {
+		props.ElementViews = append(props.ElementViews, &mdl.ElementView{ID: id})
+		in[id] = true
+	}
+	im.m.IterateRelationships(func(r *mdl.Relationship) {
+		if in[r.SourceID] && in[r.DestinationID] {
+			props.RelationshipViews = append(props.RelationshipViews, &mdl.RelationshipView{ID: r.ID})
+		}
+	})
+	return props
+}
+
+// name returns a name for the element corresponding to e that is unique
+// among the children of the element with the given parent ID.
+func (im *importer) name(parentID string, e *Entity) string {
+	names, ok := im.names[parentID]
+	if !ok {
+		names = make(map[string]bool)
+		im.names[parentID] = names
+	}
+	candidates := []string{title(e), e.Metadata.Name}
+	for i := 2; ; i++ {
+		for _, c := range candidates {
+			if !names[c] {
+				names[c] = true
+				return c
+			}
+		}
+		candidates = []string{e.Metadata.Name + " " + strconv.Itoa(i)}
+	}
+}
+
+// properties returns the element properties that record the entity name,
+// owner, lifecycle, type and domain.
+func (im *importer) properties(e *Entity) map[string]string {
+	props := map[string]string{"entity": shortRef(e.Ref(), "")}
+	if e.Spec.Owner != "" {
+		ref := normalize(e.Spec.Owner, "Group")
+		props["owner"] = shortRef(ref, "Group")
+		if g, ok := im.entities[ref]; ok {
+			name := g.Metadata.Title
+			if g.Spec.Profile != nil && g.Spec.Profile.DisplayName != "" {
+				name = g.Spec.Profile.DisplayName
+			}
+			if name != "" {
+				props["team"] = name
+			}
+		}
+	}
+	if e.Spec.Lifecycle != "" {
+		props["lifecycle"] = e.Spec.Lifecycle
+	}
+	if e.Spec.Type != "" {
+		props["type"] = e.Spec.Type
+	}
+	if e.Spec.Domain != "" {
+		props["domain"] = shortRef(normalize(e.Spec.Domain, "Domain"), "Domain")
+	}
+	return props
+}
+
+// apiProperties returns the relationship properties that record the name,
+// description, owner, lifecycle and definition of the given API entity so
+// that it can be exported back to the catalog.
+func apiProperties(api *Entity) map[string]string {
+	props := map[string]string{"api": shortRef(api.Ref(), "")}
+	if api.Metadata.Description != "" {
+		props["api.description"] = api.Metadata.Description
+	}
+	if api.Spec.Owner != "" {
+		props["api.owner"] = shortRef(normalize(api.Spec.Owner, "Group"), "Group")
+	}
+	if api.Spec.Lifecycle != "" {
+		props["api.lifecycle"] = api.Spec.Lifecycle
+	}
+	if api.Spec.Definition != nil {
+		if b, err := yaml.Marshal(api.Spec.Definition); err == nil {
+			props["api.definition"] = string(b)
+		}
+	}
+	return props
+}
+
+// lineage returns the element with the given ID followed by its ancestors.
+func (im *importer) lineage(id string) []interface{} {
+	var res []interface{}
+	for el := im.m.Element(id); el != nil; el = im.m.Parent(id) {
+		res = append(res, el)
+		id = idOf(el)
+	}
+	return res
+}
+
+// newID returns a new unique element or relationship ID.
+func (im *importer) newID() string {
+	im.seq++
+	return strconv.Itoa(im.seq)
+}
+
+// title returns the title of the entity or its name if it has no title.
+func title(e *Entity) string {
+	if e.Metadata.Title != "" {
+		return e.Metadata.Title
+	}
+	return e.Metadata.Name
+}
+
+// tags returns the comma separated list of tags made of the given default
+// tags followed by the tags of the entity.
+func tags(e *Entity, defaults ...string) string {
+	return strings.Join(append(defaults, e.Metadata.Tags...), ",")
+}
+
+// url returns the URL of the first link of the entity if any.
+func url(e *Entity) string {
+	if len(e.Metadata.Links) == 0 {
+		return ""
+	}
+	return e.Metadata.Links[0].URL
+}
+
+// idOf returns the ID of the given element.
+func idOf(el interface{}) string {
+	switch e := el.(type) {
+	case *mdl.SoftwareSystem:
+		return e.ID
+	case *mdl.Container:
+		return e.ID
+	case *mdl.Component:
+		return e.ID
+	}
+	return ""
+}
+
+// relationships returns a pointer to the relationships of the given element.
+func relationships(el interface{}) *[]*mdl.Relationship {
+	switch e := el.(type) {
+	case *mdl.SoftwareSystem:
+		return &e.Relationships
+	case *mdl.Container:
+		return &e.Relationships
+	case *mdl.Component:
+		return &e.Relationships
+	}
+	return nil
+}
+
+// sortedRefs returns the keys of the given map sorted alphabetically.
+func sortedRefs(m map[string]*Entity) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// sortedKeys returns the keys of the given map sorted alphabetically.
+func sortedKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
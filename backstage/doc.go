@@ -0,0 +1,37 @@
+/*
+Package backstage converts between software architecture designs and
+Backstage software catalogs (https://backstage.io/docs/features/software-catalog/).
+
+Design builds a design from the System, Component, API, Resource and Group
+entities read from catalog-info.yaml files. The entities are mapped to the
+model as follows:
+
+	System    -> Software System
+	Component -> Container of the system given by spec.system, Component of
+	             the container given by spec.subcomponentOf or Software System
+	             if the component belongs to no system
+	Resource  -> Container tagged "Resource" (Software System if the
+	             resource belongs to no system)
+	API       -> Relationships from the consumers to the providers
+	Group     -> "owner" and "team" properties of the owned elements
+
+The dependsOn relation of components and resources is mapped to "Uses"
+relationships. The relationships created from consumesApis record the API
+entity (name, description, owner, lifecycle and definition) in their
+properties. The name of the entity, its owner, lifecycle, type and domain
+are recorded as element properties so that the design can be exported back to
+the catalog.
+
+Catalog does the opposite: it renders the software systems, containers and
+components of a design as System, Component and Resource entities. Containers
+tagged "Resource" or rendered as cylinders are exported as resources.
+Relationships between components and resources are exported as dependsOn
+relations. Relationships that record an API entity are exported as API
+entities together with the consumesApis relation of the source and the
+providesApis relation of the destination (or the system of the API if the
+destination is a software system). Group entities are not exported as the catalog is expected to be
+the source of ownership data, the owner of an entity is the team that owns the
+corresponding element (see dsl.Owner) or the "owner" property of the element
+if it has no owner.
+*/
+package backstage
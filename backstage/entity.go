@@ -0,0 +1,207 @@
+package backstage
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+type (
+	// Entity is a Backstage catalog entity.
+	Entity struct {
+		// APIVersion is the version of the entity specification.
+		APIVersion string `yaml:"apiVersion"`
+		// Kind is the entity kind, e.g. "Component" or "System".
+		Kind string `yaml:"kind"`
+		// Metadata contains the name, description and tags of the entity.
+		Metadata *Metadata `yaml:"metadata"`
+		// Spec contains the kind specific fields.
+		Spec *Spec `yaml:"spec,omitempty"`
+	}
+
+	// Metadata describes the metadata common to all entity kinds.
+	Metadata struct {
+		// Name of entity, unique for a given kind and namespace.
+		Name string `yaml:"name"`
+		// Namespace of entity, "default" if empty.
+		Namespace string `yaml:"namespace,omitempty"`
+		// Title is the display name of the entity if any.
+		Title string `yaml:"title,omitempty"`
+		// Description of entity.
+		Description string `yaml:"description,omitempty"`
+		// Labels attached to the entity.
+		Labels map[string]string `yaml:"labels,omitempty"`
+		// Annotations attached to the entity.
+		Annotations map[string]string `yaml:"annotations,omitempty"`
+		// Tags attached to the entity.
+		Tags []string `yaml:"tags,omitempty"`
+		// Links to external resources.
+		Links []*Link `yaml:"links,omitempty"`
+	}
+
+	// Link is an external hyperlink related to an entity.
+	Link struct {
+		// URL of link.
+		URL string `yaml:"url"`
+		// Title of link.
+		Title string `yaml:"title,omitempty"`
+	}
+
+	// Spec lists the fields of the entity specifications supported by this
+	// package.
+	Spec struct {
+		// Type of component, resource, API or group.
+		Type string `yaml:"type,omitempty"`
+		// Lifecycle of component or API, e.g. "production".
+		Lifecycle string `yaml:"lifecycle,omitempty"`
+		// Owner is a reference to the entity owning the entity.
+		Owner string `yaml:"owner,omitempty"`
+		// System is a reference to the system the entity belongs to.
+		System string `yaml:"system,omitempty"`
+		// Domain is a reference to the domain a system belongs to.
+		Domain string `yaml:"domain,omitempty"`
+		// SubcomponentOf is a reference to the parent component if any.
+		SubcomponentOf string `yaml:"subcomponentOf,omitempty"`
+		// ProvidesAPIs lists references to the APIs provided by a component.
+		ProvidesAPIs []string `yaml:"providesApis,omitempty"`
+		// ConsumesAPIs lists references to the APIs consumed by a component.
+		ConsumesAPIs []string `yaml:"consumesApis,omitempty"`
+		// DependsOn lists references to the components and resources the
+		// entity depends on.
+		DependsOn []string `yaml:"dependsOn,omitempty"`
+		// Definition of API, either inline or as a substitution such as
+		// "$text: ./openapi.yaml".
+		Definition interface{} `yaml:"definition,omitempty"`
+		// Profile of group.
+		Profile *Profile `yaml:"profile,omitempty"`
+	}
+
+	// Profile contains the display information of a group.
+	Profile struct {
+		// DisplayName of group.
+		DisplayName string `yaml:"displayName,omitempty"`
+		// Email of group.
+		Email string `yaml:"email,omitempty"`
+	}
+)
+
+// APIVersion is the version of the entity specification used to render
+// catalogs.
+const APIVersion = "backstage.io/v1alpha1"
+
+// Load reads the entities defined in the file at the given path or in all
+// the files named catalog-info.yaml (or catalog-info.yml) found under the
+// given directory.
+func Load(path string) ([]*Entity, error) {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !fi.IsDir() {
+		return readFile(path)
+	}
+	var res []*Entity
+	err = filepath.Walk(path, func(p string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if fi.IsDir() {
+			return nil
+		}
+		if n := fi.Name(); n != "catalog-info.yaml" && n != "catalog-info.yml" {
+			return nil
+		}
+		es, err := readFile(p)
+		if err != nil {
+			return err
+		}
+		res = append(res, es...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(res) == 0 {
+		return nil, fmt.Errorf("no catalog-info.yaml file found in %q", path)
+	}
+	return res, nil
+}
+
+// Parse reads the entities defined in the given YAML content. The content
+// may contain multiple documents.
+func Parse(b []byte) ([]*Entity, error) {
+	var res []*Entity
+	dec := yaml.NewDecoder(bytes.NewReader(b))
+	for {
+		var e Entity
+		if err := dec.Decode(&e); err != nil {
+			if errors.Is(err, io.EOF) {
+				return res, nil
+			}
+			return nil, err
+		}
+		if e.Kind == "" {
+			continue // empty document
+		}
+		if e.Metadata == nil || e.Metadata.Name == "" {
+			return nil, fmt.Errorf("%s entity is missing metadata.name", e.Kind)
+		}
+		if e.Spec == nil {
+			e.Spec = &Spec{}
+		}
+		res = append(res, &e)
+	}
+}
+
+// Ref returns the reference to the entity, e.g. "component:default/orders".
+func (e *Entity) Ref() string {
+	ns := e.Metadata.Namespace
+	if ns == "" {
+		ns = "default"
+	}
+	return strings.ToLower(e.Kind + ":" + ns + "/" + e.Metadata.Name)
+}
+
+// readFile reads the entities defined in the file at the given path.
+func readFile(path string) ([]*Entity, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	es, err := Parse(b)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s", path, err)
+	}
+	return es, nil
+}
+
+// normalize returns the full form of the entity reference ref. kind is the
+// kind used when ref does not specify one.
+func normalize(ref, kind string) string {
+	ref = strings.ToLower(strings.TrimSpace(ref))
+	if !strings.Contains(ref, ":") {
+		ref = strings.ToLower(kind) + ":" + ref
+	}
+	if !strings.Contains(ref, "/") {
+		i := strings.Index(ref, ":")
+		ref = ref[:i+1] + "default/" + ref[i+1:]
+	}
+	return ref
+}
+
+// shortRef returns the shortest form of the full entity reference ref,
+// omitting the kind if it is kind and the namespace if it is the default.
+func shortRef(ref, kind string) string {
+	ref = strings.TrimPrefix(ref, strings.ToLower(kind)+":")
+	if i := strings.Index(ref, ":default/"); i >= 0 {
+		return ref[:i+1] + ref[i+len(":default/"):]
+	}
+	return strings.TrimPrefix(ref, "default/")
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"goa.design/model/docs"
+	"goa.design/model/mdl"
+)
+
+// genDocs generates a static documentation site for the design described in
+// pkg in the out directory. Element positions saved by the graphical editor
+// in dir are used to render the views.
+func genDocs(pkg, out, dir string, debug bool) error {
+	b, err := gen(pkg, debug)
+	if err != nil {
+		return err
+	}
+	var design mdl.Design
+	if err := json.Unmarshal(b, &design); err != nil {
+		return fmt.Errorf("failed to load design: %s", err.Error())
+	}
+	layouts, err := readLayouts(dir)
+	if err != nil {
+		return err
+	}
+	for path, content := range docs.Render(&design, layouts) {
+		path = filepath.Join(out, filepath.FromSlash(path))
+		if err := os.MkdirAll(filepath.Dir(path), 0777); err != nil {
+			return err
+		}
+		if err := ioutil.WriteFile(path, content, 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"goa.design/model/archimate"
+	"goa.design/model/backstage"
+	"goa.design/model/dot"
+	"goa.design/model/drawio"
+	"goa.design/model/graph"
+	"goa.design/model/inventory"
+	"goa.design/model/mdl"
+	"goa.design/model/mermaid"
+	"goa.design/model/plantuml"
+)
+
+// export writes the design serialized in b using the given format. out is the
+// path to the output file for the JSON format and the path to the output
+// directory for the other formats. The drawio and archimate formats use the
+// element positions saved by the graphical editor in the output directory if
+// any.
+func export(b []byte, format, out string) error {
+	if format == "json" {
+		return ioutil.WriteFile(out, b, 0644)
+	}
+	var design mdl.Design
+	if err := json.Unmarshal(b, &design); err != nil {
+		return fmt.Errorf("failed to load design: %s", err.Error())
+	}
+	var (
+		files map[string]string
+		ext   string
+	)
+	switch format {
+	case "plantuml":
+		files, ext = plantuml.Render(&design), ".puml"
+	case "dot":
+		files, ext = dot.Render(&design), ".dot"
+	case "drawio":
+		layouts, err := readLayouts(out)
+		if err != nil {
+			return err
+		}
+		files, ext = map[string]string{"design": string(drawio.Render(&design, layouts))}, ".drawio"
+	case "archimate":
+		layouts, err := readLayouts(out)
+		if err != nil {
+			return err
+		}
+		b, err := archimate.Render(&design, layouts)
+		if err != nil {
+			return err
+		}
+		files, ext = map[string]string{"design": string(b)}, ".xml"
+	case "graphml":
+		b, err := graph.GraphML(&design)
+		if err != nil {
+			return err
+		}
+		files, ext = map[string]string{"design": string(b)}, ".graphml"
+	case "cytoscape":
+		b, err := graph.Cytoscape(&design)
+		if err != nil {
+			return err
+		}
+		files, ext = map[string]string{"design": string(b)}, ".cyjs"
+	case "csv":
+		files, ext = make(map[string]string), ".csv"
+		for name, content := range inventory.CSV(&design) {
+			files[name] = string(content)
+		}
+	case "xlsx":
+		b, err := inventory.XLSX(&design)
+		if err != nil {
+			return err
+		}
+		files, ext = map[string]string{"design": string(b)}, ".xlsx"
+	case "mermaid":
+		files, ext = mermaid.Render(&design), ".mmd"
+	case "backstage":
+		b, err := backstage.Catalog(&design)
+		if err != nil {
+			return err
+		}
+		files, ext = map[string]string{"catalog-info": string(b)}, ".yaml"
+	default:
+		return fmt.Errorf("unknown format %q", format)
+	}
+	if err := os.MkdirAll(out, 0777); err != nil {
+		return err
+	}
+	for key, content := range files {
+		if err := ioutil.WriteFile(filepath.Join(out, key+ext), []byte(content), 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"goa.design/model/backstage"
+	"goa.design/model/compose"
+	"goa.design/model/dslgen"
+	"goa.design/model/k8s"
+	"goa.design/model/mdl"
+	"goa.design/model/plantuml"
+	"goa.design/model/terraform"
+)
+
+// importDesign generates the Go DSL describing the design read from path
+// using the given format and writes it to the file model.go in the directory
+// out. The name of the Go package is the name of the directory. env is the
+// name of the deployment environment for the formats that describe
+// deployments.
+func importDesign(path, format, env, out string) error {
+	var design *mdl.Design
+	switch format {
+	case "backstage":
+		entities, err := backstage.Load(path)
+		if err != nil {
+			return err
+		}
+		if design, err = backstage.Design("Software Catalog", entities); err != nil {
+			return err
+		}
+	case "kubernetes":
+		objs, err := k8s.Load(path)
+		if err != nil {
+			return err
+		}
+		if env == "" {
+			env = environment(path)
+		}
+		if design, err = k8s.Design(env, env, objs); err != nil {
+			return err
+		}
+	case "compose":
+		p, err := compose.Load(path)
+		if err != nil {
+			return err
+		}
+		if env == "" {
+			env = "Development"
+		}
+		if design, err = compose.Design(p.Name, env, p); err != nil {
+			return err
+		}
+	case "plantuml":
+		var err error
+		if design, err = plantuml.Load(path); err != nil {
+			return err
+		}
+	case "terraform":
+		s, err := terraform.Load(path)
+		if err != nil {
+			return err
+		}
+		if env == "" {
+			env = "Production"
+		}
+		design = terraform.Design("Infrastructure", env, s)
+	default:
+		return fmt.Errorf("unknown format %q", format)
+	}
+
+	return writeDSL(design, out)
+}
+
+// writeDSL generates the Go DSL describing design and writes it to the file
+// model.go in the directory out. The name of the Go package is the name of
+// the directory.
+func writeDSL(design *mdl.Design, out string) error {
+	return writeDSLFile(design, out, "model.go")
+}
+
+// writeDSLFile generates the Go DSL describing design and writes it to the
+// file with the given name in the directory out. The name of the Go package
+// is the name of the directory.
+func writeDSLFile(design *mdl.Design, out, file string) error {
+	src, err := dslgen.Generate(design, dslgen.PackageName(out))
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(out, 0777); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filepath.Join(out, file), src, 0644)
+}
+
+// environment returns the name of the deployment environment described by
+// the manifests at path: the capitalized name of the directory.
+func environment(path string) string {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		abs = path
+	}
+	if fi, err := os.Stat(abs); err == nil && !fi.IsDir() {
+		abs = filepath.Dir(abs)
+	}
+	name := filepath.Base(abs)
+	return strings.ToUpper(name[:1]) + name[1:]
+}
@@ -6,7 +6,6 @@ import (
 	"encoding/json"
 	"flag"
 	"fmt"
-	"io/ioutil"
 	"os"
 	"path/filepath"
 	"strings"
@@ -22,15 +21,39 @@ func main() {
 		debug, help, h *bool
 
 		genset = flag.NewFlagSet("gen", flag.ExitOnError)
-		out    = genset.String("out", "design.json", "set path to generated JSON representation")
+		out    = genset.String("out", "design.json", "set path to generated JSON representation or output directory for other formats")
+		format = genset.String("format", "json", "set output format (json, plantuml, mermaid, dot, drawio, archimate, graphml, cytoscape, csv, xlsx or backstage)")
 
 		svrset = flag.NewFlagSet("serve", flag.ExitOnError)
 		dir    = svrset.String("dir", codegen.Gendir, "set output directory used by editor to save SVG files")
 		port   = svrset.Int("port", 8080, "set local HTTP port used to serve diagram editor")
 
+		rdrset = flag.NewFlagSet("render", flag.ExitOnError)
+		rdir   = rdrset.String("dir", codegen.Gendir, "set output directory used to write SVG files")
+
+		docset = flag.NewFlagSet("docs", flag.ExitOnError)
+		dout   = docset.String("out", "site", "set output directory of generated documentation site")
+		ddir   = docset.String("dir", codegen.Gendir, "set directory containing the SVG files saved by the editor")
+
+		impset  = flag.NewFlagSet("import", flag.ExitOnError)
+		iout    = impset.String("out", "design", "set output directory of generated Go DSL")
+		iformat = impset.String("format", "backstage", "set input format (backstage, kubernetes, compose, terraform or plantuml)")
+		ienv    = impset.String("env", "", "set name of generated deployment environment (kubernetes, compose and terraform formats), defaults to the directory name for kubernetes, to Development for compose and to Production for terraform")
+
+		scnset     = flag.NewFlagSet("scan", flag.ExitOnError)
+		sout       = scnset.String("out", "design", "set output directory of generated Go DSL")
+		scontainer = scnset.String("container", "", "set path to container of generated components (\"<Software System>/<Container>\"), defaults to the name of the first package")
+		sdesign    = scnset.String("design", "", "set import path to Go package containing design to merge the components into")
+		sexclude   = scnset.String("exclude", "", "set regular expression matching the import path of packages to ignore")
+
+		trcset  = flag.NewFlagSet("trace", flag.ExitOnError)
+		tout    = trcset.String("out", "design", "set output directory of generated Go DSL")
+		tdesign = trcset.String("design", "", "set import path to Go package containing design to add the dynamic views to")
+		tmap    = trcset.String("map", "", "set path to YAML file mapping service names to element paths (\"<Software System>/<Container>\"), services are mapped to elements with the same name by default")
+
 		devmode = os.Getenv("DEVMODE") == "1"
 
-		showUsage = func() { printUsage(svrset, genset, gset) }
+		showUsage = func() { printUsage(svrset, genset, rdrset, docset, impset, scnset, trcset, gset) }
 	)
 
 	addGlobals := func(set *flag.FlagSet) {
@@ -40,23 +63,27 @@ func main() {
 	}
 
 	var (
-		cmd string
-		pkg string
-		idx = 1
+		cmd  string
+		pkg  string
+		args []string
+		idx  = 1
 	)
 	for _, arg := range os.Args[1:] {
 		if strings.HasPrefix(arg, "-") {
 			break
 		} else if cmd == "" {
 			cmd = arg
-		} else if pkg == "" {
-			pkg = arg
 		} else {
-			addGlobals(gset)
-			showUsage()
+			args = append(args, arg)
 		}
 		idx++
 	}
+	if len(args) > 0 {
+		pkg = args[0]
+	}
+	if len(args) > 1 && cmd != "scan" {
+		fail(`too many arguments, use "--help" for usage`)
+	}
 
 	switch cmd {
 	case "gen":
@@ -65,6 +92,21 @@ func main() {
 	case "serve":
 		addGlobals(svrset)
 		svrset.Parse(os.Args[idx:])
+	case "render":
+		addGlobals(rdrset)
+		rdrset.Parse(os.Args[idx:])
+	case "docs":
+		addGlobals(docset)
+		docset.Parse(os.Args[idx:])
+	case "import":
+		addGlobals(impset)
+		impset.Parse(os.Args[idx:])
+	case "scan":
+		addGlobals(scnset)
+		scnset.Parse(os.Args[idx:])
+	case "trace":
+		addGlobals(trcset)
+		trcset.Parse(os.Args[idx:])
 	default:
 		addGlobals(gset)
 		gset.Parse(os.Args[idx:])
@@ -84,7 +126,10 @@ func main() {
 		var b []byte
 		b, err = gen(pkg, *debug)
 		if err == nil {
-			err = ioutil.WriteFile(*out, b, 0644)
+			if *format != "json" && *out == "design.json" {
+				*out = codegen.Gendir
+			}
+			err = export(b, *format, *out)
 		}
 	case "serve":
 		if pkg == "" {
@@ -95,6 +140,31 @@ func main() {
 			fail(err.Error())
 		}
 		err = serve(*dir, pkg, *port, devmode, *debug)
+	case "render":
+		if pkg == "" {
+			fail(`missing PACKAGE argument, use "--help" for usage`)
+		}
+		err = render(pkg, *rdir, *debug)
+	case "docs":
+		if pkg == "" {
+			fail(`missing PACKAGE argument, use "--help" for usage`)
+		}
+		err = genDocs(pkg, *dout, *ddir, *debug)
+	case "import":
+		if pkg == "" {
+			fail(`missing SOURCE argument, use "--help" for usage`)
+		}
+		err = importDesign(pkg, *iformat, *ienv, *iout)
+	case "scan":
+		if pkg == "" {
+			fail(`missing PATTERN argument, use "--help" for usage`)
+		}
+		err = scanDesign(args, *scontainer, *sdesign, *sexclude, *sout, *debug)
+	case "trace":
+		if pkg == "" {
+			fail(`missing TRACES argument, use "--help" for usage`)
+		}
+		err = traceDesign(pkg, *tdesign, *tmap, *tout, *debug)
 	case "version":
 		fmt.Printf("%s %s\n", os.Args[0], model.Version())
 	case "", "help":
@@ -145,7 +215,17 @@ func printUsage(fss ...*flag.FlagSet) {
 	fmt.Fprintf(os.Stderr, "  %s serve PACKAGE [FLAGS].\n", os.Args[0])
 	fmt.Fprintf(os.Stderr, "    Start a HTTP server that serves a graphical editor for the design described in PACKAGE.\n")
 	fmt.Fprintf(os.Stderr, "  %s gen PACKAGE [FLAGS].\n", os.Args[0])
-	fmt.Fprintf(os.Stderr, "    Generate a JSON representation of the design described in PACKAGE.\n")
+	fmt.Fprintf(os.Stderr, "    Generate a JSON representation or diagram sources of the design described in PACKAGE.\n")
+	fmt.Fprintf(os.Stderr, "  %s render PACKAGE [FLAGS].\n", os.Args[0])
+	fmt.Fprintf(os.Stderr, "    Render the views of the design described in PACKAGE as SVG files without using a browser.\n")
+	fmt.Fprintf(os.Stderr, "  %s docs PACKAGE [FLAGS].\n", os.Args[0])
+	fmt.Fprintf(os.Stderr, "    Generate a static HTML documentation site for the design described in PACKAGE.\n")
+	fmt.Fprintf(os.Stderr, "  %s import SOURCE [FLAGS].\n", os.Args[0])
+	fmt.Fprintf(os.Stderr, "    Generate the Go DSL describing the design read from the file or directory SOURCE.\n")
+	fmt.Fprintf(os.Stderr, "  %s scan PATTERN... [FLAGS].\n", os.Args[0])
+	fmt.Fprintf(os.Stderr, "    Generate the Go DSL describing the components derived from the Go packages matching the PATTERNs.\n")
+	fmt.Fprintf(os.Stderr, "  %s trace TRACES [FLAGS].\n", os.Args[0])
+	fmt.Fprintf(os.Stderr, "    Add dynamic views built from the OpenTelemetry traces in the OTLP JSON file TRACES to a design.\n")
 	fmt.Fprintf(os.Stderr, "\nPACKAGE must be the import path to a Go package containing Model DSL.\n\n")
 	fmt.Fprintf(os.Stderr, "FLAGS:\n")
 	for _, fs := range fss {
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"goa.design/model/mdl"
+	"goa.design/model/svg"
+)
+
+// render renders the views of the design described in pkg as SVG files in
+// dir. Element positions saved in existing SVG files are preserved.
+func render(pkg, dir string, debug bool) error {
+	b, err := gen(pkg, debug)
+	if err != nil {
+		return err
+	}
+	var design mdl.Design
+	if err := json.Unmarshal(b, &design); err != nil {
+		return fmt.Errorf("failed to load design: %s", err.Error())
+	}
+	if err := os.MkdirAll(dir, 0777); err != nil {
+		return err
+	}
+	layouts, err := readLayouts(dir)
+	if err != nil {
+		return err
+	}
+	for key, content := range svg.Render(&design, layouts) {
+		if err := ioutil.WriteFile(filepath.Join(dir, key+".svg"), content, 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readLayouts reads the element positions saved in the SVG files located in
+// dir. It returns nil if dir does not exist.
+func readLayouts(dir string) (map[string]svg.Layout, error) {
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		return nil, nil
+	}
+	b, err := loadLayouts(dir)
+	if err != nil {
+		return nil, err
+	}
+	var raw map[string]map[string]json.RawMessage
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return nil, fmt.Errorf("failed to load layouts: %s", err.Error())
+	}
+	layouts := make(map[string]svg.Layout, len(raw))
+	for key, l := range raw {
+		layout := make(svg.Layout)
+		for id, v := range l {
+			// Skip relationship vertices, they are indexed by "e-" followed by
+			// the relationship ID and contain a list of points.
+			var pos svg.Position
+			if err := json.Unmarshal(v, &pos); err == nil {
+				layout[id] = &pos
+			}
+		}
+		layouts[key] = layout
+	}
+	return layouts, nil
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"goa.design/model/dslgen"
+	"goa.design/model/mdl"
+	"goa.design/model/scan"
+	"golang.org/x/tools/go/packages"
+)
+
+// scanDesign generates the Go DSL describing the components of a container
+// derived from the Go packages matching patterns and writes it to the
+// directory out. container is the path to the container ("<Software
+// System>/<Container>"), it defaults to the name of the first package.
+// Packages whose import path matches the regular expression exclude are
+// ignored.
+//
+// If design is empty then scanDesign writes a complete design to the file
+// model.go. Otherwise design is the import path of the Go package containing
+// the design the components are merged into and scanDesign writes the file
+// components.go which only defines the components (see scan.Extension). The
+// file is evaluated together with the design: the generated package imports
+// the design package unless out is the design package directory in which case
+// the components.go file written by a previous run is replaced.
+func scanDesign(patterns []string, container, design, exclude, out string, debug bool) error {
+	var re *regexp.Regexp
+	if exclude != "" {
+		var err error
+		if re, err = regexp.Compile(exclude); err != nil {
+			return fmt.Errorf("invalid exclude regular expression: %s", err)
+		}
+	}
+	pkgs, err := scan.Load("", re, patterns...)
+	if err != nil {
+		return err
+	}
+	if container == "" {
+		container = path.Base(pkgs[0].Path)
+	}
+	system := container
+	if i := strings.Index(container, "/"); i >= 0 {
+		system, container = container[:i], container[i+1:]
+	}
+
+	if design == "" {
+		return writeDSL(scan.Design(system, container, pkgs), out)
+	}
+	same, err := isPackageDir(design, out)
+	if err != nil {
+		return err
+	}
+	if same {
+		// Do not merge the components generated by a previous run.
+		if err := os.Remove(filepath.Join(out, "components.go")); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	b, err := gen(design, debug)
+	if err != nil {
+		return err
+	}
+	var d mdl.Design
+	if err := json.Unmarshal(b, &d); err != nil {
+		return fmt.Errorf("failed to load design: %s", err.Error())
+	}
+	if d.Views == nil {
+		d.Views = &mdl.Views{}
+	}
+	if err := writeDSLFile(scan.Extension(&d, system, container, pkgs), out, "components.go"); err != nil {
+		return err
+	}
+	if same {
+		return nil
+	}
+	src := fmt.Sprintf("package %s\n\n// The components defined in components.go extend this design.\nimport _ %q\n", dslgen.PackageName(out), design)
+	return ioutil.WriteFile(filepath.Join(out, "extends.go"), []byte(src), 0644)
+}
+
+// isPackageDir returns true if dir is the directory of the Go package with
+// the given import path.
+func isPackageDir(pkg, dir string) (bool, error) {
+	pkgs, err := packages.Load(&packages.Config{Mode: packages.NeedFiles}, pkg)
+	if err != nil {
+		return false, err
+	}
+	if len(pkgs) == 0 || len(pkgs[0].GoFiles) == 0 {
+		return false, nil
+	}
+	fi, err := os.Stat(dir)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	pfi, err := os.Stat(filepath.Dir(pkgs[0].GoFiles[0]))
+	if err != nil {
+		return false, err
+	}
+	return os.SameFile(fi, pfi), nil
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"goa.design/model/mdl"
+	"goa.design/model/otel"
+	"gopkg.in/yaml.v3"
+)
+
+// traceDesign adds the dynamic views built from the OpenTelemetry traces read
+// from path to the design defined in the Go package with import path design
+// and writes the resulting Go DSL to the file model.go in the directory out.
+// mapping is the path to a YAML file that maps service names to element
+// paths, it may be empty.
+func traceDesign(path, design, mapping, out string, debug bool) error {
+	if design == "" {
+		return fmt.Errorf(`missing design package, use "-design"`)
+	}
+	m := make(map[string]string)
+	if mapping != "" {
+		b, err := ioutil.ReadFile(mapping)
+		if err != nil {
+			return err
+		}
+		if err := yaml.Unmarshal(b, &m); err != nil {
+			return fmt.Errorf("%s: %s", mapping, err)
+		}
+	}
+	traces, err := otel.Load(path)
+	if err != nil {
+		return err
+	}
+	b, err := gen(design, debug)
+	if err != nil {
+		return err
+	}
+	var d mdl.Design
+	if err := json.Unmarshal(b, &d); err != nil {
+		return fmt.Errorf("failed to load design: %s", err.Error())
+	}
+	unmapped, err := otel.Merge(&d, traces, m)
+	if len(unmapped) > 0 {
+		fmt.Fprintf(os.Stderr, "ignoring spans of services not mapped to elements: %s\n", strings.Join(unmapped, ", "))
+	}
+	if err != nil {
+		return err
+	}
+	return writeDSL(&d, out)
+}
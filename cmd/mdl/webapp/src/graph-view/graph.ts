@@ -61,6 +61,7 @@ interface Node extends BBox {
 	title: string;
 	sub: string;
 	description: string;
+	tooltip?: string;
 
 	ref?: SVGGElement;
 	selected?: boolean;
@@ -139,6 +140,7 @@ const defaultNodeStyle: NodeStyle = {
 interface Edge {
 	id: string;
 	label: string;
+	tooltip?: string;
 	from: Node;
 	to: Node;
 	vertices?: EdgeVertex[];
@@ -202,10 +204,10 @@ export class GraphData {
 		}
 	}
 
-	addNode(id: string, label: string, sub: string, description: string, style: NodeStyle) {
+	addNode(id: string, label: string, sub: string, description: string, style: NodeStyle, tooltip = '') {
 		if (this.nodesMap.has(id)) throw Error('duplicate node: ' + id)
 		const n: Node = {
-			id, title: label, sub, description, style: {...defaultNodeStyle, ...style},
+			id, title: label, sub, description, tooltip, style: {...defaultNodeStyle, ...style},
 			x: 0, y: 0, width: style.width, height: style.height, intersect: null
 		}
 		// console.log(label, id, style, {...defaultNodeStyle, ...style})
@@ -216,7 +218,7 @@ export class GraphData {
 		return Array.from(this.nodesMap.values())
 	}
 
-	addEdge(id: string, fromNode: string, toNode: string, label: string, vertices: Point[], style: EdgeStyle) {
+	addEdge(id: string, fromNode: string, toNode: string, label: string, vertices: Point[], style: EdgeStyle, tooltip = '') {
 		vertices && vertices.forEach((p, i) => {
 			const v = p as EdgeVertex
 			v.id = `v-${id}-${i}`
@@ -237,6 +239,7 @@ export class GraphData {
 			from: this.nodesMap.get(fromNode),
 			to: this.nodesMap.get(toNode),
 			label,
+			tooltip,
 			vertices: null as EdgeVertex[],
 			style: {...defaultEdgeStyle, ...style},
 			initVertex
@@ -578,6 +581,7 @@ function buildEdge(data: GraphData, edge: Edge) {
 	g.setAttribute('id', edge.id)
 	g.setAttribute('data-from', edge.from.id)
 	g.setAttribute('data-to', edge.to.id)
+	edge.tooltip && g.append(create.title(edge.tooltip))
 
 	const position = (edge.style.position || 50) / 100
 
@@ -725,6 +729,7 @@ function buildNode(n: Node, data: GraphData) {
 
 	const g = create.element('g', {}, 'node') as SVGGElement
 	g.setAttribute('id', n.id)
+	n.tooltip && g.append(create.title(n.tooltip))
 	n.selected && g.classList.add('selected')
 	setPosition(g, n.x, n.y)
 
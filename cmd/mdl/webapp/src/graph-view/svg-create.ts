@@ -27,6 +27,13 @@ export const create = {
 		return t
 	},
 
+	// title creates a tooltip shown by the browser when hovering the parent
+	title(text: string) {
+		const t = create.element('title')
+		t.append(text)
+		return t
+	},
+
 	textArea(text: string, width: number, fontSize:number, bold: boolean, x=0, y=0, anchor='') {
 		const attrs: {[key: string]: string} = {
 			'font-size': `${fontSize}px`,
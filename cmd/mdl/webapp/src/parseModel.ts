@@ -17,6 +17,7 @@ interface Model {
 		systemLandscapeViews: View[]
 		containerViews: View[]
 		componentViews: View[]
+		codeViews: View[]
 		dynamicViews: View[]
 		deploymentViews: View[]
 		styles: {
@@ -44,10 +45,15 @@ interface Element {
 	parent?: Element;
 	tags?: string;
 	location?: string;
+	group?: string;
+	owner?: string;
+	url?: string;
 	containers?: Element[];
 	components?: Element[];
+	codeElements?: Element[];
 	relationships?: Relation[];
 	properties?: { [key: string]: string }
+	perspectives?: Perspective[];
 	children?: Element[];
 	infrastructureNodes?: Element[];
 }
@@ -60,6 +66,14 @@ interface Relation {
 	destinationId: string;
 	technology: string;
 	interactionStyle: string;
+	url?: string;
+	properties?: { [key: string]: string };
+	perspectives?: Perspective[];
+}
+
+interface Perspective {
+	name: string;
+	description: string;
 }
 
 interface View {
@@ -75,6 +89,7 @@ interface View {
 		routing: string; // takes priority over style
 	}[];
 	softwareSystemId: string;
+	componentId?: string;
 }
 
 interface Metadata {
@@ -86,6 +101,7 @@ interface Metadata {
 		tags?: string;
 		location?: string;
 		properties?: { [key: string]: string };
+		perspectives?: Perspective[];
 		elementViewKey?: string;
 	}[]
 }
@@ -133,6 +149,13 @@ export const parseView = (model: Model, layouts: Layouts, viewKey: string) => {
 						el2.parent = el1;
 						elements.set(el2.id, el2)
 						collectRels(el2)
+						if (Array.isArray(el2.codeElements)) {
+							el2.codeElements.forEach((el3: Element) => {
+								el3.parent = el2;
+								elements.set(el3.id, el3)
+								collectRels(el3)
+							})
+						}
 					})
 				}
 			})
@@ -186,6 +209,9 @@ export const parseView = (model: Model, layouts: Layouts, viewKey: string) => {
 		//don't show grouping if the element is listed in the view
 		if (!view.elements.find(ref => ref.id == view.softwareSystemId))
 			groupingIDs[view.softwareSystemId] = true
+	} else if (view.componentId) {
+		if (!view.elements.find(ref => ref.id == view.componentId))
+			groupingIDs[view.componentId] = true
 	} else if (section == 'systemLandscapeViews') {
 		// create a virtual parent element from enterprise
 		const p: Element = {id: '__enterprise__', ...model.model.enterprise}
@@ -224,13 +250,15 @@ export const parseView = (model: Model, layouts: Layouts, viewKey: string) => {
 			el ? (el.name || ref.id) : ref.id,
 			sub,
 			(el && el.description) ? el.description : '',
-			style
+			style,
+			el ? tooltip(el) : ''
 		)
 		el && metadata.elements.push({
 			id: el.id,
 			tags: el.tags,
 			location: el.location,
 			properties: el.properties,
+			perspectives: el.perspectives,
 			elementViewKey: lookupElementKeyView(model, el.id)
 		})
 	})
@@ -265,10 +293,26 @@ export const parseView = (model: Model, layouts: Layouts, viewKey: string) => {
 			})
 			if (ref.routing) style.routing = ref.routing
 
-			graph.addEdge(rel.id, rel.sourceId, rel.destinationId, rel.description, ref.vertices, style)
+			graph.addEdge(rel.id, rel.sourceId, rel.destinationId, rel.description, ref.vertices, style, tooltip(rel))
 		})
 	}
 
+	//element groups - elements of the view that belong to the same group and
+	//have the same parent are rendered inside a dashed boundary
+	const elementGroups = new Map<string, { name: string, ids: string[] }>()
+	const elementGroupID = (el: Element) =>
+		el && el.group ? `group:${el.parent ? el.parent.id : ''}/${el.group}` : null
+	view.elements.forEach(ref => {
+		// container instances are copies of the containers, ignore their group
+		if (section == 'deploymentViews' || groupingIDs[ref.id] || !graph.nodesMap.has(ref.id)) return
+		const el = elements.get(ref.id)
+		const gid = elementGroupID(el)
+		if (!gid) return
+		if (!elementGroups.has(gid)) elementGroups.set(gid, {name: el.group, ids: []})
+		elementGroups.get(gid).ids.push(el.id)
+	})
+	elementGroups.forEach((g, gid) => graph.addGroup(gid, g.name, g.ids, {}))
+
 	//groups
 	//sort by depth to solve dependency
 	const level = (el: Element) => {
@@ -290,13 +334,14 @@ export const parseView = (model: Model, layouts: Layouts, viewKey: string) => {
 				s && (style = {...style, ...s})
 			})
 		}
+		const ids = view.elements
+			.map(ref => elements.get(ref.id))
+			.filter(el => el && el.parent == parent)
+			.map(el => elementGroups.has(elementGroupID(el)) ? elementGroupID(el) : el.id)
 		graph.addGroup(
 			parent.id,
 			parent.name,
-			view.elements
-				.map(ref => elements.get(ref.id))
-				.filter(el => el && el.parent == parent)
-				.map(el => el.id),
+			ids.filter((id, i) => ids.indexOf(id) == i),
 			style
 		)
 	})
@@ -321,6 +366,25 @@ function getView(model: Model, viewKey: string) {
 	return {view, section}
 }
 
+// tooltip returns the text shown when hovering an element or a relationship:
+// its owning team, URL, properties and perspectives, one per line.
+function tooltip(item: Element | Relation) {
+	const lines: string[] = []
+	const team = 'sourceId' in item ? undefined : owner(item)
+	team && lines.push(`Owner: ${team}`)
+	item.url && lines.push(item.url)
+	item.properties && Object.keys(item.properties).sort().forEach(k => lines.push(`${k}: ${item.properties[k]}`))
+	item.perspectives && item.perspectives.forEach(p => lines.push(`[${p.name}] ${p.description}`))
+	return lines.join('\n')
+}
+
+// owner returns the name of the team that owns the element or its closest ancestor
+function owner(el: Element) {
+	for (; el; el = el.parent) {
+		if (el.owner) return el.owner
+	}
+	return undefined
+}
 
 function lookupElementKeyView(model: any, softwareSystemId: string) {
 	let key: string = undefined
@@ -11,6 +11,7 @@ import (
 	"strings"
 
 	"goa.design/goa/v3/codegen"
+	"goa.design/model/dslgen"
 	model "goa.design/model/pkg"
 	"goa.design/model/stz"
 	"golang.org/x/tools/go/packages"
@@ -19,7 +20,8 @@ import (
 func main() {
 	var (
 		fs     = flag.NewFlagSet("flags", flag.ContinueOnError)
-		out    = fs.String("out", "model.json", "Write output to given file path [use with 'stz get' or 'gen'].")
+		out    = fs.String("out", "model.json", "Write output to given file path [use with 'stz get' or 'gen'] or directory [use with 'stz import'].")
+		format = fs.String("format", "json", "Output format, one of 'json' or 'dsl' [use with 'stz gen'].")
 		wid    = fs.String("id", "", "Structurizr workspace ID [only needed for 'stz' command]")
 		key    = fs.String("key", "", "Structurizr API key [only needed for 'stz' command]")
 		secret = fs.String("secret", "", "Structurizr API secret [only needed for 'stz' command]")
@@ -36,7 +38,7 @@ func main() {
 		switch cmd {
 		case "":
 			cmd = arg
-		case "gen", "get", "put":
+		case "gen", "get", "put", "import":
 			if !strings.HasPrefix(arg, "-") {
 				path = arg
 				idx++
@@ -63,11 +65,27 @@ done:
 			err = fmt.Errorf("missing Go import package path")
 			break
 		}
-		err = gen(path, *out, *debug)
+		switch *format {
+		case "json":
+		case "dsl":
+			if *out == "model.json" {
+				*out = "workspace.dsl"
+			}
+		default:
+			err = fmt.Errorf("unknown format %q", *format)
+		}
+		if err == nil {
+			err = gen(path, *out, *format, *debug)
+		}
 	case "get":
 		err = get(pathOrDefault(*out), *wid, *key, *secret, *debug)
 	case "put":
 		err = put(pathOrDefault(path), *wid, *key, *secret, *debug)
+	case "import":
+		if *out == "model.json" {
+			*out = "design"
+		}
+		err = importWorkspace(pathOrDefault(path), *out)
 	case "version":
 		fmt.Printf("%s %s\n", os.Args[0], model.Version())
 	case "help":
@@ -81,7 +99,7 @@ done:
 	}
 }
 
-func gen(pkg string, out string, debug bool) error {
+func gen(pkg, out, format string, debug bool) error {
 	// Validate package import path
 	if _, err := packages.Load(&packages.Config{Mode: packages.NeedName}, pkg); err != nil {
 		return err
@@ -134,7 +152,7 @@ func gen(pkg string, out string, debug bool) error {
 
 	// Run program
 	out, _ = filepath.Abs(out)
-	o, err := runCmd(filepath.Join(tmpDir, "stz"), tmpDir, "-out", out)
+	o, err := runCmd(filepath.Join(tmpDir, "stz"), tmpDir, "-out", out, format)
 	if debug {
 		fmt.Fprintln(os.Stderr, o)
 	}
@@ -210,6 +228,33 @@ func put(path, wid, key, secret string, debug bool) error {
 	return c.Put(wid, local)
 }
 
+func importWorkspace(path, out string) error {
+	var w *stz.Workspace
+	if filepath.Ext(path) == ".dsl" {
+		var err error
+		if w, err = stz.ParseDSL(path); err != nil {
+			return err
+		}
+	} else {
+		b, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		w = &stz.Workspace{}
+		if err := json.Unmarshal(b, w); err != nil {
+			return err
+		}
+	}
+	src, err := w.GoDSL(dslgen.PackageName(out))
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(out, 0777); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filepath.Join(out, "model.go"), src, 0644)
+}
+
 func fail(format string, args ...interface{}) {
 	fmt.Fprintf(os.Stderr, format+"\n", args...)
 	os.Exit(1)
@@ -217,14 +262,15 @@ func fail(format string, args ...interface{}) {
 
 func showUsage(fs *flag.FlagSet) {
 	fmt.Fprintln(os.Stderr, "Usage:")
-	fmt.Fprintf(os.Stderr, "%s gen PACKAGE [FLAGS]\t# Generate Structurizr workspace JSON representation from DSL.\n", os.Args[0])
+	fmt.Fprintf(os.Stderr, "%s gen PACKAGE [FLAGS]\t# Generate Structurizr workspace JSON (or DSL with '-format dsl') representation from DSL.\n", os.Args[0])
 	fmt.Fprintf(os.Stderr, "%s get [FLAGS]\t\t# Download workspace JSON representation from Structurizr service.\n", os.Args[0])
 	fmt.Fprintf(os.Stderr, "%s put FILE FLAGS\t# Upload generated design JSON representation to Structurizr service,\n\t\t\t# merges layout (if a layout file is present) with workspace in Structurizr\n\t\t\t# service and generates or updates the merged layout file.\n", os.Args[0])
+	fmt.Fprintf(os.Stderr, "%s import FILE [FLAGS]\t# Generate Go DSL from a workspace JSON representation or from a\n\t\t\t# Structurizr DSL file (with extension '.dsl'), writes the file model.go in the directory given by '-out' (default 'design').\n", os.Args[0])
 	fmt.Fprintf(os.Stderr, "%s help\t\t# Print this help message.\n", os.Args[0])
 	fmt.Fprintf(os.Stderr, "%s version\t\t# Print the tool version.\n\n", os.Args[0])
 	fmt.Fprintln(os.Stderr, "Where:")
 	fmt.Fprintln(os.Stderr, "\nPACKAGE is the import path to a Go package containing the DSL describing a Structurizr workspace.")
-	fmt.Fprintf(os.Stderr, "FILE is the path to a file previously created via '%s gen'\n", os.Args[0])
+	fmt.Fprintf(os.Stderr, "FILE is the path to a file previously created via '%s gen' or '%s get'\n", os.Args[0], os.Args[0])
 	fmt.Fprintln(os.Stderr, "FLAGS is a sequence of:")
 	fs.PrintDefaults()
 }
@@ -244,8 +290,8 @@ func runCmd(path, dir string, args ...string) (string, error) {
 
 // mainT is the template for the generator main.
 const mainT = `func main() {
-	// Retrieve output path
-	out := os.Args[1]
+	// Retrieve output path and format
+	out, format := os.Args[1], os.Args[2]
 		
     // Run the model DSL
     w, err := stz.RunDSL()
@@ -253,10 +299,13 @@ const mainT = `func main() {
         fmt.Fprint(os.Stderr, err.Error())
         os.Exit(1)
 	}
-	b, err := json.MarshalIndent(w, "", "    ")
-    if err != nil {
-        fmt.Fprintf(os.Stderr, "failed to encode into JSON: %s", err.Error())
-        os.Exit(1)
+	b := []byte(w.DSL())
+	if format == "json" {
+		b, err = json.MarshalIndent(w, "", "    ")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to encode into JSON: %s", err.Error())
+			os.Exit(1)
+		}
 	}
 	if err := ioutil.WriteFile(out, b, 0644); err != nil {
         fmt.Fprintf(os.Stderr, "failed to write file: %s", err.Error())
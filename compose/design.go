@@ -0,0 +1,173 @@
+package compose
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"goa.design/model/mdl"
+)
+
+// urlRegexp matches the HTTP URLs used in health check commands.
+var urlRegexp = regexp.MustCompile(`https?://[^\s'"]+`)
+
+// Design returns the design with the given name that describes the services
+// of the given project deployed in the deployment environment env. It returns
+// an error if a service depends on or links to a service that is not defined
+// in the project.
+func Design(name, env string, p *Project) (*mdl.Design, error) {
+	var seq int
+	newID := func() string {
+		seq++
+		return strconv.Itoa(seq)
+	}
+	var (
+		sys = &mdl.SoftwareSystem{
+			ID:   newID(),
+			Name: p.Name,
+			Tags: "Element,Software System",
+		}
+		host = &mdl.DeploymentNode{
+			ID:          newID(),
+			Name:        "Docker host",
+			Technology:  "Docker",
+			Environment: env,
+			Tags:        "Element,Deployment Node",
+		}
+		containers = make(map[string]*mdl.Container)
+	)
+	for _, s := range p.Services {
+		c := &mdl.Container{
+			ID:         newID(),
+			Name:       s.Name,
+			Technology: s.Image,
+			Tags:       "Element,Container",
+		}
+		sys.Containers = append(sys.Containers, c)
+		containers[s.Name] = c
+		ci := &mdl.ContainerInstance{
+			ID:          newID(),
+			Tags:        "Container Instance",
+			ContainerID: c.ID,
+			InstanceID:  1,
+			Environment: env,
+			Properties:  properties(s),
+		}
+		if hc := healthCheck(s.Healthcheck); hc != nil {
+			ci.HealthChecks = []*mdl.HealthCheck{hc}
+		}
+		host.ContainerInstances = append(host.ContainerInstances, ci)
+	}
+	for _, s := range p.Services {
+		c := containers[s.Name]
+		seen := make(map[string]bool)
+		for _, dep := range append(append([]string{}, s.DependsOn...), s.Links...) {
+			dest, ok := containers[dep]
+			if !ok {
+				return nil, fmt.Errorf("service %q depends on undefined service %q", s.Name, dep)
+			}
+			if dest == c || seen[dep] {
+				continue
+			}
+			seen[dep] = true
+			c.Relationships = append(c.Relationships, &mdl.Relationship{
+				ID:            newID(),
+				Description:   "Uses",
+				Tags:          "Relationship",
+				SourceID:      c.ID,
+				DestinationID: dest.ID,
+			})
+		}
+	}
+
+	m := &mdl.Model{Systems: []*mdl.SoftwareSystem{sys}, DeploymentNodes: []*mdl.DeploymentNode{host}}
+	key := strings.Join(strings.Fields(env), "")
+	cv := &mdl.ContainerView{
+		ViewProps: &mdl.ViewProps{
+			Key:         "Containers",
+			Description: "Services of the " + p.Name + " project.",
+		},
+		SoftwareSystemID: sys.ID,
+	}
+	dv := &mdl.DeploymentView{
+		ViewProps: &mdl.ViewProps{
+			Key:          key + "Deployment",
+			Description:  "Docker deployment of the " + env + " environment.",
+			ElementViews: []*mdl.ElementView{{ID: host.ID}},
+		},
+		SoftwareSystemID: sys.ID,
+		Environment:      env,
+	}
+	for _, c := range sys.Containers {
+		cv.ElementViews = append(cv.ElementViews, &mdl.ElementView{ID: c.ID})
+		for _, r := range c.Relationships {
+			cv.RelationshipViews = append(cv.RelationshipViews, &mdl.RelationshipView{ID: r.ID})
+		}
+	}
+	for _, ci := range host.ContainerInstances {
+		dv.ElementViews = append(dv.ElementViews, &mdl.ElementView{ID: ci.ID})
+	}
+	return &mdl.Design{
+		Name:  name,
+		Model: m,
+		Views: &mdl.Views{
+			ContainerViews:  []*mdl.ContainerView{cv},
+			DeploymentViews: []*mdl.DeploymentView{dv},
+		},
+	}, nil
+}
+
+// properties returns the properties of the container instance corresponding
+// to s.
+func properties(s *Service) map[string]string {
+	props := make(map[string]string)
+	if s.ContainerName != "" {
+		props["container"] = s.ContainerName
+	}
+	if s.Build != nil && s.Build.Context != "" {
+		props["build"] = s.Build.Context
+	}
+	if len(s.Ports) > 0 {
+		props["ports"] = strings.Join(s.Ports, ", ")
+	}
+	if s.Deploy != nil && s.Deploy.Replicas != nil {
+		props["replicas"] = strconv.Itoa(*s.Deploy.Replicas)
+	}
+	if len(props) == 0 {
+		return nil
+	}
+	return props
+}
+
+// healthCheck returns the health check corresponding to hc if its test
+// command uses a HTTP URL, nil otherwise.
+func healthCheck(hc *Healthcheck) *mdl.HealthCheck {
+	if hc == nil || hc.Disable {
+		return nil
+	}
+	url := urlRegexp.FindString(string(hc.Test))
+	if url == "" {
+		return nil
+	}
+	return &mdl.HealthCheck{
+		Name:     "Healthcheck",
+		URL:      url,
+		Interval: int(duration(hc.Interval, 30*time.Second) / time.Second),
+		Timeout:  int(duration(hc.Timeout, 30*time.Second) / time.Millisecond),
+	}
+}
+
+// duration parses the compose duration d, it returns def if d is empty or
+// invalid.
+func duration(d string, def time.Duration) time.Duration {
+	if d == "" {
+		return def
+	}
+	res, err := time.ParseDuration(d)
+	if err != nil {
+		return def
+	}
+	return res
+}
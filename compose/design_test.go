@@ -0,0 +1,151 @@
+package compose
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoad(t *testing.T) {
+	p, err := Load(filepath.Join("testdata", "shop"))
+	if err != nil {
+		t.Fatalf("failed to load project: %s", err)
+	}
+	if p.Name != "shop" {
+		t.Errorf("got project name %q, want %q", p.Name, "shop")
+	}
+	var names []string
+	for _, s := range p.Services {
+		names = append(names, s.Name)
+	}
+	if got := strings.Join(names, ","); got != "web,api,db,cache,worker" {
+		t.Errorf("got services %q, want web,api,db,cache,worker", got)
+	}
+	web := p.Service("web")
+	if got := strings.Join(web.Ports, ","); got != "8080:80,8443:443/tcp" {
+		t.Errorf("got web ports %q, want ports of main and override files", got)
+	}
+	if web.Build == nil || web.Build.Context != "./web" {
+		t.Errorf("got web build %+v, want context ./web", web.Build)
+	}
+	if web.Deploy == nil || web.Deploy.Replicas == nil || *web.Deploy.Replicas != 2 {
+		t.Errorf("got web deploy %+v, want 2 replicas", web.Deploy)
+	}
+	api := p.Service("api")
+	if got := strings.Join(api.DependsOn, ","); got != "db,cache" {
+		t.Errorf("got api depends_on %q, want db,cache (long syntax)", got)
+	}
+	if got := strings.Join(api.Links, ","); got != "db,api" {
+		t.Errorf("got api links %q, want db,api (aliases removed)", got)
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	if _, err := Load("testdata"); err == nil || !strings.Contains(err.Error(), "no compose file found") {
+		t.Errorf("got error %v, want no compose file found error", err)
+	}
+	if _, err := Load(filepath.Join("testdata", "missing.yaml")); err == nil {
+		t.Error("got no error for missing file")
+	}
+}
+
+func TestDesign(t *testing.T) {
+	p, err := Load(filepath.Join("testdata", "shop", "compose.yaml"))
+	if err != nil {
+		t.Fatalf("failed to load project: %s", err)
+	}
+	d, err := Design("Shop", "Development", p)
+	if err != nil {
+		t.Fatalf("failed to build design: %s", err)
+	}
+	if len(d.Model.Systems) != 1 {
+		t.Fatalf("got %d systems, want 1", len(d.Model.Systems))
+	}
+	sys := d.Model.Systems[0]
+	if sys.Name != "shop" || len(sys.Containers) != 4 {
+		t.Fatalf("got system %q with %d containers, want shop with 4", sys.Name, len(sys.Containers))
+	}
+	names := make(map[string]string)
+	for _, c := range sys.Containers {
+		names[c.ID] = c.Name
+	}
+	rels := func(name string) string {
+		for _, c := range sys.Containers {
+			if c.Name != name {
+				continue
+			}
+			var dests []string
+			for _, r := range c.Relationships {
+				dests = append(dests, names[r.DestinationID])
+			}
+			return strings.Join(dests, ",")
+		}
+		return ""
+	}
+	cases := []struct {
+		Name, Tech, Uses string
+	}{
+		{"web", "", "api"},                  // no technology for locally built image
+		{"api", "shop/api:1.2", "db,cache"}, // db link deduplicated, self link ignored
+		{"db", "postgres:15", ""},
+		{"cache", "redis:7", ""},
+	}
+	for i, c := range cases {
+		cont := sys.Containers[i]
+		if cont.Name != c.Name || cont.Technology != c.Tech {
+			t.Errorf("got container %d %q with technology %q, want %q with %q", i, cont.Name, cont.Technology, c.Name, c.Tech)
+		}
+		if got := rels(c.Name); got != c.Uses {
+			t.Errorf("got %s relationships to %q, want %q", c.Name, got, c.Uses)
+		}
+	}
+
+	host := d.Model.DeploymentNodes[0]
+	if host.Name != "Docker host" || host.Environment != "Development" || len(host.ContainerInstances) != 4 {
+		t.Fatalf("got deployment node %q in %q with %d instances", host.Name, host.Environment, len(host.ContainerInstances))
+	}
+	api, db := host.ContainerInstances[1], host.ContainerInstances[2]
+	if len(api.HealthChecks) != 1 {
+		t.Fatalf("got %d api health checks, want 1", len(api.HealthChecks))
+	}
+	if hc := api.HealthChecks[0]; hc.URL != "http://localhost:8000/healthz" || hc.Interval != 10 || hc.Timeout != 2000 {
+		t.Errorf("got health check %+v", hc)
+	}
+	if len(db.HealthChecks) != 0 {
+		t.Errorf("got %d db health checks, want none as the command has no URL", len(db.HealthChecks))
+	}
+	if db.Properties["container"] != "shop-db" {
+		t.Errorf("got db properties %v, want container name", db.Properties)
+	}
+
+	cv := d.Views.ContainerViews[0]
+	if len(cv.ElementViews) != 4 || len(cv.RelationshipViews) != 3 {
+		t.Errorf("got container view with %d elements and %d relationships, want 4 and 3", len(cv.ElementViews), len(cv.RelationshipViews))
+	}
+	dv := d.Views.DeploymentViews[0]
+	if dv.Key != "DevelopmentDeployment" || len(dv.ElementViews) != 5 {
+		t.Errorf("got deployment view %q with %d elements, want DevelopmentDeployment with 5", dv.Key, len(dv.ElementViews))
+	}
+}
+
+func TestDesignUndefinedService(t *testing.T) {
+	cases := []struct {
+		Name    string
+		Service *Service
+	}{
+		{"depends_on", &Service{Name: "api", DependsOn: []string{"db", "metrics"}}},
+		{"links", &Service{Name: "api", Links: []string{"metrics"}}},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			p := &Project{Name: "shop", Services: []*Service{c.Service, {Name: "db"}}}
+			_, err := Design("Shop", "Development", p)
+			if err == nil {
+				t.Fatal("got no error, want error for undefined service")
+			}
+			if want := `service "api" depends on undefined service "metrics"`; err.Error() != want {
+				t.Errorf("got error %q, want %q", err.Error(), want)
+			}
+		})
+	}
+}
@@ -0,0 +1,22 @@
+/*
+Package compose builds the deployment model of a software architecture design
+from docker-compose files.
+
+Load reads the services defined in a compose file (or in the compose files of
+a directory including override files). Design maps the services to a
+deployment environment where each service becomes a container instance inside
+a "Docker host" deployment node:
+
+	Project               -> Software System
+	Service               -> Container and Container Instance
+	depends_on and links  -> Relationships between containers
+	Image                 -> Technology of container (none for services
+	                         that are only built locally)
+	Healthcheck           -> Health check of container instance (when the
+	                         test command contains a HTTP URL)
+
+Design returns an error if depends_on or links refers to a service that is
+not defined, as docker-compose does. The design also contains a container view and a deployment view listing all
+the containers and container instances.
+*/
+package compose
@@ -0,0 +1,276 @@
+package compose
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+type (
+	// Project is a docker-compose project.
+	Project struct {
+		// Name of project.
+		Name string
+		// Services lists the services of the project in order of
+		// definition.
+		Services []*Service
+	}
+
+	// Service is a docker-compose service.
+	Service struct {
+		// Name of service.
+		Name string
+		// Image run by service if any.
+		Image string `yaml:"image"`
+		// Build describes how the image is built if any.
+		Build *Build `yaml:"build"`
+		// ContainerName is the custom name of the container if any.
+		ContainerName string `yaml:"container_name"`
+		// DependsOn lists the names of the services the service depends on.
+		DependsOn Names `yaml:"depends_on"`
+		// Links lists the names of the linked services.
+		Links Names `yaml:"links"`
+		// Ports lists the published ports.
+		Ports Ports `yaml:"ports"`
+		// Healthcheck describes the container health check if any.
+		Healthcheck *Healthcheck `yaml:"healthcheck"`
+		// Deploy describes the deployment configuration if any.
+		Deploy *struct {
+			// Replicas is the number of containers.
+			Replicas *int `yaml:"replicas"`
+		} `yaml:"deploy"`
+	}
+
+	// Build describes how the image of a service is built.
+	Build struct {
+		// Context is the path to the build context.
+		Context string `yaml:"context"`
+		// Dockerfile is the path to the Dockerfile if not the default.
+		Dockerfile string `yaml:"dockerfile"`
+	}
+
+	// Healthcheck describes the health check of a service.
+	Healthcheck struct {
+		// Test is the command run to check the container health.
+		Test Command `yaml:"test"`
+		// Interval between checks, e.g. "30s".
+		Interval string `yaml:"interval"`
+		// Timeout of check, e.g. "10s".
+		Timeout string `yaml:"timeout"`
+		// Disable disables the health check.
+		Disable bool `yaml:"disable"`
+	}
+
+	// Names is a list of service names defined as a sequence or as a mapping
+	// (depends_on long syntax). Link aliases are removed.
+	Names []string
+
+	// Ports is a list of published ports defined using the short or the long
+	// syntax.
+	Ports []string
+
+	// Command is a command defined as a string or as a sequence.
+	Command string
+
+	// file is the content of a compose file.
+	file struct {
+		Name     string    `yaml:"name"`
+		Services yaml.Node `yaml:"services"`
+	}
+)
+
+// fileNames lists the names of the main and override compose files loaded
+// from a directory in order of precedence.
+var fileNames = [][]string{
+	{"compose.yaml", "compose.yml", "docker-compose.yaml", "docker-compose.yml"},
+	{"compose.override.yaml", "compose.override.yml", "docker-compose.override.yaml", "docker-compose.override.yml"},
+}
+
+// Load reads the compose file at the given path or the compose files found in
+// the given directory. Services defined in override files are merged with the
+// services defined in the main file. The name of the project defaults to the
+// name of the directory containing the files.
+func Load(path string) (*Project, error) {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	var paths []string
+	dir := path
+	if fi.IsDir() {
+		for _, names := range fileNames {
+			for _, n := range names {
+				if _, err := os.Stat(filepath.Join(path, n)); err == nil {
+					paths = append(paths, filepath.Join(path, n))
+					break
+				}
+			}
+		}
+		if len(paths) == 0 {
+			return nil, fmt.Errorf("no compose file found in %q", path)
+		}
+	} else {
+		paths = []string{path}
+		dir = filepath.Dir(path)
+	}
+	if abs, err := filepath.Abs(dir); err == nil {
+		dir = abs
+	}
+	p := &Project{Name: filepath.Base(dir)}
+	for _, path := range paths {
+		b, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		if err := p.parse(b); err != nil {
+			return nil, fmt.Errorf("%s: %s", path, err)
+		}
+	}
+	return p, nil
+}
+
+// parse reads the compose file content and merges the services it defines
+// into p.
+func (p *Project) parse(b []byte) error {
+	var f file
+	if err := yaml.Unmarshal(b, &f); err != nil {
+		return err
+	}
+	if f.Name != "" {
+		p.Name = f.Name
+	}
+	if f.Services.Kind == 0 {
+		return nil
+	}
+	if f.Services.Kind != yaml.MappingNode {
+		return fmt.Errorf("services must be a mapping")
+	}
+	for i := 0; i+1 < len(f.Services.Content); i += 2 {
+		var s Service
+		if err := f.Services.Content[i+1].Decode(&s); err != nil {
+			return err
+		}
+		s.Name = f.Services.Content[i].Value
+		p.merge(&s)
+	}
+	return nil
+}
+
+// Service returns the service with the given name if any, nil otherwise.
+func (p *Project) Service(name string) *Service {
+	for _, s := range p.Services {
+		if s.Name == name {
+			return s
+		}
+	}
+	return nil
+}
+
+// merge adds s to the project services or merges it with the service with
+// the same name if any.
+func (p *Project) merge(s *Service) {
+	existing := p.Service(s.Name)
+	if existing == nil {
+		p.Services = append(p.Services, s)
+		return
+	}
+	if s.Image != "" {
+		existing.Image = s.Image
+	}
+	if s.Build != nil {
+		existing.Build = s.Build
+	}
+	if s.ContainerName != "" {
+		existing.ContainerName = s.ContainerName
+	}
+	if s.Healthcheck != nil {
+		existing.Healthcheck = s.Healthcheck
+	}
+	if s.Deploy != nil {
+		existing.Deploy = s.Deploy
+	}
+	existing.DependsOn = append(existing.DependsOn, s.DependsOn...)
+	existing.Links = append(existing.Links, s.Links...)
+	existing.Ports = append(existing.Ports, s.Ports...)
+}
+
+// UnmarshalYAML reads service names from a sequence or a mapping.
+func (n *Names) UnmarshalYAML(node *yaml.Node) error {
+	switch node.Kind {
+	case yaml.SequenceNode:
+		for _, c := range node.Content {
+			name := c.Value
+			if i := strings.Index(name, ":"); i >= 0 {
+				name = name[:i] // link alias
+			}
+			*n = append(*n, name)
+		}
+	case yaml.MappingNode:
+		for i := 0; i < len(node.Content); i += 2 {
+			*n = append(*n, node.Content[i].Value)
+		}
+	default:
+		return fmt.Errorf("line %d: expected sequence or mapping", node.Line)
+	}
+	return nil
+}
+
+// UnmarshalYAML reads ports defined with the short or the long syntax.
+func (p *Ports) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind != yaml.SequenceNode {
+		return fmt.Errorf("line %d: expected sequence", node.Line)
+	}
+	for _, c := range node.Content {
+		if c.Kind != yaml.MappingNode {
+			*p = append(*p, c.Value)
+			continue
+		}
+		var long struct {
+			Target    int    `yaml:"target"`
+			Published string `yaml:"published"`
+			Protocol  string `yaml:"protocol"`
+		}
+		if err := c.Decode(&long); err != nil {
+			return err
+		}
+		port := strconv.Itoa(long.Target)
+		if long.Published != "" {
+			port = long.Published + ":" + port
+		}
+		if long.Protocol != "" {
+			port += "/" + long.Protocol
+		}
+		*p = append(*p, port)
+	}
+	return nil
+}
+
+// UnmarshalYAML reads a command defined as a string or as a sequence.
+func (c *Command) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind != yaml.SequenceNode {
+		*c = Command(node.Value)
+		return nil
+	}
+	args := make([]string, len(node.Content))
+	for i, a := range node.Content {
+		args[i] = a.Value
+	}
+	*c = Command(strings.Join(args, " "))
+	return nil
+}
+
+// UnmarshalYAML reads the build context defined as a string or the build
+// configuration defined as a mapping.
+func (b *Build) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		b.Context = node.Value
+		return nil
+	}
+	type build Build // avoid infinite recursion
+	return node.Decode((*build)(b))
+}
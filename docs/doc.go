@@ -0,0 +1,16 @@
+/*
+Package docs generates a static HTML documentation site for a software
+architecture design.
+
+Render produces one page per person, software system, container and component
+listing the element description, technology, URL, owning team, tags and
+properties as well as its incoming and outgoing relationships. Pages link to the pages of the
+parent, children and related elements. Each view is rendered as a SVG file
+using the svg package and embedded in its own page as well as in the pages of
+the elements it describes. The index page lists all the elements and views of
+the design.
+
+The resulting files only reference each other via relative links so that the
+site can be served by any static file server or browsed directly from disk.
+*/
+package docs
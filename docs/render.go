@@ -0,0 +1,347 @@
+package docs
+
+import (
+	"bytes"
+	"sort"
+	"strings"
+
+	"goa.design/model/mdl"
+	"goa.design/model/svg"
+)
+
+type (
+	// site contains the data used to render the pages of a design.
+	site struct {
+		// Design is the design being documented.
+		Design *mdl.Design
+		// People, Systems, Containers, Components and CodeElements list the
+		// element pages.
+		People, Systems, Containers, Components, CodeElements []*element
+		// Views lists the view pages.
+		Views []*view
+		// elements indexes the element pages by element ID.
+		elements map[string]*element
+	}
+
+	// element contains the data used to render the page of a person, software
+	// system, container or component.
+	element struct {
+		ID          string
+		Name        string
+		Kind        string
+		Description string
+		Technology  string
+		URL         string
+		Group       string
+		Tags        []string
+		Properties  []*property
+		// Owner is the team that owns the element or its closest ancestor
+		// if any.
+		Owner *mdl.Team
+		// Perspectives lists the element perspectives in definition order.
+		Perspectives []*property
+		Parent       *link
+		Children     []*link
+		Outgoing     []*relationship
+		Incoming     []*relationship
+		// Views lists the views whose scope is the element, they are embedded
+		// in the element page.
+		Views []*view
+		// AppearsIn lists the other views that include the element.
+		AppearsIn []*link
+		// File is the name of the page file.
+		File string
+	}
+
+	// view contains the data used to render the page of a view.
+	view struct {
+		Key         string
+		Title       string
+		Kind        string
+		Description string
+		// Image is the path to the SVG file relative to the site root.
+		Image    string
+		Elements []*link
+		// File is the name of the page file.
+		File string
+	}
+
+	// relationship describes a relationship from the point of view of one
+	// of its ends.
+	relationship struct {
+		// Element is the other end of the relationship.
+		Element     *link
+		Description string
+		Technology  string
+		Tags        []string
+	}
+
+	// link is a link to an element or view page. Href is empty for elements
+	// that do not have a page (deployment nodes and infrastructure nodes).
+	link struct {
+		Name string
+		Href string
+	}
+
+	// property is a single name-value property.
+	property struct {
+		Name  string
+		Value string
+	}
+)
+
+// Render produces the files of the documentation site for the given design
+// indexed by path relative to the site root. layouts contains the element
+// positions saved by the graphical editor indexed by view key if any.
+func Render(d *mdl.Design, layouts map[string]svg.Layout) map[string][]byte {
+	s := newSite(d)
+	files := make(map[string][]byte)
+	for key, content := range svg.Render(d, layouts) {
+		files["views/"+key+".svg"] = content
+	}
+	files["index.html"] = s.execute("index", s)
+	for _, e := range s.elements {
+		files[e.File] = s.execute("element", e)
+	}
+	for _, v := range s.Views {
+		files[v.File] = s.execute("view", v)
+	}
+	return files
+}
+
+// newSite builds the pages data for the given design.
+func newSite(d *mdl.Design) *site {
+	s := &site{Design: d, elements: make(map[string]*element)}
+	m := d.Model
+	if m == nil {
+		m = &mdl.Model{}
+	}
+	for _, p := range m.People {
+		e := s.addElement("Person", p.ID, p.Name, p.Description, "", p.URL, p.Tags, p.Properties)
+		e.Group = p.Group
+		e.Perspectives = perspectives(p.Perspectives)
+		s.People = append(s.People, e)
+	}
+	for _, sys := range m.Systems {
+		se := s.addElement("Software System", sys.ID, sys.Name, sys.Description, "", sys.URL, sys.Tags, sys.Properties)
+		se.Group = sys.Group
+		se.Perspectives = perspectives(sys.Perspectives)
+		s.Systems = append(s.Systems, se)
+		for _, c := range sys.Containers {
+			ce := s.addElement("Container", c.ID, c.Name, c.Description, c.Technology, c.URL, c.Tags, c.Properties)
+			ce.Parent = s.link(sys.ID)
+			ce.Group = c.Group
+			ce.Perspectives = perspectives(c.Perspectives)
+			se.Children = append(se.Children, s.link(c.ID))
+			s.Containers = append(s.Containers, ce)
+			for _, cmp := range c.Components {
+				cmpe := s.addElement("Component", cmp.ID, cmp.Name, cmp.Description, cmp.Technology, cmp.URL, cmp.Tags, cmp.Properties)
+				cmpe.Parent = s.link(c.ID)
+				cmpe.Group = cmp.Group
+				cmpe.Perspectives = perspectives(cmp.Perspectives)
+				ce.Children = append(ce.Children, s.link(cmp.ID))
+				s.Components = append(s.Components, cmpe)
+				for _, code := range cmp.CodeElements {
+					codee := s.addElement("Code Element", code.ID, code.Name, code.Description, code.Technology, code.URL, code.Tags, code.Properties)
+					codee.Parent = s.link(cmp.ID)
+					codee.Perspectives = perspectives(code.Perspectives)
+					cmpe.Children = append(cmpe.Children, s.link(code.ID))
+					s.CodeElements = append(s.CodeElements, codee)
+				}
+			}
+		}
+	}
+
+	// Owners
+	for id, e := range s.elements {
+		e.Owner = m.Owner(id)
+	}
+
+	// Relationships
+	m.IterateRelationships(func(r *mdl.Relationship) {
+		if src, ok := s.elements[r.SourceID]; ok {
+			src.Outgoing = append(src.Outgoing, s.relationship(r, r.DestinationID))
+		}
+		if dst, ok := s.elements[r.DestinationID]; ok {
+			dst.Incoming = append(dst.Incoming, s.relationship(r, r.SourceID))
+		}
+	})
+
+	// Views
+	if d.Views == nil {
+		return s
+	}
+	for _, v := range d.Views.All() {
+		props := v.Props()
+		vp := &view{
+			Key:         props.Key,
+			Title:       props.Title,
+			Kind:        viewKind(v),
+			Description: props.Description,
+			Image:       "views/" + props.Key + ".svg",
+			File:        "view-" + props.Key + ".html",
+		}
+		if vp.Title == "" {
+			vp.Title = props.Key
+		}
+		s.Views = append(s.Views, vp)
+		scope := s.elements[scopeID(v)]
+		if scope != nil {
+			scope.Views = append(scope.Views, vp)
+		}
+		for _, ev := range props.ElementViews {
+			if l := s.link(ev.ID); l != nil {
+				vp.Elements = append(vp.Elements, l)
+			}
+			id := ev.ID
+			if ci, ok := m.Element(id).(*mdl.ContainerInstance); ok {
+				id = ci.ContainerID
+			}
+			if e, ok := s.elements[id]; ok && e != scope && !appearsIn(e, vp) {
+				e.AppearsIn = append(e.AppearsIn, &link{Name: vp.Title, Href: vp.File})
+			}
+		}
+		sort.Slice(vp.Elements, func(i, j int) bool { return vp.Elements[i].Name < vp.Elements[j].Name })
+	}
+
+	return s
+}
+
+// addElement creates and indexes the page data for the given element.
+func (s *site) addElement(kind, id, name, desc, tech, u, tags string, props map[string]string) *element {
+	e := &element{
+		ID:          id,
+		Name:        name,
+		Kind:        kind,
+		Description: desc,
+		Technology:  tech,
+		URL:         u,
+		Tags:        splitTags(tags),
+		File:        strings.ToLower(strings.ReplaceAll(kind, " ", "-")) + "-" + id + ".html",
+	}
+	for n, v := range props {
+		e.Properties = append(e.Properties, &property{Name: n, Value: v})
+	}
+	sort.Slice(e.Properties, func(i, j int) bool { return e.Properties[i].Name < e.Properties[j].Name })
+	s.elements[id] = e
+	return e
+}
+
+// perspectives returns the page data for the given element perspectives.
+func perspectives(ps []*mdl.Perspective) []*property {
+	res := make([]*property, len(ps))
+	for i, p := range ps {
+		res[i] = &property{Name: p.Name, Value: p.Description}
+	}
+	return res
+}
+
+// link returns a link to the page of the element with the given ID. The link
+// of a container instance points to the page of the corresponding container.
+// link returns nil if there is no element with the given ID.
+func (s *site) link(id string) *link {
+	if e, ok := s.elements[id]; ok {
+		return &link{Name: e.Name, Href: e.File}
+	}
+	switch e := s.Design.Model.Element(id).(type) {
+	case *mdl.ContainerInstance:
+		if l := s.link(e.ContainerID); l != nil {
+			return &link{Name: l.Name + " (instance)", Href: l.Href}
+		}
+	case *mdl.DeploymentNode:
+		return &link{Name: e.Name}
+	case *mdl.InfrastructureNode:
+		return &link{Name: e.Name}
+	}
+	return nil
+}
+
+// relationship returns the data used to render r where other is the ID of the
+// element at the other end of the relationship.
+func (s *site) relationship(r *mdl.Relationship, other string) *relationship {
+	l := s.link(other)
+	if l == nil {
+		l = &link{Name: other}
+	}
+	return &relationship{
+		Element:     l,
+		Description: r.Description,
+		Technology:  r.Technology,
+		Tags:        splitTags(r.Tags),
+	}
+}
+
+// execute renders the template with the given name.
+func (s *site) execute(name string, data interface{}) []byte {
+	var buf bytes.Buffer
+	if err := pages.ExecuteTemplate(&buf, name, struct {
+		Site *site
+		Page interface{}
+	}{s, data}); err != nil {
+		panic(err) // bug
+	}
+	return buf.Bytes()
+}
+
+// scopeID returns the ID of the element whose scope is the given view if any.
+func scopeID(v mdl.View) string {
+	switch vv := v.(type) {
+	case *mdl.ContextView:
+		return vv.SoftwareSystemID
+	case *mdl.ContainerView:
+		return vv.SoftwareSystemID
+	case *mdl.ComponentView:
+		return vv.ContainerID
+	case *mdl.CodeView:
+		return vv.ComponentID
+	case *mdl.DynamicView:
+		return vv.ElementID
+	case *mdl.DeploymentView:
+		return vv.SoftwareSystemID
+	}
+	return ""
+}
+
+// viewKind returns a human friendly name for the kind of v.
+func viewKind(v mdl.View) string {
+	switch v.(type) {
+	case *mdl.LandscapeView:
+		return "System Landscape View"
+	case *mdl.ContextView:
+		return "System Context View"
+	case *mdl.ContainerView:
+		return "Container View"
+	case *mdl.ComponentView:
+		return "Component View"
+	case *mdl.CodeView:
+		return "Code View"
+	case *mdl.DynamicView:
+		return "Dynamic View"
+	case *mdl.DeploymentView:
+		return "Deployment View"
+	}
+	return "View"
+}
+
+// appearsIn returns true if the page of e already links to the page of v.
+func appearsIn(e *element, v *view) bool {
+	for _, l := range e.AppearsIn {
+		if l.Href == v.File {
+			return true
+		}
+	}
+	return false
+}
+
+// splitTags returns the distinct tags in the given comma separated list.
+func splitTags(tags string) []string {
+	var res []string
+	seen := make(map[string]bool)
+	for _, t := range strings.Split(tags, ",") {
+		if t = strings.TrimSpace(t); t != "" && !seen[t] {
+			seen[t] = true
+			res = append(res, t)
+		}
+	}
+	return res
+}
@@ -0,0 +1,182 @@
+package docs
+
+import (
+	"sort"
+	"strings"
+	"testing"
+
+	. "goa.design/model/dsl"
+	"goa.design/model/internal/testutil"
+	"goa.design/model/mdl"
+)
+
+// bakery defines the design used to test the documentation renderer.
+func bakery() {
+	Design("Bakery", "Bakes <fresh> bread.", func() {
+		Version("1.2")
+		Team("Ovens", func() {
+			Contact("ovens@example.com")
+			Channel("#ovens")
+			OnCall("https://oncall.example.com/ovens")
+		})
+		var Orders = SoftwareSystem("Orders", "Takes orders.", func() {
+			Owner("Ovens")
+			URL("https://orders.example.com")
+			Prop("tier", "1")
+			Prop("region", "eu")
+			Perspective("Security", "Stores customer addresses.")
+			Container("Web", "Serves the <shop> & menu.", "Go", func() {
+				Tag("Frontend")
+				Uses("Database", "Reads and writes", "SQL", func() {
+					Tag("Storage")
+				})
+				Component("Cart", "Manages carts.", "Go", func() {
+					CodeElement("Basket", "Holds items.", "struct")
+				})
+			})
+			Group("Data", func() {
+				Container("Database", "Stores orders.", "PostgreSQL", func() {
+					Tag("Database")
+				})
+			})
+		})
+		Person("Customer", "Buys bread.", func() {
+			Uses("Orders/Web", "Visits", "HTTPS")
+		})
+		DeploymentEnvironment("Production", func() {
+			DeploymentNode("Cloud", "", "AWS", func() {
+				InfrastructureNode("CDN", "Caches pages.")
+				ContainerInstance("Orders/Web")
+				ContainerInstance("Orders/Database")
+			})
+		})
+		Views(func() {
+			SystemContextView(Orders, "context", "Orders context.", func() {
+				AddAll()
+			})
+			ContainerView(Orders, "containers", "Orders containers.", func() {
+				Title("Orders containers")
+				AddAll()
+			})
+			ComponentView("Orders/Web", "components", func() {
+				AddAll()
+			})
+			DeploymentView(Orders, "Production", "production", "Production deployment.", func() {
+				AddAll()
+			})
+		})
+	})
+}
+
+func TestRender(t *testing.T) {
+	d := testutil.RunDSL(t, bakery)
+	files := Render(d, nil)
+	var names []string
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	web := elementFile(d, "Container", "Web")
+	for _, want := range []string{
+		"index.html",
+		"view-production.html",
+		"views/production.svg",
+		elementFile(d, "Person", "Customer"),
+		elementFile(d, "Software System", "Orders"),
+		web,
+		elementFile(d, "Component", "Cart"),
+		elementFile(d, "Code Element", "Basket"),
+	} {
+		if _, ok := files[want]; !ok {
+			t.Errorf("file %q not rendered, got %v", want, names)
+		}
+	}
+	if len(files) != 15 {
+		t.Errorf("got %d files, want 15: %v", len(files), names)
+	}
+	testutil.AssertGolden(t, "index.html", string(files["index.html"]))
+	testutil.AssertGolden(t, "container.html", string(files[web]))
+}
+
+func TestRenderLinks(t *testing.T) {
+	d := testutil.RunDSL(t, bakery)
+	files := Render(d, nil)
+	web := elementFile(d, "Container", "Web")
+	orders := elementFile(d, "Software System", "Orders")
+	tests := []struct {
+		name, file, want string
+		absent           bool
+	}{
+		{"appears in container view", web, `<li><a href="view-containers.html">Orders containers</a></li>`, false},
+		{"appears in deployment view as instance", web, `<li><a href="view-production.html">production</a></li>`, false},
+		{"scope view embedded", web, `<img src="views/components.svg" alt="components">`, false},
+		{"scope view not listed in appears in", web, `<li><a href="view-components.html">`, true},
+		{"instance links to container", "view-production.html", `<li><a href="` + web + `">Web (instance)</a></li>`, false},
+		{"deployment node without page", "view-production.html", `<li>Cloud</li>`, false},
+		{"database appears in deployment view", elementFile(d, "Container", "Database"), `<li><a href="view-production.html">production</a></li>`, false},
+		{"infrastructure node without page", "view-production.html", `<li>CDN</li>`, false},
+		{"escaped design description", "index.html", `<p>Bakes &lt;fresh&gt; bread.</p>`, false},
+		{"escaped element description", web, `<p>Serves the &lt;shop&gt; &amp; menu.</p>`, false},
+		{"url", orders, `<tr><th>URL</th><td><a href="https://orders.example.com">https://orders.example.com</a></td></tr>`, false},
+		{"sorted properties", orders, "<tr><th>region</th><td>eu</td></tr>\n<tr><th>tier</th><td>1</td></tr>", false},
+		{"perspectives", orders, `<tr><th>Security</th><td>Stores customer addresses.</td></tr>`, false},
+		{"owner", orders, `<tr><th>Owner</th><td>Ovens</td></tr>`, false},
+		{"inherited owner", web, `<tr><th>Owner</th><td>Ovens</td></tr>`, false},
+		{"team on-call", web, `<tr><th>On-call</th><td><a href="https://oncall.example.com/ovens">`, false},
+		{"group", elementFile(d, "Container", "Database"), `<tr><th>Group</th><td>Data</td></tr>`, false},
+		{"relationship tags", web, `<span class="tag">Storage</span>`, false},
+		{"code elements", elementFile(d, "Component", "Cart"), `<h2>Code Elements</h2>`, false},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			got := string(files[tt.file])
+			if strings.Contains(got, tt.want) == tt.absent {
+				t.Errorf("%s: got contains %q = %v, want %v:\n%s", tt.file, tt.want, tt.absent, !tt.absent, got)
+			}
+		})
+	}
+}
+
+func TestRenderEmpty(t *testing.T) {
+	files := Render(&mdl.Design{Name: "Empty"}, nil)
+	if len(files) != 1 {
+		t.Errorf("got %d files, want only index.html", len(files))
+	}
+	if !strings.Contains(string(files["index.html"]), "<title>Empty</title>") {
+		t.Errorf("unexpected index page:\n%s", files["index.html"])
+	}
+}
+
+// elementFile returns the name of the page file of the element with the given
+// kind and name.
+func elementFile(d *mdl.Design, kind, name string) string {
+	var id string
+	m := d.Model
+	for _, p := range m.People {
+		if p.Name == name {
+			id = p.ID
+		}
+	}
+	for _, s := range m.Systems {
+		if s.Name == name {
+			id = s.ID
+		}
+		for _, c := range s.Containers {
+			if c.Name == name {
+				id = c.ID
+			}
+			for _, cmp := range c.Components {
+				if cmp.Name == name {
+					id = cmp.ID
+				}
+				for _, code := range cmp.CodeElements {
+					if code.Name == name {
+						id = code.ID
+					}
+				}
+			}
+		}
+	}
+	return strings.ToLower(strings.ReplaceAll(kind, " ", "-")) + "-" + id + ".html"
+}
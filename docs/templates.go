@@ -0,0 +1,143 @@
+package docs
+
+import "html/template"
+
+// pages contains the templates used to render the site pages. Each page
+// template is given the site and the page data.
+var pages = template.Must(template.New("docs").Funcs(template.FuncMap{
+	"relationships": func(label string, rels []*relationship) interface{} {
+		return struct {
+			Label         string
+			Relationships []*relationship
+		}{label, rels}
+	},
+}).Parse(layoutT + indexT + elementT + viewT))
+
+const layoutT = `{{ define "header" }}<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{ . }}</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; color: #333; margin: 0; }
+header { background: #1168bd; padding: 12px 24px; }
+header a { color: #fff; font-weight: bold; text-decoration: none; }
+main { max-width: 1100px; margin: 0 auto; padding: 12px 24px 48px; }
+a { color: #1168bd; }
+h2 { border-bottom: 1px solid #ddd; padding-bottom: 4px; margin-top: 32px; }
+table { border-collapse: collapse; width: 100%; }
+th, td { text-align: left; vertical-align: top; padding: 6px 8px; border-bottom: 1px solid #eee; }
+.kind { color: #888; font-size: 14px; text-transform: uppercase; }
+.tag { display: inline-block; background: #eee; border-radius: 4px; padding: 1px 6px; margin: 1px 2px; font-size: 12px; }
+.tech { color: #888; }
+figure { margin: 16px 0; }
+figure img { max-width: 100%; border: 1px solid #eee; }
+</style>
+</head>
+<body>
+<header><a href="index.html">{{ . }}</a></header>
+<main>
+{{ end }}
+
+{{ define "footer" }}</main>
+</body>
+</html>
+{{ end }}
+
+{{ define "tags" }}{{ range . }}<span class="tag">{{ . }}</span>{{ end }}{{ end }}
+
+{{ define "link" }}{{ if .Href }}<a href="{{ .Href }}">{{ .Name }}</a>{{ else }}{{ .Name }}{{ end }}{{ end }}
+
+{{ define "links" }}<ul>
+{{ range . }}<li>{{ template "link" . }}</li>
+{{ end }}</ul>
+{{ end }}
+
+{{ define "elements" }}<table>
+<tr><th>Name</th><th>Description</th><th>Technology</th></tr>
+{{ range . }}<tr><td><a href="{{ .File }}">{{ .Name }}</a></td><td>{{ .Description }}</td><td class="tech">{{ .Technology }}</td></tr>
+{{ end }}</table>
+{{ end }}
+
+{{ define "relationships" }}<table>
+<tr><th>{{ .Label }}</th><th>Description</th><th>Technology</th><th>Tags</th></tr>
+{{ range .Relationships }}<tr><td>{{ template "link" .Element }}</td><td>{{ .Description }}</td><td class="tech">{{ .Technology }}</td><td>{{ template "tags" .Tags }}</td></tr>
+{{ end }}</table>
+{{ end }}
+
+{{ define "figure" }}<figure>
+<a href="{{ .File }}"><img src="{{ .Image }}" alt="{{ .Title }}"></a>
+<figcaption><a href="{{ .File }}">{{ .Title }}</a>{{ if .Description }} - {{ .Description }}{{ end }}</figcaption>
+</figure>
+{{ end }}
+`
+
+const indexT = `{{ define "index" }}{{ template "header" .Site.Design.Name }}
+<h1>{{ .Site.Design.Name }}</h1>
+{{ with .Site.Design.Description }}<p>{{ . }}</p>{{ end }}
+{{ with .Site.Design.Version }}<p class="kind">Version {{ . }}</p>{{ end }}
+{{ with .Site.Views }}<h2>Views</h2>
+<table>
+<tr><th>View</th><th>Kind</th><th>Description</th></tr>
+{{ range . }}<tr><td><a href="{{ .File }}">{{ .Title }}</a></td><td>{{ .Kind }}</td><td>{{ .Description }}</td></tr>
+{{ end }}</table>
+{{ end }}
+{{ with .Site.People }}<h2>People</h2>
+{{ template "elements" . }}{{ end }}
+{{ with .Site.Systems }}<h2>Software Systems</h2>
+{{ template "elements" . }}{{ end }}
+{{ with .Site.Containers }}<h2>Containers</h2>
+{{ template "elements" . }}{{ end }}
+{{ with .Site.Components }}<h2>Components</h2>
+{{ template "elements" . }}{{ end }}
+{{ with .Site.CodeElements }}<h2>Code Elements</h2>
+{{ template "elements" . }}{{ end }}
+{{ template "footer" }}{{ end }}
+`
+
+const elementT = `{{ define "element" }}{{ template "header" .Site.Design.Name }}{{ with .Page }}
+<p class="kind">{{ .Kind }}{{ with .Parent }} in {{ template "link" . }}{{ end }}</p>
+<h1>{{ .Name }}</h1>
+{{ with .Description }}<p>{{ . }}</p>{{ end }}
+<table>
+{{ with .Technology }}<tr><th>Technology</th><td>{{ . }}</td></tr>{{ end }}
+{{ with .URL }}<tr><th>URL</th><td><a href="{{ . }}">{{ . }}</a></td></tr>{{ end }}
+{{ with .Group }}<tr><th>Group</th><td>{{ . }}</td></tr>{{ end }}
+{{ with .Owner }}<tr><th>Owner</th><td>{{ .Name }}</td></tr>
+{{ with .Contact }}<tr><th>Contact</th><td>{{ . }}</td></tr>{{ end }}
+{{ with .Channel }}<tr><th>Channel</th><td>{{ . }}</td></tr>{{ end }}
+{{ with .OnCall }}<tr><th>On-call</th><td><a href="{{ . }}">{{ . }}</a></td></tr>{{ end }}{{ end }}
+{{ with .Tags }}<tr><th>Tags</th><td>{{ template "tags" . }}</td></tr>{{ end }}
+</table>
+{{ with .Properties }}<h2>Properties</h2>
+<table>
+{{ range . }}<tr><th>{{ .Name }}</th><td>{{ .Value }}</td></tr>
+{{ end }}</table>
+{{ end }}
+{{ with .Perspectives }}<h2>Perspectives</h2>
+<table>
+{{ range . }}<tr><th>{{ .Name }}</th><td>{{ .Value }}</td></tr>
+{{ end }}</table>
+{{ end }}
+{{ with .Children }}<h2>{{ if eq $.Page.Kind "Software System" }}Containers{{ else if eq $.Page.Kind "Component" }}Code Elements{{ else }}Components{{ end }}</h2>
+{{ template "links" . }}{{ end }}
+{{ with .Outgoing }}<h2>Outgoing Relationships</h2>
+{{ template "relationships" (relationships "Destination" .) }}{{ end }}
+{{ with .Incoming }}<h2>Incoming Relationships</h2>
+{{ template "relationships" (relationships "Source" .) }}{{ end }}
+{{ with .Views }}<h2>Views</h2>
+{{ range . }}{{ template "figure" . }}{{ end }}{{ end }}
+{{ with .AppearsIn }}<h2>Appears In</h2>
+{{ template "links" . }}{{ end }}
+{{ end }}{{ template "footer" }}{{ end }}
+`
+
+const viewT = `{{ define "view" }}{{ template "header" .Site.Design.Name }}{{ with .Page }}
+<p class="kind">{{ .Kind }}</p>
+<h1>{{ .Title }}</h1>
+{{ with .Description }}<p>{{ . }}</p>{{ end }}
+<figure><img src="{{ .Image }}" alt="{{ .Title }}"></figure>
+{{ with .Elements }}<h2>Elements</h2>
+{{ template "links" . }}{{ end }}
+{{ end }}{{ template "footer" }}{{ end }}
+`
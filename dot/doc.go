@@ -0,0 +1,20 @@
+/*
+Package dot renders the views of a software architecture design using the
+Graphviz (https://graphviz.org) DOT language.
+
+Render produces one DOT graph per system landscape, system context, container,
+component, dynamic and deployment view defined in the design. Software
+systems, containers and deployment nodes that contain elements of a view are
+rendered as nested clusters. The element styles defined in the design are
+mapped to node shapes, colors and sizes and the relationship styles to edge
+colors, thickness and line styles. The automatic layout settings of a view
+(rank direction, rank and node separations) are mapped to the corresponding
+graph attributes. The separations are scaled down so that the default values
+(300 and 600 pixels) map to 0.5 and 1 inch.
+
+The resulting graphs can be rendered with the standard Graphviz tools, for
+example:
+
+	dot -Tsvg -o view.svg view.dot
+*/
+package dot
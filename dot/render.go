@@ -0,0 +1,462 @@
+package dot
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"goa.design/model/mdl"
+)
+
+type (
+	// renderer renders a single view.
+	renderer struct {
+		design *mdl.Design
+		view   mdl.View
+		styles *mdl.Styles
+		buf    *bytes.Buffer
+		// inView indexes the IDs of the elements present in the view.
+		inView map[string]bool
+		// clusters indexes the IDs of the elements rendered as clusters.
+		clusters map[string]bool
+		// children lists the IDs of the nodes and clusters contained in each
+		// cluster indexed by cluster ID, the empty key lists the top level
+		// nodes and clusters.
+		children map[string][]string
+		// groups maps the IDs of the clusters rendering element groups to
+		// the group names.
+		groups map[string]string
+	}
+)
+
+const (
+	// pointsPerInch is used to convert pixel values to DOT inches.
+	pointsPerInch = 72.0
+	// pixelsPerSpacingInch is used to convert the rank and node separations
+	// to DOT inches. The separations are expressed in pixels relative to
+	// the large Structurizr boxes, the scale maps the defaults (300 and 600
+	// pixels) to 0.5 and 1 inch.
+	pixelsPerSpacingInch = 600.0
+)
+
+// Render renders all the views of the given design. It returns the DOT source
+// of each view indexed by view key.
+func Render(d *mdl.Design) map[string]string {
+	res := make(map[string]string)
+	if d.Views == nil {
+		return res
+	}
+	for _, v := range d.Views.All() {
+		res[v.Props().Key] = RenderView(d, v)
+	}
+	return res
+}
+
+// RenderView renders the given view of the given design and returns the
+// corresponding DOT source.
+func RenderView(d *mdl.Design, v mdl.View) string {
+	r := &renderer{
+		design:   d,
+		view:     v,
+		buf:      &bytes.Buffer{},
+		inView:   make(map[string]bool),
+		clusters: make(map[string]bool),
+		children: make(map[string][]string),
+		groups:   make(map[string]string),
+	}
+	if d.Views != nil {
+		r.styles = d.Views.Styles
+	}
+	for _, ev := range v.Props().ElementViews {
+		r.inView[ev.ID] = true
+	}
+	r.buildTree()
+	r.render()
+	return r.buf.String()
+}
+
+// buildTree computes the clusters and their content. Deployment nodes that
+// contain other elements of the view are rendered as clusters. Software
+// systems and containers that are not part of the view but whose children are
+// are also rendered as clusters. Element groups are rendered as clusters
+// nested in the cluster of the grouped elements.
+func (r *renderer) buildTree() {
+	m := r.design.Model
+	added := make(map[string]bool)
+	var add func(id string)
+	add = func(id string) {
+		if added[id] {
+			return
+		}
+		added[id] = true
+		p := m.Parent(id)
+		for p != nil {
+			pid := idOf(p)
+			if _, ok := p.(*mdl.DeploymentNode); ok || !r.inView[pid] {
+				break
+			}
+			p = m.Parent(pid)
+		}
+		var key string
+		if p != nil {
+			key = idOf(p)
+			r.clusters[key] = true
+			add(key)
+		}
+		if g := m.Group(id); g != "" {
+			gkey := "group:" + idOf(m.Parent(id)) + "/" + g
+			if !r.clusters[gkey] {
+				r.clusters[gkey] = true
+				r.groups[gkey] = g
+				r.children[key] = append(r.children[key], gkey)
+			}
+			key = gkey
+		}
+		r.children[key] = append(r.children[key], id)
+	}
+	for _, ev := range r.view.Props().ElementViews {
+		if m.Element(ev.ID) != nil {
+			add(ev.ID)
+		}
+	}
+}
+
+func (r *renderer) render() {
+	props := r.view.Props()
+	r.line("digraph %s {", quote(props.Key))
+	r.line("    compound=true")
+	r.line("    fontname=\"Arial\"")
+	title := props.Title
+	if title == "" {
+		title = props.Description
+	}
+	if title != "" {
+		r.line("    label=%s", quote(title))
+		r.line("    labelloc=t")
+	}
+	if l := props.AutoLayout; l != nil {
+		switch l.RankDirection {
+		case mdl.RankTopBottom:
+			r.line("    rankdir=TB")
+		case mdl.RankBottomTop:
+			r.line("    rankdir=BT")
+		case mdl.RankLeftRight:
+			r.line("    rankdir=LR")
+		case mdl.RankRightLeft:
+			r.line("    rankdir=RL")
+		}
+		if l.RankSep != nil {
+			r.line("    ranksep=%s", spacing(*l.RankSep))
+		}
+		if l.NodeSep != nil {
+			r.line("    nodesep=%s", spacing(*l.NodeSep))
+		}
+	}
+	r.line("    node [fontname=\"Arial\" style=\"filled\" fillcolor=\"#dddddd\"]")
+	r.line("    edge [fontname=\"Arial\" fontsize=10]")
+	r.line("")
+	for _, id := range r.children[""] {
+		r.renderItem(id, "    ")
+	}
+	r.line("")
+	r.renderRelationships()
+	r.line("}")
+}
+
+// renderItem renders the element with the given ID as a cluster or a node.
+func (r *renderer) renderItem(id, indent string) {
+	if !r.clusters[id] {
+		r.renderNode(id, indent)
+		return
+	}
+	e := r.design.Model.Element(id)
+	r.line("%ssubgraph %s {", indent, quote("cluster_"+id))
+	label := clusterLabel(e)
+	if g, ok := r.groups[id]; ok {
+		label = g
+	}
+	style := "dashed"
+	if n, ok := e.(*mdl.DeploymentNode); ok {
+		style = "solid"
+		if n.Instances != nil && *n.Instances > 1 {
+			label += fmt.Sprintf(" (x%d)", *n.Instances)
+		}
+	}
+	r.line("%s    label=%s", indent, quote(label))
+	r.line("%s    style=%s", indent, style)
+	for _, c := range r.children[id] {
+		r.renderItem(c, indent+"    ")
+	}
+	r.line("%s}", indent)
+}
+
+func (r *renderer) renderNode(id, indent string) {
+	var (
+		name, kind, tech, desc, tags string
+		shape                        = mdl.ShapeBox
+	)
+	switch a := r.design.Model.Element(id).(type) {
+	case *mdl.Person:
+		name, kind, desc, tags = a.Name, "Person", a.Description, a.Tags
+		shape = mdl.ShapePerson
+	case *mdl.SoftwareSystem:
+		name, kind, desc, tags = a.Name, "Software System", a.Description, a.Tags
+	case *mdl.Container:
+		name, kind, tech, desc, tags = a.Name, "Container", a.Technology, a.Description, a.Tags
+	case *mdl.Component:
+		name, kind, tech, desc, tags = a.Name, "Component", a.Technology, a.Description, a.Tags
+	case *mdl.CodeElement:
+		name, kind, tech, desc, tags = a.Name, "Code Element", a.Technology, a.Description, a.Tags
+	case *mdl.DeploymentNode:
+		name, kind, tech, desc, tags = a.Name, "Deployment Node", a.Technology, a.Description, a.Tags
+	case *mdl.InfrastructureNode:
+		name, kind, tech, desc, tags = a.Name, "Infrastructure Node", a.Technology, a.Description, a.Tags
+	case *mdl.ContainerInstance:
+		c, ok := r.design.Model.Element(a.ContainerID).(*mdl.Container)
+		if !ok {
+			return
+		}
+		name, kind, tech, desc, tags = c.Name, "Container", c.Technology, c.Description, c.Tags
+	default:
+		return
+	}
+	label := name + "\n[" + kind
+	if tech != "" {
+		label += ": " + tech
+	}
+	label += "]"
+	if desc != "" {
+		label += "\n\n" + wrap(desc, 40)
+	}
+	attrs := []string{"label=" + quote(label)}
+	es := r.styles.ElementStyle(tags)
+	if es != nil && es.Shape != mdl.ShapeUndefined {
+		shape = es.Shape
+	}
+	s, rounded := dotShape(shape)
+	attrs = append(attrs, "shape="+s)
+	var styles []string
+	if rounded {
+		styles = append(styles, "rounded")
+	}
+	if es != nil {
+		if es.Background != "" {
+			attrs = append(attrs, "fillcolor="+quote(es.Background))
+		}
+		if es.Color != "" {
+			attrs = append(attrs, "fontcolor="+quote(es.Color))
+		}
+		if es.Stroke != "" {
+			attrs = append(attrs, "color="+quote(es.Stroke))
+		}
+		if es.Width != nil {
+			attrs = append(attrs, "width="+inches(*es.Width))
+		}
+		if es.Height != nil {
+			attrs = append(attrs, "height="+inches(*es.Height))
+		}
+		if es.FontSize != nil {
+			attrs = append(attrs, fmt.Sprintf("fontsize=%d", *es.FontSize))
+		}
+		switch es.Border {
+		case mdl.BorderDashed:
+			styles = append(styles, "dashed")
+		case mdl.BorderDotted:
+			styles = append(styles, "dotted")
+		}
+	}
+	if len(styles) > 0 {
+		styles = append(styles, "filled")
+		attrs = append(attrs, "style="+quote(strings.Join(styles, ",")))
+	}
+	r.line("%s%s [%s]", indent, quote(id), strings.Join(attrs, " "))
+}
+
+func (r *renderer) renderRelationships() {
+	_, dynamic := r.view.(*mdl.DynamicView)
+	for _, rv := range r.view.Props().RelationshipViews {
+		rel := r.design.Model.Relationship(rv.ID)
+		if rel == nil {
+			continue
+		}
+		src, ltail := r.anchor(rel.SourceID)
+		dst, lhead := r.anchor(rel.DestinationID)
+		if src == "" || dst == "" {
+			continue
+		}
+		desc := rel.Description
+		if rv.Description != "" {
+			desc = rv.Description
+		}
+		if dynamic && rv.Order != "" {
+			desc = rv.Order + ". " + desc
+		}
+		label := wrap(desc, 30)
+		if rel.Technology != "" {
+			label += "\n[" + rel.Technology + "]"
+		}
+		attrs := []string{"label=" + quote(label)}
+		if ltail != "" {
+			attrs = append(attrs, "ltail="+quote(ltail))
+		}
+		if lhead != "" {
+			attrs = append(attrs, "lhead="+quote(lhead))
+		}
+		dashed := rel.InteractionStyle == mdl.InteractionAsynchronous
+		rs := r.styles.RelationshipStyle(rel.Tags)
+		if rs != nil && rs.Dashed != nil {
+			dashed = *rs.Dashed
+		}
+		if dashed {
+			attrs = append(attrs, "style=dashed")
+		} else if rs != nil && rs.Dashed != nil {
+			attrs = append(attrs, "style=solid")
+		}
+		if rs != nil {
+			if rs.Color != "" {
+				attrs = append(attrs, "color="+quote(rs.Color), "fontcolor="+quote(rs.Color))
+			}
+			if rs.Thickness != nil {
+				attrs = append(attrs, fmt.Sprintf("penwidth=%d", *rs.Thickness))
+			}
+			if rs.FontSize != nil {
+				attrs = append(attrs, fmt.Sprintf("fontsize=%d", *rs.FontSize))
+			}
+		}
+		r.line("    %s -> %s [%s]", quote(src), quote(dst), strings.Join(attrs, " "))
+	}
+}
+
+// anchor returns the ID of the DOT node that relationships to or from the
+// element with the given ID should use. If the element is rendered as a
+// cluster then anchor returns the first node in the cluster as well as the
+// cluster name. anchor returns an empty string if the cluster contains no
+// node.
+func (r *renderer) anchor(id string) (string, string) {
+	if !r.clusters[id] {
+		if !r.inView[id] {
+			return "", ""
+		}
+		return id, ""
+	}
+	for _, c := range r.children[id] {
+		if n, _ := r.anchor(c); n != "" {
+			return n, "cluster_" + id
+		}
+	}
+	return "", ""
+}
+
+func (r *renderer) line(format string, a ...interface{}) {
+	fmt.Fprintf(r.buf, format+"\n", a...)
+}
+
+// dotShape returns the DOT shape corresponding to the given shape and whether
+// the shape corners should be rounded.
+func dotShape(shape mdl.ShapeKind) (string, bool) {
+	switch shape {
+	case mdl.ShapeCircle:
+		return "circle", false
+	case mdl.ShapeCylinder, mdl.ShapePipe:
+		return "cylinder", false
+	case mdl.ShapeEllipse:
+		return "ellipse", false
+	case mdl.ShapeHexagon:
+		return "hexagon", false
+	case mdl.ShapeComponent:
+		return "component", false
+	case mdl.ShapeFolder:
+		return "folder", false
+	case mdl.ShapeWebBrowser:
+		return "tab", false
+	case mdl.ShapeRoundedBox, mdl.ShapePerson, mdl.ShapeRobot,
+		mdl.ShapeMobileDeviceLandscape, mdl.ShapeMobileDevicePortrait:
+		return "box", true
+	default:
+		return "box", false
+	}
+}
+
+// inches converts the given number of pixels to inches.
+func inches(px int) string {
+	return fmt.Sprintf("%.2f", float64(px)/pointsPerInch)
+}
+
+// spacing converts the given rank or node separation in pixels to inches.
+func spacing(px int) string {
+	return fmt.Sprintf("%.2f", float64(px)/pixelsPerSpacingInch)
+}
+
+// quote returns a DOT string literal for s.
+func quote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	s = strings.ReplaceAll(s, "\n", `\n`)
+	return `"` + s + `"`
+}
+
+// wrap inserts newlines in s so that lines are no longer than n characters
+// when possible.
+func wrap(s string, n int) string {
+	var (
+		lines []string
+		cur   string
+	)
+	for _, w := range strings.Fields(s) {
+		if cur != "" && len(cur)+len(w)+1 > n {
+			lines = append(lines, cur)
+			cur = w
+			continue
+		}
+		if cur != "" {
+			cur += " "
+		}
+		cur += w
+	}
+	if cur != "" {
+		lines = append(lines, cur)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// idOf returns the ID of the given element.
+func idOf(e interface{}) string {
+	switch a := e.(type) {
+	case *mdl.Person:
+		return a.ID
+	case *mdl.SoftwareSystem:
+		return a.ID
+	case *mdl.Container:
+		return a.ID
+	case *mdl.Component:
+		return a.ID
+	case *mdl.CodeElement:
+		return a.ID
+	case *mdl.DeploymentNode:
+		return a.ID
+	case *mdl.InfrastructureNode:
+		return a.ID
+	case *mdl.ContainerInstance:
+		return a.ID
+	}
+	return ""
+}
+
+// clusterLabel returns the label used to render the cluster corresponding to the
+// given element.
+func clusterLabel(e interface{}) string {
+	switch a := e.(type) {
+	case *mdl.SoftwareSystem:
+		return a.Name + "\n[Software System]"
+	case *mdl.Container:
+		return a.Name + "\n[Container]"
+	case *mdl.Component:
+		return a.Name + "\n[Component]"
+	case *mdl.DeploymentNode:
+		if a.Technology != "" {
+			return a.Name + "\n[" + a.Technology + "]"
+		}
+		return a.Name
+	}
+	return ""
+}
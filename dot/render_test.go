@@ -0,0 +1,161 @@
+package dot
+
+import (
+	"strings"
+	"testing"
+
+	. "goa.design/model/dsl"
+	"goa.design/model/internal/testutil"
+)
+
+// clinic defines the design used to test the DOT renderer.
+func clinic() {
+	Design("Clinic", "A medical clinic.", func() {
+		var Insurer = SoftwareSystem("Insurer", `Checks "coverage".`, func() {
+			External()
+			Tag("External", "Legacy")
+		})
+		var Records = SoftwareSystem("Records", "Stores patient records.", func() {
+			Container("Portal", "Lets patients book appointments and read the results of their examinations.", "React", func() {
+				Uses("API", "Calls", "JSON/HTTPS")
+			})
+			Container("API", "Serves records.", "Go", func() {
+				Uses("Store", "Reads and writes", "SQL")
+				Uses(Insurer, "Checks coverage", "HTTPS", Asynchronous)
+				Component("Scheduler", "Books appointments.", "Go", func() {
+					Uses("Records/Store", "Reads slots", "SQL")
+				})
+				Component("Billing", "Bills patients.", "Go", func() {
+					Uses(Insurer, "Submits claims", Asynchronous, func() {
+						Tag("Urgent")
+					})
+				})
+			})
+			Group("Data", func() {
+				Container("Store", `Keeps files in C:\records.`, "PostgreSQL", func() {
+					Tag("Database")
+				})
+			})
+		})
+		Person("Patient", "Visits the clinic.", func() {
+			Uses(Records, "Books appointments")
+			Uses("Records/Portal", "Uses", "HTTPS")
+		})
+		DeploymentEnvironment("Production", func() {
+			DeploymentNode("Hospital", "On premise data center.", func() {
+				InfrastructureNode("Router", "Routes traffic.", "Cisco")
+				DeploymentNode("Rack", "", "Linux", func() {
+					Instances(3)
+					DeploymentNode("Docker", "", "Docker", func() {
+						ContainerInstance("Records/Portal")
+						ContainerInstance("Records/API")
+					})
+				})
+				DeploymentNode("DB Server", func() {
+					ContainerInstance("Records/Store")
+				})
+			})
+		})
+		Views(func() {
+			SystemContextView(Records, "context", "Records context.", func() {
+				AddAll()
+				AutoLayout(RankBottomTop, func() {
+					RankSeparation(600)
+					NodeSeparation(150)
+				})
+			})
+			ContainerView(Records, "containers", "Records containers.", func() {
+				AddAll()
+				Title("Records\ncontainers")
+				AutoLayout(RankRightLeft)
+			})
+			ComponentView("Records/API", "components", "API components.", func() {
+				AddComponents()
+				Add("Records/Store")
+				Add(Insurer)
+			})
+			DynamicView(Records, "booking", "Booking.", func() {
+				Link("Patient", "Records/Portal", "Uses")
+				Link("Records/Portal", "Records/API", "Calls")
+				Link("Records/API", "Records/Store", "Reads and writes")
+			})
+			DeploymentView(Records, "Production", "production", "Production deployment.", func() {
+				AddAll()
+			})
+			Styles(func() {
+				ElementStyle("Database", func() {
+					Shape(ShapeCylinder)
+					Width(450)
+					Height(300)
+					FontSize(30)
+				})
+				ElementStyle("External", func() {
+					Shape(ShapeRoundedBox)
+					Background("#999999")
+					Color("#ffffff")
+				})
+				ElementStyle("Legacy", func() {
+					Border(BorderDotted)
+					Stroke("#ff0000")
+				})
+				RelationshipStyle("Asynchronous", func() {
+					Thickness(3)
+					Color("#0000ff")
+					FontSize(12)
+				})
+				RelationshipStyle("Urgent", func() {
+					Solid()
+				})
+			})
+		})
+	})
+}
+
+func TestRender(t *testing.T) {
+	views := Render(testutil.RunDSL(t, clinic))
+	keys := []string{"booking", "components", "containers", "context", "production"}
+	if len(views) != len(keys) {
+		t.Errorf("got %d views, want %d", len(views), len(keys))
+	}
+	for _, key := range keys {
+		key := key
+		t.Run(key, func(t *testing.T) {
+			src, ok := views[key]
+			if !ok {
+				t.Fatalf("view %q not rendered", key)
+			}
+			testutil.AssertGolden(t, key+".dot", src)
+		})
+	}
+}
+
+func TestRenderEdgeCases(t *testing.T) {
+	views := Render(testutil.RunDSL(t, clinic))
+	tests := []struct {
+		name, view, want string
+		absent           bool
+	}{
+		{"escaped quotes", "context", `Checks \"coverage\".`, false},
+		{"escaped backslash", "containers", `C:\\records.`, false},
+		{"multiline title", "containers", `label="Records\ncontainers"`, false},
+		{"wrapped description", "containers", `Lets patients book appointments and read\nthe results of their examinations.`, false},
+		{"rank direction", "containers", "rankdir=RL", false},
+		{"scaled separations", "context", "rankdir=BT\n    ranksep=1.00\n    nodesep=0.25", false},
+		{"merged styles", "context", `fillcolor="#999999" fontcolor="#ffffff" color="#ff0000" style="rounded,dotted,filled"]`, false},
+		{"element size", "containers", `shape=cylinder width=6.25 height=4.17 fontsize=30`, false},
+		{"group cluster", "containers", `label="Data"`, false},
+		{"container cluster", "components", "label=\"API\\n[Container]\"\n            style=dashed", false},
+		{"relationship style", "containers", `style=dashed color="#0000ff" fontcolor="#0000ff" penwidth=3 fontsize=12`, false},
+		{"style overrides interaction", "components", `[label="Submits claims" style=solid color="#0000ff"`, false},
+		{"single edge style", "components", `fontsize=12 style=solid]`, true},
+		{"nested deployment nodes", "production", "label=\"Rack\\n[Linux] (x3)\"\n            style=solid\n            subgraph \"cluster_", false},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			if got := views[tt.view]; strings.Contains(got, tt.want) == tt.absent {
+				t.Errorf("%s view: got contains %q = %v, want %v:\n%s", tt.view, tt.want, tt.absent, !tt.absent, got)
+			}
+		})
+	}
+}
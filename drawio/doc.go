@@ -0,0 +1,15 @@
+/*
+Package drawio exports the views of a software architecture design to the
+draw.io (diagrams.net) file format.
+
+Render produces a single draw.io document where each view is a page. Elements
+are rendered as editable cells using the same shapes and colors as the draw.io
+C4 shape library: the element name, type, technology and description are
+stored as cell properties so they can be edited in draw.io. Element styles
+defined in the design override the default colors and shapes.
+
+Elements are positioned using the positions saved by the graphical editor or
+defined in the design (ElementView X and Y) when available and are otherwise
+laid out automatically using the same algorithm as the svg package.
+*/
+package drawio
@@ -0,0 +1,391 @@
+package drawio
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"math"
+	"strconv"
+	"strings"
+
+	"goa.design/model/mdl"
+	"goa.design/model/svg"
+)
+
+type (
+	// palette is the set of default colors used to render an element.
+	palette struct {
+		fill, stroke, font string
+	}
+
+	// style is a draw.io cell style, it maps style keys to values and
+	// preserves the order in which the keys are first set. Keys that are
+	// flags such as "ellipse" have an empty value.
+	style struct {
+		keys   []string
+		values map[string]string
+	}
+)
+
+var (
+	personPalette    = palette{"#08427B", "#073B6F", "#FFFFFF"}
+	systemPalette    = palette{"#1168BD", "#0B4884", "#FFFFFF"}
+	containerPalette = palette{"#438DD5", "#3C7FC0", "#FFFFFF"}
+	componentPalette = palette{"#85BBF0", "#78A8D8", "#000000"}
+	codePalette      = palette{"#C5DDF5", "#A9C8E8", "#000000"}
+	externalPalette  = palette{"#999999", "#8A8A8A", "#FFFFFF"}
+	nodePalette      = palette{"#FFFFFF", "#888888", "#000000"}
+)
+
+// elementLabel is the label of element cells, it uses the cell properties so
+// that the name, type, technology and description can be edited in draw.io.
+const elementLabel = `<font style="font-size: 16px"><b>%c4Name%</b></font><div>[%c4Type%%c4Technology%]</div><br><div><font style="font-size: 11px">%c4Description%</font></div>`
+
+// relationshipLabel is the label of relationship cells.
+const relationshipLabel = `<div style="text-align: left"><div style="text-align: center"><b>%c4Description%</b></div><div style="text-align: center">%c4Technology%</div></div>`
+
+// Render returns the draw.io document for the given design. layouts contains
+// the positions of the elements of each view indexed by view key as saved by
+// the graphical editor, it may be nil.
+func Render(d *mdl.Design, layouts map[string]svg.Layout) []byte {
+	var buf bytes.Buffer
+	buf.WriteString(`<mxfile host="goa.design/model">` + "\n")
+	if d.Views != nil {
+		for _, v := range d.Views.All() {
+			renderView(&buf, d, v, layouts[v.Props().Key])
+		}
+	}
+	buf.WriteString("</mxfile>\n")
+	return buf.Bytes()
+}
+
+func renderView(buf *bytes.Buffer, d *mdl.Design, v mdl.View, layout svg.Layout) {
+	var styles *mdl.Styles
+	if d.Views != nil {
+		styles = d.Views.Styles
+	}
+	props := v.Props()
+	vl := svg.LayoutView(d, v, layout)
+
+	// Translate the diagram so it starts at the origin of the page.
+	minX, minY := math.Inf(1), math.Inf(1)
+	maxX, maxY := math.Inf(-1), math.Inf(-1)
+	extend := func(b svg.Box) {
+		minX, minY = math.Min(minX, b.X), math.Min(minY, b.Y)
+		maxX, maxY = math.Max(maxX, b.X+b.Width), math.Max(maxY, b.Y+b.Height)
+	}
+	for _, b := range vl.Elements {
+		extend(*b)
+	}
+	for _, b := range vl.Boundaries {
+		extend(b.Box)
+	}
+	if math.IsInf(minX, 1) {
+		minX, minY, maxX, maxY = 0, 0, 0, 0
+	}
+	dx, dy := 40-minX, 40-minY
+
+	name := props.Title
+	if name == "" {
+		name = props.Key
+	}
+	fmt.Fprintf(buf, `  <diagram id="%s" name="%s">`+"\n", attr(props.Key), attr(name))
+	fmt.Fprintf(buf, `    <mxGraphModel grid="1" gridSize="10" guides="1" tooltips="1" connect="1" arrows="1" fold="1" page="1" pageScale="1" pageWidth="%d" pageHeight="%d" math="0" shadow="0">`+"\n",
+		int(maxX-minX+80), int(maxY-minY+80))
+	buf.WriteString("      <root>\n")
+	buf.WriteString(`        <mxCell id="0"/>` + "\n")
+	buf.WriteString(`        <mxCell id="1" parent="0"/>` + "\n")
+
+	for _, b := range vl.Boundaries {
+		var typ, tags string
+		switch e := d.Model.Element(b.ID).(type) {
+		case *mdl.SoftwareSystem:
+			typ = "SystemScopeBoundary"
+		case *mdl.Container:
+			typ = "ContainerScopeBoundary"
+		case *mdl.Component:
+			typ = "ComponentScopeBoundary"
+		case *mdl.DeploymentNode:
+			typ, tags = "DeploymentNode", e.Tags
+		default:
+			typ = "EnterpriseBoundary"
+			if strings.HasPrefix(b.ID, "group:") {
+				typ = "Group"
+			}
+		}
+		style := newStyle("rounded=1;fontSize=11;whiteSpace=wrap;html=1;dashed=1;arcSize=20;fillColor=none;strokeColor=#666666;fontColor=#333333;labelBackgroundColor=none;align=left;verticalAlign=bottom;labelBorderColor=none;spacingTop=0;spacing=10;dashPattern=8 4;metaEdit=1;rotatable=0;perimeter=rectanglePerimeter;noLabel=0;labelPadding=0;allowArrows=0;connectable=0;expand=0;recursiveResize=0;editable=1;pointerEvents=0;absoluteArcSize=1;")
+		if es := styles.ElementStyle(tags); es != nil {
+			colors(style, es)
+		}
+		fmt.Fprintf(buf, `        <object placeholders="1" c4Name="%s" c4Type="%s" c4Application="" label="%s" id="%s">`+"\n",
+			attr(b.Name), attr(typ), attr(`<font style="font-size: 16px"><b><div style="text-align: left">%c4Name%</div></b></font><div style="text-align: left">[%c4Type%]</div>`), "b"+b.ID)
+		fmt.Fprintf(buf, `          <mxCell style="%s" vertex="1" parent="1">`+"\n", attr(style.String()))
+		fmt.Fprintf(buf, `            <mxGeometry x="%.0f" y="%.0f" width="%.0f" height="%.0f" as="geometry"/>`+"\n", b.X+dx, b.Y+dy, b.Width, b.Height)
+		buf.WriteString("          </mxCell>\n")
+		buf.WriteString("        </object>\n")
+	}
+
+	for _, ev := range props.ElementViews {
+		b, ok := vl.Elements[ev.ID]
+		if !ok {
+			continue
+		}
+		name, typ, tech, desc, tags, pal := describe(d.Model, ev.ID)
+		if typ == "" {
+			continue
+		}
+		style := elementStyle(typ, pal, styles.ElementStyle(tags))
+		if tech != "" {
+			tech = ": " + tech
+		}
+		fmt.Fprintf(buf, `        <object placeholders="1" c4Name="%s" c4Type="%s" c4Technology="%s" c4Description="%s" label="%s" id="%s">`+"\n",
+			attr(name), attr(typ), attr(tech), attr(desc), attr(elementLabel), "e"+ev.ID)
+		fmt.Fprintf(buf, `          <mxCell style="%s" vertex="1" parent="1">`+"\n", attr(style.String()))
+		fmt.Fprintf(buf, `            <mxGeometry x="%.0f" y="%.0f" width="%.0f" height="%.0f" as="geometry"/>`+"\n", b.X+dx, b.Y+dy, b.Width, b.Height)
+		buf.WriteString("          </mxCell>\n")
+		buf.WriteString("        </object>\n")
+	}
+
+	_, dynamic := v.(*mdl.DynamicView)
+	for _, rv := range props.RelationshipViews {
+		r := d.Model.Relationship(rv.ID)
+		if r == nil || vl.Elements[r.SourceID] == nil || vl.Elements[r.DestinationID] == nil {
+			continue
+		}
+		desc := r.Description
+		if rv.Description != "" {
+			desc = rv.Description
+		}
+		if dynamic && rv.Order != "" {
+			desc = rv.Order + ": " + desc
+		}
+		tech := r.Technology
+		if tech != "" {
+			tech = "[" + tech + "]"
+		}
+		rs := styles.RelationshipStyle(r.Tags)
+		routing := rv.Routing
+		if routing == mdl.RoutingUndefined && rs != nil {
+			routing = rs.Routing
+		}
+		fmt.Fprintf(buf, `        <object placeholders="1" c4Type="Relationship" c4Technology="%s" c4Description="%s" label="%s" id="%s">`+"\n",
+			attr(tech), attr(desc), attr(relationshipLabel), "r"+rv.ID)
+		fmt.Fprintf(buf, `          <mxCell style="%s" edge="1" parent="1" source="%s" target="%s">`+"\n",
+			attr(relationshipStyle(rs, routing).String()), "e"+r.SourceID, "e"+r.DestinationID)
+		if len(rv.Vertices) == 0 {
+			buf.WriteString(`            <mxGeometry relative="1" as="geometry"/>` + "\n")
+		} else {
+			buf.WriteString(`            <mxGeometry relative="1" as="geometry">` + "\n")
+			buf.WriteString(`              <Array as="points">` + "\n")
+			for _, p := range rv.Vertices {
+				fmt.Fprintf(buf, `                <mxPoint x="%.0f" y="%.0f"/>`+"\n", float64(p.X)+dx, float64(p.Y)+dy)
+			}
+			buf.WriteString("              </Array>\n")
+			buf.WriteString("            </mxGeometry>\n")
+		}
+		buf.WriteString("          </mxCell>\n")
+		buf.WriteString("        </object>\n")
+	}
+
+	buf.WriteString("      </root>\n")
+	buf.WriteString("    </mxGraphModel>\n")
+	buf.WriteString("  </diagram>\n")
+}
+
+// describe returns the name, C4 type, technology, description, tags and
+// default palette of the element with the given ID.
+func describe(m *mdl.Model, id string) (name, typ, tech, desc, tags string, pal palette) {
+	switch e := m.Element(id).(type) {
+	case *mdl.Person:
+		pal = personPalette
+		if e.Location == mdl.LocationExternal {
+			pal = externalPalette
+		}
+		return e.Name, "Person", "", e.Description, e.Tags, pal
+	case *mdl.SoftwareSystem:
+		pal = systemPalette
+		if e.Location == mdl.LocationExternal {
+			pal = externalPalette
+		}
+		return e.Name, "Software System", "", e.Description, e.Tags, pal
+	case *mdl.Container:
+		return e.Name, "Container", e.Technology, e.Description, e.Tags, containerPalette
+	case *mdl.Component:
+		return e.Name, "Component", e.Technology, e.Description, e.Tags, componentPalette
+	case *mdl.CodeElement:
+		return e.Name, "Code Element", e.Technology, e.Description, e.Tags, codePalette
+	case *mdl.DeploymentNode:
+		return e.Name, "Deployment Node", e.Technology, e.Description, e.Tags, nodePalette
+	case *mdl.InfrastructureNode:
+		return e.Name, "Infrastructure Node", e.Technology, e.Description, e.Tags, nodePalette
+	case *mdl.ContainerInstance:
+		c, ok := m.Element(e.ContainerID).(*mdl.Container)
+		if !ok {
+			return
+		}
+		return c.Name, "Container", c.Technology, c.Description, c.Tags, containerPalette
+	}
+	return
+}
+
+// elementStyle returns the draw.io style of an element cell.
+func elementStyle(typ string, pal palette, es *mdl.ElementStyle) *style {
+	shape := mdl.ShapeRoundedBox
+	if typ == "Person" {
+		shape = mdl.ShapePerson
+	}
+	if es != nil && es.Shape != mdl.ShapeUndefined {
+		shape = es.Shape
+	}
+	st := newStyle("whiteSpace=wrap;html=1;labelBackgroundColor=none;align=center;metaEdit=1;")
+	switch shape {
+	case mdl.ShapePerson, mdl.ShapeRobot:
+		st.parse("shape=mxgraph.c4.person2;")
+	case mdl.ShapeCylinder:
+		st.parse("shape=cylinder3;size=15;boundedLbl=1;")
+	case mdl.ShapePipe:
+		st.parse("shape=cylinder3;size=15;boundedLbl=1;direction=south;")
+	case mdl.ShapeBox:
+		st.parse("rounded=0;")
+	case mdl.ShapeCircle:
+		st.parse("ellipse;aspect=fixed;")
+	case mdl.ShapeEllipse:
+		st.parse("ellipse;")
+	case mdl.ShapeHexagon:
+		st.parse("shape=hexagon;perimeter=hexagonPerimeter2;size=0.25;")
+	case mdl.ShapeComponent:
+		st.parse("shape=component;align=left;spacingLeft=36;")
+	case mdl.ShapeFolder:
+		st.parse("shape=folder;tabWidth=80;tabHeight=20;tabPosition=left;")
+	case mdl.ShapeWebBrowser:
+		st.parse("shape=mxgraph.c4.webBrowserContainer2;")
+	case mdl.ShapeMobileDevicePortrait:
+		st.parse("shape=mxgraph.android.phone2;")
+	case mdl.ShapeMobileDeviceLandscape:
+		st.parse("shape=mxgraph.android.phone2;direction=north;")
+	default:
+		st.parse("rounded=1;arcSize=10;")
+	}
+	st.set("fillColor", pal.fill)
+	st.set("strokeColor", pal.stroke)
+	st.set("fontColor", pal.font)
+	if es != nil {
+		colors(st, es)
+	}
+	return st
+}
+
+// colors sets the draw.io style properties corresponding to the colors,
+// border and opacity defined in the given element style. The properties
+// override the values already set in st.
+func colors(st *style, es *mdl.ElementStyle) {
+	if es.Background != "" {
+		st.set("fillColor", es.Background)
+	}
+	if es.Stroke != "" {
+		st.set("strokeColor", es.Stroke)
+	}
+	if es.Color != "" {
+		st.set("fontColor", es.Color)
+	}
+	if es.Opacity != nil {
+		st.set("opacity", strconv.Itoa(*es.Opacity))
+		st.set("textOpacity", strconv.Itoa(*es.Opacity))
+	}
+	switch es.Border {
+	case mdl.BorderSolid:
+		st.set("dashed", "0")
+	case mdl.BorderDashed:
+		st.set("dashed", "1")
+		st.set("dashPattern", "8 4")
+	case mdl.BorderDotted:
+		st.set("dashed", "1")
+		st.set("dashPattern", "1 4")
+	}
+}
+
+// relationshipStyle returns the draw.io style of a relationship cell.
+func relationshipStyle(rs *mdl.RelationshipStyle, routing mdl.RoutingKind) *style {
+	var (
+		color     = "#828282"
+		thickness = 1
+		dashed    = true
+	)
+	if rs != nil {
+		if rs.Color != "" {
+			color = rs.Color
+		}
+		if rs.Thickness != nil {
+			thickness = *rs.Thickness
+		}
+		if rs.Dashed != nil {
+			dashed = *rs.Dashed
+		}
+	}
+	st := newStyle(fmt.Sprintf("endArrow=blockThin;html=1;fontSize=10;fontColor=%s;strokeWidth=%d;endFill=1;strokeColor=%s;metaEdit=1;endSize=14;startSize=14;jumpStyle=arc;jumpSize=16;rounded=0;",
+		color, thickness, color))
+	if dashed {
+		st.parse("dashed=1;dashPattern=8 4;")
+	}
+	switch routing {
+	case mdl.RoutingOrthogonal:
+		st.set("edgeStyle", "orthogonalEdgeStyle")
+	case mdl.RoutingCurved:
+		st.set("curved", "1")
+	}
+	if rs != nil && rs.FontSize != nil {
+		st.set("fontSize", strconv.Itoa(*rs.FontSize))
+	}
+	return st
+}
+
+// newStyle returns a style initialized with the properties of the given
+// draw.io style string.
+func newStyle(s string) *style {
+	st := &style{values: make(map[string]string)}
+	st.parse(s)
+	return st
+}
+
+// parse sets the properties of the given draw.io style string, s is a list of
+// "key=value" or "key" properties separated with semicolons.
+func (st *style) parse(s string) {
+	for _, p := range strings.Split(s, ";") {
+		if p == "" {
+			continue
+		}
+		kv := strings.SplitN(p, "=", 2)
+		if len(kv) == 1 {
+			st.set(kv[0], "")
+			continue
+		}
+		st.set(kv[0], kv[1])
+	}
+}
+
+// set sets the value of the given key, the key keeps its position if it is
+// already set.
+func (st *style) set(key, value string) {
+	if _, ok := st.values[key]; !ok {
+		st.keys = append(st.keys, key)
+	}
+	st.values[key] = value
+}
+
+// String returns the draw.io style string, each key appears once.
+func (st *style) String() string {
+	var b strings.Builder
+	for _, k := range st.keys {
+		b.WriteString(k)
+		if v := st.values[k]; v != "" {
+			b.WriteString("=" + v)
+		}
+		b.WriteString(";")
+	}
+	return b.String()
+}
+
+// attr escapes s for use as a XML attribute value.
+func attr(s string) string {
+	return strings.ReplaceAll(html.EscapeString(s), "\n", "&#xa;")
+}
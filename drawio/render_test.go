@@ -0,0 +1,173 @@
+package drawio
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	. "goa.design/model/dsl"
+	"goa.design/model/internal/testutil"
+	"goa.design/model/mdl"
+	"goa.design/model/svg"
+)
+
+// warehouse defines the design used to test the draw.io renderer.
+func warehouse() {
+	Design("Warehouse", "Stores and ships goods.", func() {
+		Enterprise("Logistics")
+		var Carrier = SoftwareSystem("Carrier", `Ships "parcels".`, func() {
+			External()
+			Tag("External")
+		})
+		var Stock = SoftwareSystem("Stock", "Tracks goods.\nKnows where they are.", func() {
+			Container("Scanner", "Reads barcodes.", "Kotlin", func() {
+				Tag("Mobile")
+				Uses("Inventory", "Reports scans", "gRPC")
+			})
+			Container("Inventory", "Keeps stock levels.", "Go", func() {
+				Uses("Ledger", "Reads and writes", "SQL")
+				Uses(Carrier, "Books pickups", "HTTPS", Asynchronous, func() {
+					Tag("Booking")
+				})
+			})
+			Group("Storage", func() {
+				Container("Ledger", "Stores movements.", "PostgreSQL", func() {
+					Tag("Database")
+				})
+			})
+		})
+		Person("Picker", "Picks goods.", func() {
+			Uses("Stock/Scanner", "Scans goods with")
+		})
+		Person("Robot", "Moves pallets.", func() {
+			External()
+			Tag("Robot")
+			Uses(Stock, "Gets orders from")
+		})
+		DeploymentEnvironment("Production", func() {
+			DeploymentNode("Site", "", "On premise", func() {
+				Tag("Site")
+				DeploymentNode("Server", "", "Linux", func() {
+					ContainerInstance("Stock/Inventory")
+					ContainerInstance("Stock/Ledger")
+				})
+			})
+		})
+		Views(func() {
+			SystemContextView(Stock, "context", "Stock context.", func() {
+				AddAll()
+			})
+			ContainerView(Stock, "containers", "Stock containers.", func() {
+				AddContainers()
+				Add(Carrier)
+				Link("Stock/Inventory", Carrier, "Books pickups", func() {
+					Vertices(700, 100, 900, 100)
+				})
+				Title("Stock\ncontainers")
+			})
+			DeploymentView(Stock, "Production", "production", "Production deployment.", func() {
+				AddAll()
+			})
+			Styles(func() {
+				ElementStyle("External", func() {
+					Background("#999999")
+					Stroke("#666666")
+					Color("#000000")
+					Opacity(60)
+				})
+				ElementStyle("Robot", func() {
+					Shape(ShapeRobot)
+					Border(BorderDotted)
+				})
+				ElementStyle("Mobile", func() {
+					Shape(ShapeMobileDevicePortrait)
+				})
+				ElementStyle("Database", func() {
+					Shape(ShapeCylinder)
+					Background("#438dd5")
+				})
+				ElementStyle("Site", func() {
+					Border(BorderSolid)
+					Stroke("#000000")
+				})
+				RelationshipStyle("Booking", func() {
+					Solid()
+					FontSize(14)
+					Routing(RoutingOrthogonal)
+				})
+			})
+		})
+	})
+}
+
+func TestRender(t *testing.T) {
+	d := testutil.RunDSL(t, warehouse)
+	layouts := map[string]svg.Layout{
+		"context": {
+			elementID(d, "Picker"):  {X: 100, Y: 100},
+			elementID(d, "Robot"):   {X: 500, Y: 100},
+			elementID(d, "Stock"):   {X: 300, Y: 600},
+			elementID(d, "Carrier"): {X: 300, Y: 1100},
+		},
+	}
+	testutil.AssertGolden(t, "warehouse.drawio", string(Render(d, layouts)))
+}
+
+func TestRenderEdgeCases(t *testing.T) {
+	got := string(Render(testutil.RunDSL(t, warehouse), nil))
+	tests := []struct {
+		name, want string
+	}{
+		{"escaped quotes", `c4Description="Ships &#34;parcels&#34;."`},
+		{"escaped newline", `c4Description="Tracks goods.&#xa;Knows where they are."`},
+		{"escaped title", `name="Stock&#xa;containers"`},
+		{"style overrides palette", `fillColor=#999999;strokeColor=#666666;fontColor=#000000;opacity=60;textOpacity=60;`},
+		{"external robot", `shape=mxgraph.c4.person2;fillColor=#999999;strokeColor=#8A8A8A;fontColor=#FFFFFF;dashed=1;dashPattern=1 4;`},
+		{"solid boundary", `dashed=0;arcSize=20;fillColor=none;strokeColor=#000000;`},
+		{"group boundary", `c4Name="Storage" c4Type="Group"`},
+		{"relationship style", `fontSize=14;fontColor=#828282;strokeWidth=1;endFill=1;strokeColor=#828282;metaEdit=1;endSize=14;startSize=14;jumpStyle=arc;jumpSize=16;rounded=0;edgeStyle=orthogonalEdgeStyle;`},
+		{"vertices", `<mxPoint x="`},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			if !strings.Contains(got, tt.want) {
+				t.Errorf("document does not contain %q:\n%s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestRenderUniqueStyleKeys(t *testing.T) {
+	got := string(Render(testutil.RunDSL(t, warehouse), nil))
+	styles := regexp.MustCompile(`<mxCell style="([^"]*)"`).FindAllStringSubmatch(got, -1)
+	if len(styles) == 0 {
+		t.Fatal("no styled cell rendered")
+	}
+	for _, s := range styles {
+		seen := make(map[string]bool)
+		for _, p := range strings.Split(strings.TrimSuffix(s[1], ";"), ";") {
+			key := strings.SplitN(p, "=", 2)[0]
+			if seen[key] {
+				t.Errorf("style key %q defined more than once in %q", key, s[1])
+			}
+			seen[key] = true
+		}
+	}
+}
+
+// elementID returns the ID of the person or software system with the given
+// name.
+func elementID(d *mdl.Design, name string) string {
+	for _, p := range d.Model.People {
+		if p.Name == name {
+			return p.ID
+		}
+	}
+	for _, s := range d.Model.Systems {
+		if s.Name == name {
+			return s.ID
+		}
+	}
+	return ""
+}
@@ -0,0 +1,156 @@
+package dsl
+
+import (
+	"strings"
+
+	"goa.design/goa/v3/eval"
+	"goa.design/model/expr"
+)
+
+// Archetype defines a reusable element definition. Software systems,
+// containers, components, deployment nodes and infrastructure nodes created
+// from an archetype inherit its description, technology, tags, URL, properties
+// and perspectives and are tagged with the archetype name. Elements may
+// override the inherited values: an explicit description or technology takes
+// precedence and the element DSL runs after the archetype values are applied.
+//
+// Archetype is a top-level DSL function, it may also appear in a Design
+// expression. Archetypes are typically defined in a shared package and
+// imported by the packages that define the designs.
+//
+// Archetype takes 1 to 5 arguments. The first argument is the archetype name.
+// The name may be optionally followed by a description. If a description is
+// set then it may be followed by a technology. The name may also be followed
+// by another archetype in which case the new archetype extends it. Finally
+// Archetype may take a func() as last argument to define tags, URL, properties,
+// perspectives and shape.
+//
+// The valid syntax for Archetype is thus:
+//
+//    Archetype("<name>", "[description]", "[technology]", func())
+//
+//    Archetype("<name>", Archetype, "[description]", "[technology]", func())
+//
+// Archetype also accepts an archetype as sole argument in which case it
+// returns it. This makes it possible to write
+// Container("<name>", Archetype(Postgres)) to create an element from an
+// archetype.
+//
+// If the archetype defines a shape then a style with the archetype name as tag
+// and the given shape is added to the views unless the views already define a
+// shape for that tag.
+//
+// Example:
+//
+//    var Postgres = Archetype("Postgres database", "Stores data", "PostgreSQL", func() {
+//        Tag("Database")
+//        Prop("tier", "data")
+//        Shape(ShapeCylinder)
+//    })
+//
+//    var _ = Design(func() {
+//        SoftwareSystem("Shop", func() {
+//            Container("Orders DB", Archetype(Postgres))
+//            Container("Users DB", "Stores user profiles", Archetype(Postgres), func() {
+//                Prop("tier", "sensitive data")
+//            })
+//        })
+//    })
+//
+func Archetype(args ...interface{}) *expr.Archetype {
+	if len(args) == 0 {
+		eval.ReportError("Archetype: missing argument")
+		return nil
+	}
+	if a, ok := args[0].(*expr.Archetype); ok {
+		if len(args) > 1 {
+			eval.ReportError("Archetype: too many arguments")
+		}
+		return a
+	}
+	switch eval.Current().(type) {
+	case eval.TopExpr, *expr.Design:
+	default:
+		eval.IncompatibleDSL()
+		return nil
+	}
+	name, ok := args[0].(string)
+	if !ok {
+		eval.InvalidArgError("name or archetype", args[0])
+		return nil
+	}
+	if name == "" {
+		eval.ReportError("Archetype: name cannot be empty")
+		return nil
+	}
+	parent, rest := archetypeArg(args[1:])
+	description, technology, dsl, err := parseArgs(rest...)
+	if err != nil {
+		eval.ReportError("Archetype: " + err.Error())
+		return nil
+	}
+	a := &expr.Archetype{Name: name, Description: description, Technology: technology}
+	if parent != nil {
+		if a.Description == "" {
+			a.Description = parent.Description
+		}
+		if a.Technology == "" {
+			a.Technology = parent.Technology
+		}
+		a.Shape = parent.Shape
+		dsl = archetypeDSL(parent, dsl)
+	}
+	if dsl != nil {
+		eval.Execute(dsl, a)
+	}
+	return a
+}
+
+// archetypeArg returns the first archetype in args if any and the remaining
+// arguments.
+func archetypeArg(args []interface{}) (*expr.Archetype, []interface{}) {
+	for i, arg := range args {
+		if a, ok := arg.(*expr.Archetype); ok {
+			rest := append(append([]interface{}{}, args[:i]...), args[i+1:]...)
+			return a, rest
+		}
+	}
+	return nil, args
+}
+
+// archetypeDSL returns a DSL function that applies the tags, URL, properties
+// and perspectives of the given archetype to the current expression and then
+// runs dsl.
+func archetypeDSL(a *expr.Archetype, dsl func()) func() {
+	return func() {
+		tags := []string{a.Name}
+		for _, t := range strings.Split(a.Tags, ",") {
+			if t != "" {
+				tags = append(tags, t)
+			}
+		}
+		Tag(tags[0], tags[1:]...)
+		if a.URL != "" {
+			URL(a.URL)
+		}
+		for name, value := range a.Properties {
+			Prop(name, value)
+		}
+		for _, p := range a.Perspectives {
+			Perspective(p.Name, p.Description)
+		}
+		if dsl != nil {
+			dsl()
+		}
+	}
+}
+
+// useArchetype records that the given archetype is used to define elements.
+func useArchetype(a *expr.Archetype) {
+	for _, u := range expr.Root.Model.Archetypes {
+		if u == a {
+			return
+		}
+	}
+	expr.Root.Model.Archetypes = append(expr.Root.Model.Archetypes, a)
+}
@@ -0,0 +1,111 @@
+package dsl_test
+
+import (
+	"testing"
+
+	. "goa.design/model/dsl"
+	"goa.design/model/mdl"
+)
+
+func TestArchetype(t *testing.T) {
+	d, err := runDSL(t, func() {
+		var Postgres = Archetype("Postgres database", "Stores data.", "PostgreSQL", func() {
+			Tag("Database")
+			URL("https://www.postgresql.org")
+			Prop("tier", "data")
+			Perspective("Security", "Encrypted at rest.")
+			Shape(ShapeCylinder)
+		})
+		var Replica = Archetype("Replica", Postgres, func() {
+			Prop("replica", "true")
+		})
+		SoftwareSystem("Shop", func() {
+			Container("Orders DB", Archetype(Postgres))
+			Container("Users DB", "Stores user profiles.", "Aurora", Archetype(Postgres), func() {
+				URL("https://aws.amazon.com/rds/aurora")
+				Prop("tier", "sensitive data")
+				Perspective("Security", "Encrypted with customer keys.")
+			})
+			Container("Reports DB", Archetype(Replica))
+		})
+	})
+	if err != nil {
+		t.Fatalf("failed to run DSL: %s", err)
+	}
+	shop := system(t, d.Model, "Shop")
+	tests := []struct {
+		name, desc, tech, tags, url string
+		props                       map[string]string
+		security                    string
+	}{
+		{
+			name: "Orders DB", desc: "Stores data.", tech: "PostgreSQL",
+			tags: "Element,Container,Postgres database,Database", url: "https://www.postgresql.org",
+			props: map[string]string{"tier": "data"}, security: "Encrypted at rest.",
+		},
+		{
+			name: "Users DB", desc: "Stores user profiles.", tech: "Aurora",
+			tags: "Element,Container,Postgres database,Database", url: "https://aws.amazon.com/rds/aurora",
+			props: map[string]string{"tier": "sensitive data"}, security: "Encrypted with customer keys.",
+		},
+		{
+			name: "Reports DB", desc: "Stores data.", tech: "PostgreSQL",
+			tags: "Element,Container,Replica,Postgres database,Database", url: "https://www.postgresql.org",
+			props: map[string]string{"tier": "data", "replica": "true"}, security: "Encrypted at rest.",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			c := container(t, shop, tt.name)
+			if c.Description != tt.desc {
+				t.Errorf("got description %q, want %q", c.Description, tt.desc)
+			}
+			if c.Technology != tt.tech {
+				t.Errorf("got technology %q, want %q", c.Technology, tt.tech)
+			}
+			if c.Tags != tt.tags {
+				t.Errorf("got tags %q, want %q", c.Tags, tt.tags)
+			}
+			if c.URL != tt.url {
+				t.Errorf("got URL %q, want %q", c.URL, tt.url)
+			}
+			if len(c.Properties) != len(tt.props) {
+				t.Errorf("got properties %v, want %v", c.Properties, tt.props)
+			}
+			for k, v := range tt.props {
+				if c.Properties[k] != v {
+					t.Errorf("got property %s=%q, want %q", k, c.Properties[k], v)
+				}
+			}
+			if len(c.Perspectives) != 1 || c.Perspectives[0].Description != tt.security {
+				t.Errorf("got perspectives %v, want Security: %q", c.Perspectives, tt.security)
+			}
+		})
+	}
+
+	t.Run("shape style", func(t *testing.T) {
+		es := d.Views.Styles.ElementStyle("Element,Container,Postgres database")
+		if es == nil || es.Shape != mdl.ShapeCylinder {
+			t.Errorf("got style %+v, want cylinder shape for archetype tag", es)
+		}
+	})
+}
+
+func TestArchetypeErrors(t *testing.T) {
+	t.Run("empty name", func(t *testing.T) {
+		_, err := runDSL(t, func() {
+			Archetype("")
+		})
+		assertError(t, err, "Archetype: name cannot be empty")
+	})
+	t.Run("too many arguments", func(t *testing.T) {
+		_, err := runDSL(t, func() {
+			var a = Archetype("Service")
+			SoftwareSystem("Shop", func() {
+				Container("API", Archetype(a, "extra"))
+			})
+		})
+		assertError(t, err, "Archetype: too many arguments")
+	})
+}
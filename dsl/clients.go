@@ -0,0 +1,252 @@
+package dsl
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"goa.design/goa/v3/codegen"
+	goaexpr "goa.design/goa/v3/expr"
+)
+
+// clients maps the directory of a Go module and the Goa design to the names
+// of the Goa services whose clients are used by the implementation of each Goa
+// service defined in the module, see serviceClients.
+var clients = make(map[clientsKey]map[string][]string)
+
+// clientsKey is the key of the clients map.
+type clientsKey struct {
+	dir  string
+	root *goaexpr.RootExpr
+}
+
+// serviceClients returns the names of the Goa services whose clients are used
+// by the implementation of the given Goa service. The implementation is the
+// struct defined in the Go module containing the working directory that has
+// the methods of the Service interface generated by Goa. The clients are the
+// fields of the struct whose type is the Client struct or Service interface
+// generated for another service or one of its HTTP or gRPC transport clients.
+// serviceClients returns nil if the working directory is not in a Go module.
+func serviceClients(svc *goaexpr.ServiceExpr) []string {
+	dir := moduleDir()
+	if dir == "" {
+		return nil
+	}
+	key := clientsKey{dir, goaexpr.Root}
+	deps, ok := clients[key]
+	if !ok {
+		deps = parseClients(dir)
+		clients[key] = deps
+	}
+	return deps[svc.Name]
+}
+
+// parseClients parses the Go files of the module in dir and returns the names
+// of the Goa services whose clients are used by the implementation of each
+// Goa service indexed by service name. The code generated by Goa, the tests
+// and the vendor and testdata directories are ignored.
+func parseClients(dir string) map[string][]string {
+	deps := make(map[string][]string)
+	if len(goaexpr.Root.Services) == 0 {
+		return deps
+	}
+	filepath.Walk(dir, func(p string, info os.FileInfo, err error) error {
+		if err != nil || !info.IsDir() {
+			return nil
+		}
+		name := info.Name()
+		if p != dir {
+			if name == codegen.Gendir || name == "vendor" || name == "testdata" || strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_") {
+				return filepath.SkipDir
+			}
+			if _, err := os.Stat(filepath.Join(p, "go.mod")); err == nil {
+				return filepath.SkipDir
+			}
+		}
+		fset := token.NewFileSet()
+		pkgs, err := parser.ParseDir(fset, p, func(fi os.FileInfo) bool {
+			return !strings.HasSuffix(fi.Name(), "_test.go")
+		}, 0)
+		if err != nil {
+			return nil
+		}
+		for _, pkg := range pkgs {
+			addClients(deps, pkg)
+		}
+		return nil
+	})
+	return deps
+}
+
+// addClients adds the Goa services whose clients are used by the Goa service
+// implementations defined in pkg to deps.
+func addClients(deps map[string][]string, pkg *ast.Package) {
+	methods := make(map[string]map[string]bool)
+	for _, f := range pkg.Files {
+		for _, decl := range f.Decls {
+			fd, ok := decl.(*ast.FuncDecl)
+			if !ok || fd.Recv == nil || len(fd.Recv.List) == 0 {
+				continue
+			}
+			t := fd.Recv.List[0].Type
+			if s, ok := t.(*ast.StarExpr); ok {
+				t = s.X
+			}
+			if id, ok := t.(*ast.Ident); ok {
+				if methods[id.Name] == nil {
+					methods[id.Name] = make(map[string]bool)
+				}
+				methods[id.Name][fd.Name.Name] = true
+			}
+		}
+	}
+	for _, f := range pkg.Files {
+		imports := make(map[string]string)
+		for _, imp := range f.Imports {
+			p, err := strconv.Unquote(imp.Path.Value)
+			if err != nil {
+				continue
+			}
+			imports[importName(imp, p)] = p
+		}
+		ast.Inspect(f, func(n ast.Node) bool {
+			ts, ok := n.(*ast.TypeSpec)
+			if !ok {
+				return true
+			}
+			st, ok := ts.Type.(*ast.StructType)
+			if !ok {
+				return false
+			}
+			for _, svc := range goaexpr.Root.Services {
+				if !implements(methods[ts.Name.Name], svc) {
+					continue
+				}
+				for _, field := range st.Fields.List {
+					dep := client(field.Type, imports)
+					if dep == nil || dep == svc || contains(deps[svc.Name], dep.Name) {
+						continue
+					}
+					deps[svc.Name] = append(deps[svc.Name], dep.Name)
+				}
+			}
+			return false
+		})
+	}
+}
+
+// implements returns true if methods contains the methods of the Service
+// interface generated by Goa for svc.
+func implements(methods map[string]bool, svc *goaexpr.ServiceExpr) bool {
+	if len(svc.Methods) == 0 {
+		return false
+	}
+	for _, m := range svc.Methods {
+		if !methods[codegen.Goify(m.Name, true)] {
+			return false
+		}
+	}
+	return true
+}
+
+// client returns the Goa service whose Client struct, Service interface or
+// transport client is the type described by t or the type pointed to by t,
+// nil if there is none. imports maps the package names used in the file to
+// their import paths.
+func client(t ast.Expr, imports map[string]string) *goaexpr.ServiceExpr {
+	if s, ok := t.(*ast.StarExpr); ok {
+		t = s.X
+	}
+	sel, ok := t.(*ast.SelectorExpr)
+	if !ok {
+		return nil
+	}
+	id, ok := sel.X.(*ast.Ident)
+	if !ok {
+		return nil
+	}
+	p, name := imports[id.Name], sel.Sel.Name
+	if svc := genService(p); svc != nil && (name == "Client" || name == "Service") {
+		return svc
+	}
+	if svc := genClient(p); svc != nil && name == "Client" {
+		return svc
+	}
+	return nil
+}
+
+// importName returns the name used to refer to the package imported by imp
+// with import path p.
+func importName(imp *ast.ImportSpec, p string) string {
+	if imp.Name != nil {
+		return imp.Name.Name
+	}
+	if svc := genService(p); svc != nil {
+		return strings.ToLower(codegen.Goify(svc.Name, false))
+	}
+	return path.Base(p)
+}
+
+// genService returns the Goa service whose package is generated at the given
+// import path, nil if there is none.
+func genService(p string) *goaexpr.ServiceExpr {
+	for _, svc := range goaexpr.Root.Services {
+		if strings.HasSuffix(p, "/"+codegen.Gendir+"/"+genDir(svc)) {
+			return svc
+		}
+	}
+	return nil
+}
+
+// genClient returns the Goa service whose HTTP or gRPC client package is
+// generated at the given import path, nil if there is none.
+func genClient(p string) *goaexpr.ServiceExpr {
+	for _, svc := range goaexpr.Root.Services {
+		for _, transport := range []string{"http", "grpc"} {
+			if strings.HasSuffix(p, "/"+codegen.Gendir+"/"+transport+"/"+genDir(svc)+"/client") {
+				return svc
+			}
+		}
+	}
+	return nil
+}
+
+// genDir returns the name of the directory containing the code generated by
+// Goa for the given service.
+func genDir(svc *goaexpr.ServiceExpr) string {
+	return codegen.SnakeCase(codegen.Goify(svc.Name, false))
+}
+
+// moduleDir returns the root directory of the Go module containing the
+// working directory, the empty string if there is none.
+func moduleDir() string {
+	dir, err := os.Getwd()
+	if err != nil {
+		return ""
+	}
+	for {
+		if _, err := os.Stat(filepath.Join(dir, "go.mod")); err == nil {
+			return dir
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// contains returns true if vals contains val.
+func contains(vals []string, val string) bool {
+	for _, v := range vals {
+		if v == val {
+			return true
+		}
+	}
+	return false
+}
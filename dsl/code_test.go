@@ -0,0 +1,108 @@
+package dsl_test
+
+import (
+	"testing"
+
+	. "goa.design/model/dsl"
+	"goa.design/model/mdl"
+)
+
+func TestCodeView(t *testing.T) {
+	d, err := runDSL(t, func() {
+		Person("Developer")
+		SoftwareSystem("Payments")
+		SoftwareSystem("Shop", func() {
+			Container("Worker")
+			Container("API", func() {
+				Component("Store", "Persists orders.", "Go package", func() {
+					CodeElement("Store", "Store interface.", "Go interface")
+					CodeElement("pgStore", "Postgres store.", "Go struct", func() {
+						Uses("Store", "Implements")
+					})
+				})
+				Component("Handler", func() {
+					CodeElement("handler", "HTTP handler.", "Go struct")
+				})
+			})
+		})
+		Views(func() {
+			CodeView("Shop/API/Store", "all", "All code elements.", func() {
+				AddAll()
+			})
+			CodeView("Shop/API/Store", "default", "Default code elements.", func() {
+				AddDefault()
+			})
+		})
+	})
+	if err != nil {
+		t.Fatalf("failed to run DSL: %s", err)
+	}
+	shop := system(t, d.Model, "Shop")
+	api := container(t, shop, "API")
+	store := component(t, api, "Store")
+	if len(store.CodeElements) != 2 {
+		t.Fatalf("got %d code elements, want 2", len(store.CodeElements))
+	}
+	iface, impl := store.CodeElements[0], store.CodeElements[1]
+	if iface.Technology != "Go interface" || iface.Tags != "Element,Code Element" {
+		t.Errorf("got code element technology %q and tags %q", iface.Technology, iface.Tags)
+	}
+	if len(impl.Relationships) != 1 || impl.Relationships[0].DestinationID != iface.ID {
+		t.Errorf("got relationships %v, want relationship to %q", impl.Relationships, iface.ID)
+	}
+	if len(d.Views.CodeViews) != 2 {
+		t.Fatalf("got %d code views, want 2", len(d.Views.CodeViews))
+	}
+
+	tests := []struct {
+		key  string
+		want []string
+	}{
+		{"all", []string{
+			d.Model.People[0].ID,
+			system(t, d.Model, "Payments").ID,
+			shop.ID,
+			container(t, shop, "Worker").ID,
+			api.ID,
+			component(t, api, "Handler").ID,
+			iface.ID,
+			impl.ID,
+		}},
+		{"default", []string{iface.ID, impl.ID}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			var v *mdl.CodeView
+			for _, cv := range d.Views.CodeViews {
+				if cv.Key == tt.key {
+					v = cv
+				}
+			}
+			if v == nil {
+				t.Fatalf("code view %q not found", tt.key)
+			}
+			if v.ComponentID != store.ID {
+				t.Errorf("got component ID %q, want %q", v.ComponentID, store.ID)
+			}
+			ids := make(map[string]bool)
+			for _, ev := range v.ElementViews {
+				ids[ev.ID] = true
+			}
+			if len(ids) != len(tt.want) {
+				t.Errorf("got %d elements, want %d", len(ids), len(tt.want))
+			}
+			for _, id := range tt.want {
+				if !ids[id] {
+					t.Errorf("element %q missing", id)
+				}
+			}
+			// The code elements of other components are out of scope.
+			if ids[component(t, api, "Handler").CodeElements[0].ID] {
+				t.Errorf("code element of another component added to view")
+			}
+			if ids[store.ID] {
+				t.Errorf("component of view added to view")
+			}
+		})
+	}
+}
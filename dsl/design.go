@@ -153,8 +153,9 @@ func AddImpliedRelationships() {
 // Tag defines a set of tags on the given element. Tags are used in views to
 // identify group of elements that should be rendered together for example.
 //
-// Tag may appear in Person, SoftwareSystem, Container, Component,
-// DeploymentNode, InfrastructureNode, ContainerInstance.
+// Tag may appear in Person, SoftwareSystem, Container, Component, CodeElement,
+// DeploymentNode, InfrastructureNode, ContainerInstance, Archetype or in the
+// DSL function of a relationship.
 //
 // Tag accepts the set of tag values as argument. Tag may appear multiple times
 // in the same expression in which case the tags accumulate.
@@ -175,16 +176,19 @@ func Tag(first string, t ...string) {
 		e.GetElement().MergeTags(t...)
 	case *expr.Relationship:
 		e.MergeTags(t...)
+	case *expr.Archetype:
+		e.MergeTags(t...)
 	default:
 		eval.IncompatibleDSL()
 	}
 }
 
-// URL where more information about this element can be found.
+// URL where more information about this element or relationship can be found.
 // Or URL of health check when used within a HealthCheck expression.
 //
-// URL may appear in Person, SoftwareSystem, Container, Component,
-// DeploymentNode, InfrastructureNode or HealthCheck.
+// URL may appear in Person, SoftwareSystem, Container, Component, CodeElement,
+// DeploymentNode, InfrastructureNode, HealthCheck, Archetype or in the DSL
+// function of a relationship (Uses, Delivers or InteractsWith).
 //
 // URL takes exactly one argument: a valid URL.
 //
@@ -209,12 +213,18 @@ func URL(u string) {
 		e.URL = u
 	case *expr.Component:
 		e.URL = u
+	case *expr.CodeElement:
+		e.URL = u
 	case *expr.DeploymentNode:
 		e.URL = u
 	case *expr.InfrastructureNode:
 		e.URL = u
 	case *expr.HealthCheck:
 		e.URL = u
+	case *expr.Relationship:
+		e.URL = u
+	case *expr.Archetype:
+		e.URL = u
 	default:
 		eval.IncompatibleDSL()
 	}
@@ -245,10 +255,12 @@ func External() {
 }
 
 // Prop defines arbitrary key-value pairs. They are shown in the diagram
-// tooltip and can be used to store metadata (e.g. team name).
+// tooltip and can be used to store metadata (e.g. team name or data
+// classification).
 //
 // Prop must appear in Person, SoftwareSystem, Container, Component,
-// DeploymentNode, InfrastructureNode or ContainerInstance.
+// CodeElement, DeploymentNode, InfrastructureNode, ContainerInstance, Archetype
+// or in the DSL function of a relationship (Uses, Delivers or InteractsWith).
 //
 // Prop accepts two arguments: the name and value of a property.
 //
@@ -257,6 +269,9 @@ func External() {
 //    var _ = Design(func() {
 //        SoftwareSystem("MySystem", func() {
 //           Prop("name", "value")
+//           Uses("OtherSystem", "Sends data to", func() {
+//               Prop("classification", "confidential")
+//           })
 //        })
 //    })
 //
@@ -283,6 +298,11 @@ func Prop(name, value string) {
 			e.Properties = make(map[string]string)
 		}
 		props = e.Properties
+	case *expr.CodeElement:
+		if e.Properties == nil {
+			e.Properties = make(map[string]string)
+		}
+		props = e.Properties
 	case *expr.DeploymentNode:
 		if e.Properties == nil {
 			e.Properties = make(map[string]string)
@@ -298,9 +318,64 @@ func Prop(name, value string) {
 			e.Properties = make(map[string]string)
 		}
 		props = e.Properties
+	case *expr.Relationship:
+		if e.Properties == nil {
+			e.Properties = make(map[string]string)
+		}
+		props = e.Properties
+	case *expr.Archetype:
+		if e.Properties == nil {
+			e.Properties = make(map[string]string)
+		}
+		props = e.Properties
 	default:
 		eval.IncompatibleDSL()
 		return
 	}
 	props[name] = value
 }
+
+// Perspective describes the element or relationship from a given point of
+// view such as security, operations or data classification. Perspectives are
+// shown in the diagram tooltip.
+//
+// Perspective must appear in Person, SoftwareSystem, Container, Component,
+// CodeElement, DeploymentNode, InfrastructureNode, ContainerInstance, Archetype
+// or in the DSL function of a relationship (Uses, Delivers or InteractsWith).
+//
+// Perspective accepts two arguments: the name of the perspective and the
+// description of the element or relationship from that perspective. Defining
+// the same perspective twice overrides the description.
+//
+// Example:
+//
+//    var _ = Design(func() {
+//        SoftwareSystem("MySystem", func() {
+//           Perspective("Operations", "Runs on Kubernetes")
+//           Uses("OtherSystem", "Sends data to", "gRPC", func() {
+//               Perspective("Security", "mTLS required")
+//           })
+//        })
+//    })
+//
+func Perspective(name, description string) {
+	var ps *[]*expr.Perspective
+	switch e := eval.Current().(type) {
+	case expr.ElementHolder:
+		ps = &e.GetElement().Perspectives
+	case *expr.Relationship:
+		ps = &e.Perspectives
+	case *expr.Archetype:
+		ps = &e.Perspectives
+	default:
+		eval.IncompatibleDSL()
+		return
+	}
+	for _, p := range *ps {
+		if p.Name == name {
+			p.Description = description
+			return
+		}
+	}
+	*ps = append(*ps, &expr.Perspective{Name: name, Description: description})
+}
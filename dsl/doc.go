@@ -20,47 +20,83 @@ general shape of the DSL is:
     Design                              Design
     ├── Version                         └── Views
     ├── Enterprise                          ├── SystemLandscapeView
-    ├── Person                              │   ├── Title
-    │   ├── Tag                             │   ├── AddDefault
-    │   ├── URL                             │   ├── Add
-    │   ├── External                        │   ├── AddAll
-    │   ├── Prop                            │   ├── AddNeighbors
-    │   ├── Uses                            │   ├── Link
-    │   └── InteractsWith                   │   ├── Remove
-    ├── SoftwareSystem                      │   ├── RemoveTagged
-    │   ├── Tag                             │   ├── RemoveUnreachable
-    │   ├── URL                             │   ├── RemoveUnrelated
-    │   ├── External                        │   ├── Unlink
-    │   ├── Prop                            │   ├── AutoLayout
-    │   ├── Uses                            │   ├── AnimationStep
-    │   ├── Delivers                        │   ├── PaperSize
-    │   └─── Container                      │   └── EnterpriseBoundaryVisible
-    │       ├── Tag                         ├── SystemContextView
-    │       ├── URL                         │   └──  ... (same as SystemLandsapeView)
-    │       ├── Prop                        ├── ContainerView
-    │       ├── Uses                        │   ├── AddContainers
-    │       ├── Delivers                    │   ├── AddInfluencers
-    │       └── Component                   │   ├── SystemBoundariesVisible
-    │           ├── Tag                     │   └── ... (same as SystemLandscapeView*)
-    │           ├── URL                     ├── ComponentView
-    │           ├── Prop                    │   ├── AddContainers
-    │           ├── Uses                    │   ├── AddComponents
-    │           └── Delivers                │   ├── ContainerBoundariesVisible
-    └── DeploymentEnvironment               │   └── ... (same as SystemLandscapeView*)
-        ├── DeploymentNode                  ├── FilteredView
-        │   ├── Tag                         │   ├── FilterTag
-        │   ├── Instances                   │   └── Exclude
-        │   ├── URL                         ├── DynamicView
-        │   ├── Prop                        │   ├── Title
-        │   └── DeploymentNode              │   ├── AutoLayout
-        │       └── ...                     │   ├── PaperSize
-        ├── InfrastructureNode              │   ├── Add
-        │   ├── Tag                         ├── DeploymentView
-        │   ├── URL                         │   └── ... (same as SystemLandscapeView*)
-        │   └── Prop                        └── Style
-        └── ContainerInstance                   ├── ElementStyle
-            ├── Tag                             └── RelationshipStyle
+    ├── RequireOwners                       │   ├── Title
+    ├── Team                                │   ├── AddDefault
+    │   ├── Contact                         │   ├── Add
+    │   ├── Channel                         │   ├── AddAll
+    │   └── OnCall                          │   ├── AddNeighbors
+    ├── Archetype                           │   ├── Link
+    │   ├── Tag                             │   ├── Remove
+    │   ├── URL                             │   ├── RemoveTagged
+    │   ├── Prop                            │   ├── OwnedBy
+    │   ├── Perspective                     │   ├── RemoveUnreachable
+    │   └── Shape                           │   ├── RemoveUnrelated
+    ├── Group                               │   ├── Unlink
+    ├── Person                              │   ├── AutoLayout
+    │   ├── Tag                             │   ├── AnimationStep
+    │   ├── Owner                           │   ├── PaperSize
+    │   ├── URL                             │   └── EnterpriseBoundaryVisible
+    │   ├── External                        ├── SystemContextView
+    │   ├── Prop                            │   └──  ... (same as SystemLandsapeView)
+    │   ├── Perspective                     ├── ContainerView
+    │   ├── Uses                            │   ├── AddContainers
+    │   └── InteractsWith                   │   ├── AddInfluencers
+    ├── SoftwareSystem                      │   ├── SystemBoundariesVisible
+    │   ├── Tag                             │   └── ... (same as SystemLandscapeView*)
+    │   ├── Owner                           ├── ComponentView
+    │   ├── URL                             │   ├── AddContainers
+    │   ├── External                        │   ├── AddComponents
+    │   ├── Prop                            │   ├── ContainerBoundariesVisible
+    │   ├── Perspective                     │   └── ... (same as SystemLandscapeView*)
+    │   ├── Uses                            ├── CodeView
+    │   ├── Delivers                        │   ├── AddContainers
+    │   ├── Group                           │   ├── AddComponents
+    │   └─── Container                      │   ├── AddCodeElements
+    │       ├── Tag                         │   └── ... (same as SystemLandscapeView*)
+    │       ├── Owner                       ├── FilteredView
+    │       ├── URL                         │   ├── FilterTag
+    │       ├── Prop                        │   └── Exclude
+    │       ├── Perspective                 ├── DynamicView
+    │       ├── Uses                        │   ├── Title
+    │       ├── Delivers                    │   ├── AutoLayout
+    │       ├── Group                       │   ├── PaperSize
+    │       └── Component                   │   ├── Add
+    │           ├── Tag                     ├── DeploymentView
+    │           ├── Owner                   │   └── ... (same as SystemLandscapeView*)
+    │           ├── URL                     └── Style
+    │           ├── Prop                        ├── ElementStyle
+    │           ├── Perspective                 └── RelationshipStyle
+    │           ├── Uses
+    │           ├── Delivers
+    │           └── CodeElement
+    │               ├── Tag
+    │               ├── Owner
+    │               ├── URL
+    │               ├── Prop
+    │               ├── Perspective
+    │               ├── Uses
+    │               └── Delivers
+    └── DeploymentEnvironment
+        ├── DeploymentNode
+        │   ├── Tag
+        │   ├── Owner
+        │   ├── Instances
+        │   ├── URL
+        │   ├── Prop
+        │   ├── Perspective
+        │   └── DeploymentNode
+        │       └── ...
+        ├── InfrastructureNode
+        │   ├── Tag
+        │   ├── Owner
+        │   ├── URL
+        │   ├── Prop
+        │   └── Perspective
+        └── ContainerInstance
+            ├── Tag
+            ├── Owner
             ├── HealthCheck
-            └── Prop                        (* minus EnterpriseBoundaryVisible)
+            ├── Prop
+            └── Perspective                 (* minus EnterpriseBoundaryVisible)
 */
 package dsl
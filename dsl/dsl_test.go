@@ -0,0 +1,84 @@
+package dsl_test
+
+import (
+	"strings"
+	"testing"
+
+	"goa.design/goa/v3/eval"
+	goaexpr "goa.design/goa/v3/expr"
+	. "goa.design/model/dsl"
+	"goa.design/model/expr"
+	"goa.design/model/mdl"
+)
+
+// runDSL resets the DSL engine, evaluates a design defined with the given DSL
+// and returns the corresponding model.
+func runDSL(t *testing.T, dsl func()) (*mdl.Design, error) {
+	t.Helper()
+	reset(t)
+	Design("Test", dsl)
+	return mdl.RunDSL()
+}
+
+// reset resets the DSL engine and registers the Goa and model roots.
+func reset(t *testing.T) {
+	t.Helper()
+	eval.Reset()
+	goaexpr.Root = &goaexpr.RootExpr{GeneratedTypes: &goaexpr.GeneratedRoot{}}
+	expr.Root = &expr.Design{Model: &expr.Model{}, Views: &expr.Views{}}
+	expr.Registry = make(map[string]interface{})
+	for _, r := range []eval.Root{goaexpr.Root, goaexpr.Root.GeneratedTypes, expr.Root} {
+		if err := eval.Register(r); err != nil {
+			t.Fatalf("failed to register root: %s", err)
+		}
+	}
+}
+
+// system returns the software system with the given name.
+func system(t *testing.T, m *mdl.Model, name string) *mdl.SoftwareSystem {
+	t.Helper()
+	for _, s := range m.Systems {
+		if s.Name == name {
+			return s
+		}
+	}
+	t.Fatalf("software system %q not found", name)
+	return nil
+}
+
+// container returns the container with the given name of the software system
+// s.
+func container(t *testing.T, s *mdl.SoftwareSystem, name string) *mdl.Container {
+	t.Helper()
+	for _, c := range s.Containers {
+		if c.Name == name {
+			return c
+		}
+	}
+	t.Fatalf("container %q not found in %q", name, s.Name)
+	return nil
+}
+
+// assertError fails the test if err is nil or if its message does not
+// contain want.
+func assertError(t *testing.T, err error, want string) {
+	t.Helper()
+	if err == nil {
+		t.Fatalf("got no error, want error containing %q", want)
+	}
+	if !strings.Contains(err.Error(), want) {
+		t.Errorf("got error %q, want error containing %q", err.Error(), want)
+	}
+}
+
+// component returns the component with the given name of the container c.
+func component(t *testing.T, c *mdl.Container, name string) *mdl.Component {
+	t.Helper()
+	for _, cmp := range c.Components {
+		if cmp.Name == name {
+			return cmp
+		}
+	}
+	t.Fatalf("component %q not found in %q", name, c.Name)
+	return nil
+}
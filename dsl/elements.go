@@ -11,7 +11,7 @@ import (
 
 // SoftwareSystem defines a software system.
 //
-// SoftwareSystem must appear in a Design expression.
+// SoftwareSystem must appear in a Design or Group expression.
 //
 // Software system takes 1 to 3 arguments. The first argument is the software
 // system name and the last argument a function that contains the expressions
@@ -41,7 +41,8 @@ import (
 //    })
 //
 func SoftwareSystem(name string, args ...interface{}) *expr.SoftwareSystem {
-	w, ok := eval.Current().(*expr.Design)
+	scope, group := groupScope()
+	w, ok := scope.(*expr.Design)
 	if !ok {
 		eval.IncompatibleDSL()
 		return nil
@@ -59,6 +60,7 @@ func SoftwareSystem(name string, args ...interface{}) *expr.SoftwareSystem {
 			DSLFunc:     dsl,
 			Name:        name,
 			Description: description,
+			Group:       group,
 		},
 	}
 	return w.Model.AddSystem(s)
@@ -66,7 +68,7 @@ func SoftwareSystem(name string, args ...interface{}) *expr.SoftwareSystem {
 
 // Container defines a container.
 //
-// Container must appear in a SoftwareSystem expression.
+// Container must appear in a SoftwareSystem or Group expression.
 //
 // Container takes 1 to 4 arguments. The first argument is the container name.
 // The name may be optionally followed by a description. If a description is set
@@ -88,9 +90,17 @@ func SoftwareSystem(name string, args ...interface{}) *expr.SoftwareSystem {
 //
 //    Container("<name>", "[description]", "[technology]", func())
 //
+// The arguments may also include an archetype in which case the container
+// inherits its definition, see Archetype:
+//
+//    Container("<name>", Archetype(Archetype))
+//
+//    Container("<name>", "[description]", Archetype(Archetype), func())
+//
 // Container also accepts a Goa service as argument in which case the name and
 // description are taken from the service and the technology is set to "Go and
-// Goa v3"
+// Goa v3". The container uses the services whose clients are used by the
+// service implementation, see ServiceComponent for details.
 //
 //    Container(Service)
 //
@@ -115,7 +125,8 @@ func SoftwareSystem(name string, args ...interface{}) *expr.SoftwareSystem {
 //    })
 //
 func Container(args ...interface{}) *expr.Container {
-	system, ok := eval.Current().(*expr.SoftwareSystem)
+	scope, group := groupScope()
+	system, ok := scope.(*expr.SoftwareSystem)
 	if !ok {
 		eval.IncompatibleDSL()
 		return nil
@@ -140,6 +151,10 @@ func Container(args ...interface{}) *expr.Container {
 			return nil
 		}
 	case *goaexpr.ServiceExpr:
+		if a == nil {
+			eval.ReportError("Container: service is nil")
+			return nil
+		}
 		name = a.Name
 		description = a.Description
 		technology = "Go and Goa v3"
@@ -153,6 +168,7 @@ func Container(args ...interface{}) *expr.Container {
 		if len(args) > 2 {
 			eval.ReportError("Container: too many arguments")
 		}
+		dsl = serviceDSL(a, dsl)
 	default:
 		eval.InvalidArgError("name or Goa service", args[0])
 	}
@@ -167,6 +183,7 @@ func Container(args ...interface{}) *expr.Container {
 			Name:        name,
 			Description: description,
 			Technology:  technology,
+			Group:       group,
 		},
 		System: system,
 	}
@@ -175,7 +192,7 @@ func Container(args ...interface{}) *expr.Container {
 
 // Component defines a component.
 //
-// Component must appear in a Container expression.
+// Component must appear in a Container or Group expression.
 //
 // Component takes 1 to 4 arguments. The first argument is the component name.
 // The name may be optionally followed by a description. If a description is set
@@ -197,6 +214,13 @@ func Container(args ...interface{}) *expr.Container {
 //
 //    Component("<name>", "[description]", "[technology]", func())
 //
+// The arguments may also include an archetype in which case the component
+// inherits its definition, see Archetype:
+//
+//    Component("<name>", Archetype(Archetype))
+//
+// Use ServiceComponent to define a component from a Goa service.
+//
 // Example:
 //
 //    var _ = Design(func() {
@@ -213,33 +237,248 @@ func Container(args ...interface{}) *expr.Container {
 //    })
 //
 func Component(name string, args ...interface{}) *expr.Component {
-	container, ok := eval.Current().(*expr.Container)
+	description, technology, dsl, err := parseElementArgs(args...)
+	if err != nil {
+		eval.ReportError("Component: " + err.Error())
+		return nil
+	}
+	return component("Component", name, description, technology, dsl)
+}
+
+// ServiceComponent defines a component from a Goa service. The name and
+// description of the component are taken from the service and the technology
+// is set to "Go and Goa v3".
+//
+// ServiceComponent must appear in a Container or Group expression.
+//
+// ServiceComponent takes 1 or 2 arguments. The first argument is the Goa
+// service. ServiceComponent may take a func() as second argument to define
+// additional properties of the component, see Component.
+//
+// The valid syntax for ServiceComponent is thus:
+//
+//    ServiceComponent(Service)
+//
+//    ServiceComponent(Service, func())
+//
+// The component uses the Goa services whose clients are used by the
+// implementation of the service. The implementation is the type defined in
+// the Go module of the design that implements the Service interface generated
+// by Goa, the clients are the fields of that type whose type is the Client
+// struct or Service interface generated for another service or one of its
+// HTTP or gRPC transport clients. The services must be defined as sibling
+// components (or containers when using Container). The technology of the
+// relationships is the transport of the service used ("HTTP", "gRPC" or "HTTP
+// and gRPC"). Relationships to other services may also be defined explicitly
+// with Uses.
+//
+// Example:
+//
+//    // Service implementation
+//    type orderssrvc struct {
+//        inventory *inventory.Client // Goa client of the inventory service
+//    }
+//
+//    // Model design
+//    Container("Backend", func() {
+//        ServiceComponent(Orders) // Uses Inventory
+//        ServiceComponent(Inventory)
+//        ServiceComponent(Payments, func() {
+//            Tag("PCI")
+//        })
+//    })
+//
+func ServiceComponent(svc *goaexpr.ServiceExpr, args ...interface{}) *expr.Component {
+	if svc == nil {
+		eval.ReportError("ServiceComponent: service is nil")
+		return nil
+	}
+	var dsl func()
+	if len(args) > 0 {
+		if d, ok := args[0].(func()); ok {
+			dsl = d
+		} else {
+			eval.InvalidArgError("DSL function", args[0])
+		}
+	}
+	if len(args) > 1 {
+		eval.ReportError("ServiceComponent: too many arguments")
+	}
+	return component("ServiceComponent", svc.Name, svc.Description, "Go and Goa v3", serviceDSL(svc, dsl))
+}
+
+// component adds a component to the current container. fn is the name of the
+// DSL function used in error messages.
+func component(fn, name, description, technology string, dsl func()) *expr.Component {
+	scope, group := groupScope()
+	container, ok := scope.(*expr.Container)
+	if !ok {
+		eval.IncompatibleDSL()
+		return nil
+	}
+	if strings.Contains(name, "/") {
+		eval.ReportError(fn + ": name cannot include slashes")
+	}
+	c := &expr.Component{
+		Element: &expr.Element{
+			Name:        name,
+			Description: description,
+			Technology:  technology,
+			Group:       group,
+			DSLFunc:     dsl,
+		},
+		Container: container,
+	}
+	return container.AddComponent(c)
+}
+
+// CodeElement defines a code element, that is a type, interface, function or
+// any other piece of code that implements a component. Code elements make it
+// possible to describe the fourth level of the C4 model.
+//
+// CodeElement must appear in a Component expression.
+//
+// CodeElement takes 1 to 4 arguments. The first argument is the code element
+// name. The name may be optionally followed by a description. If a description
+// is set then it may be followed by the technology details of the code element
+// (e.g. "Go interface"). Finally CodeElement may take a func() as last argument
+// to define additional properties of the code element.
+//
+// The valid syntax for CodeElement is thus:
+//
+//    CodeElement("<name>")
+//
+//    CodeElement("<name>", "[description]")
+//
+//    CodeElement("<name>", "[description]", "[technology]")
+//
+//    CodeElement("<name>", func())
+//
+//    CodeElement("<name>", "[description]", func())
+//
+//    CodeElement("<name>", "[description]", "[technology]", func())
+//
+// Example:
+//
+//    var _ = Design(func() {
+//        SoftwareSystem("My system", func() {
+//            Container("My container", func() {
+//                Component("Store", "Persists orders", "Go package", func() {
+//                    CodeElement("Store", "Store interface", "Go interface")
+//                    CodeElement("pgStore", "Postgres implementation", "Go struct", func() {
+//                        Uses("Store", "Implements")
+//                        URL("https://pkg.go.dev/goa.design/model")
+//                    })
+//                })
+//            })
+//        })
+//    })
+//
+func CodeElement(name string, args ...interface{}) *expr.CodeElement {
+	component, ok := eval.Current().(*expr.Component)
 	if !ok {
 		eval.IncompatibleDSL()
 		return nil
 	}
 	if strings.Contains(name, "/") {
-		eval.ReportError("Component: name cannot include slashes")
+		eval.ReportError("CodeElement: name cannot include slashes")
 	}
 	description, technology, dsl, err := parseElementArgs(args...)
 	if err != nil {
-		eval.ReportError("Component: " + err.Error())
+		eval.ReportError("CodeElement: " + err.Error())
 		return nil
 	}
-	c := &expr.Component{
+	c := &expr.CodeElement{
 		Element: &expr.Element{
 			Name:        name,
 			Description: description,
 			Technology:  technology,
 			DSLFunc:     dsl,
 		},
-		Container: container,
+		Component: component,
 	}
-	return container.AddComponent(c)
+	return component.AddCodeElement(c)
+}
+
+// Group defines a named group of elements. Groups make it possible to
+// cluster elements that belong together (e.g. a business domain or legacy
+// systems) without introducing an additional software system or container.
+// Views render the elements of a group inside a dashed boundary.
+//
+// Group must appear in a Design, SoftwareSystem or Container expression.
+// Groups defined in a Design may contain people and software systems, groups
+// defined in a SoftwareSystem may contain containers and groups defined in a
+// Container may contain components. Groups cannot be nested.
+//
+// Group takes two arguments: the name of the group and a function that
+// defines the elements of the group.
+//
+// Example:
+//
+//    var _ = Design(func() {
+//        Group("Payments domain", func() {
+//            SoftwareSystem("Payments")
+//            SoftwareSystem("Billing", func() {
+//                Group("Frontend", func() {
+//                    Container("Web App")
+//                    Container("Mobile App")
+//                })
+//                Container("Database")
+//            })
+//        })
+//        SoftwareSystem("Reporting")
+//    })
+//
+func Group(name string, dsl func()) {
+	switch eval.Current().(type) {
+	case *expr.Design, *expr.SoftwareSystem, *expr.Container:
+	case *expr.Group:
+		eval.ReportError("Group: groups cannot be nested")
+		return
+	default:
+		eval.IncompatibleDSL()
+		return
+	}
+	if name == "" {
+		eval.ReportError("Group: name cannot be empty")
+		return
+	}
+	eval.Execute(dsl, &expr.Group{Name: name, Parent: eval.Current()})
+}
+
+// groupScope returns the expression elements defined in the current context
+// belong to together with the name of the enclosing group if any.
+func groupScope() (eval.Expression, string) {
+	if g, ok := eval.Current().(*expr.Group); ok {
+		return g.Parent, g.Name
+	}
+	return eval.Current(), ""
+}
+
+// parseElementArgs is a helper function that parses the given element DSL
+// arguments. The arguments may include an archetype in which case the
+// description and technology default to the archetype's and the returned DSL
+// applies the archetype before running the element DSL. See parseArgs for the
+// accepted syntax.
+func parseElementArgs(args ...interface{}) (description, technology string, dsl func(), err error) {
+	a, rest := archetypeArg(args)
+	description, technology, dsl, err = parseArgs(rest...)
+	if err != nil || a == nil {
+		return
+	}
+	useArchetype(a)
+	if description == "" {
+		description = a.Description
+	}
+	if technology == "" {
+		technology = a.Technology
+	}
+	dsl = archetypeDSL(a, dsl)
+	return
 }
 
-// parseElement is a helper function that parses the given element DSL
-// arguments. Accepted syntax are:
+// parseArgs is a helper function that parses the given element or archetype
+// DSL arguments. Accepted syntax are:
 //
 //     "[decription]"
 //     "[description]", "[technology]"
@@ -247,7 +486,7 @@ func Component(name string, args ...interface{}) *expr.Component {
 //     "[description]", func()
 //     "[description]", "[technology]", func()
 //
-func parseElementArgs(args ...interface{}) (description, technology string, dsl func(), err error) {
+func parseArgs(args ...interface{}) (description, technology string, dsl func(), err error) {
 	if len(args) == 0 {
 		return
 	}
@@ -292,3 +531,51 @@ func parseElementArgs(args ...interface{}) (description, technology string, dsl
 	}
 	return
 }
+
+// serviceDSL returns a DSL function that runs dsl and adds the relationships
+// to the Goa services whose clients are used by the implementation of the
+// given Goa service (see serviceClients) that are not already defined by dsl.
+func serviceDSL(svc *goaexpr.ServiceExpr, dsl func()) func() {
+	return func() {
+		if dsl != nil {
+			dsl()
+		}
+		var src *expr.Element
+		switch e := eval.Current().(type) {
+		case *expr.Container:
+			src = e.Element
+		case *expr.Component:
+			src = e.Element
+		default:
+			return
+		}
+	deps:
+		for _, dep := range serviceClients(svc) {
+			for _, r := range src.Relationships {
+				if r.DestinationPath == dep || r.Destination != nil && r.Destination.Name == dep {
+					continue deps
+				}
+			}
+			if err := uses(src, goaexpr.Root.Service(dep), "Uses"); err != nil {
+				eval.ReportError(err.Error())
+			}
+		}
+	}
+}
+
+// transports returns the transports exposed by the given Goa service: "HTTP",
+// "gRPC" or "HTTP and gRPC".
+func transports(svc *goaexpr.ServiceExpr) string {
+	api := goaexpr.Root.API
+	if api == nil {
+		return ""
+	}
+	var ts []string
+	if api.HTTP != nil && api.HTTP.Service(svc.Name) != nil {
+		ts = append(ts, "HTTP")
+	}
+	if api.GRPC != nil && api.GRPC.Service(svc.Name) != nil {
+		ts = append(ts, "gRPC")
+	}
+	return strings.Join(ts, " and ")
+}
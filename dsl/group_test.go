@@ -0,0 +1,94 @@
+package dsl_test
+
+import (
+	"testing"
+
+	. "goa.design/model/dsl"
+)
+
+func TestGroup(t *testing.T) {
+	d, err := runDSL(t, func() {
+		Group("Payments domain", func() {
+			SoftwareSystem("Payments")
+			Person("Accountant")
+		})
+		SoftwareSystem("Shop", func() {
+			Group("Frontend", func() {
+				Container("Web")
+				Container("Mobile")
+			})
+			Container("API", func() {
+				Group("Persistence", func() {
+					Component("Store")
+				})
+				Component("Handler")
+			})
+		})
+	})
+	if err != nil {
+		t.Fatalf("failed to run DSL: %s", err)
+	}
+	shop := system(t, d.Model, "Shop")
+	api := container(t, shop, "API")
+	tests := []struct {
+		name, id, want string
+	}{
+		{"system", system(t, d.Model, "Payments").ID, "Payments domain"},
+		{"person", d.Model.People[0].ID, "Payments domain"},
+		{"ungrouped system", shop.ID, ""},
+		{"container", container(t, shop, "Web").ID, "Frontend"},
+		{"second container", container(t, shop, "Mobile").ID, "Frontend"},
+		{"ungrouped container", api.ID, ""},
+		{"component", component(t, api, "Store").ID, "Persistence"},
+		{"ungrouped component", component(t, api, "Handler").ID, ""},
+	}
+	for _, tt := range tests {
+		if got := d.Model.Group(tt.id); got != tt.want {
+			t.Errorf("%s: got group %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestGroupErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		dsl  func()
+		want string
+	}{
+		{
+			name: "nested",
+			dsl: func() {
+				Group("Outer", func() {
+					Group("Inner", func() {})
+				})
+			},
+			want: "Group: groups cannot be nested",
+		},
+		{
+			name: "empty name",
+			dsl: func() {
+				Group("", func() {})
+			},
+			want: "Group: name cannot be empty",
+		},
+		{
+			name: "in component",
+			dsl: func() {
+				SoftwareSystem("Shop", func() {
+					Container("API", func() {
+						Component("Store", func() {
+							Group("Code", func() {})
+						})
+					})
+				})
+			},
+			want: "invalid use of Group in component",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := runDSL(t, tt.dsl)
+			assertError(t, err, tt.want)
+		})
+	}
+}
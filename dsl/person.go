@@ -9,7 +9,7 @@ import (
 
 // Person defines a person (user, actor, role or persona).
 //
-// Person must appear in a Model expression.
+// Person must appear in a Design or Group expression.
 //
 // Person takes one to three arguments. The first argument is the name of the
 // person. An optional description may be passed as second argument. The last
@@ -39,7 +39,8 @@ import (
 //    })
 //
 func Person(name string, args ...interface{}) *expr.Person {
-	w, ok := eval.Current().(*expr.Design)
+	scope, group := groupScope()
+	w, ok := scope.(*expr.Design)
 	if !ok {
 		eval.IncompatibleDSL()
 		return nil
@@ -77,6 +78,7 @@ func Person(name string, args ...interface{}) *expr.Person {
 		Element: &expr.Element{
 			Name:        name,
 			Description: desc,
+			Group:       group,
 			DSLFunc:     dsl,
 		},
 	}
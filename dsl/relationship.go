@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"goa.design/goa/v3/eval"
+	goaexpr "goa.design/goa/v3/expr"
 	"goa.design/model/expr"
 )
 
@@ -19,7 +20,8 @@ const (
 
 // Uses adds a uni-directional relationship between two elements.
 //
-// Uses may appear in Person, SoftwareSystem, Container or Component.
+// Uses may appear in Person, SoftwareSystem, Container, Component or
+// CodeElement.
 //
 // Uses takes 2 to 5 arguments. The first argument identifies the target of the
 // relationship. The following argument is a short description for the
@@ -29,16 +31,21 @@ const (
 // define additional properties on the relationship.
 //
 // The target of the relationship is identified by providing an element (person,
-// software system, container or component) or the path of an element. The path
-// consists of the element name if a top level element (person or software
-// system) or if the element is in scope (container in the same software system
-// as the source or component in the same container as the source). When the
+// software system, container, component or code element) or the path of an
+// element. The path consists of the element name if a top level element (person
+// or software system) or if the element is in scope (container in the same
+// software system as the source, component in the same container as the source
+// or code element in the same component as the source). When the
 // element is not in scope the path specifies the parent element name followed
 // by a slash and the element name. If the parent itself is not in scope (i.e. a
 // component that is a child of a different software system than the source)
 // then the path specifies the top-level software system followed by a slash,
 // the container name, another slash and the component name.
 //
+// The target may also be a Goa service in which case the technology defaults
+// to the transports exposed by the service ("HTTP", "gRPC" or "HTTP and
+// gRPC").
+//
 // Usage:
 //
 //    Uses(Element, "<description>")
@@ -59,11 +66,15 @@ const (
 //
 // Where Element is one of:
 //
-//    - Person, SoftwareSystem, Container or Component
-//    - "<Person>", "<SoftwareSystem>", "<SoftwareSystem>/<Container>" or "<SoftwareSystem>/<Container>/<Component>"
+//    - Person, SoftwareSystem, Container, Component or CodeElement
+//    - Goa service (if the container or component derived from the service is a sibling of the source)
+//    - "<Person>", "<SoftwareSystem>", "<SoftwareSystem>/<Container>", "<SoftwareSystem>/<Container>/<Component>"
+//      or "<SoftwareSystem>/<Container>/<Component>/<CodeElement>"
 //    - "<Container>" (if container is a sibling of the source)
-//    - "<Component>" (if component is a sibling of the source)
+//    - "<Component>" (if component is a sibling of the source or of the parent of the source)
+//    - "<CodeElement>" (if code element is a sibling of the source)
 //    - "<Container>/<Component>" (if container is a sibling of the source)
+//    - "<Component>/<CodeElement>" (if component is a sibling of the source or of the parent of the source)
 //
 // Example:
 //
@@ -96,6 +107,8 @@ func Uses(element interface{}, description string, args ...interface{}) {
 		src = e.Element
 	case *expr.Component:
 		src = e.Element
+	case *expr.CodeElement:
+		src = e.Element
 	default:
 		eval.IncompatibleDSL()
 		return
@@ -169,7 +182,7 @@ func InteractsWith(person interface{}, description string, args ...interface{})
 
 // Delivers adds an interaction between an element and a person.
 //
-// Delivers must appear in SoftareSystem, Container or Component.
+// Delivers must appear in SoftareSystem, Container, Component or CodeElement.
 //
 // Delivers accepts 2 to 5 arguments. The first argument is the target of the
 // relationship, it must be a person or the name of a person. The target may
@@ -215,6 +228,8 @@ func Delivers(person interface{}, description string, args ...interface{}) {
 		src = e.Element
 	case *expr.Component:
 		src = e.Element
+	case *expr.CodeElement:
+		src = e.Element
 	default:
 		eval.IncompatibleDSL()
 		return
@@ -329,6 +344,19 @@ func uses(src *expr.Element, dest interface{}, desc string, args ...interface{})
 			return fmt.Errorf("Component reference is nil")
 		}
 		rel.Destination = d.Element
+	case *expr.CodeElement:
+		if d == nil {
+			return fmt.Errorf("CodeElement reference is nil")
+		}
+		rel.Destination = d.Element
+	case *goaexpr.ServiceExpr:
+		if d == nil {
+			return fmt.Errorf("Service reference is nil")
+		}
+		rel.DestinationPath = d.Name
+		if rel.Technology == "" {
+			rel.Technology = transports(d)
+		}
 	case string:
 		rel.DestinationPath = d
 	default:
@@ -0,0 +1,68 @@
+package dsl_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	. "goa.design/model/dsl"
+	"goa.design/model/expr"
+	"goa.design/model/mdl"
+	"goa.design/model/stz"
+)
+
+func TestRelationshipPerspectives(t *testing.T) {
+	d, err := runDSL(t, func() {
+		SoftwareSystem("Payments")
+		SoftwareSystem("Shop", func() {
+			Uses("Payments", "Charges cards", "HTTPS", func() {
+				URL("https://example.com/payments-api")
+				Prop("rate-limit", "100/s")
+				Perspective("Security", "mTLS required.")
+				Perspective("Security", "mTLS and OAuth2 required.")
+				Perspective("Operations", "Retried on failure.")
+			})
+		})
+	})
+	if err != nil {
+		t.Fatalf("failed to run DSL: %s", err)
+	}
+	shop := system(t, d.Model, "Shop")
+	if len(shop.Relationships) != 1 {
+		t.Fatalf("got %d relationships, want 1", len(shop.Relationships))
+	}
+	r := shop.Relationships[0]
+	if r.URL != "https://example.com/payments-api" {
+		t.Errorf("got URL %q, want https://example.com/payments-api", r.URL)
+	}
+	if r.Properties["rate-limit"] != "100/s" {
+		t.Errorf("got properties %v, want rate-limit=100/s", r.Properties)
+	}
+	want := []*mdl.Perspective{
+		{Name: "Security", Description: "mTLS and OAuth2 required."},
+		{Name: "Operations", Description: "Retried on failure."},
+	}
+	if len(r.Perspectives) != len(want) {
+		t.Fatalf("got %d perspectives, want %d", len(r.Perspectives), len(want))
+	}
+	for i, p := range r.Perspectives {
+		if *p != *want[i] {
+			t.Errorf("perspective %d: got %+v, want %+v", i, *p, *want[i])
+		}
+	}
+
+	b, err := json.Marshal(stz.WorkspaceFromDesign(expr.Root))
+	if err != nil {
+		t.Fatalf("failed to serialize workspace: %s", err)
+	}
+	js := string(b)
+	for _, s := range []string{
+		`"perspectives":[{"name":"Security","description":"mTLS and OAuth2 required."},{"name":"Operations","description":"Retried on failure."}]`,
+		`"properties":{"rate-limit":"100/s"}`,
+		`"url":"https://example.com/payments-api"`,
+	} {
+		if !strings.Contains(js, s) {
+			t.Errorf("workspace does not contain %s:\n%s", s, js)
+		}
+	}
+}
@@ -0,0 +1,163 @@
+package dsl_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	goadsl "goa.design/goa/v3/dsl"
+	goaexpr "goa.design/goa/v3/expr"
+	. "goa.design/model/dsl"
+	"goa.design/model/mdl"
+)
+
+func TestServiceComponent(t *testing.T) {
+	var orders, inventory *goaexpr.ServiceExpr
+	d, err := runServiceDSL(t, func() {
+		goadsl.API("shop", func() {})
+		orders = goadsl.Service("orders", func() {
+			goadsl.Description("Orders service.")
+			goadsl.Method("create", func() {})
+		})
+		inventory = goadsl.Service("inventory", func() {
+			goadsl.Description("Inventory service.")
+			goadsl.Method("reserve", func() {
+				goadsl.HTTP(func() { goadsl.GET("/") })
+				goadsl.GRPC(func() {})
+			})
+		})
+	}, func() {
+		SoftwareSystem("Shop", func() {
+			Container("Backend", func() {
+				ServiceComponent(orders, func() {
+					Tag("Core")
+				})
+				ServiceComponent(inventory)
+			})
+		})
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	c := container(t, system(t, d.Model, "Shop"), "Backend")
+	o := component(t, c, "orders")
+	if o.Description != "Orders service." || o.Technology != "Go and Goa v3" {
+		t.Errorf("got description %q and technology %q", o.Description, o.Technology)
+	}
+	if !hasTag(o.Tags, "Core") {
+		t.Errorf("got tags %q, want Core", o.Tags)
+	}
+	inv := component(t, c, "inventory")
+	if len(o.Relationships) != 1 {
+		t.Fatalf("got %d relationships, want 1", len(o.Relationships))
+	}
+	r := o.Relationships[0]
+	if r.DestinationID != inv.ID || r.Technology != "HTTP and gRPC" {
+		t.Errorf("got relationship to %q with technology %q, want %q with %q", r.DestinationID, r.Technology, inv.ID, "HTTP and gRPC")
+	}
+	if len(inv.Relationships) != 0 {
+		t.Errorf("got %d relationships from inventory, want none", len(inv.Relationships))
+	}
+}
+
+func TestServiceContainer(t *testing.T) {
+	var orders, inventory, payments *goaexpr.ServiceExpr
+	d, err := runServiceDSL(t, func() {
+		orders = goadsl.Service("orders", func() {
+			goadsl.Description("Orders service.")
+			goadsl.Method("create", func() {})
+		})
+		inventory = goadsl.Service("inventory", func() {
+			goadsl.Method("reserve", func() {})
+		})
+		payments = goadsl.Service("payments", func() {
+			goadsl.Method("charge", func() {})
+		})
+	}, func() {
+		SoftwareSystem("Shop", func() {
+			Container(orders, func() {
+				Uses(inventory, "Reserves items", "gRPC")
+			})
+			Container(inventory)
+			Container(payments)
+		})
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	s := system(t, d.Model, "Shop")
+	o := container(t, s, "orders")
+	i := container(t, s, "inventory")
+	p := container(t, s, "payments")
+	if o.Description != "Orders service." || o.Technology != "Go and Goa v3" {
+		t.Errorf("got description %q and technology %q", o.Description, o.Technology)
+	}
+	rels := make(map[string]string)
+	for _, r := range o.Relationships {
+		rels[r.DestinationID] = r.Description
+	}
+	if len(rels) != 2 || rels[i.ID] != "Reserves items" || rels[p.ID] != "Uses" {
+		t.Errorf("got relationships %v, want explicit relationship to inventory and inferred relationship to payments", rels)
+	}
+	if len(p.Relationships) != 1 || p.Relationships[0].DestinationID != o.ID {
+		t.Errorf("got relationships %+v from payments, want one to orders", p.Relationships)
+	}
+}
+
+func TestServiceNoModule(t *testing.T) {
+	var orders, inventory *goaexpr.ServiceExpr
+	d, err := runServiceDSL(t, func() {
+		orders = goadsl.Service("orders", func() {
+			goadsl.Method("create", func() {})
+		})
+		inventory = goadsl.Service("inventory", func() {
+			goadsl.Method("reserve", func() {})
+		})
+	}, func() {
+		SoftwareSystem("Shop", func() {
+			Container(orders)
+			Container(inventory)
+		})
+	}, t.TempDir())
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if o := container(t, system(t, d.Model, "Shop"), "orders"); len(o.Relationships) != 0 {
+		t.Errorf("got relationships %+v, want none", o.Relationships)
+	}
+}
+
+// runServiceDSL evaluates the Goa design defined by goa followed by the model
+// design defined by dsl and returns the corresponding model. The DSL is
+// evaluated in the directory dir if given, in the directory of the Go module
+// that implements the services of the tests otherwise.
+func runServiceDSL(t *testing.T, goa, dsl func(), dir ...string) (*mdl.Design, error) {
+	t.Helper()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	moduleDir := filepath.Join(wd, "testdata", "shop")
+	if len(dir) > 0 {
+		moduleDir = dir[0]
+	}
+	if err := os.Chdir(moduleDir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+	reset(t)
+	goa()
+	Design("Test", dsl)
+	return mdl.RunDSL()
+}
+
+// hasTag returns true if the comma separated list of tags contains tag.
+func hasTag(tags, tag string) bool {
+	for _, t := range strings.Split(tags, ",") {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
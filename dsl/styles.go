@@ -168,7 +168,7 @@ func RelationshipStyle(tag string, dsl func()) {
 
 // Shape defines element shapes, default is ShapeBox.
 //
-// Shape must apear in ElementStyle.
+// Shape must apear in ElementStyle or Archetype.
 //
 // Shape accepts one argument, one of: ShapeBox, ShapeRoundedBox, ShapeCircle,
 // ShapeEllipse, ShapeHexagon or ShapeCylinder, ShapePipe, ShapePerson
@@ -178,6 +178,8 @@ func Shape(kind ShapeKind) {
 	switch es := eval.Current().(type) {
 	case *expr.ElementStyle:
 		es.Shape = expr.ShapeKind(kind)
+	case *expr.Archetype:
+		es.Shape = expr.ShapeKind(kind)
 	default:
 		eval.IncompatibleDSL()
 	}
@@ -0,0 +1,197 @@
+package dsl
+
+import (
+	"goa.design/goa/v3/eval"
+	"goa.design/model/expr"
+)
+
+// Team defines a team that owns elements of the model. Teams make it possible
+// to record who is responsible for an element, to validate that software
+// systems have an owner (see RequireOwners) and to render views that only
+// include the elements owned by given teams (see OwnedBy).
+//
+// Team is a top-level DSL function, it may also appear in a Design expression.
+// Teams are typically defined in a shared package and imported by the packages
+// that define the designs.
+//
+// Team takes one or two arguments: the name of the team and an optional
+// function that defines the team contact, chat channel and on-call URL. Team
+// returns the team expression which may be given to Owner and OwnedBy.
+//
+// Example:
+//
+//    var Payments = Team("Payments", func() {
+//        Contact("payments@example.com")
+//        Channel("#payments")
+//        OnCall("https://oncall.example.com/payments")
+//    })
+//
+//    var _ = Design(func() {
+//        SoftwareSystem("Billing", func() {
+//            Owner(Payments)
+//        })
+//    })
+//
+func Team(name string, dsl ...func()) *expr.Team {
+	switch eval.Current().(type) {
+	case eval.TopExpr, *expr.Design:
+	default:
+		eval.IncompatibleDSL()
+		return nil
+	}
+	if name == "" {
+		eval.ReportError("Team: name cannot be empty")
+		return nil
+	}
+	if len(dsl) > 1 {
+		eval.ReportError("Team: too many arguments")
+		return nil
+	}
+	if expr.Root.Model.Team(name) != nil {
+		eval.ReportError("Team: team %q already defined", name)
+		return nil
+	}
+	t := &expr.Team{Name: name}
+	if len(dsl) == 1 {
+		eval.Execute(dsl[0], t)
+	}
+	expr.Root.Model.Teams = append(expr.Root.Model.Teams, t)
+	return t
+}
+
+// Contact sets the email address or name of the person to contact for a team.
+//
+// Contact must appear in a Team expression.
+//
+// Contact takes one argument: the contact.
+//
+// Example:
+//
+//    var Payments = Team("Payments", func() {
+//        Contact("payments@example.com")
+//    })
+//
+func Contact(contact string) {
+	t, ok := eval.Current().(*expr.Team)
+	if !ok {
+		eval.IncompatibleDSL()
+		return
+	}
+	t.Contact = contact
+}
+
+// Channel sets the chat channel of a team.
+//
+// Channel must appear in a Team expression.
+//
+// Channel takes one argument: the name of the channel.
+//
+// Example:
+//
+//    var Payments = Team("Payments", func() {
+//        Channel("#payments")
+//    })
+//
+func Channel(channel string) {
+	t, ok := eval.Current().(*expr.Team)
+	if !ok {
+		eval.IncompatibleDSL()
+		return
+	}
+	t.Channel = channel
+}
+
+// OnCall sets the URL of the on-call schedule or paging service of a team.
+//
+// OnCall must appear in a Team expression.
+//
+// OnCall takes one argument: the URL.
+//
+// Example:
+//
+//    var Payments = Team("Payments", func() {
+//        OnCall("https://oncall.example.com/payments")
+//    })
+//
+func OnCall(u string) {
+	t, ok := eval.Current().(*expr.Team)
+	if !ok {
+		eval.IncompatibleDSL()
+		return
+	}
+	t.OnCall = u
+}
+
+// Owner sets the team that owns the element. Elements that do not define an
+// owner inherit the owner of their parent (software system for containers,
+// container for components, parent deployment node for deployment nodes etc.).
+//
+// Owner may appear in Person, SoftwareSystem, Container, Component,
+// CodeElement, DeploymentNode, InfrastructureNode or ContainerInstance.
+//
+// Owner takes one argument: the team expression or the name of the team. The
+// team must be defined with Team.
+//
+// Example:
+//
+//    var _ = Design(func() {
+//        Team("Payments")
+//        SoftwareSystem("Billing", func() {
+//            Owner("Payments")
+//        })
+//    })
+//
+func Owner(team interface{}) {
+	eh, ok := eval.Current().(expr.ElementHolder)
+	if !ok {
+		eval.IncompatibleDSL()
+		return
+	}
+	name := teamName(team)
+	if name == "" {
+		return
+	}
+	eh.GetElement().Owner = name
+}
+
+// RequireOwners causes the validation of the design to fail if any software
+// system does not define an owner.
+//
+// RequireOwners must appear in Design.
+//
+// Example:
+//
+//    var _ = Design(func() {
+//        RequireOwners()
+//        Team("Payments")
+//        SoftwareSystem("Billing", func() {
+//            Owner("Payments")
+//        })
+//    })
+//
+func RequireOwners() {
+	d, ok := eval.Current().(*expr.Design)
+	if !ok {
+		eval.IncompatibleDSL()
+		return
+	}
+	d.Model.RequireOwners = true
+}
+
+// teamName returns the name of the given team which is either a team
+// expression or the name of a team. It reports an error and returns an empty
+// string if team is neither.
+func teamName(team interface{}) string {
+	switch t := team.(type) {
+	case *expr.Team:
+		return t.Name
+	case string:
+		if t == "" {
+			eval.ReportError("team name cannot be empty")
+		}
+		return t
+	default:
+		eval.InvalidArgError("team or team name", team)
+		return ""
+	}
+}
@@ -0,0 +1,153 @@
+package dsl_test
+
+import (
+	"testing"
+
+	. "goa.design/model/dsl"
+)
+
+func TestOwner(t *testing.T) {
+	d, err := runDSL(t, func() {
+		var Payments = Team("Payments", func() {
+			Contact("payments@example.com")
+			Channel("#payments")
+			OnCall("https://oncall.example.com/payments")
+		})
+		Team("Storefront")
+		RequireOwners()
+		SoftwareSystem("Billing", func() {
+			Owner(Payments)
+			Container("Invoices")
+		})
+		SoftwareSystem("Shop", func() {
+			Owner("Storefront")
+			Container("Web")
+			Container("Checkout", func() {
+				Owner(Payments)
+			})
+		})
+		Views(func() {
+			SystemLandscapeView("payments", "Owned by payments.", func() {
+				AddAll()
+				OwnedBy(Payments)
+			})
+			ContainerView("Shop", "shop", "Shop containers owned by payments.", func() {
+				AddAll()
+				OwnedBy("Payments")
+			})
+		})
+	})
+	if err != nil {
+		t.Fatalf("failed to run DSL: %s", err)
+	}
+
+	t.Run("teams", func(t *testing.T) {
+		if len(d.Model.Teams) != 2 {
+			t.Fatalf("got %d teams, want 2", len(d.Model.Teams))
+		}
+		p := d.Model.Teams[0]
+		if p.Name != "Payments" || p.Contact != "payments@example.com" || p.Channel != "#payments" || p.OnCall != "https://oncall.example.com/payments" {
+			t.Errorf("got team %+v", *p)
+		}
+	})
+
+	t.Run("inherited owner", func(t *testing.T) {
+		tests := []struct {
+			system, container, want string
+		}{
+			{"Billing", "Invoices", "Payments"},
+			{"Shop", "Web", "Storefront"},
+			{"Shop", "Checkout", "Payments"},
+		}
+		for _, tt := range tests {
+			c := container(t, system(t, d.Model, tt.system), tt.container)
+			if o := d.Model.Owner(c.ID); o == nil || o.Name != tt.want {
+				t.Errorf("%s/%s: got owner %v, want %s", tt.system, tt.container, o, tt.want)
+			}
+		}
+	})
+
+	t.Run("owned by", func(t *testing.T) {
+		tests := []struct {
+			view string
+			want []string
+		}{
+			{"payments", []string{system(t, d.Model, "Billing").ID}},
+			{"shop", []string{system(t, d.Model, "Billing").ID, container(t, system(t, d.Model, "Shop"), "Checkout").ID}},
+		}
+		for _, tt := range tests {
+			ids := make(map[string]bool)
+			for _, v := range d.Views.All() {
+				if v.Props().Key != tt.view {
+					continue
+				}
+				for _, ev := range v.Props().ElementViews {
+					ids[ev.ID] = true
+				}
+			}
+			if len(ids) != len(tt.want) {
+				t.Errorf("%s: got elements %v, want %v", tt.view, ids, tt.want)
+			}
+			for _, id := range tt.want {
+				if !ids[id] {
+					t.Errorf("%s: element %q missing, got %v", tt.view, id, ids)
+				}
+			}
+		}
+	})
+}
+
+func TestOwnerErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		dsl  func()
+		want string
+	}{
+		{
+			name: "require owners",
+			dsl: func() {
+				Team("Payments")
+				RequireOwners()
+				SoftwareSystem("Billing", func() { Owner("Payments") })
+				SoftwareSystem("Shop")
+			},
+			want: `software system "Shop": missing owner, the design requires all software systems to have an owner`,
+		},
+		{
+			name: "unknown owner",
+			dsl: func() {
+				SoftwareSystem("Shop", func() {
+					Container("Web", func() { Owner("Storefront") })
+				})
+			},
+			want: `container "Web": unknown owner "Storefront", owner must be a team`,
+		},
+		{
+			name: "unknown owned by team",
+			dsl: func() {
+				SoftwareSystem("Shop")
+				Views(func() {
+					SystemLandscapeView("landscape", func() {
+						AddAll()
+						OwnedBy("Storefront")
+					})
+				})
+			},
+			want: `unknown team "Storefront" used in OwnedBy of view "landscape"`,
+		},
+		{
+			name: "duplicate team",
+			dsl: func() {
+				Team("Payments")
+				Team("Payments")
+			},
+			want: `Team: team "Payments" already defined`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := runDSL(t, tt.dsl)
+			assertError(t, err, tt.want)
+		})
+	}
+}
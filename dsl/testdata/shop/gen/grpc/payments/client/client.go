@@ -0,0 +1,4 @@
+package client
+
+// Client is the payments service gRPC client.
+type Client struct{}
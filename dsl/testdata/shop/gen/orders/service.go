@@ -0,0 +1,8 @@
+package orders
+
+import "context"
+
+// Service is the orders service interface.
+type Service interface {
+	Create(context.Context) error
+}
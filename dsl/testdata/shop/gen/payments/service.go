@@ -0,0 +1,8 @@
+package payments
+
+import "context"
+
+// Service is the payments service interface.
+type Service interface {
+	Charge(context.Context) error
+}
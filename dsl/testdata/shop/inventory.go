@@ -0,0 +1,9 @@
+package shop
+
+import "context"
+
+// inventorysrvc implements the inventory service.
+type inventorysrvc struct{}
+
+// Reserve reserves items.
+func (s *inventorysrvc) Reserve(context.Context) error { return nil }
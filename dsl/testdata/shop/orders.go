@@ -0,0 +1,17 @@
+package shop
+
+import (
+	"context"
+
+	"example.com/shop/gen/inventory"
+	paymentsc "example.com/shop/gen/grpc/payments/client"
+)
+
+// orderssrvc implements the orders service.
+type orderssrvc struct {
+	inventory *inventory.Client
+	payments  *paymentsc.Client
+}
+
+// Create creates an order.
+func (s *orderssrvc) Create(context.Context) error { return nil }
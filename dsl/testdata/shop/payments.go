@@ -0,0 +1,18 @@
+package shop
+
+import (
+	"context"
+
+	"example.com/shop/gen/orders"
+	"example.com/shop/gen/payments"
+)
+
+// paymentssrvc implements the payments service.
+type paymentssrvc struct {
+	orders orders.Service
+}
+
+// Charge charges a card.
+func (s *paymentssrvc) Charge(context.Context) error { return nil }
+
+var _ payments.Service = &paymentssrvc{}
@@ -416,6 +416,95 @@ func ComponentView(container interface{}, key string, args ...interface{}) {
 	vs.ComponentViews = append(vs.ComponentViews, v)
 }
 
+// CodeView defines a code view, that is a view that shows the code elements of
+// a component and their relationships.
+//
+// CodeView must appear in Views.
+//
+// CodeView accepts 3 to 4 arguments: the first argument is the component or
+// the path to the component being described by the code view. The path
+// consists of the name of the software system that contains the component
+// followed by a slash, the name of the container, another slash and the name of
+// the component. The following argument is a unique key which can be used to
+// reference the view when creating a filtered views. Next is an optional
+// description. The last argument must be a function describing the properties
+// of the view.
+//
+// Usage:
+//
+//    CodeView(Component, "<key>", func())
+//
+//    CodeView("<Software System>/<Container>/<Component>", "<key>", func())
+//
+//    CodeView(Component, "<key>", "[description]", func())
+//
+//    CodeView("<Software System>/<Container>/<Component>", "<key>", "[description]", func())
+//
+// Example:
+//
+//     var _ = Design(func() {
+//         SoftwareSystem("Software System", "My software system.", func() {
+//             Container("Container", func() {
+//                 Component("Component", func() {
+//                     CodeElement("Store", "Store interface", "Go interface")
+//                     CodeElement("pgStore", "Postgres store", "Go struct", func() {
+//                         Uses("Store", "Implements")
+//                     })
+//                 })
+//             })
+//         })
+//         Views(func() {
+//             CodeView("Software System/Container/Component", "code", "Key types of component.", func() {
+//                 Title("Component code")
+//                 AddDefault()
+//                 AutoLayout()
+//             })
+//         })
+//     })
+//
+func CodeView(component interface{}, key string, args ...interface{}) {
+	vs, ok := eval.Current().(*expr.Views)
+	if !ok {
+		eval.IncompatibleDSL()
+		return
+	}
+	var c *expr.Component
+	switch a := component.(type) {
+	case *expr.Component:
+		c = a
+	case string:
+		cmp, err := expr.Root.Model.FindElement(nil, a)
+		if err != nil {
+			eval.ReportError("CodeView: " + err.Error())
+			return
+		}
+		c, ok = cmp.(*expr.Component)
+		if !ok {
+			eval.ReportError("CodeView: %q is not a component", a)
+			return
+		}
+	default:
+		eval.InvalidArgError("component or component path", component)
+		return
+	}
+	description, dsl, err := parseView(args...)
+	if err != nil {
+		eval.ReportError("CodeView: " + err.Error())
+		return
+	}
+	v := &expr.CodeView{
+		ViewProps: &expr.ViewProps{
+			Key:         key,
+			Description: description,
+		},
+		ComponentID: c.GetElement().ID,
+	}
+	if dsl != nil {
+		eval.Execute(dsl, v)
+	}
+	vs.CodeViews = append(vs.CodeViews, v)
+}
+
 // FilteredView defines a filtered view on top of the specified view.
 // The base key specifies the key of the System Landscape, System
 // Context, Container, or Component view on which this filtered view
@@ -856,7 +945,7 @@ func Add(element interface{}, dsl ...func()) {
 		view expr.View
 	)
 	switch v := eval.Current().(type) {
-	case *expr.LandscapeView, *expr.ContextView, *expr.ContainerView, *expr.ComponentView:
+	case *expr.LandscapeView, *expr.ContextView, *expr.ContainerView, *expr.ComponentView, *expr.CodeView:
 		view = v.(expr.View)
 		eh, err = findViewElement(view, element)
 	case *expr.DeploymentView:
@@ -1007,7 +1096,7 @@ func Link(source, destination interface{}, args ...interface{}) {
 // AddAll includes all elements and relationships in the view scope.
 //
 // AddAll may appear in SystemLandscapeView, SystemContextView, ContainerView,
-// ComponentView or DeploymentView.
+// ComponentView, CodeView or DeploymentView.
 //
 // AddAll takes no argument.
 //
@@ -1109,10 +1198,12 @@ func AddNeighbors(element interface{}) {
 //      software systems and people.
 //    - Component view: adds all components in container as well as related
 //      containers, software systems and people.
+//    - Code view: adds all code elements in component as well as related
+//      components, containers, software systems and people.
 //    - Deployment view: adds all deployment nodes.
 //
 // AddDefault must appear in SystemLandscapeView, SystemContextView,
-// ContainerView or ComponentView.
+// ContainerView, ComponentView or CodeView.
 //
 // AddDefault takes no argument.
 //
@@ -1142,7 +1233,7 @@ func AddDefault() {
 
 // AddContainers includes all containers in scope to the view.
 //
-// AddContainers may appear in ContainerView or ComponentView.
+// AddContainers may appear in ContainerView, ComponentView or CodeView.
 //
 // AddContainers takes no argument.
 func AddContainers() {
@@ -1152,6 +1243,9 @@ func AddContainers() {
 	case *expr.ComponentView:
 		c := expr.Registry[v.ContainerID].(*expr.Container)
 		v.AddElements(c.System.Containers.Elements()...)
+	case *expr.CodeView:
+		c := expr.Registry[v.ComponentID].(*expr.Component)
+		v.AddElements(c.Container.System.Containers.Elements()...)
 	default:
 		eval.IncompatibleDSL()
 	}
@@ -1176,12 +1270,28 @@ func AddInfluencers() {
 
 // AddComponents includes all components in scope to the view.
 //
-// AddComponents must appear in ComponentView.
+// AddComponents must appear in ComponentView or CodeView.
 //
 // AddComponents takes no argument
 func AddComponents() {
-	if cv, ok := eval.Current().(*expr.ComponentView); ok {
-		cv.AddElements(expr.Registry[cv.ContainerID].(*expr.Container).Components.Elements()...)
+	switch v := eval.Current().(type) {
+	case *expr.ComponentView:
+		v.AddElements(expr.Registry[v.ContainerID].(*expr.Container).Components.Elements()...)
+	case *expr.CodeView:
+		v.AddElements(expr.Registry[v.ComponentID].(*expr.Component).Container.Components.Elements()...)
+	default:
+		eval.IncompatibleDSL()
+	}
+}
+
+// AddCodeElements includes all code elements in scope to the view.
+//
+// AddCodeElements must appear in CodeView.
+//
+// AddCodeElements takes no argument
+func AddCodeElements() {
+	if cv, ok := eval.Current().(*expr.CodeView); ok {
+		cv.AddElements(expr.Registry[cv.ComponentID].(*expr.Component).CodeElements.Elements()...)
 		return
 	}
 	eval.IncompatibleDSL()
@@ -1299,6 +1409,49 @@ func RemoveTagged(tag string) {
 	v.Props().RemoveTags = append(v.Props().RemoveTags, tag)
 }
 
+// OwnedBy removes all elements that are not owned by one of the given teams
+// from the view. Elements that do not define an owner inherit the owner of
+// their parent.
+//
+// OwnedBy must appear in SystemLandscapeView, SystemContextView,
+// ContainerView, ComponentView, CodeView or DeploymentView.
+//
+// OwnedBy takes one or more arguments: the teams or the names of the teams
+// whose elements should be kept.
+//
+// Usage:
+//
+//     OwnedBy(Team, [Team, ...])
+//
+// Example:
+//
+//     var _ = Design(func() {
+//         var Payments = Team("Payments")
+//         SoftwareSystem("Billing", func() {
+//             Owner(Payments)
+//         })
+//         SoftwareSystem("Catalog")
+//         Views(func() {
+//             SystemLandscapeView("payments", "Systems owned by the payments team.", func() {
+//                 AddAll()
+//                 OwnedBy(Payments)
+//             })
+//         })
+//     })
+//
+func OwnedBy(team interface{}, teams ...interface{}) {
+	v, ok := eval.Current().(expr.View)
+	if !ok {
+		eval.IncompatibleDSL()
+		return
+	}
+	for _, t := range append([]interface{}{team}, teams...) {
+		if name := teamName(t); name != "" {
+			v.Props().OwnedBy = append(v.Props().OwnedBy, name)
+		}
+	}
+}
+
 // Unlink removes a relationship from a view.
 //
 // Unlink must appear in SystemLandscapeView, SystemContextView, ContainerView
@@ -1755,7 +1908,7 @@ func Vertices(args ...int) {
 		eval.IncompatibleDSL()
 	}
 	for i := 0; i < len(args); i += 2 {
-		rv.Vertices = append(rv.Vertices, &expr.Vertex{args[i], args[i+1]})
+		rv.Vertices = append(rv.Vertices, &expr.Vertex{X: args[i], Y: args[i+1]})
 	}
 }
 
@@ -2017,6 +2170,9 @@ func findViewElement(view expr.View, element interface{}) (expr.ElementHolder, e
 		scope := expr.Registry[v.ContainerID].(expr.ElementHolder)
 		res, err := expr.Root.Model.FindElement(scope, name)
 		return res, err
+	case *expr.CodeView:
+		scope := expr.Registry[v.ComponentID].(expr.ElementHolder)
+		return expr.Root.Model.FindElement(scope, name)
 	case *expr.DeploymentView:
 		return findDeploymentViewElement(v.Environment, name)
 	case *expr.DynamicView:
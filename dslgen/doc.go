@@ -0,0 +1,22 @@
+/*
+Package dslgen generates Go source code that uses the model DSL to describe a
+given software architecture design.
+
+Generate produces a single Go file that defines the design using the
+functions of the dsl package: Design, Person, SoftwareSystem, Container,
+Component, Uses, DeploymentEnvironment, Views, Styles etc. It makes it
+possible to migrate designs created with other tools (for example the
+Structurizr service, see the stz package) to model-as-code.
+
+Relationships are defined using element paths so that the order in which
+elements are declared does not matter. Elements referenced in views or by
+container instances are stored in Go variables named after the elements.
+The tags automatically added by the DSL (e.g. "Element" or "Relationship")
+are omitted.
+
+Relationships between container instances are not generated as they are
+automatically derived from the relationships between the corresponding
+containers. Filtered views are not supported, Generate returns an error if
+the design defines any.
+*/
+package dslgen
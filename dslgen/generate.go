@@ -0,0 +1,1089 @@
+package dslgen
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"goa.design/model/mdl"
+)
+
+type (
+	// generator keeps the state needed to generate the DSL.
+	generator struct {
+		d   *mdl.Design
+		m   *mdl.Model
+		buf *bytes.Buffer
+		// vars maps the IDs of the elements referenced via variables to the
+		// names of the variables.
+		vars map[string]string
+		// names records the variable names already in use.
+		names map[string]bool
+		// people records the people already generated.
+		people map[string]bool
+		// declared records the IDs of the elements whose variables have been
+		// forward declared.
+		declared map[string]bool
+		// importExpr is true if the generated code uses the expr package.
+		importExpr bool
+	}
+)
+
+// Generate returns the Go source code of a package named pkg that defines
+// the given design using the model DSL. It returns an error if the design
+// defines filtered views as the DSL cannot refer to the views they filter.
+func Generate(d *mdl.Design, pkg string) ([]byte, error) {
+	if d.Views != nil && len(d.Views.FilteredViews) > 0 {
+		return nil, fmt.Errorf("filtered view %q cannot be expressed in the model DSL", d.Views.FilteredViews[0].Key)
+	}
+	g := &generator{
+		d:        d,
+		m:        d.Model,
+		buf:      &bytes.Buffer{},
+		vars:     make(map[string]string),
+		names:    make(map[string]bool),
+		people:   make(map[string]bool),
+		declared: make(map[string]bool),
+	}
+	if g.m == nil {
+		g.m = &mdl.Model{}
+	}
+	g.nameVariables()
+	g.design()
+	body := g.buf.String()
+
+	var src bytes.Buffer
+	fmt.Fprintf(&src, "package %s\n\n", pkg)
+	if g.importExpr {
+		src.WriteString("import (\n\t. \"goa.design/model/dsl\"\n\t\"goa.design/model/expr\"\n)\n\n")
+	} else {
+		src.WriteString("import . \"goa.design/model/dsl\"\n\n")
+	}
+	src.WriteString(body)
+	res, err := format.Source(src.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("failed to format generated code: %s", err) // bug
+	}
+	return res, nil
+}
+
+// PackageName returns the name of the Go package generated in the directory
+// dir: the lowercase name of the directory where dashes, dots and spaces are
+// replaced with underscores.
+func PackageName(dir string) string {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		abs = dir
+	}
+	return strings.ToLower(strings.Map(func(r rune) rune {
+		if r == '-' || r == '.' || r == ' ' {
+			return '_'
+		}
+		return r
+	}, filepath.Base(abs)))
+}
+
+// design generates the Design expression.
+func (g *generator) design() {
+	args := []string{strconv.Quote(g.d.Name)}
+	if g.d.Description != "" {
+		args = append(args, strconv.Quote(g.d.Description))
+	}
+	g.call("var _ = ", "Design", args, func() {
+		if g.d.Version != "" {
+			g.line("Version(%s)", strconv.Quote(g.d.Version))
+		}
+		if g.m.Enterprise != nil && g.m.Enterprise.Name != "" {
+			g.line("Enterprise(%s)", strconv.Quote(g.m.Enterprise.Name))
+		}
+		for _, t := range g.m.Teams {
+			g.line("")
+			g.team(t)
+		}
+		var groups []string
+		for _, p := range g.m.People {
+			if p.Group == "" {
+				g.line("")
+				g.person(p)
+			}
+			groups = append(groups, p.Group)
+		}
+		for _, s := range g.m.Systems {
+			if s.Group == "" {
+				g.line("")
+				g.system(s)
+			}
+			groups = append(groups, s.Group)
+		}
+		for _, grp := range groupNames(groups) {
+			// Variables declared in the group function would not be visible
+			// in views, forward declare them.
+			var ids []string
+			for _, p := range g.m.People {
+				if p.Group == grp {
+					ids = append(ids, p.ID)
+				}
+			}
+			for _, s := range g.m.Systems {
+				if s.Group == grp {
+					ids = append(ids, s.ID)
+					ids = append(ids, nestedIDs(s)...)
+				}
+			}
+			g.line("")
+			g.forwardDeclare(ids)
+			g.call("", "Group", []string{strconv.Quote(grp)}, func() {
+				for _, p := range g.m.People {
+					if p.Group == grp {
+						g.line("")
+						g.person(p)
+					}
+				}
+				for _, s := range g.m.Systems {
+					if s.Group == grp {
+						g.line("")
+						g.system(s)
+					}
+				}
+			})
+		}
+		for _, env := range environments(g.m.DeploymentNodes) {
+			g.line("")
+			g.environment(env)
+		}
+		if g.d.Views != nil {
+			g.line("")
+			g.call("", "Views", nil, g.views)
+		}
+	})
+}
+
+// person generates the DSL for p.
+func (g *generator) person(p *mdl.Person) {
+	g.call(g.declare(p.ID), "Person", strArgs(p.Name, p.Description), func() {
+		if p.Location == mdl.LocationExternal {
+			g.line("External()")
+		}
+		g.owner(p.Owner)
+		g.props(p.URL, customTags(p.Tags, "Person"), p.Properties, p.Perspectives)
+		g.relationships(p.ID, p.Relationships)
+	})
+	g.people[p.ID] = true
+}
+
+// system generates the DSL for s.
+func (g *generator) system(s *mdl.SoftwareSystem) {
+	g.forwardDeclare(nestedIDs(s))
+	g.call(g.declare(s.ID), "SoftwareSystem", strArgs(s.Name, s.Description), func() {
+		if s.Location == mdl.LocationExternal {
+			g.line("External()")
+		}
+		g.owner(s.Owner)
+		g.props(s.URL, customTags(s.Tags, "Software System"), s.Properties, s.Perspectives)
+		g.relationships(s.ID, s.Relationships)
+		groups := make([]string, len(s.Containers))
+		for i, c := range s.Containers {
+			if c.Group == "" {
+				g.line("")
+				g.container(c)
+			}
+			groups[i] = c.Group
+		}
+		for _, grp := range groupNames(groups) {
+			g.line("")
+			g.call("", "Group", []string{strconv.Quote(grp)}, func() {
+				for _, c := range s.Containers {
+					if c.Group == grp {
+						g.line("")
+						g.container(c)
+					}
+				}
+			})
+		}
+	})
+}
+
+// container generates the DSL for c.
+func (g *generator) container(c *mdl.Container) {
+	g.call(g.assign(c.ID), "Container", strArgs(c.Name, c.Description, c.Technology), func() {
+		g.owner(c.Owner)
+		g.props(c.URL, customTags(c.Tags, "Container"), c.Properties, c.Perspectives)
+		g.relationships(c.ID, c.Relationships)
+		groups := make([]string, len(c.Components))
+		for i, cmp := range c.Components {
+			if cmp.Group == "" {
+				g.component(cmp)
+			}
+			groups[i] = cmp.Group
+		}
+		for _, grp := range groupNames(groups) {
+			g.call("", "Group", []string{strconv.Quote(grp)}, func() {
+				for _, cmp := range c.Components {
+					if cmp.Group == grp {
+						g.component(cmp)
+					}
+				}
+			})
+		}
+	})
+}
+
+// component generates the DSL for cmp.
+func (g *generator) component(cmp *mdl.Component) {
+	g.call(g.assign(cmp.ID), "Component", strArgs(cmp.Name, cmp.Description, cmp.Technology), func() {
+		g.owner(cmp.Owner)
+		g.props(cmp.URL, customTags(cmp.Tags, "Component"), cmp.Properties, cmp.Perspectives)
+		g.relationships(cmp.ID, cmp.Relationships)
+		for _, ce := range cmp.CodeElements {
+			g.call(g.assign(ce.ID), "CodeElement", strArgs(ce.Name, ce.Description, ce.Technology), func() {
+				g.owner(ce.Owner)
+				g.props(ce.URL, customTags(ce.Tags, "Code Element"), ce.Properties, ce.Perspectives)
+				g.relationships(ce.ID, ce.Relationships)
+			})
+		}
+	})
+}
+
+// environment generates the DSL for the deployment environment env.
+func (g *generator) environment(env string) {
+	var (
+		nodes []*mdl.DeploymentNode
+		ids   []string
+		walk  func([]*mdl.DeploymentNode)
+	)
+	walk = func(dns []*mdl.DeploymentNode) {
+		for _, n := range dns {
+			ids = append(ids, n.ID)
+			for _, inf := range n.InfrastructureNodes {
+				ids = append(ids, inf.ID)
+			}
+			for _, ci := range n.ContainerInstances {
+				ids = append(ids, ci.ID)
+			}
+			walk(n.Children)
+		}
+	}
+	for _, n := range g.m.DeploymentNodes {
+		if n.Environment == env {
+			nodes = append(nodes, n)
+		}
+	}
+	walk(nodes)
+	g.forwardDeclare(ids)
+	g.call("", "DeploymentEnvironment", []string{strconv.Quote(env)}, func() {
+		for _, n := range nodes {
+			g.deploymentNode(n)
+		}
+	})
+}
+
+// deploymentNode generates the DSL for n.
+func (g *generator) deploymentNode(n *mdl.DeploymentNode) {
+	g.call(g.assign(n.ID), "DeploymentNode", strArgs(n.Name, n.Description, n.Technology), func() {
+		g.owner(n.Owner)
+		g.props(n.URL, customTags(n.Tags, "Deployment Node"), n.Properties, n.Perspectives)
+		if n.Instances != nil && *n.Instances != 1 {
+			g.line("Instances(%d)", *n.Instances)
+		}
+		for _, inf := range n.InfrastructureNodes {
+			g.call(g.assign(inf.ID), "InfrastructureNode", strArgs(inf.Name, inf.Description, inf.Technology), func() {
+				g.owner(inf.Owner)
+				g.props(inf.URL, customTags(inf.Tags, "Infrastructure Node"), inf.Properties, inf.Perspectives)
+			})
+		}
+		for _, ci := range n.ContainerInstances {
+			g.call(g.assign(ci.ID), "ContainerInstance", []string{g.ref(ci.ContainerID)}, func() {
+				if ci.InstanceID > 1 {
+					g.line("InstanceID(%d)", ci.InstanceID)
+				}
+				g.owner(ci.Owner)
+				g.props("", customTags(ci.Tags, "Container Instance"), ci.Properties, ci.Perspectives)
+				for _, hc := range ci.HealthChecks {
+					g.call("", "HealthCheck", []string{strconv.Quote(hc.Name)}, func() {
+						if hc.URL != "" {
+							g.line("URL(%s)", strconv.Quote(hc.URL))
+						}
+						if hc.Interval != 0 {
+							g.line("Interval(%d)", hc.Interval)
+						}
+						if hc.Timeout != 0 {
+							g.line("Timeout(%d)", hc.Timeout)
+						}
+						for _, k := range sortedKeys(hc.Headers) {
+							g.line("Header(%s, %s)", strconv.Quote(k), strconv.Quote(hc.Headers[k]))
+						}
+					})
+				}
+			})
+		}
+		for _, c := range n.Children {
+			g.deploymentNode(c)
+		}
+	})
+}
+
+// team generates the DSL for t.
+func (g *generator) team(t *mdl.Team) {
+	g.call("", "Team", []string{strconv.Quote(t.Name)}, func() {
+		if t.Contact != "" {
+			g.line("Contact(%s)", strconv.Quote(t.Contact))
+		}
+		if t.Channel != "" {
+			g.line("Channel(%s)", strconv.Quote(t.Channel))
+		}
+		if t.OnCall != "" {
+			g.line("OnCall(%s)", strconv.Quote(t.OnCall))
+		}
+	})
+}
+
+// owner generates the Owner expression if owner is not empty.
+func (g *generator) owner(owner string) {
+	if owner != "" {
+		g.line("Owner(%s)", strconv.Quote(owner))
+	}
+}
+
+// props generates the URL, Tag, Prop and Perspective expressions.
+func (g *generator) props(u, tags string, props map[string]string, persps []*mdl.Perspective) {
+	if u != "" {
+		g.line("URL(%s)", strconv.Quote(u))
+	}
+	if tags != "" {
+		g.line("Tag(%s)", quoteAll(strings.Split(tags, ",")))
+	}
+	for _, k := range sortedKeys(props) {
+		g.line("Prop(%s, %s)", strconv.Quote(k), strconv.Quote(props[k]))
+	}
+	for _, p := range persps {
+		g.line("Perspective(%s, %s)", strconv.Quote(p.Name), strconv.Quote(p.Description))
+	}
+}
+
+// relationships generates the relationships of the element with the given ID.
+func (g *generator) relationships(srcID string, rels []*mdl.Relationship) {
+	for _, r := range rels {
+		if r.LinkedRelationshipID != "" {
+			continue
+		}
+		dest := g.m.Element(r.DestinationID)
+		fn := "Uses"
+		var target string
+		switch d := dest.(type) {
+		case *mdl.Person:
+			target = d.Name
+			if _, ok := g.m.Element(srcID).(*mdl.Person); ok {
+				if g.people[d.ID] {
+					fn = "InteractsWith"
+				}
+			} else {
+				fn = "Delivers"
+			}
+		case *mdl.SoftwareSystem, *mdl.Container, *mdl.Component, *mdl.CodeElement:
+			target = g.path(srcID, r.DestinationID)
+		default:
+			continue // Relationships to deployment elements cannot be described with the DSL.
+		}
+		args := []string{strconv.Quote(target), strconv.Quote(r.Description)}
+		if r.Technology != "" {
+			args = append(args, strconv.Quote(r.Technology))
+		}
+		switch r.InteractionStyle {
+		case mdl.InteractionSynchronous:
+			args = append(args, "Synchronous")
+		case mdl.InteractionAsynchronous:
+			args = append(args, "Asynchronous")
+		}
+		g.call("", fn, args, func() {
+			defaults := []string{"Relationship"}
+			if r.InteractionStyle == mdl.InteractionAsynchronous {
+				defaults = append(defaults, "Asynchronous")
+			}
+			g.props(r.URL, customTags(r.Tags, defaults...), r.Properties, r.Perspectives)
+		})
+	}
+}
+
+// path returns the path used to identify the element with ID destID in a
+// relationship whose source has ID srcID.
+func (g *generator) path(srcID, destID string) string {
+	var (
+		src  = g.m.Element(srcID)
+		dest = g.m.Element(destID)
+	)
+	switch d := dest.(type) {
+	case *mdl.SoftwareSystem:
+		return d.Name
+	case *mdl.Container:
+		sys := g.m.Parent(d.ID).(*mdl.SoftwareSystem)
+		if c, ok := src.(*mdl.Container); ok && g.m.Parent(c.ID) == sys {
+			return d.Name
+		}
+		return sys.Name + "/" + d.Name
+	case *mdl.Component:
+		c := g.m.Parent(d.ID).(*mdl.Container)
+		sys := g.m.Parent(c.ID).(*mdl.SoftwareSystem)
+		switch s := src.(type) {
+		case *mdl.Component:
+			if g.m.Parent(s.ID) == c {
+				return d.Name
+			}
+		case *mdl.CodeElement:
+			// Code elements of the source component take precedence.
+			if cmp := g.m.Parent(s.ID).(*mdl.Component); g.m.Parent(cmp.ID) == c && !hasCodeElement(cmp, d.Name) {
+				return d.Name
+			}
+		case *mdl.Container:
+			if g.m.Parent(s.ID) == sys {
+				return c.Name + "/" + d.Name
+			}
+		}
+		return sys.Name + "/" + c.Name + "/" + d.Name
+	case *mdl.CodeElement:
+		cmp := g.m.Parent(d.ID).(*mdl.Component)
+		c := g.m.Parent(cmp.ID).(*mdl.Container)
+		sys := g.m.Parent(c.ID).(*mdl.SoftwareSystem)
+		switch s := src.(type) {
+		case *mdl.CodeElement:
+			scmp := g.m.Parent(s.ID).(*mdl.Component)
+			if scmp == cmp {
+				return d.Name
+			}
+			if g.m.Parent(scmp.ID) == c {
+				return cmp.Name + "/" + d.Name
+			}
+		case *mdl.Component:
+			if g.m.Parent(s.ID) == c {
+				return cmp.Name + "/" + d.Name
+			}
+		}
+		return sys.Name + "/" + c.Name + "/" + cmp.Name + "/" + d.Name
+	}
+	return ""
+}
+
+// views generates the views and styles.
+func (g *generator) views() {
+	vs := g.d.Views
+	for _, v := range vs.LandscapeViews {
+		g.view("SystemLandscapeView", nil, v.ViewProps, func() {
+			if v.EnterpriseBoundaryVisible != nil && *v.EnterpriseBoundaryVisible {
+				g.line("EnterpriseBoundaryVisible()")
+			}
+		})
+	}
+	for _, v := range vs.ContextViews {
+		g.view("SystemContextView", []string{g.ref(v.SoftwareSystemID)}, v.ViewProps, func() {
+			if v.EnterpriseBoundaryVisible != nil && *v.EnterpriseBoundaryVisible {
+				g.line("EnterpriseBoundaryVisible()")
+			}
+		})
+	}
+	for _, v := range vs.ContainerViews {
+		g.view("ContainerView", []string{g.ref(v.SoftwareSystemID)}, v.ViewProps, func() {
+			if v.SystemBoundariesVisible != nil && *v.SystemBoundariesVisible {
+				g.line("SystemBoundariesVisible()")
+			}
+		})
+	}
+	for _, v := range vs.ComponentViews {
+		g.view("ComponentView", []string{g.ref(v.ContainerID)}, v.ViewProps, func() {
+			if v.ContainerBoundariesVisible != nil && *v.ContainerBoundariesVisible {
+				g.line("ContainerBoundariesVisible()")
+			}
+		})
+	}
+	for _, v := range vs.CodeViews {
+		g.view("CodeView", []string{g.ref(v.ComponentID)}, v.ViewProps, nil)
+	}
+	for _, v := range vs.DynamicViews {
+		scope := "Global"
+		if v.ElementID != "" {
+			scope = g.ref(v.ElementID)
+		}
+		g.view("DynamicView", []string{scope}, v.ViewProps, nil)
+	}
+	for _, v := range vs.DeploymentViews {
+		scope := "Global"
+		if v.SoftwareSystemID != "" {
+			scope = g.ref(v.SoftwareSystemID)
+		}
+		g.view("DeploymentView", []string{scope, strconv.Quote(v.Environment)}, v.ViewProps, nil)
+	}
+	if vs.Styles != nil && (len(vs.Styles.Elements) > 0 || len(vs.Styles.Relationships) > 0) {
+		g.line("")
+		g.call("", "Styles", nil, func() { g.styles(vs.Styles) })
+	}
+}
+
+// view generates the DSL for a view. args are the arguments that precede the
+// view key and extra generates the view kind specific expressions.
+func (g *generator) view(fn string, args []string, props *mdl.ViewProps, extra func()) {
+	dynamic := fn == "DynamicView"
+	args = append(args, strconv.Quote(props.Key))
+	if props.Description != "" {
+		args = append(args, strconv.Quote(props.Description))
+	}
+	g.line("")
+	g.call("", fn, args, func() {
+		if props.Title != "" {
+			g.line("Title(%s)", strconv.Quote(props.Title))
+		}
+		if props.PaperSize != mdl.SizeUndefined {
+			g.line("PaperSize(%s)", paperSize(props.PaperSize))
+		}
+		if props.AutoLayout != nil {
+			g.autoLayout(props.AutoLayout)
+		}
+		if extra != nil {
+			extra()
+		}
+		if !dynamic {
+			for _, ev := range props.ElementViews {
+				g.call("", "Add", []string{g.ref(ev.ID)}, func() {
+					if ev.X != nil && ev.Y != nil {
+						g.line("Coord(%d, %d)", *ev.X, *ev.Y)
+					}
+				})
+			}
+		}
+		for _, rv := range g.links(props, dynamic) {
+			r := g.m.Relationship(rv.ID)
+			g.call("", "Link", []string{g.ref(r.SourceID), g.ref(r.DestinationID), strconv.Quote(r.Description)}, func() {
+				if len(rv.Vertices) > 0 {
+					coords := make([]string, 0, 2*len(rv.Vertices))
+					for _, v := range rv.Vertices {
+						coords = append(coords, strconv.Itoa(v.X), strconv.Itoa(v.Y))
+					}
+					g.line("Vertices(%s)", strings.Join(coords, ", "))
+				}
+				if rv.Routing != mdl.RoutingUndefined {
+					g.line("Routing(Routing%s)", enum(rv.Routing))
+				}
+				if rv.Position != nil {
+					g.line("Position(%d)", *rv.Position)
+				}
+			})
+		}
+		if !dynamic {
+			for _, r := range g.unlinked(props) {
+				g.line("Unlink(%s, %s, %s)", g.ref(r.SourceID), g.ref(r.DestinationID), strconv.Quote(r.Description))
+			}
+		}
+		for _, step := range animations(props) {
+			refs := make([]string, len(step.Elements))
+			for i, id := range step.Elements {
+				refs[i] = g.ref(id)
+			}
+			g.line("AnimationStep(%s)", strings.Join(refs, ", "))
+		}
+	})
+}
+
+// autoLayout generates the AutoLayout expression for l.
+func (g *generator) autoLayout(l *mdl.AutoLayout) {
+	rank := "RankTopBottom"
+	if l.RankDirection != mdl.RankUndefined {
+		rank = "Rank" + enum(l.RankDirection)
+	}
+	g.call("", "AutoLayout", []string{rank}, func() {
+		if l.RankSep != nil && *l.RankSep != 300 {
+			g.line("RankSeparation(%d)", *l.RankSep)
+		}
+		if l.NodeSep != nil && *l.NodeSep != 600 {
+			g.line("NodeSeparation(%d)", *l.NodeSep)
+		}
+		if l.EdgeSep != nil && *l.EdgeSep != 200 {
+			g.line("EdgeSeparation(%d)", *l.EdgeSep)
+		}
+		if l.Vertices != nil && *l.Vertices {
+			g.line("RenderVertices()")
+		}
+	})
+}
+
+// styles generates the element and relationship styles.
+func (g *generator) styles(s *mdl.Styles) {
+	optInt := func(fn string, v *int) {
+		if v != nil {
+			g.line("%s(%d)", fn, *v)
+		}
+	}
+	optStr := func(fn, v string) {
+		if v != "" {
+			g.line("%s(%s)", fn, strconv.Quote(v))
+		}
+	}
+	for _, es := range s.Elements {
+		g.call("", "ElementStyle", []string{strconv.Quote(es.Tag)}, func() {
+			if es.Shape != mdl.ShapeUndefined {
+				g.line("Shape(Shape%s)", enum(es.Shape))
+			}
+			optStr("Icon", es.Icon)
+			optInt("Width", es.Width)
+			optInt("Height", es.Height)
+			optStr("Background", es.Background)
+			optStr("Color", es.Color)
+			optStr("Stroke", es.Stroke)
+			optInt("FontSize", es.FontSize)
+			if es.Border != mdl.BorderUndefined {
+				g.line("Border(Border%s)", enum(es.Border))
+			}
+			optInt("Opacity", es.Opacity)
+			if es.Metadata != nil && *es.Metadata {
+				g.line("ShowMetadata()")
+			}
+			if es.Description != nil && *es.Description {
+				g.line("ShowDescription()")
+			}
+		})
+	}
+	for _, rs := range s.Relationships {
+		g.call("", "RelationshipStyle", []string{strconv.Quote(rs.Tag)}, func() {
+			optInt("Thickness", rs.Thickness)
+			optStr("Color", rs.Color)
+			optInt("FontSize", rs.FontSize)
+			optInt("Width", rs.Width)
+			if rs.Dashed != nil {
+				if *rs.Dashed {
+					g.line("Dashed()")
+				} else {
+					g.line("Solid()")
+				}
+			}
+			if rs.Routing != mdl.RoutingUndefined {
+				g.line("Routing(Routing%s)", enum(rs.Routing))
+			}
+			optInt("Position", rs.Position)
+			optInt("Opacity", rs.Opacity)
+		})
+	}
+}
+
+// nameVariables computes the names of the variables used to reference the
+// elements in views and container instances.
+func (g *generator) nameVariables() {
+	refs := make(map[string]bool)
+	for _, v := range g.allViews() {
+		props := v.Props()
+		switch vv := v.(type) {
+		case *mdl.ContextView:
+			refs[vv.SoftwareSystemID] = true
+		case *mdl.ContainerView:
+			refs[vv.SoftwareSystemID] = true
+		case *mdl.ComponentView:
+			refs[vv.ContainerID] = true
+		case *mdl.CodeView:
+			refs[vv.ComponentID] = true
+		case *mdl.DynamicView:
+			refs[vv.ElementID] = true
+		case *mdl.DeploymentView:
+			refs[vv.SoftwareSystemID] = true
+		}
+		_, dynamic := v.(*mdl.DynamicView)
+		if !dynamic {
+			for _, ev := range props.ElementViews {
+				refs[ev.ID] = true
+			}
+		}
+		for _, rv := range g.links(props, dynamic) {
+			r := g.m.Relationship(rv.ID)
+			refs[r.SourceID] = true
+			refs[r.DestinationID] = true
+		}
+		if !dynamic {
+			for _, r := range g.unlinked(props) {
+				refs[r.SourceID] = true
+				refs[r.DestinationID] = true
+			}
+		}
+		for _, a := range props.Animations {
+			for _, id := range a.Elements {
+				refs[id] = true
+			}
+		}
+	}
+	var walk func([]*mdl.DeploymentNode)
+	walk = func(nodes []*mdl.DeploymentNode) {
+		for _, n := range nodes {
+			for _, ci := range n.ContainerInstances {
+				refs[ci.ContainerID] = true
+			}
+			walk(n.Children)
+		}
+	}
+	walk(g.m.DeploymentNodes)
+
+	name := func(id, name, suffix string) {
+		if refs[id] {
+			g.vars[id] = g.varName(name, suffix)
+		}
+	}
+	for _, p := range g.m.People {
+		name(p.ID, p.Name, "Person")
+	}
+	for _, s := range g.m.Systems {
+		name(s.ID, s.Name, "System")
+		for _, c := range s.Containers {
+			name(c.ID, c.Name, "Container")
+			for _, cmp := range c.Components {
+				name(cmp.ID, cmp.Name, "Component")
+				for _, ce := range cmp.CodeElements {
+					name(ce.ID, ce.Name, "Code")
+				}
+			}
+		}
+	}
+	walk = func(nodes []*mdl.DeploymentNode) {
+		for _, n := range nodes {
+			name(n.ID, n.Name, n.Environment)
+			for _, inf := range n.InfrastructureNodes {
+				name(inf.ID, inf.Name, inf.Environment)
+			}
+			for _, ci := range n.ContainerInstances {
+				if c, ok := g.m.Element(ci.ContainerID).(*mdl.Container); ok {
+					name(ci.ID, ci.Environment+" "+c.Name+" Instance", "")
+				}
+			}
+			walk(n.Children)
+		}
+	}
+	walk(g.m.DeploymentNodes)
+}
+
+// varName returns a unique Go variable name for an element with the given
+// name. suffix is appended to the name if the name is already in use.
+func (g *generator) varName(name, suffix string) string {
+	base := camelize(name)
+	if base == "" || !unicode.IsLetter([]rune(base)[0]) {
+		base = "E" + base
+	}
+	n := base
+	if g.names[n] || reserved[n] {
+		base += camelize(suffix)
+		n = base
+	}
+	for i := 2; g.names[n] || reserved[n]; i++ {
+		n = base + strconv.Itoa(i)
+	}
+	g.names[n] = true
+	return n
+}
+
+// declare returns the assignment prefix for a top level element: the
+// declaration of the corresponding variable if the element is referenced,
+// the empty string otherwise.
+func (g *generator) declare(id string) string {
+	if v, ok := g.vars[id]; ok {
+		if g.declared[id] {
+			return v + " = "
+		}
+		return "var " + v + " = "
+	}
+	return ""
+}
+
+// assign returns the assignment prefix for a nested element which must have
+// been forward declared.
+func (g *generator) assign(id string) string {
+	if v, ok := g.vars[id]; ok {
+		return v + " = "
+	}
+	return ""
+}
+
+// forwardDeclare generates the declarations of the variables referencing the
+// elements with the given IDs. Variables that have already been declared are
+// skipped.
+func (g *generator) forwardDeclare(ids []string) {
+	var decls []string
+	for _, id := range ids {
+		v, ok := g.vars[id]
+		if !ok || g.declared[id] {
+			continue
+		}
+		g.declared[id] = true
+		var typ string
+		switch g.m.Element(id).(type) {
+		case *mdl.Person:
+			typ = "Person"
+		case *mdl.SoftwareSystem:
+			typ = "SoftwareSystem"
+		case *mdl.Container:
+			typ = "Container"
+		case *mdl.Component:
+			typ = "Component"
+		case *mdl.CodeElement:
+			typ = "CodeElement"
+		case *mdl.DeploymentNode:
+			typ = "DeploymentNode"
+		case *mdl.InfrastructureNode:
+			typ = "InfrastructureNode"
+		case *mdl.ContainerInstance:
+			typ = "ContainerInstance"
+		}
+		decls = append(decls, v+" *expr."+typ)
+	}
+	if len(decls) == 0 {
+		return
+	}
+	g.importExpr = true
+	g.line("var (")
+	g.line("// Forward declarations so variables can be used in views.")
+	for _, d := range decls {
+		g.line(d)
+	}
+	g.line(")")
+	g.line("")
+}
+
+// ref returns the variable referencing the element with the given ID.
+func (g *generator) ref(id string) string {
+	return g.vars[id]
+}
+
+// links returns the relationship views that must be generated explicitly:
+// all relationships in dynamic views and relationships with custom rendering
+// (vertices, routing or label position) in other views. Relationships whose
+// ends are not both part of the view are ignored.
+func (g *generator) links(props *mdl.ViewProps, dynamic bool) []*mdl.RelationshipView {
+	in := make(map[string]bool)
+	for _, ev := range props.ElementViews {
+		in[ev.ID] = true
+	}
+	var res []*mdl.RelationshipView
+	for _, rv := range props.RelationshipViews {
+		r := g.m.Relationship(rv.ID)
+		if r == nil || r.LinkedRelationshipID != "" {
+			continue
+		}
+		if len(in) > 0 && (!in[r.SourceID] || !in[r.DestinationID]) {
+			continue
+		}
+		if dynamic || len(rv.Vertices) > 0 || rv.Routing != mdl.RoutingUndefined || rv.Position != nil {
+			res = append(res, rv)
+		}
+	}
+	if dynamic {
+		sort.SliceStable(res, func(i, j int) bool { return lessOrder(res[i].Order, res[j].Order) })
+	}
+	return res
+}
+
+// unlinked returns the relationships between elements of the view that the
+// view does not include. Such relationships would otherwise be added
+// automatically when the design is evaluated.
+func (g *generator) unlinked(props *mdl.ViewProps) []*mdl.Relationship {
+	in := make(map[string]bool)
+	for _, ev := range props.ElementViews {
+		in[ev.ID] = true
+	}
+	rvs := make(map[string]bool)
+	for _, rv := range props.RelationshipViews {
+		rvs[rv.ID] = true
+	}
+	var res []*mdl.Relationship
+	g.m.IterateRelationships(func(r *mdl.Relationship) {
+		if r.LinkedRelationshipID != "" || rvs[r.ID] {
+			return
+		}
+		if in[r.SourceID] && in[r.DestinationID] {
+			res = append(res, r)
+		}
+	})
+	return res
+}
+
+// allViews returns all the views of the design.
+func (g *generator) allViews() []mdl.View {
+	if g.d.Views == nil {
+		return nil
+	}
+	return g.d.Views.All()
+}
+
+// call generates a call to the DSL function fn with the given arguments. body
+// generates the content of the anonymous function given as last argument, the
+// function is omitted if body does not generate any code.
+func (g *generator) call(prefix, fn string, args []string, body func()) {
+	if body != nil {
+		buf := g.buf
+		g.buf = &bytes.Buffer{}
+		body()
+		if content := strings.TrimSpace(g.buf.String()); content != "" {
+			args = append(args, "func() {\n"+content+"\n}")
+		}
+		g.buf = buf
+	}
+	g.line("%s%s(%s)", prefix, fn, strings.Join(args, ", "))
+}
+
+// line writes a line of code.
+func (g *generator) line(format string, a ...interface{}) {
+	fmt.Fprintf(g.buf, format+"\n", a...)
+}
+
+// camelize returns the CamelCase concatenation of the words in s.
+func camelize(s string) string {
+	var b strings.Builder
+	for _, w := range strings.FieldsFunc(strings.ReplaceAll(s, "'", ""), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		rs := []rune(w)
+		rs[0] = unicode.ToUpper(rs[0])
+		b.WriteString(string(rs))
+	}
+	return b.String()
+}
+
+// animations returns the animation steps of the view sorted by order.
+func animations(props *mdl.ViewProps) []*mdl.AnimationStep {
+	steps := make([]*mdl.AnimationStep, len(props.Animations))
+	copy(steps, props.Animations)
+	sort.SliceStable(steps, func(i, j int) bool { return steps[i].Order < steps[j].Order })
+	return steps
+}
+
+// environments returns the sorted names of the deployment environments.
+func environments(nodes []*mdl.DeploymentNode) []string {
+	seen := make(map[string]bool)
+	var envs []string
+	for _, n := range nodes {
+		if !seen[n.Environment] {
+			seen[n.Environment] = true
+			envs = append(envs, n.Environment)
+		}
+	}
+	sort.Strings(envs)
+	return envs
+}
+
+// lessOrder compares two dynamic view relationship orders numerically when
+// possible.
+func lessOrder(a, b string) bool {
+	ai, errA := strconv.Atoi(a)
+	bi, errB := strconv.Atoi(b)
+	if errA == nil && errB == nil {
+		return ai < bi
+	}
+	return a < b
+}
+
+// customTags removes the given default tags and the "Element" tag from the
+// comma separated list of tags.
+func customTags(tags string, defaults ...string) string {
+	var res []string
+loop:
+	for _, t := range strings.Split(tags, ",") {
+		t = strings.TrimSpace(t)
+		if t == "" || t == "Element" {
+			continue
+		}
+		for _, d := range defaults {
+			if t == d {
+				continue loop
+			}
+		}
+		for _, r := range res {
+			if t == r {
+				continue loop
+			}
+		}
+		res = append(res, t)
+	}
+	return strings.Join(res, ",")
+}
+
+// strArgs returns the quoted arguments omitting trailing empty values. The
+// first argument is always included.
+func strArgs(vals ...string) []string {
+	last := len(vals) - 1
+	for last > 0 && vals[last] == "" {
+		last--
+	}
+	res := make([]string, last+1)
+	for i := 0; i <= last; i++ {
+		res[i] = strconv.Quote(vals[i])
+	}
+	return res
+}
+
+// quoteAll returns the comma separated list of quoted values.
+func quoteAll(vals []string) string {
+	res := make([]string, len(vals))
+	for i, v := range vals {
+		res[i] = strconv.Quote(v)
+	}
+	return strings.Join(res, ", ")
+}
+
+// paperSize returns the name of the DSL constant for the given paper size.
+func paperSize(p mdl.PaperSizeKind) string {
+	parts := strings.Split(enum(p), "_")
+	if parts[0] == "Slide" && len(parts) == 3 {
+		return "SizeSlide" + parts[1] + "X" + parts[2]
+	}
+	return "Size" + strings.Join(parts, "")
+}
+
+// nestedIDs returns the IDs of the containers, components and code elements
+// of s.
+func nestedIDs(s *mdl.SoftwareSystem) []string {
+	var ids []string
+	for _, c := range s.Containers {
+		ids = append(ids, c.ID)
+		for _, cmp := range c.Components {
+			ids = append(ids, cmp.ID)
+			for _, ce := range cmp.CodeElements {
+				ids = append(ids, ce.ID)
+			}
+		}
+	}
+	return ids
+}
+
+// groupNames returns the distinct non-empty group names in groups in order of
+// first appearance.
+func groupNames(groups []string) []string {
+	var names []string
+	seen := make(map[string]bool)
+	for _, grp := range groups {
+		if grp != "" && !seen[grp] {
+			seen[grp] = true
+			names = append(names, grp)
+		}
+	}
+	return names
+}
+
+// hasCodeElement returns true if cmp has a code element with the given name.
+func hasCodeElement(cmp *mdl.Component, name string) bool {
+	for _, ce := range cmp.CodeElements {
+		if ce.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// sortedKeys returns the keys of m sorted alphabetically.
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// enum returns the string representation of the given enum value.
+func enum(v json.Marshaler) string {
+	b, _ := v.MarshalJSON()
+	return strings.Trim(string(b), `"`)
+}
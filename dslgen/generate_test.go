@@ -0,0 +1,236 @@
+package dslgen_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kylelemons/godebug/diff"
+	"goa.design/goa/v3/eval"
+	goaexpr "goa.design/goa/v3/expr"
+	. "goa.design/model/dsl"
+	"goa.design/model/dslgen"
+	"goa.design/model/expr"
+	"goa.design/model/mdl"
+)
+
+// mainSrc is the source code of the program used to evaluate the generated
+// DSL.
+const mainSrc = `package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	_ "goa.design/model/dslgen/%s/design"
+	"goa.design/model/mdl"
+)
+
+func main() {
+	d, err := mdl.RunDSL()
+	if err != nil {
+		fmt.Fprint(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+	if err := json.NewEncoder(os.Stdout).Encode(d); err != nil {
+		fmt.Fprint(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+}
+`
+
+func TestGenerate(t *testing.T) {
+	d := runDSL(t, func() {
+		Enterprise("Acme")
+		var user = Person("User", "A user.", func() {
+			Tag("Customer")
+		})
+		var shop = SoftwareSystem("Shop", "The shop.", func() {
+			Container("Web", "Web app.", "React", func() {
+				Uses("API", "Calls", "HTTPS")
+			})
+			Container("API", "Backend.", "Go", func() {
+				Tag("infra")
+				Component("Orders", "Manages orders.", "Go")
+				Component("Payments", "Manages payments.", "Go", func() {
+					Uses("Orders", "Reads")
+				})
+			})
+		})
+		SoftwareSystem("Bank", "External bank.", func() {
+			External()
+		})
+		Person("User", func() {
+			Uses("Shop", "Buys from")
+		})
+		DeploymentEnvironment("Production", func() {
+			DeploymentNode("Cloud", "AWS", "Amazon", func() {
+				ContainerInstance("Shop/API")
+			})
+		})
+		Views(func() {
+			SystemLandscapeView("landscape", "All systems.", func() {
+				AddAll()
+				AutoLayout(RankLeftRight)
+			})
+			SystemContextView(shop, "context", func() {
+				Title("Shop context")
+				Add(user)
+				AddNeighbors(shop)
+			})
+			ContainerView(shop, "containers", func() {
+				AddContainers()
+			})
+			ComponentView("Shop/API", "components", func() {
+				AddComponents()
+			})
+			DeploymentView(Global, "Production", "deployment", func() {
+				AddAll()
+			})
+			Styles(func() {
+				ElementStyle("infra", func() {
+					Background("#ff0000")
+				})
+			})
+		})
+	})
+
+	src, err := dslgen.Generate(normalize(t, d), "design")
+	if err != nil {
+		t.Fatalf("failed to generate DSL: %s", err)
+	}
+	if !strings.Contains(string(src), `Title("Shop context")`) {
+		t.Errorf("generated code does not contain view title:\n%s", src)
+	}
+
+	if testing.Short() {
+		t.Skip("skipping compilation of generated code in short mode")
+	}
+	got, err := dslgen.Generate(normalize(t, compile(t, src)), "design")
+	if err != nil {
+		t.Fatalf("failed to generate DSL from compiled design: %s", err)
+	}
+	if string(got) != string(src) {
+		t.Errorf("design does not round trip through generated code:\n%s", diff.Diff(string(src), string(got)))
+	}
+}
+
+func TestGenerateEmpty(t *testing.T) {
+	src, err := dslgen.Generate(&mdl.Design{Name: "Empty"}, "design")
+	if err != nil {
+		t.Fatalf("failed to generate DSL: %s", err)
+	}
+	if !strings.Contains(string(src), `var _ = Design("Empty")`) {
+		t.Errorf("unexpected generated code:\n%s", src)
+	}
+}
+
+func TestPackageName(t *testing.T) {
+	tests := []struct {
+		dir, want string
+	}{
+		{"design", "design"},
+		{filepath.Join("models", "My-Design.v2"), "my_design_v2"},
+		{"big bank", "big_bank"},
+	}
+	for _, tt := range tests {
+		if got := dslgen.PackageName(tt.dir); got != tt.want {
+			t.Errorf("%s: got package name %q, want %q", tt.dir, got, tt.want)
+		}
+	}
+}
+
+func TestGenerateFilteredViews(t *testing.T) {
+	d := &mdl.Design{
+		Name: "Test",
+		Views: &mdl.Views{
+			LandscapeViews: []*mdl.LandscapeView{{ViewProps: &mdl.ViewProps{Key: "landscape"}}},
+			FilteredViews:  []*mdl.FilteredView{{Key: "filtered", BaseKey: "landscape"}},
+		},
+	}
+	_, err := dslgen.Generate(d, "design")
+	if err == nil {
+		t.Fatal("got no error, want error for filtered view")
+	}
+	if want := `filtered view "filtered"`; !strings.Contains(err.Error(), want) {
+		t.Errorf("got error %q, want error containing %q", err.Error(), want)
+	}
+}
+
+// normalize returns a copy of d with elements and views sorted the same way as
+// when the design is serialized.
+func normalize(t *testing.T, d *mdl.Design) *mdl.Design {
+	t.Helper()
+	b, err := json.Marshal(d)
+	if err != nil {
+		t.Fatalf("failed to encode design: %s", err)
+	}
+	var res mdl.Design
+	if err := json.Unmarshal(b, &res); err != nil {
+		t.Fatalf("failed to decode design: %s", err)
+	}
+	return &res
+}
+
+// compile builds and runs a program that evaluates the generated DSL src and
+// returns the resulting design.
+func compile(t *testing.T, src []byte) *mdl.Design {
+	t.Helper()
+	gobin, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go compiler not found")
+	}
+	dir, err := ioutil.TempDir(".", "mdl--")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	if err := os.Mkdir(filepath.Join(dir, "design"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, "design", "design.go"), src, 0644); err != nil {
+		t.Fatal(err)
+	}
+	main := strings.Replace(mainSrc, "%s", filepath.Base(dir), 1)
+	if err := ioutil.WriteFile(filepath.Join(dir, "main.go"), []byte(main), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cmd := exec.Command(gobin, "run", ".")
+	cmd.Dir = dir
+	cmd.Stderr = os.Stderr
+	out, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("failed to compile generated code: %s\n%s", err, src)
+	}
+	var d mdl.Design
+	if err := json.Unmarshal(out, &d); err != nil {
+		t.Fatalf("failed to decode design: %s", err)
+	}
+	return &d
+}
+
+// runDSL resets the DSL engine, evaluates a design defined with the given DSL
+// and returns the corresponding model.
+func runDSL(t *testing.T, dsl func()) *mdl.Design {
+	t.Helper()
+	eval.Reset()
+	goaexpr.Root = &goaexpr.RootExpr{GeneratedTypes: &goaexpr.GeneratedRoot{}}
+	expr.Root = &expr.Design{Model: &expr.Model{}, Views: &expr.Views{}}
+	expr.Registry = make(map[string]interface{})
+	for _, r := range []eval.Root{goaexpr.Root, goaexpr.Root.GeneratedTypes, expr.Root} {
+		if err := eval.Register(r); err != nil {
+			t.Fatalf("failed to register root: %s", err)
+		}
+	}
+	Design("Test", dsl)
+	d, err := mdl.RunDSL()
+	if err != nil {
+		t.Fatalf("failed to run DSL: %s", err)
+	}
+	return d
+}
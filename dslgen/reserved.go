@@ -0,0 +1,156 @@
+package dslgen
+
+// reserved lists the identifiers exported by the dsl package. Generated
+// variable names must not shadow them since the package is dot imported.
+var reserved = map[string]bool{
+	"Add":                        true,
+	"AddAll":                     true,
+	"AddCodeElements":            true,
+	"AddComponents":              true,
+	"AddContainers":              true,
+	"AddDefault":                 true,
+	"AddImpliedRelationships":    true,
+	"AddInfluencers":             true,
+	"AddNeighbors":               true,
+	"AnimationStep":              true,
+	"Archetype":                  true,
+	"Asynchronous":               true,
+	"AutoLayout":                 true,
+	"Background":                 true,
+	"Border":                     true,
+	"BorderDashed":               true,
+	"BorderDotted":               true,
+	"BorderKind":                 true,
+	"BorderSolid":                true,
+	"Channel":                    true,
+	"CodeElement":                true,
+	"CodeView":                   true,
+	"Color":                      true,
+	"Component":                  true,
+	"ComponentView":              true,
+	"Contact":                    true,
+	"Container":                  true,
+	"ContainerBoundariesVisible": true,
+	"ContainerInstance":          true,
+	"ContainerView":              true,
+	"Coord":                      true,
+	"Dashed":                     true,
+	"Delivers":                   true,
+	"DeploymentEnvironment":      true,
+	"DeploymentNode":             true,
+	"DeploymentView":             true,
+	"Description":                true,
+	"Design":                     true,
+	"DynamicView":                true,
+	"EdgeSeparation":             true,
+	"ElementStyle":               true,
+	"Enterprise":                 true,
+	"EnterpriseBoundaryVisible":  true,
+	"Exclude":                    true,
+	"External":                   true,
+	"FilterTag":                  true,
+	"FilteredView":               true,
+	"FontSize":                   true,
+	"Global":                     true,
+	"Group":                      true,
+	"Header":                     true,
+	"HealthCheck":                true,
+	"Height":                     true,
+	"Icon":                       true,
+	"InfrastructureNode":         true,
+	"InstanceID":                 true,
+	"Instances":                  true,
+	"InteractionStyleKind":       true,
+	"InteractsWith":              true,
+	"Interval":                   true,
+	"Link":                       true,
+	"NoRelationship":             true,
+	"NodeSeparation":             true,
+	"OnCall":                     true,
+	"Opacity":                    true,
+	"OwnedBy":                    true,
+	"Owner":                      true,
+	"PaperSize":                  true,
+	"PaperSizeKind":              true,
+	"Person":                     true,
+	"Perspective":                true,
+	"Position":                   true,
+	"Prop":                       true,
+	"RankBottomTop":              true,
+	"RankDirectionKind":          true,
+	"RankLeftRight":              true,
+	"RankRightLeft":              true,
+	"RankSeparation":             true,
+	"RankTopBottom":              true,
+	"RelationshipStyle":          true,
+	"Remove":                     true,
+	"RemoveTagged":               true,
+	"RemoveUnreachable":          true,
+	"RemoveUnrelated":            true,
+	"RenderVertices":             true,
+	"RequireOwners":              true,
+	"Routing":                    true,
+	"RoutingCurved":              true,
+	"RoutingDirect":              true,
+	"RoutingKind":                true,
+	"RoutingOrthogonal":          true,
+	"Shape":                      true,
+	"ShapeBox":                   true,
+	"ShapeCircle":                true,
+	"ShapeComponent":             true,
+	"ShapeCylinder":              true,
+	"ShapeEllipse":               true,
+	"ShapeFolder":                true,
+	"ShapeHexagon":               true,
+	"ShapeKind":                  true,
+	"ShapeMobileDeviceLandscape": true,
+	"ShapeMobileDevicePortrait":  true,
+	"ShapePerson":                true,
+	"ShapePipe":                  true,
+	"ShapeRobot":                 true,
+	"ShapeRoundedBox":            true,
+	"ShapeWebBrowser":            true,
+	"ShowDescription":            true,
+	"ShowMetadata":               true,
+	"SizeA0Landscape":            true,
+	"SizeA0Portrait":             true,
+	"SizeA1Landscape":            true,
+	"SizeA1Portrait":             true,
+	"SizeA2Landscape":            true,
+	"SizeA2Portrait":             true,
+	"SizeA3Landscape":            true,
+	"SizeA3Portrait":             true,
+	"SizeA4Landscape":            true,
+	"SizeA4Portrait":             true,
+	"SizeA5Landscape":            true,
+	"SizeA5Portrait":             true,
+	"SizeA6Landscape":            true,
+	"SizeA6Portrait":             true,
+	"SizeLegalLandscape":         true,
+	"SizeLegalPortrait":          true,
+	"SizeLetterLandscape":        true,
+	"SizeLetterPortrait":         true,
+	"SizeSlide16X10":             true,
+	"SizeSlide16X9":              true,
+	"SizeSlide4X3":               true,
+	"SoftwareSystem":             true,
+	"Solid":                      true,
+	"Stroke":                     true,
+	"Styles":                     true,
+	"Synchronous":                true,
+	"SystemBoundariesVisible":    true,
+	"SystemContextView":          true,
+	"SystemLandscapeView":        true,
+	"Tag":                        true,
+	"Team":                       true,
+	"Thickness":                  true,
+	"Timeout":                    true,
+	"Title":                      true,
+	"URL":                        true,
+	"Unlink":                     true,
+	"Uses":                       true,
+	"Version":                    true,
+	"Vertices":                   true,
+	"Views":                      true,
+	"Width":                      true,
+}
@@ -0,0 +1,38 @@
+package expr
+
+import (
+	"fmt"
+)
+
+// Archetype describes a reusable element definition. Elements created from an
+// archetype inherit its description, technology, tags, URL, properties and
+// perspectives and are tagged with the archetype name.
+type Archetype struct {
+	// Name of archetype.
+	Name string
+	// Description inherited by elements that do not define one.
+	Description string
+	// Technology inherited by elements that do not define one.
+	Technology string
+	// Tags added to the elements.
+	Tags string
+	// URL inherited by elements that do not define one.
+	URL string
+	// Properties added to the elements, elements may override the values.
+	Properties map[string]string
+	// Perspectives added to the elements, elements may override the
+	// descriptions.
+	Perspectives []*Perspective
+	// Shape used to render the elements if any.
+	Shape ShapeKind
+}
+
+// EvalName returns the generic expression name used in error messages.
+func (a *Archetype) EvalName() string {
+	return fmt.Sprintf("archetype %q", a.Name)
+}
+
+// MergeTags adds the given tags. It skips tags already present in a.Tags.
+func (a *Archetype) MergeTags(tags ...string) {
+	a.Tags = mergeTags(a.Tags, tags)
+}
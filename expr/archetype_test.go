@@ -0,0 +1,37 @@
+package expr
+
+import (
+	"testing"
+)
+
+func TestArchetypeEvalName(t *testing.T) {
+	t.Parallel()
+	a := Archetype{Name: "foo"}
+	if got, want := a.EvalName(), `archetype "foo"`; got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}
+
+func TestArchetypeMergeTags(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		tags string
+		add  []string
+		want string
+	}{
+		{tags: "", add: []string{"foo"}, want: "foo"},
+		{tags: "foo", add: []string{"bar"}, want: "foo,bar"},
+		{tags: "foo,bar", add: []string{"bar", "baz"}, want: "foo,bar,baz"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.want, func(t *testing.T) {
+			t.Parallel()
+			a := Archetype{Tags: tt.tags}
+			a.MergeTags(tt.add...)
+			if a.Tags != tt.want {
+				t.Errorf("got %s, want %s", a.Tags, tt.want)
+			}
+		})
+	}
+}
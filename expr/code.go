@@ -0,0 +1,84 @@
+package expr
+
+import (
+	"fmt"
+	"strings"
+)
+
+type (
+	// CodeElement represents a code element (type, interface, function etc.)
+	// of a component.
+	CodeElement struct {
+		*Element
+		Component *Component
+	}
+
+	// CodeElements is a slice of code elements that can be easily converted
+	// into a slice of ElementHolder.
+	CodeElements []*CodeElement
+)
+
+// EvalName returns the generic expression name used in error messages.
+func (c *CodeElement) EvalName() string {
+	if c.Name == "" {
+		return "unnamed code element"
+	}
+	return fmt.Sprintf("code element %q", c.Name)
+}
+
+// Finalize adds the 'Code Element' tag ands finalizes relationships.
+func (c *CodeElement) Finalize() {
+	c.PrefixTags("Element", "Code Element")
+	c.Element.Finalize()
+}
+
+// Elements returns a slice of ElementHolder that contains the elements of c.
+func (cs CodeElements) Elements() []ElementHolder {
+	res := make([]ElementHolder, len(cs))
+	for i, cc := range cs {
+		res[i] = cc
+	}
+	return res
+}
+
+// CodeElement returns the code element with the given name if any, nil
+// otherwise.
+func (c *Component) CodeElement(name string) *CodeElement {
+	for _, ce := range c.CodeElements {
+		if ce.Name == name {
+			return ce
+		}
+	}
+	return nil
+}
+
+// AddCodeElement adds the given code element to the component. If there is
+// already a code element with the given name then AddCodeElement merges both
+// definitions. The merge algorithm:
+//
+//    * overrides the description, technology and URL if provided,
+//    * merges any new tag or propery into the existing tags and properties,
+//
+// AddCodeElement returns the new or merged code element.
+func (c *Component) AddCodeElement(ce *CodeElement) *CodeElement {
+	existing := c.CodeElement(ce.Name)
+	if existing == nil {
+		Identify(ce)
+		c.CodeElements = append(c.CodeElements, ce)
+		return ce
+	}
+	if ce.Description != "" {
+		existing.Description = ce.Description
+	}
+	if ce.Technology != "" {
+		existing.Technology = ce.Technology
+	}
+	if ce.URL != "" {
+		existing.URL = ce.URL
+	}
+	existing.MergeTags(strings.Split(ce.Tags, ",")...)
+	if olddsl := existing.DSLFunc; olddsl != nil {
+		existing.DSLFunc = func() { olddsl(); ce.DSLFunc() }
+	}
+	return existing
+}
@@ -0,0 +1,96 @@
+package expr
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestCodeElementEvalName(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name, want string
+	}{
+		{name: "", want: "unnamed code element"},
+		{name: "foo", want: `code element "foo"`},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ce := CodeElement{
+				Element: &Element{
+					Name: tt.name,
+				},
+			}
+			if got := ce.EvalName(); got != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCodeElementFinalize(t *testing.T) {
+	t.Parallel()
+	ce := CodeElement{
+		Element: &Element{
+			Name: "foo",
+		},
+	}
+	tests := []struct {
+		pre  func()
+		want string
+	}{
+		{want: ""},
+		{pre: func() { ce.Tags = "foo" }, want: "foo"},
+		{pre: func() { ce.Finalize() }, want: "Element,Code Element,foo"},
+	}
+	for i, tt := range tests {
+		tt := tt
+		t.Run(fmt.Sprint(i), func(t *testing.T) {
+			if tt.pre != nil {
+				tt.pre()
+			}
+			if got := ce.Tags; got != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCodeElementsElements(t *testing.T) {
+	t.Parallel()
+	ces := CodeElements{
+		{Element: &Element{Name: "foo"}},
+		{Element: &Element{Name: "bar"}},
+	}
+	if got := ces.Elements(); len(got) != len(ces) {
+		t.Errorf("got %d, want %d", len(got), len(ces))
+	}
+}
+
+func TestComponentCodeElement(t *testing.T) {
+	t.Parallel()
+	component := Component{
+		CodeElements: CodeElements{
+			{Element: &Element{Name: "foo"}},
+			{Element: &Element{Name: "bar"}},
+		},
+	}
+	tests := []struct {
+		name string
+		want *CodeElement
+	}{
+		{name: "foo", want: component.CodeElements[0]},
+		{name: "bar", want: component.CodeElements[1]},
+		{name: "baz", want: nil},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := component.CodeElement(tt.name); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -8,7 +8,8 @@ type (
 	// Component represents a component.
 	Component struct {
 		*Element
-		Container *Container
+		CodeElements CodeElements
+		Container    *Container
 	}
 
 	// Components is a slice of components that can be easily converted into
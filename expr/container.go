@@ -55,7 +55,7 @@ func (c *Container) Component(name string) *Component {
 // component with the given name then AddComponent merges both definitions. The
 // merge algorithm:
 //
-//    * overrides the description, technology and URL if provided,
+//    * overrides the description, technology, URL and group if provided,
 //    * merges any new tag or propery into the existing tags and properties,
 //
 // AddComponent returns the new or merged component.
@@ -76,6 +76,9 @@ func (c *Container) AddComponent(cmp *Component) *Component {
 		existing.URL = cmp.URL
 	}
 	existing.MergeTags(strings.Split(cmp.Tags, ",")...)
+	if cmp.Group != "" {
+		existing.Group = cmp.Group
+	}
 	if olddsl := existing.DSLFunc; olddsl != nil {
 		existing.DSLFunc = func() { olddsl(); cmp.DSLFunc() }
 	}
@@ -48,9 +48,17 @@ func (d *Design) WalkSets(walk eval.SetWalker) {
 			walk(eval.ToExpressionSet(c.Components))
 		}
 	}
-	// 6. Deployment environments
+	// 6. Code elements
+	for _, s := range d.Model.Systems {
+		for _, c := range s.Containers {
+			for _, cm := range c.Components {
+				walk(eval.ToExpressionSet(cm.CodeElements))
+			}
+		}
+	}
+	// 7. Deployment environments
 	walkDeploymentNodes(d.Model.DeploymentNodes, walk)
-	// 7. Views
+	// 8. Views
 	walk([]eval.Expression{d.Views})
 }
 
@@ -13,11 +13,21 @@ type (
 		Technology    string
 		Tags          string
 		URL           string
+		Group         string
+		Owner         string
 		Properties    map[string]string
+		Perspectives  []*Perspective
 		Relationships []*Relationship
 		DSLFunc       func()
 	}
 
+	// Perspective describes an element or a relationship from a given point of
+	// view (e.g. "Security").
+	Perspective struct {
+		Name        string
+		Description string
+	}
+
 	// ElementHolder provides access to the underlying element.
 	ElementHolder interface {
 		GetElement() *Element
@@ -0,0 +1,23 @@
+package expr
+
+import (
+	"fmt"
+
+	"goa.design/goa/v3/eval"
+)
+
+// Group provides context to the elements defined in a group. Groups cluster
+// people, software systems, containers or components that belong together
+// without being an element themselves.
+type Group struct {
+	// Name of group.
+	Name string
+	// Parent is the design, software system or container expression in which
+	// the group is defined.
+	Parent eval.Expression
+}
+
+// EvalName returns the generic expression name used in error messages.
+func (g *Group) EvalName() string {
+	return fmt.Sprintf("group %q", g.Name)
+}
@@ -15,6 +15,12 @@ type (
 		Systems                 SoftwareSystems
 		DeploymentNodes         []*DeploymentNode
 		AddImpliedRelationships bool
+		// Archetypes lists the archetypes used to define elements.
+		Archetypes []*Archetype
+		// Teams lists the teams that own elements.
+		Teams []*Team
+		// RequireOwners is true if all software systems must have an owner.
+		RequireOwners bool
 	}
 )
 
@@ -28,6 +34,8 @@ func Parent(eh ElementHolder) ElementHolder {
 		return e.System
 	case *Component:
 		return e.Container
+	case *CodeElement:
+		return e.Component
 	default:
 		panic(fmt.Sprintf("unknown element type %T", e)) // bug
 	}
@@ -36,7 +44,8 @@ func Parent(eh ElementHolder) ElementHolder {
 // EvalName is the qualified name of the DSL expression.
 func (m *Model) EvalName() string { return "model" }
 
-// Validate makes sure all element names are unique.
+// Validate makes sure all element names are unique, that element owners are
+// known teams and that software systems have an owner if required.
 func (m *Model) Validate() error {
 	verr := new(eval.ValidationErrors)
 	known := make(map[string]struct{})
@@ -51,6 +60,9 @@ func (m *Model) Validate() error {
 			verr.Add(s, "name already in use")
 		}
 		known[s.Name] = struct{}{}
+		if m.RequireOwners && s.Owner == "" {
+			verr.Add(s, "missing owner, the design requires all software systems to have an owner")
+		}
 		containers := make(map[string]struct{})
 		for _, c := range s.Containers {
 			if _, ok := containers[c.Name]; ok {
@@ -63,10 +75,27 @@ func (m *Model) Validate() error {
 					verr.Add(cm, "name already in use")
 				}
 				components[cm.Name] = struct{}{}
+				codeElements := make(map[string]struct{})
+				for _, ce := range cm.CodeElements {
+					if _, ok := codeElements[ce.Name]; ok {
+						verr.Add(ce, "name already in use")
+					}
+					codeElements[ce.Name] = struct{}{}
+				}
 			}
 		}
 	}
 
+	Iterate(func(e interface{}) {
+		eh, ok := e.(ElementHolder)
+		if !ok {
+			return
+		}
+		if o := eh.GetElement().Owner; o != "" && m.Team(o) == nil {
+			verr.Add(e.(eval.Expression), "unknown owner %q, owner must be a team", o)
+		}
+	})
+
 	// Finalize all relationship destination now that the DSL has been executed.
 	IterateRelationships(func(r *Relationship) {
 		if r.Destination != nil {
@@ -85,6 +114,16 @@ func (m *Model) Validate() error {
 	return verr
 }
 
+// Team returns the team with the given name if any, nil otherwise.
+func (m *Model) Team(name string) *Team {
+	for _, t := range m.Teams {
+		if t.Name == name {
+			return t
+		}
+	}
+	return nil
+}
+
 // Finalize adds all implied relationships if needed.
 func (m *Model) Finalize() {
 	// Add relationships between container instances.
@@ -127,6 +166,11 @@ func (m *Model) Finalize() {
 				addImpliedRelationships(src, r.Destination, r)
 				addImpliedRelationships(s.Container, r.Destination, r)
 				addImpliedRelationships(s.Container.System, r.Destination, r)
+			case *CodeElement:
+				addImpliedRelationships(src, r.Destination, r)
+				addImpliedRelationships(s.Component, r.Destination, r)
+				addImpliedRelationships(s.Component.Container, r.Destination, r)
+				addImpliedRelationships(s.Component.Container.System, r.Destination, r)
 			}
 		}
 	})
@@ -166,10 +210,13 @@ func (m *Model) DeploymentNode(env, name string) *DeploymentNode {
 
 // FindElement finds the element with the given path in the given scope. The path must be one of:
 //
-//    - "<Person>", "<SoftwareSystem>", "<SoftwareSystem>/<Container>" or "<SoftwareSystem>/<Container>/<Component>"
-//    - "<Container>" (if container is a child of the software system scope)
-//    - "<Component>" (if component is a child of the container scope)
-//    - "<Container>/<Component>" (if container is a child of the software system scope)
+//   - "<Person>", "<SoftwareSystem>", "<SoftwareSystem>/<Container>", "<SoftwareSystem>/<Container>/<Component>"
+//     or "<SoftwareSystem>/<Container>/<Component>/<CodeElement>"
+//   - "<Container>" (if container is a child of the software system scope)
+//   - "<Component>" (if component is a child of the container scope or a sibling of the component scope)
+//   - "<CodeElement>" (if code element is a child of the component scope)
+//   - "<Container>/<Component>" (if container is a child of the software system scope)
+//   - "<Component>/<CodeElement>" (if component is a child of the container scope or a sibling of the component scope)
 //
 // The scope may be nil in which case the path must be rooted with a top level
 // element (person or software system).
@@ -186,6 +233,12 @@ func (m *Model) FindElement(scope ElementHolder, path string) (eh ElementHolder,
 			if c := s.Component(path); c != nil {
 				eh = c
 			}
+		case *Component:
+			if ce := s.CodeElement(path); ce != nil {
+				eh = ce
+			} else if c := s.Container.Component(path); c != nil {
+				eh = c
+			}
 		}
 		if eh == nil {
 			if p := m.Person(path); p != nil {
@@ -197,12 +250,25 @@ func (m *Model) FindElement(scope ElementHolder, path string) (eh ElementHolder,
 			}
 		}
 	case 2:
-		if s, ok := scope.(*SoftwareSystem); ok {
+		var container *Container
+		switch s := scope.(type) {
+		case *SoftwareSystem:
 			if c := s.Container(elems[0]); c != nil {
 				if cmp := c.Component(elems[1]); cmp != nil {
 					eh = cmp
 				}
 			}
+		case *Container:
+			container = s
+		case *Component:
+			container = s.Container
+		}
+		if container != nil {
+			if cmp := container.Component(elems[0]); cmp != nil {
+				if ce := cmp.CodeElement(elems[1]); ce != nil {
+					eh = ce
+				}
+			}
 		}
 		if eh == nil {
 			if s := m.SoftwareSystem(elems[0]); s != nil {
@@ -232,6 +298,19 @@ func (m *Model) FindElement(scope ElementHolder, path string) (eh ElementHolder,
 		if eh == nil {
 			return nil, fmt.Errorf("%q does not match the name of a software system, container and component", path)
 		}
+	case 4:
+		if s := m.SoftwareSystem(elems[0]); s != nil {
+			if c := s.Container(elems[1]); c != nil {
+				if cmp := c.Component(elems[2]); cmp != nil {
+					if ce := cmp.CodeElement(elems[3]); ce != nil {
+						eh = ce
+					}
+				}
+			}
+		}
+		if eh == nil {
+			return nil, fmt.Errorf("%q does not match the name of a software system, container, component and code element", path)
+		}
 	default:
 		return nil, fmt.Errorf("too many colons in path")
 	}
@@ -242,9 +321,9 @@ func (m *Model) FindElement(scope ElementHolder, path string) (eh ElementHolder,
 // with the given name then AddPerson merges both definitions. The merge
 // algorithm:
 //
-//    * overrides the description, technology and URL if provided,
-//    * merges any new tag or propery into the existing tags and properties,
-//    * merges any new relationship into the existing relationships.
+//   - overrides the description, technology, URL and group if provided,
+//   - merges any new tag or propery into the existing tags and properties,
+//   - merges any new relationship into the existing relationships.
 //
 // AddPerson returns the new or merged person.
 func (m *Model) AddPerson(p *Person) *Person {
@@ -257,6 +336,9 @@ func (m *Model) AddPerson(p *Person) *Person {
 	if p.Description != "" {
 		existing.Description = p.Description
 	}
+	if p.Group != "" {
+		existing.Group = p.Group
+	}
 	if olddsl := existing.DSLFunc; olddsl != nil {
 		existing.DSLFunc = func() { olddsl(); p.DSLFunc() }
 	}
@@ -267,10 +349,10 @@ func (m *Model) AddPerson(p *Person) *Person {
 // software system with the given name then AddSystem merges both definitions.
 // The merge algorithm:
 //
-//    * overrides the description, technology and URL if provided,
-//    * merges any new tag or propery into the existing tags and properties,
-//    * merges any new relationship into the existing relationships,
-//    * merges any new container into the existing containers.
+//   - overrides the description, technology, URL and group if provided,
+//   - merges any new tag or propery into the existing tags and properties,
+//   - merges any new relationship into the existing relationships,
+//   - merges any new container into the existing containers.
 //
 // AddSystem returns the new or merged software system.
 func (m *Model) AddSystem(s *SoftwareSystem) *SoftwareSystem {
@@ -283,6 +365,9 @@ func (m *Model) AddSystem(s *SoftwareSystem) *SoftwareSystem {
 	if s.Description != "" {
 		existing.Description = s.Description
 	}
+	if s.Group != "" {
+		existing.Group = s.Group
+	}
 	if olddsl := existing.DSLFunc; olddsl != nil {
 		existing.DSLFunc = func() { olddsl(); s.DSLFunc() }
 	}
@@ -293,12 +378,12 @@ func (m *Model) AddSystem(s *SoftwareSystem) *SoftwareSystem {
 // already a deployment node with the given name then AddDeploymentNode merges
 // both definitions. The merge algorithm:
 //
-//    * overrides the description, technology and URL if provided,
-//    * merges any new tag or propery into the existing tags and properties,
-//    * merges any new relationship into the existing relationships,
-//    * merges any new child deployment node into the existing children,
-//    * merges any new container instance or infrastructure nodes into existing
-//      ones.
+//   - overrides the description, technology and URL if provided,
+//   - merges any new tag or propery into the existing tags and properties,
+//   - merges any new relationship into the existing relationships,
+//   - merges any new child deployment node into the existing children,
+//   - merges any new container instance or infrastructure nodes into existing
+//     ones.
 //
 // AddDeploymentNode returns the new or merged deployment node.
 func (m *Model) AddDeploymentNode(d *DeploymentNode) *DeploymentNode {
@@ -361,5 +446,9 @@ func addImpliedRelationships(src ElementHolder, destElem *Element, existing *Rel
 	case *Component:
 		addImpliedRelationships(src, e.Container.Element, existing)
 		addImpliedRelationships(src, e.Container.System.Element, existing)
+	case *CodeElement:
+		addImpliedRelationships(src, e.Component.Element, existing)
+		addImpliedRelationships(src, e.Component.Container.Element, existing)
+		addImpliedRelationships(src, e.Component.Container.System.Element, existing)
 	}
 }
@@ -53,6 +53,9 @@ func Identify(element interface{}) {
 	case *Component:
 		e.ID = idify(e.Container.ID + ":" + e.Name)
 		Registry[e.ID] = e
+	case *CodeElement:
+		e.ID = idify(e.Component.ID + ":" + e.Name)
+		Registry[e.ID] = e
 	case *DeploymentNode:
 		prefix := "dn:" + e.Environment + ":"
 		for f := e.Parent; f != nil; f = f.Parent {
@@ -14,6 +14,8 @@ type (
 		InteractionStyle InteractionStyleKind
 		Tags             string
 		URL              string
+		Properties       map[string]string
+		Perspectives     []*Perspective
 
 		// DestinationPath is used to compute the destination after all DSL has
 		// completed execution.
@@ -64,14 +66,28 @@ func (r *Relationship) Finalize() {
 }
 
 // Dup creates a new relationship with identical description, tags, URL,
-// technology and interaction style as r. Dup also creates a new ID for the
-// result.
+// properties, perspectives, technology and interaction style as r. Dup also
+// creates a new ID for the result. The properties and perspectives are copied
+// so that changes made to the result do not affect r.
 func (r *Relationship) Dup(newSrc, newDest *Element) *Relationship {
+	var props map[string]string
+	if r.Properties != nil {
+		props = make(map[string]string, len(r.Properties))
+		for k, v := range r.Properties {
+			props[k] = v
+		}
+	}
+	var persps []*Perspective
+	for _, p := range r.Perspectives {
+		persps = append(persps, &Perspective{Name: p.Name, Description: p.Description})
+	}
 	dup := &Relationship{
 		Source:           newSrc,
 		InteractionStyle: r.InteractionStyle,
 		Tags:             r.Tags,
 		URL:              r.URL,
+		Properties:       props,
+		Perspectives:     persps,
 		Destination:      newDest,
 		Description:      r.Description,
 		Technology:       r.Technology,
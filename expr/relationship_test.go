@@ -0,0 +1,41 @@
+package expr
+
+import "testing"
+
+func TestRelationshipDup(t *testing.T) {
+	src := &Element{ID: "src", Name: "Source"}
+	dest := &Element{ID: "dest", Name: "Destination"}
+	r := &Relationship{
+		Source:           src,
+		Destination:      dest,
+		Description:      "Uses",
+		Technology:       "HTTPS",
+		InteractionStyle: InteractionAsynchronous,
+		URL:              "https://example.com",
+		Properties:       map[string]string{"timeout": "10s"},
+		Perspectives:     []*Perspective{{Name: "Security", Description: "mTLS required."}},
+	}
+	dup := r.Dup(dest, src)
+	if dup.Source != dest || dup.Destination != src {
+		t.Errorf("got source %v and destination %v, want swapped elements", dup.Source, dup.Destination)
+	}
+	if dup.ID == "" || dup.ID == r.ID {
+		t.Errorf("got ID %q, want new ID", dup.ID)
+	}
+	if dup.Description != r.Description || dup.Technology != r.Technology || dup.InteractionStyle != r.InteractionStyle || dup.URL != r.URL {
+		t.Errorf("got %+v, want same description, technology, interaction style and URL as %+v", dup, r)
+	}
+	if dup.Properties["timeout"] != "10s" || len(dup.Perspectives) != 1 || *dup.Perspectives[0] != *r.Perspectives[0] {
+		t.Fatalf("got properties %v and perspectives %v, want copies of original", dup.Properties, dup.Perspectives)
+	}
+
+	dup.Properties["timeout"] = "1s"
+	dup.Perspectives[0].Description = "None."
+	dup.Perspectives = append(dup.Perspectives, &Perspective{Name: "Operations"})
+	if r.Properties["timeout"] != "10s" {
+		t.Errorf("got original property %q, want %q", r.Properties["timeout"], "10s")
+	}
+	if len(r.Perspectives) != 1 || r.Perspectives[0].Description != "mTLS required." {
+		t.Errorf("got original perspectives %v, want unchanged", r.Perspectives)
+	}
+}
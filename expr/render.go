@@ -27,6 +27,14 @@ func addAllElements(view View) {
 		c := Registry[v.ContainerID].(*Container)
 		v.AddElements(c.System.Containers.Elements()...)
 		v.AddElements(c.Components.Elements()...)
+	case *CodeView:
+		v.AddElements(m.People.Elements()...)
+		v.AddElements(m.Systems.Elements()...)
+		c := Registry[v.ComponentID].(*Component)
+		v.AddElements(c.Container.System.Containers.Elements()...)
+		v.AddElements(c.Container.Components.Elements()...)
+		v.AddElements(c.CodeElements.Elements()...)
+		removeElements(v.Props(), c.Element)
 	case *DeploymentView:
 		for _, n := range m.DeploymentNodes {
 			if n.Environment == "" || n.Environment == v.Environment {
@@ -64,6 +72,15 @@ func addDefaultElements(view View) {
 			v.AddElements(relatedSoftwareSystems(c.Element).Elements()...)
 			v.AddElements(relatedPeople(c.Element).Elements()...)
 		}
+	case *CodeView:
+		c := Registry[v.ComponentID].(*Component)
+		v.AddElements(c.CodeElements.Elements()...)
+		for _, ce := range c.CodeElements {
+			v.AddElements(relatedComponents(ce.Element).Elements()...)
+			v.AddElements(relatedContainers(ce.Element).Elements()...)
+			v.AddElements(relatedSoftwareSystems(ce.Element).Elements()...)
+			v.AddElements(relatedPeople(ce.Element).Elements()...)
+		}
 	case *DeploymentView:
 		addAllElements(v)
 	}
@@ -160,6 +177,12 @@ func addNeighbors(e *Element, view View) {
 		v.AddElements(relatedSoftwareSystems(e).Elements()...)
 		v.AddElements(relatedContainers(e).Elements()...)
 		v.AddElements(relatedComponents(e).Elements()...)
+	case *CodeView:
+		v.AddElements(relatedPeople(e).Elements()...)
+		v.AddElements(relatedSoftwareSystems(e).Elements()...)
+		v.AddElements(relatedContainers(e).Elements()...)
+		v.AddElements(relatedComponents(e).Elements()...)
+		v.AddElements(relatedCodeElements(e).Elements()...)
 	case *DeploymentView:
 		v.AddElements(relatedInfrastructureNodes(e).Elements()...)
 		v.AddElements(relatedContainerInstances(e).Elements()...)
@@ -413,6 +436,32 @@ func relatedComponents(elem *Element) (res Components) {
 	return
 }
 
+// relatedCodeElements returns all code elements the element has a relationship
+// with (either as source or as destination).
+func relatedCodeElements(elem *Element) (res CodeElements) {
+	add := func(c *CodeElement) {
+		for _, es := range res {
+			if es.ID == c.ID {
+				return
+			}
+		}
+		res = append(res, c)
+	}
+	IterateRelationships(func(r *Relationship) {
+		if r.Source.ID == elem.ID {
+			if c, ok := Registry[r.Destination.ID].(*CodeElement); ok {
+				add(c)
+			}
+		}
+		if r.Destination.ID == elem.ID {
+			if c, ok := Registry[r.Source.ID].(*CodeElement); ok {
+				add(c)
+			}
+		}
+	})
+	return
+}
+
 // relatedInfrastructureNodes returns all infrastructure nodes the element has a
 // relationship with (either as source or as destination).
 func relatedInfrastructureNodes(elem *Element) (res InfrastructureNodes) {
@@ -494,3 +543,55 @@ func tagged(v *ViewProps, tag string) (elems []*Element) {
 	}
 	return
 }
+
+// notOwned returns all elements in the view that are not owned by one of the
+// given teams.
+func notOwned(v *ViewProps, teams []string) (elems []*Element) {
+loop:
+	for _, ev := range v.ElementViews {
+		o := owner(ev.Element)
+		for _, t := range teams {
+			if o == t {
+				continue loop
+			}
+		}
+		elems = append(elems, ev.Element)
+	}
+	return
+}
+
+// owner returns the name of the team that owns the given element. Elements
+// that do not define an owner inherit the owner of their parent.
+func owner(e *Element) string {
+	for e != nil {
+		if e.Owner != "" {
+			return e.Owner
+		}
+		switch eh := Registry[e.ID].(type) {
+		case *Container:
+			e = eh.System.Element
+		case *Component:
+			e = eh.Container.Element
+		case *CodeElement:
+			e = eh.Component.Element
+		case *ContainerInstance:
+			e = eh.Container.Element
+		case *InfrastructureNode:
+			e = deploymentElement(eh.Parent)
+		case *DeploymentNode:
+			e = deploymentElement(eh.Parent)
+		default:
+			return ""
+		}
+	}
+	return ""
+}
+
+// deploymentElement returns the element of the deployment node n, nil if n
+// is nil.
+func deploymentElement(n *DeploymentNode) *Element {
+	if n == nil {
+		return nil
+	}
+	return n.Element
+}
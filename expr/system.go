@@ -55,7 +55,7 @@ func (s *SoftwareSystem) Container(name string) *Container {
 // already a container with the given name then AddContainer merges both
 // definitions. The merge algorithm:
 //
-//    * overrides the description, technology and URL if provided,
+//    * overrides the description, technology, URL and group if provided,
 //    * merges any new tag or propery into the existing tags and properties,
 //    * merges any new component into the existing components.
 //
@@ -80,6 +80,9 @@ func (s *SoftwareSystem) AddContainer(c *Container) *Container {
 	for _, cmp := range c.Components {
 		existing.AddComponent(cmp) // will merge if needed
 	}
+	if c.Group != "" {
+		existing.Group = c.Group
+	}
 	if olddsl := existing.DSLFunc; olddsl != nil {
 		existing.DSLFunc = func() { olddsl(); c.DSLFunc() }
 	}
@@ -0,0 +1,22 @@
+package expr
+
+import (
+	"fmt"
+)
+
+// Team describes a team that owns elements of the model.
+type Team struct {
+	// Name of team.
+	Name string
+	// Contact is the email address or name of the person to contact.
+	Contact string
+	// Channel is the chat channel of the team (e.g. "#payments").
+	Channel string
+	// OnCall is the URL of the team on-call schedule or paging service.
+	OnCall string
+}
+
+// EvalName returns the generic expression name used in error messages.
+func (t *Team) EvalName() string {
+	return fmt.Sprintf("team %q", t.Name)
+}
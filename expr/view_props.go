@@ -29,6 +29,7 @@ type (
 		RemoveRelationships []*Relationship
 		RemoveUnreachable   []*Element
 		RemoveUnrelated     bool
+		OwnedBy             []string
 	}
 
 	// ElementView describes an instance of a model element (Person,
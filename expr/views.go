@@ -13,6 +13,7 @@ type (
 		ContextViews    []*ContextView
 		ContainerViews  []*ContainerView
 		ComponentViews  []*ComponentView
+		CodeViews       []*CodeView
 		DynamicViews    []*DynamicView
 		DeploymentViews []*DeploymentView
 		FilteredViews   []*FilteredView
@@ -49,6 +50,12 @@ type (
 		ContainerID                string
 	}
 
+	// CodeView describes a code view for a specific component.
+	CodeView struct {
+		*ViewProps
+		ComponentID string
+	}
+
 	// DynamicView describes a dynamic view for a specified scope.
 	DynamicView struct {
 		*ViewProps
@@ -149,6 +156,7 @@ var (
 	_ View = &ContextView{}
 	_ View = &ContainerView{}
 	_ View = &ComponentView{}
+	_ View = &CodeView{}
 	_ View = &DynamicView{}
 	_ View = &DeploymentView{}
 
@@ -157,6 +165,7 @@ var (
 	_ ViewAdder = &ContextView{}
 	_ ViewAdder = &ContainerView{}
 	_ ViewAdder = &ComponentView{}
+	_ ViewAdder = &CodeView{}
 	_ ViewAdder = &DeploymentView{}
 )
 
@@ -250,6 +259,13 @@ func (vs *Views) Validate() error {
 			validateElementInView(v, e, "RemoveUnreachable", verr)
 		}
 
+		// Make sure all teams used to filter the view are defined.
+		for _, t := range v.OwnedBy {
+			if Root.Model.Team(t) == nil {
+				verr.Add(v, "unknown team %q used in OwnedBy of view %q", t, v.Key)
+			}
+		}
+
 		for i, s := range v.AnimationSteps {
 			// Make sure all animation steps define at least one element.
 			if len(s.Elements) == 0 {
@@ -267,6 +283,9 @@ func (vs *Views) Validate() error {
 
 // Finalize relationships.
 func (vs *Views) Finalize() {
+	// Add the styles of the archetypes that define a shape.
+	addArchetypeStyles(vs)
+
 	// Add influencers to container views.
 	for _, view := range vs.ContainerViews {
 		if view.AddInfluencers {
@@ -305,6 +324,9 @@ func (vs *Views) Finalize() {
 		if vp.RemoveUnrelated {
 			removeElements(vp, unrelated(vp)...)
 		}
+		if len(vp.OwnedBy) > 0 {
+			removeElements(vp, notOwned(vp, vp.OwnedBy)...)
+		}
 		for _, ev := range vp.ElementViews {
 			if ev.NoRelationship {
 				i := 0
@@ -334,6 +356,9 @@ func (vs Views) All() (vps []View) {
 	for _, cv := range vs.ComponentViews {
 		vps = append(vps, cv)
 	}
+	for _, cv := range vs.CodeViews {
+		vps = append(vps, cv)
+	}
 	for _, dv := range vs.DynamicViews {
 		vps = append(vps, dv)
 	}
@@ -427,6 +452,27 @@ func (cv *ComponentView) AddAnimationStep(s *AnimationStep) error {
 	return addAnimationStep(cv.ViewProps, s)
 }
 
+// AddElements adds the given elements to the view if not already present.
+func (cv *CodeView) AddElements(ehs ...ElementHolder) error {
+	for _, eh := range ehs {
+		if !isPSCCC(eh) {
+			return fmt.Errorf("elements of type %T cannot be added to code view", eh)
+		}
+	}
+	addElements(cv.ViewProps, ehs...)
+	return nil
+}
+
+// AddAnimationStep adds the given animation step to the view.
+func (cv *CodeView) AddAnimationStep(s *AnimationStep) error {
+	for _, eh := range s.Elements {
+		if !isPSCCC(eh) {
+			return fmt.Errorf("elements of type %T cannot be added to an animation step in a code view", eh)
+		}
+	}
+	return addAnimationStep(cv.ViewProps, s)
+}
+
 // AddElements adds the given elements to the view if not already present.
 func (dv *DeploymentView) AddElements(ehs ...ElementHolder) error {
 	var nodes []*DeploymentNode
@@ -516,6 +562,16 @@ func isPSCC(eh ElementHolder) bool {
 	return ok
 }
 
+// isPSCCC returns true if element is a person, a software system, a container,
+// a component or a code element, false otherwise.
+func isPSCCC(eh ElementHolder) bool {
+	if isPSCC(eh) {
+		return true
+	}
+	_, ok := eh.(*CodeElement)
+	return ok
+}
+
 // isDCI returns true if element is a deployment node, a container instance or
 // an infrastructure node, false otherwise.
 func isDCI(eh ElementHolder) bool {
@@ -540,6 +596,33 @@ loop:
 	}
 }
 
+// addArchetypeStyles adds the shape of the archetypes used to define elements
+// to the styles of the views. The style uses the archetype name as tag, it is
+// not added if the styles already define a shape for that tag.
+func addArchetypeStyles(vs *Views) {
+	for _, a := range Root.Model.Archetypes {
+		if a.Shape == ShapeUndefined {
+			continue
+		}
+		if vs.Styles == nil {
+			vs.Styles = &Styles{}
+		}
+		found := false
+		for _, es := range vs.Styles.Elements {
+			if es.Tag == a.Name {
+				if es.Shape == ShapeUndefined {
+					es.Shape = a.Shape
+				}
+				found = true
+				break
+			}
+		}
+		if !found {
+			vs.Styles.Elements = append(vs.Styles.Elements, &ElementStyle{Tag: a.Name, Shape: a.Shape})
+		}
+	}
+}
+
 // addDeploymentNodeChildren adds the children, infrastructure nodes and container
 // instances of n to dv and returns true if anything was added, false otherwise.
 func addDeploymentNodeChildren(dv *DeploymentView, n *DeploymentNode) bool {
@@ -10,4 +10,5 @@ require (
 	golang.org/x/sys v0.0.0-20200926100807-9d91bd62050c // indirect
 	golang.org/x/tools v0.0.0-20200923182640-463111b69878
 	gopkg.in/fsnotify.v1 v1.4.7 // indirect
+	gopkg.in/yaml.v3 v3.0.1
 )
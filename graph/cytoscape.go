@@ -0,0 +1,72 @@
+package graph
+
+import (
+	"encoding/json"
+
+	"goa.design/model/mdl"
+)
+
+type (
+	// cytoscape is the root of a Cytoscape.js JSON document.
+	cytoscape struct {
+		Elements cyElements `json:"elements"`
+	}
+
+	// cyElements lists the nodes and edges of the graph.
+	cyElements struct {
+		Nodes []*cyElement `json:"nodes"`
+		Edges []*cyElement `json:"edges"`
+	}
+
+	// cyElement is a Cytoscape.js node or edge.
+	cyElement struct {
+		Data map[string]interface{} `json:"data"`
+	}
+)
+
+// Cytoscape renders the graph of the given design in the Cytoscape.js JSON
+// format. The parent of an element is stored in the "parent" data field so
+// that Cytoscape.js renders elements as compound nodes.
+func Cytoscape(d *mdl.Design) ([]byte, error) {
+	nodes, edges := build(d)
+	doc := &cytoscape{Elements: cyElements{Nodes: []*cyElement{}, Edges: []*cyElement{}}}
+	for _, n := range nodes {
+		data := map[string]interface{}{"id": n.ID}
+		set(data, "name", n.Name)
+		set(data, "type", n.Type)
+		set(data, "parent", n.Parent)
+		set(data, "description", n.Description)
+		set(data, "technology", n.Technology)
+		set(data, "tags", n.Tags)
+		set(data, "url", n.URL)
+		set(data, "group", n.Group)
+		set(data, "owner", n.Owner)
+		set(data, "location", n.Location)
+		set(data, "environment", n.Environment)
+		if len(n.Properties) > 0 {
+			data["properties"] = n.Properties
+		}
+		doc.Elements.Nodes = append(doc.Elements.Nodes, &cyElement{Data: data})
+	}
+	for _, e := range edges {
+		data := map[string]interface{}{"id": e.ID, "source": e.Source, "target": e.Target}
+		set(data, "description", e.Description)
+		set(data, "technology", e.Technology)
+		set(data, "tags", e.Tags)
+		set(data, "url", e.URL)
+		set(data, "interactionStyle", e.InteractionStyle)
+		set(data, "linkedRelationshipId", e.LinkedRelationshipID)
+		if len(e.Properties) > 0 {
+			data["properties"] = e.Properties
+		}
+		doc.Elements.Edges = append(doc.Elements.Edges, &cyElement{Data: data})
+	}
+	return json.MarshalIndent(doc, "", "  ")
+}
+
+// set sets the value of the given key if val is not empty.
+func set(data map[string]interface{}, key, val string) {
+	if val != "" {
+		data[key] = val
+	}
+}
@@ -0,0 +1,22 @@
+/*
+Package graph exports the complete model of a software architecture design as
+a graph suitable for graph analytics tools such as Gephi, yEd or Cytoscape.
+
+Unlike the view centric exporters the graph contains every element of the
+model (people, software systems, containers, components, deployment nodes,
+infrastructure nodes and container instances) as a node and every
+relationship as an edge. This includes the implied relationships added when
+the design is finalized (relationships between parents of related elements
+and between container instances).
+
+Nodes carry the element ID, name, type, parent ID, description, technology,
+tags, URL, group, owning team, location, environment and properties. Edges
+carry the relationship ID, description, technology, tags, URL, interaction
+style and linked relationship ID (for relationships between container
+instances).
+
+GraphML renders the graph in the GraphML format and Cytoscape renders it in
+the Cytoscape.js JSON format where the parent of an element is used to create
+compound nodes.
+*/
+package graph
@@ -0,0 +1,142 @@
+package graph
+
+import (
+	"encoding/json"
+	"sort"
+
+	"goa.design/model/mdl"
+)
+
+type (
+	// node represents a model element.
+	node struct {
+		ID          string
+		Name        string
+		Type        string
+		Parent      string
+		Description string
+		Technology  string
+		Tags        string
+		URL         string
+		Group       string
+		Owner       string
+		Location    string
+		Environment string
+		Properties  map[string]string
+	}
+
+	// edge represents a model relationship.
+	edge struct {
+		ID                   string
+		Source               string
+		Target               string
+		Description          string
+		Technology           string
+		Tags                 string
+		URL                  string
+		InteractionStyle     string
+		LinkedRelationshipID string
+		Properties           map[string]string
+	}
+)
+
+// build returns the nodes and edges of the graph of the given design.
+func build(d *mdl.Design) ([]*node, []*edge) {
+	m := d.Model
+	if m == nil {
+		return nil, nil
+	}
+	var nodes []*node
+	for _, p := range m.People {
+		nodes = append(nodes, &node{ID: p.ID, Name: p.Name, Type: "Person", Description: p.Description,
+			Tags: p.Tags, URL: p.URL, Group: p.Group, Location: enum(p.Location), Properties: p.Properties})
+	}
+	for _, s := range m.Systems {
+		nodes = append(nodes, &node{ID: s.ID, Name: s.Name, Type: "SoftwareSystem", Description: s.Description,
+			Tags: s.Tags, URL: s.URL, Group: s.Group, Location: enum(s.Location), Properties: s.Properties})
+		for _, c := range s.Containers {
+			nodes = append(nodes, &node{ID: c.ID, Name: c.Name, Type: "Container", Parent: s.ID, Description: c.Description,
+				Technology: c.Technology, Tags: c.Tags, URL: c.URL, Group: c.Group, Properties: c.Properties})
+			for _, cmp := range c.Components {
+				nodes = append(nodes, &node{ID: cmp.ID, Name: cmp.Name, Type: "Component", Parent: c.ID, Description: cmp.Description,
+					Technology: cmp.Technology, Tags: cmp.Tags, URL: cmp.URL, Group: cmp.Group, Properties: cmp.Properties})
+				for _, ce := range cmp.CodeElements {
+					nodes = append(nodes, &node{ID: ce.ID, Name: ce.Name, Type: "CodeElement", Parent: cmp.ID, Description: ce.Description,
+						Technology: ce.Technology, Tags: ce.Tags, URL: ce.URL, Properties: ce.Properties})
+				}
+			}
+		}
+	}
+	var addNodes func([]*mdl.DeploymentNode, string)
+	addNodes = func(dns []*mdl.DeploymentNode, parent string) {
+		for _, n := range dns {
+			nodes = append(nodes, &node{ID: n.ID, Name: n.Name, Type: "DeploymentNode", Parent: parent, Description: n.Description,
+				Technology: n.Technology, Tags: n.Tags, URL: n.URL, Environment: n.Environment, Properties: n.Properties})
+			for _, inf := range n.InfrastructureNodes {
+				nodes = append(nodes, &node{ID: inf.ID, Name: inf.Name, Type: "InfrastructureNode", Parent: n.ID, Description: inf.Description,
+					Technology: inf.Technology, Tags: inf.Tags, URL: inf.URL, Environment: inf.Environment, Properties: inf.Properties})
+			}
+			for _, ci := range n.ContainerInstances {
+				var name string
+				if c, ok := m.Element(ci.ContainerID).(*mdl.Container); ok {
+					name = c.Name
+				}
+				nodes = append(nodes, &node{ID: ci.ID, Name: name, Type: "ContainerInstance", Parent: n.ID,
+					Tags: ci.Tags, URL: ci.URL, Environment: ci.Environment, Properties: ci.Properties})
+			}
+			addNodes(n.Children, n.ID)
+		}
+	}
+	addNodes(m.DeploymentNodes, "")
+	for _, n := range nodes {
+		if t := m.Owner(n.ID); t != nil {
+			n.Owner = t.Name
+		}
+	}
+
+	var edges []*edge
+	m.IterateRelationships(func(r *mdl.Relationship) {
+		edges = append(edges, &edge{
+			ID:                   r.ID,
+			Source:               r.SourceID,
+			Target:               r.DestinationID,
+			Description:          r.Description,
+			Technology:           r.Technology,
+			Tags:                 r.Tags,
+			URL:                  r.URL,
+			InteractionStyle:     enum(r.InteractionStyle),
+			LinkedRelationshipID: r.LinkedRelationshipID,
+			Properties:           r.Properties,
+		})
+	})
+
+	return nodes, edges
+}
+
+// propertyNames returns the sorted names of all the given properties.
+func propertyNames(props []map[string]string) []string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, p := range props {
+		for name := range p {
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// enum returns the string representation of the given enum value using its
+// JSON representation or the empty string if the value is undefined.
+func enum(v json.Marshaler) string {
+	b, _ := v.MarshalJSON()
+	var s string
+	json.Unmarshal(b, &s)
+	if s == "Undefined" {
+		return ""
+	}
+	return s
+}
@@ -0,0 +1,204 @@
+package graph
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"strings"
+	"testing"
+
+	. "goa.design/model/dsl"
+	"goa.design/model/internal/testutil"
+	"goa.design/model/mdl"
+)
+
+// cinema defines the design used to test the graph renderers.
+func cinema() {
+	Design("Cinema", "Shows movies.", func() {
+		var Ops = Team("Ops")
+		var Cinema = SoftwareSystem("Cinema", "Sells tickets.", func() {
+			Container("Box Office", "Sells <tickets> & snacks.", "Go", func() {
+				Prop("port", "8080")
+				Uses("Database", "Reads and writes", "SQL", Synchronous, func() {
+					Prop("pool", "10")
+				})
+				Uses("Projector", "Starts shows", Asynchronous)
+				Component("Seating", "Assigns seats.", "Go", func() {
+					Prop("rows", "12")
+				})
+			})
+			Container("Projector", "Plays movies.", "C")
+			Group("Data", func() {
+				Container("Database", "Stores tickets.", "PostgreSQL", func() {
+					Owner(Ops)
+					Tag("Database")
+				})
+			})
+		})
+		Person("Viewer", "Watches movies.", func() {
+			External()
+			Uses(Cinema, "Buys tickets")
+		})
+		DeploymentEnvironment("Production", func() {
+			DeploymentNode("Cloud", "", "AWS", func() {
+				InfrastructureNode("Gateway", "Routes traffic.", "API Gateway")
+				DeploymentNode("Cluster", "", "Kubernetes", func() {
+					ContainerInstance("Cinema/Box Office")
+					ContainerInstance("Cinema/Database")
+				})
+			})
+		})
+	})
+}
+
+func TestGraphML(t *testing.T) {
+	b, err := GraphML(testutil.RunDSL(t, cinema))
+	if err != nil {
+		t.Fatalf("failed to render GraphML: %s", err)
+	}
+	var doc struct {
+		Keys []struct {
+			ID   string `xml:"id,attr"`
+			For  string `xml:"for,attr"`
+			Name string `xml:"attr.name,attr"`
+		} `xml:"key"`
+		Graph struct {
+			Nodes []struct {
+				ID   string `xml:"id,attr"`
+				Data []struct {
+					Key   string `xml:"key,attr"`
+					Value string `xml:",chardata"`
+				} `xml:"data"`
+			} `xml:"node"`
+			Edges []struct {
+				ID string `xml:"id,attr"`
+			} `xml:"edge"`
+		} `xml:"graph"`
+	}
+	if err := xml.Unmarshal(b, &doc); err != nil {
+		t.Fatalf("invalid XML: %s", err)
+	}
+	if len(doc.Graph.Nodes) != 11 {
+		t.Errorf("got %d nodes, want 11", len(doc.Graph.Nodes))
+	}
+	keys := make(map[string]string)
+	for _, k := range doc.Keys {
+		keys[k.For+"/"+k.Name] = k.ID
+	}
+	for k, want := range map[string]string{"node/port": "p0", "node/rows": "p1", "edge/pool": "ep0"} {
+		if got := keys[k]; got != want {
+			t.Errorf("got key %q for property %s, want %q", got, k, want)
+		}
+	}
+	var desc string
+	for _, n := range doc.Graph.Nodes {
+		for _, d := range n.Data {
+			if d.Key == "description" && strings.Contains(d.Value, "tickets>") {
+				desc = d.Value
+			}
+		}
+	}
+	if desc != "Sells <tickets> & snacks." {
+		t.Errorf("got description %q, want %q", desc, "Sells <tickets> & snacks.")
+	}
+	testutil.AssertGolden(t, "cinema.graphml", string(b))
+}
+
+func TestCytoscape(t *testing.T) {
+	b, err := Cytoscape(testutil.RunDSL(t, cinema))
+	if err != nil {
+		t.Fatalf("failed to render Cytoscape JSON: %s", err)
+	}
+	var doc struct {
+		Elements struct {
+			Nodes, Edges []struct {
+				Data map[string]interface{} `json:"data"`
+			}
+		} `json:"elements"`
+	}
+	if err := json.Unmarshal(b, &doc); err != nil {
+		t.Fatalf("invalid JSON: %s", err)
+	}
+	nodes := make(map[string]map[string]interface{})
+	for _, n := range doc.Elements.Nodes {
+		nodes[n.Data["type"].(string)+"/"+n.Data["name"].(string)] = n.Data
+	}
+	byID := func(id interface{}) string {
+		for key, n := range nodes {
+			if n["id"] == id {
+				return key
+			}
+		}
+		return ""
+	}
+	tests := []struct {
+		node, field, want string
+	}{
+		{"Person/Viewer", "location", "External"},
+		{"Container/Box Office", "parent", "SoftwareSystem/Cinema"},
+		{"Container/Box Office", "properties.port", "8080"},
+		{"Component/Seating", "parent", "Container/Box Office"},
+		{"Component/Seating", "properties.rows", "12"},
+		{"Container/Database", "owner", "Ops"},
+		{"Container/Database", "group", "Data"},
+		{"InfrastructureNode/Gateway", "parent", "DeploymentNode/Cloud"},
+		{"InfrastructureNode/Gateway", "environment", "Production"},
+		{"DeploymentNode/Cluster", "parent", "DeploymentNode/Cloud"},
+		{"ContainerInstance/Box Office", "parent", "DeploymentNode/Cluster"},
+	}
+	for _, tt := range tests {
+		n, ok := nodes[tt.node]
+		if !ok {
+			t.Errorf("node %q not found", tt.node)
+			continue
+		}
+		var got string
+		switch {
+		case tt.field == "parent":
+			got = byID(n["parent"])
+		case strings.HasPrefix(tt.field, "properties."):
+			props, _ := n["properties"].(map[string]interface{})
+			got, _ = props[strings.TrimPrefix(tt.field, "properties.")].(string)
+		default:
+			got, _ = n[tt.field].(string)
+		}
+		if got != tt.want {
+			t.Errorf("%s: got %s %q, want %q", tt.node, tt.field, got, tt.want)
+		}
+	}
+	var implied, linked, async bool
+	for _, e := range doc.Elements.Edges {
+		src, dst := byID(e.Data["source"]), byID(e.Data["target"])
+		switch {
+		case src == "Person/Viewer" && dst == "Container/Box Office":
+			implied = true
+		case src == "ContainerInstance/Box Office" && dst == "ContainerInstance/Database":
+			props, _ := e.Data["properties"].(map[string]interface{})
+			linked = e.Data["linkedRelationshipId"] != nil && e.Data["interactionStyle"] == "Synchronous" && props["pool"] == "10"
+		case src == "Container/Box Office" && dst == "Container/Projector":
+			async = e.Data["interactionStyle"] == "Asynchronous"
+		}
+	}
+	if implied {
+		t.Error("got relationship from Viewer to Box Office container, want none")
+	}
+	if !linked {
+		t.Error("missing synchronous relationship between container instances linked to container relationship")
+	}
+	if !async {
+		t.Error("missing asynchronous relationship from Box Office to Projector")
+	}
+	testutil.AssertGolden(t, "cinema.json", string(b))
+}
+
+func TestEmpty(t *testing.T) {
+	b, err := Cytoscape(&mdl.Design{})
+	if err != nil {
+		t.Fatalf("failed to render Cytoscape JSON: %s", err)
+	}
+	if !strings.Contains(string(b), `"nodes": []`) || !strings.Contains(string(b), `"edges": []`) {
+		t.Errorf("got %s, want empty node and edge lists", b)
+	}
+	if _, err := GraphML(&mdl.Design{}); err != nil {
+		t.Errorf("failed to render empty GraphML: %s", err)
+	}
+}
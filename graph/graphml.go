@@ -0,0 +1,134 @@
+package graph
+
+import (
+	"encoding/xml"
+	"strconv"
+
+	"goa.design/model/mdl"
+)
+
+type (
+	// graphML is the root of a GraphML document.
+	graphML struct {
+		XMLName   xml.Name `xml:"graphml"`
+		Namespace string   `xml:"xmlns,attr"`
+		Keys      []*key   `xml:"key"`
+		Graph     *mlGraph `xml:"graph"`
+	}
+
+	// key declares a node or edge attribute.
+	key struct {
+		ID   string `xml:"id,attr"`
+		For  string `xml:"for,attr"`
+		Name string `xml:"attr.name,attr"`
+		Type string `xml:"attr.type,attr"`
+	}
+
+	// mlGraph is a GraphML graph.
+	mlGraph struct {
+		ID          string    `xml:"id,attr"`
+		EdgeDefault string    `xml:"edgedefault,attr"`
+		Nodes       []*mlNode `xml:"node"`
+		Edges       []*mlEdge `xml:"edge"`
+	}
+
+	// mlNode is a GraphML node.
+	mlNode struct {
+		ID   string  `xml:"id,attr"`
+		Data []*data `xml:"data"`
+	}
+
+	// mlEdge is a GraphML edge.
+	mlEdge struct {
+		ID     string  `xml:"id,attr"`
+		Source string  `xml:"source,attr"`
+		Target string  `xml:"target,attr"`
+		Data   []*data `xml:"data"`
+	}
+
+	// data is the value of an attribute.
+	data struct {
+		Key   string `xml:"key,attr"`
+		Value string `xml:",chardata"`
+	}
+)
+
+// GraphML renders the graph of the given design in the GraphML format. Each
+// element property is rendered as a distinct node attribute and each
+// relationship property as a distinct edge attribute.
+func GraphML(d *mdl.Design) ([]byte, error) {
+	nodes, edges := build(d)
+	doc := &graphML{
+		Namespace: "http://graphml.graphdrawing.org/xmlns",
+		Graph:     &mlGraph{ID: "G", EdgeDefault: "directed"},
+	}
+	addKey := func(id, kind, name string) string {
+		doc.Keys = append(doc.Keys, &key{ID: id, For: kind, Name: name, Type: "string"})
+		return id
+	}
+	nodeKeys := []string{"name", "type", "parent", "description", "technology", "tags", "url", "group", "owner", "location", "environment"}
+	for _, k := range nodeKeys {
+		addKey(k, "node", k)
+	}
+	nodeProps := make([]map[string]string, len(nodes))
+	for i, n := range nodes {
+		nodeProps[i] = n.Properties
+	}
+	props := propertyNames(nodeProps)
+	propKeys := make(map[string]string, len(props))
+	for i, p := range props {
+		propKeys[p] = addKey("p"+strconv.Itoa(i), "node", p)
+	}
+	edgeKeys := []string{"description", "technology", "tags", "url", "interactionStyle", "linkedRelationshipId"}
+	for _, k := range edgeKeys {
+		addKey("e_"+k, "edge", k)
+	}
+	edgeProps := make([]map[string]string, len(edges))
+	for i, e := range edges {
+		edgeProps[i] = e.Properties
+	}
+	eprops := propertyNames(edgeProps)
+	epropKeys := make(map[string]string, len(eprops))
+	for i, p := range eprops {
+		epropKeys[p] = addKey("ep"+strconv.Itoa(i), "edge", p)
+	}
+
+	for _, n := range nodes {
+		vals := []string{n.Name, n.Type, n.Parent, n.Description, n.Technology, n.Tags, n.URL, n.Group, n.Owner, n.Location, n.Environment}
+		mn := &mlNode{ID: n.ID, Data: values(nodeKeys, vals, "")}
+		for _, p := range props {
+			if v, ok := n.Properties[p]; ok {
+				mn.Data = append(mn.Data, &data{Key: propKeys[p], Value: v})
+			}
+		}
+		doc.Graph.Nodes = append(doc.Graph.Nodes, mn)
+	}
+	for _, e := range edges {
+		vals := []string{e.Description, e.Technology, e.Tags, e.URL, e.InteractionStyle, e.LinkedRelationshipID}
+		me := &mlEdge{ID: e.ID, Source: e.Source, Target: e.Target, Data: values(edgeKeys, vals, "e_")}
+		for _, p := range eprops {
+			if v, ok := e.Properties[p]; ok {
+				me.Data = append(me.Data, &data{Key: epropKeys[p], Value: v})
+			}
+		}
+		doc.Graph.Edges = append(doc.Graph.Edges, me)
+	}
+
+	b, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), append(b, '\n')...), nil
+}
+
+// values returns the data for the non-empty values using the given keys
+// prefixed with prefix.
+func values(keys, vals []string, prefix string) []*data {
+	var res []*data
+	for i, v := range vals {
+		if v != "" {
+			res = append(res, &data{Key: prefix + keys[i], Value: v})
+		}
+	}
+	return res
+}
@@ -0,0 +1,61 @@
+// Package testutil implements helpers shared by the tests of the packages that
+// render designs.
+package testutil
+
+import (
+	"flag"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/kylelemons/godebug/diff"
+	"goa.design/goa/v3/eval"
+	goaexpr "goa.design/goa/v3/expr"
+	"goa.design/model/expr"
+	"goa.design/model/mdl"
+)
+
+// update is set with "go test -update" to overwrite the golden files with the
+// actual output.
+var update = flag.Bool("update", false, "update golden files")
+
+// RunDSL resets the DSL engine, runs the given function which must define the
+// design using the Design DSL function and returns the corresponding model.
+func RunDSL(t *testing.T, design func()) *mdl.Design {
+	t.Helper()
+	eval.Reset()
+	goaexpr.Root = &goaexpr.RootExpr{GeneratedTypes: &goaexpr.GeneratedRoot{}}
+	expr.Root = &expr.Design{Model: &expr.Model{}, Views: &expr.Views{}}
+	expr.Registry = make(map[string]interface{})
+	for _, r := range []eval.Root{goaexpr.Root, goaexpr.Root.GeneratedTypes, expr.Root} {
+		if err := eval.Register(r); err != nil {
+			t.Fatalf("failed to register root: %s", err)
+		}
+	}
+	design()
+	d, err := mdl.RunDSL()
+	if err != nil {
+		t.Fatalf("failed to evaluate test design: %s", err)
+	}
+	return d
+}
+
+// AssertGolden compares got with the content of the file testdata/NAME.golden
+// and updates the file instead if the -update flag is set.
+func AssertGolden(t *testing.T, name, got string) {
+	t.Helper()
+	path := filepath.Join("testdata", name+".golden")
+	if *update {
+		if err := ioutil.WriteFile(path, []byte(got), 0644); err != nil {
+			t.Fatalf("failed to update golden file: %s", err)
+		}
+		return
+	}
+	want, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read golden file: %s", err)
+	}
+	if d := diff.Diff(string(want), got); d != "" {
+		t.Errorf("%s: got vs. want:\n%s", name, d)
+	}
+}
@@ -0,0 +1,22 @@
+package inventory
+
+import (
+	"bytes"
+	"encoding/csv"
+	"strings"
+
+	"goa.design/model/mdl"
+)
+
+// CSV renders the elements and relationships tables of the given design as
+// CSV documents indexed by lowercase sheet name.
+func CSV(d *mdl.Design) map[string][]byte {
+	res := make(map[string][]byte)
+	for _, s := range Sheets(d) {
+		var buf bytes.Buffer
+		w := csv.NewWriter(&buf)
+		w.WriteAll(s.Rows) // cannot fail when writing to a bytes.Buffer
+		res[strings.ToLower(s.Name)] = buf.Bytes()
+	}
+	return res
+}
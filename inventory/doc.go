@@ -0,0 +1,22 @@
+/*
+Package inventory flattens the model of a software architecture design into
+tables suitable for spreadsheets.
+
+Sheets returns two tables: the elements table lists all the people, software
+systems, containers, components, deployment nodes, infrastructure nodes and
+container instances of the model together with the path of their parent,
+their description, technology, tags, group, owner, properties and location.
+The owner is the name of the team that owns the element or its closest
+ancestor. The
+relationships table lists all the relationships of the model identifying the
+source and destination elements by path.
+
+An element path consists of the names of the element ancestors followed by
+the element name separated with slashes, for example "Internet Banking
+System/API Application/Sign In Controller". The paths of deployment elements
+start with the name of the deployment environment.
+
+CSV renders the tables as CSV documents and XLSX renders them as the sheets
+of a single Office Open XML workbook. Both renderers are written in pure Go.
+*/
+package inventory
@@ -0,0 +1,147 @@
+package inventory
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+
+	. "goa.design/model/dsl"
+	"goa.design/model/internal/testutil"
+)
+
+// payroll defines the design used to test the inventory renderers.
+func payroll() {
+	Design("Payroll", "Pays employees.", func() {
+		Team("Finance")
+		var Bank = SoftwareSystem("Bank", `Transfers "money", fast.`, func() {
+			External()
+			URL("https://bank.example.com")
+		})
+		SoftwareSystem("Payroll", "Computes salaries.\nRuns monthly.", func() {
+			Owner("Finance")
+			Prop("tier", "1")
+			Prop("cost center", "42")
+			Container("API", "Serves payslips.", "Go", func() {
+				Uses("Ledger", "Reads and writes", "SQL", Synchronous)
+				Uses(Bank, "Sends transfers", "SWIFT", Asynchronous, func() {
+					URL("https://docs.example.com/swift")
+				})
+				Component("Calculator", "Computes taxes.", "Go", func() {
+					CodeElement("Rates", "Holds tax rates.", "struct")
+				})
+			})
+			Group("Storage", func() {
+				Container("Ledger", "Stores payments.", "PostgreSQL", func() {
+					Tag("Database")
+				})
+			})
+		})
+		Person("Accountant", "Runs payroll.", func() {
+			Uses("Payroll/API", "Approves payments", "HTTPS")
+		})
+		DeploymentEnvironment("Production", func() {
+			DeploymentNode("Data center", "", "On premise", func() {
+				InfrastructureNode("Firewall", "Filters traffic.")
+				DeploymentNode("Server", "", "Linux", func() {
+					ContainerInstance("Payroll/API")
+				})
+			})
+		})
+	})
+}
+
+func TestCSV(t *testing.T) {
+	d := testutil.RunDSL(t, payroll)
+	for name, doc := range CSV(d) {
+		t.Run(name, func(t *testing.T) {
+			testutil.AssertGolden(t, name+".csv", string(doc))
+		})
+	}
+}
+
+func TestSheets(t *testing.T) {
+	sheets := Sheets(testutil.RunDSL(t, payroll))
+	cells := make(map[string]map[string]string)
+	for _, s := range sheets {
+		header := s.Rows[0]
+		for _, row := range s.Rows[1:] {
+			// Index elements by path and relationships by description.
+			key := row[3]
+			if s.Name == "Elements" && row[2] != "" {
+				key = row[2] + "/" + row[3]
+			}
+			cells[key] = make(map[string]string)
+			for i, h := range header {
+				cells[key][h] = row[i]
+			}
+		}
+	}
+	tests := []struct {
+		name, key, column, want string
+	}{
+		{"sorted properties", "Payroll", "Properties", "cost center=42; tier=1"},
+		{"owner", "Payroll", "Owner", "Finance"},
+		{"inherited owner", "Payroll/API/Calculator/Rates", "Owner", "Finance"},
+		{"external location", "Bank", "Location", "External"},
+		{"url", "Bank", "URL", "https://bank.example.com"},
+		{"group", "Payroll/Ledger", "Group", "Storage"},
+		{"code element", "Payroll/API/Calculator/Rates", "Type", "Code Element"},
+		{"infrastructure node", "Production/Data center/Firewall", "Type", "Infrastructure Node"},
+		{"container instance", "Production/Data center/Server/API", "Type", "Container Instance"},
+		{"synchronous", "Reads and writes", "Interaction Style", "Synchronous"},
+		{"asynchronous", "Sends transfers", "Interaction Style", "Asynchronous"},
+		{"relationship url", "Sends transfers", "URL", "https://docs.example.com/swift"},
+		{"relationship source", "Approves payments", "Source", "Accountant"},
+		{"relationship destination", "Approves payments", "Destination", "Payroll/API"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			row, ok := cells[tt.key]
+			if !ok {
+				t.Fatalf("no row for %q", tt.key)
+			}
+			if got := row[tt.column]; got != tt.want {
+				t.Errorf("%s %s: got %q, want %q", tt.key, tt.column, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestXLSX(t *testing.T) {
+	d := testutil.RunDSL(t, payroll)
+	b, err := XLSX(d)
+	if err != nil {
+		t.Fatalf("failed to render workbook: %s", err)
+	}
+	z, err := zip.NewReader(bytes.NewReader(b), int64(len(b)))
+	if err != nil {
+		t.Fatalf("invalid workbook archive: %s", err)
+	}
+	sheets := 0
+	for _, f := range z.File {
+		if strings.HasPrefix(f.Name, "xl/worksheets/") {
+			sheets++
+		}
+		r, err := f.Open()
+		if err != nil {
+			t.Fatalf("%s: %s", f.Name, err)
+		}
+		dec := xml.NewDecoder(r)
+		for {
+			if _, err = dec.Token(); err != nil {
+				break
+			}
+		}
+		r.Close()
+		if err != io.EOF {
+			t.Errorf("%s: invalid XML: %s", f.Name, err)
+		}
+	}
+	if want := len(Sheets(d)); sheets != want {
+		t.Errorf("got %d worksheets, expected %d", sheets, want)
+	}
+}
@@ -0,0 +1,119 @@
+package inventory
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"goa.design/model/mdl"
+)
+
+// Sheet is a named table. The first row contains the column headers.
+type Sheet struct {
+	// Name of sheet.
+	Name string
+	// Rows of sheet including the header row.
+	Rows [][]string
+}
+
+// Sheets returns the elements and relationships tables of the given design.
+func Sheets(d *mdl.Design) []*Sheet {
+	m := d.Model
+	if m == nil {
+		m = &mdl.Model{}
+	}
+	elems := &Sheet{
+		Name: "Elements",
+		Rows: [][]string{{"ID", "Type", "Parent", "Name", "Description", "Technology", "Tags", "URL", "Group", "Owner", "Properties", "Location"}},
+	}
+	paths := make(map[string]string)
+	add := func(id, typ, parent, name, desc, tech, tags, u string, props map[string]string, loc mdl.LocationKind) {
+		path := name
+		if parent != "" {
+			path = parent + "/" + name
+		}
+		paths[id] = path
+		var owner string
+		if t := m.Owner(id); t != nil {
+			owner = t.Name
+		}
+		elems.Rows = append(elems.Rows, []string{id, typ, parent, name, desc, tech, tags, u, m.Group(id), owner, properties(props), enum(loc)})
+	}
+	for _, p := range m.People {
+		add(p.ID, "Person", "", p.Name, p.Description, "", p.Tags, p.URL, p.Properties, p.Location)
+	}
+	for _, s := range m.Systems {
+		add(s.ID, "Software System", "", s.Name, s.Description, "", s.Tags, s.URL, s.Properties, s.Location)
+		for _, c := range s.Containers {
+			add(c.ID, "Container", paths[s.ID], c.Name, c.Description, c.Technology, c.Tags, c.URL, c.Properties, mdl.LocationUndefined)
+			for _, cmp := range c.Components {
+				add(cmp.ID, "Component", paths[c.ID], cmp.Name, cmp.Description, cmp.Technology, cmp.Tags, cmp.URL, cmp.Properties, mdl.LocationUndefined)
+				for _, ce := range cmp.CodeElements {
+					add(ce.ID, "Code Element", paths[cmp.ID], ce.Name, ce.Description, ce.Technology, ce.Tags, ce.URL, ce.Properties, mdl.LocationUndefined)
+				}
+			}
+		}
+	}
+	var addNodes func(nodes []*mdl.DeploymentNode, parent string)
+	addNodes = func(nodes []*mdl.DeploymentNode, parent string) {
+		for _, n := range nodes {
+			p := parent
+			if p == "" {
+				p = n.Environment
+			}
+			add(n.ID, "Deployment Node", p, n.Name, n.Description, n.Technology, n.Tags, n.URL, n.Properties, mdl.LocationUndefined)
+			for _, inf := range n.InfrastructureNodes {
+				add(inf.ID, "Infrastructure Node", paths[n.ID], inf.Name, inf.Description, inf.Technology, inf.Tags, inf.URL, inf.Properties, mdl.LocationUndefined)
+			}
+			for _, ci := range n.ContainerInstances {
+				var name string
+				if c, ok := m.Element(ci.ContainerID).(*mdl.Container); ok {
+					name = c.Name
+				}
+				add(ci.ID, "Container Instance", paths[n.ID], name, "", "", ci.Tags, ci.URL, ci.Properties, mdl.LocationUndefined)
+			}
+			addNodes(n.Children, paths[n.ID])
+		}
+	}
+	addNodes(m.DeploymentNodes, "")
+
+	rels := &Sheet{
+		Name: "Relationships",
+		Rows: [][]string{{"ID", "Source", "Destination", "Description", "Technology", "Interaction Style", "Tags", "URL"}},
+	}
+	m.IterateRelationships(func(r *mdl.Relationship) {
+		rels.Rows = append(rels.Rows, []string{
+			r.ID, paths[r.SourceID], paths[r.DestinationID], r.Description, r.Technology,
+			enum(r.InteractionStyle), r.Tags, r.URL,
+		})
+	})
+
+	return []*Sheet{elems, rels}
+}
+
+// properties returns a string representation of the given properties sorted
+// by name.
+func properties(props map[string]string) string {
+	names := make([]string, 0, len(props))
+	for n := range props {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	vals := make([]string, len(names))
+	for i, n := range names {
+		vals[i] = n + "=" + props[n]
+	}
+	return strings.Join(vals, "; ")
+}
+
+// enum returns the string representation of the given enum value using its
+// JSON representation or the empty string if the value is undefined.
+func enum(v json.Marshaler) string {
+	b, _ := v.MarshalJSON()
+	var s string
+	json.Unmarshal(b, &s)
+	if s == "Undefined" {
+		return ""
+	}
+	return s
+}
@@ -0,0 +1,128 @@
+package inventory
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+
+	"goa.design/model/mdl"
+)
+
+// XLSX renders the elements and relationships tables of the given design as
+// the sheets of an Office Open XML workbook. The first row of each sheet is
+// frozen so that the headers remain visible when scrolling.
+func XLSX(d *mdl.Design) ([]byte, error) {
+	sheets := Sheets(d)
+	var buf bytes.Buffer
+	z := zip.NewWriter(&buf)
+	write := func(name string, fn func(w io.Writer)) error {
+		w, err := z.Create(name)
+		if err != nil {
+			return err
+		}
+		io.WriteString(w, xml.Header)
+		fn(w)
+		return nil
+	}
+	err := write("[Content_Types].xml", func(w io.Writer) {
+		io.WriteString(w, `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">`)
+		io.WriteString(w, `<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>`)
+		io.WriteString(w, `<Default Extension="xml" ContentType="application/xml"/>`)
+		io.WriteString(w, `<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>`)
+		io.WriteString(w, `<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>`)
+		for i := range sheets {
+			fmt.Fprintf(w, `<Override PartName="/xl/worksheets/sheet%d.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>`, i+1)
+		}
+		io.WriteString(w, `</Types>`)
+	})
+	if err != nil {
+		return nil, err
+	}
+	err = write("_rels/.rels", func(w io.Writer) {
+		io.WriteString(w, `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">`)
+		io.WriteString(w, `<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>`)
+		io.WriteString(w, `</Relationships>`)
+	})
+	if err != nil {
+		return nil, err
+	}
+	err = write("xl/workbook.xml", func(w io.Writer) {
+		io.WriteString(w, `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets>`)
+		for i, s := range sheets {
+			fmt.Fprintf(w, `<sheet name="%s" sheetId="%d" r:id="rId%d"/>`, escape(s.Name), i+1, i+1)
+		}
+		io.WriteString(w, `</sheets></workbook>`)
+	})
+	if err != nil {
+		return nil, err
+	}
+	err = write("xl/_rels/workbook.xml.rels", func(w io.Writer) {
+		io.WriteString(w, `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">`)
+		for i := range sheets {
+			fmt.Fprintf(w, `<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet%d.xml"/>`, i+1, i+1)
+		}
+		fmt.Fprintf(w, `<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>`, len(sheets)+1)
+		io.WriteString(w, `</Relationships>`)
+	})
+	if err != nil {
+		return nil, err
+	}
+	err = write("xl/styles.xml", func(w io.Writer) {
+		// Style 1 is used for the header row and uses a bold font.
+		io.WriteString(w, `<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">`)
+		io.WriteString(w, `<fonts count="2"><font><sz val="11"/><name val="Calibri"/></font><font><b/><sz val="11"/><name val="Calibri"/></font></fonts>`)
+		io.WriteString(w, `<fills count="2"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill></fills>`)
+		io.WriteString(w, `<borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders>`)
+		io.WriteString(w, `<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>`)
+		io.WriteString(w, `<cellXfs count="2"><xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/><xf numFmtId="0" fontId="1" fillId="0" borderId="0" xfId="0" applyFont="1"/></cellXfs>`)
+		io.WriteString(w, `</styleSheet>`)
+	})
+	if err != nil {
+		return nil, err
+	}
+	for i, s := range sheets {
+		err = write(fmt.Sprintf("xl/worksheets/sheet%d.xml", i+1), func(w io.Writer) {
+			io.WriteString(w, `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">`)
+			io.WriteString(w, `<sheetViews><sheetView workbookViewId="0"><pane ySplit="1" topLeftCell="A2" activePane="bottomLeft" state="frozen"/></sheetView></sheetViews>`)
+			io.WriteString(w, `<sheetData>`)
+			for r, row := range s.Rows {
+				fmt.Fprintf(w, `<row r="%d">`, r+1)
+				for c, val := range row {
+					style := ""
+					if r == 0 {
+						style = ` s="1"`
+					}
+					fmt.Fprintf(w, `<c r="%s%d" t="inlineStr"%s><is><t xml:space="preserve">%s</t></is></c>`, column(c), r+1, style, escape(val))
+				}
+				io.WriteString(w, `</row>`)
+			}
+			io.WriteString(w, `</sheetData></worksheet>`)
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err := z.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// column returns the spreadsheet name of the column with the given zero based
+// index, e.g. "A" for 0 and "AA" for 26.
+func column(i int) string {
+	var name string
+	for i++; i > 0; i = (i - 1) / 26 {
+		name = string(rune('A'+(i-1)%26)) + name
+	}
+	return name
+}
+
+// escape escapes the XML special characters in s.
+func escape(s string) string {
+	var buf bytes.Buffer
+	xml.EscapeText(&buf, []byte(s)) // cannot fail when writing to a bytes.Buffer
+	return buf.String()
+}
@@ -0,0 +1,438 @@
+package k8s
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"goa.design/model/mdl"
+)
+
+// builder builds a design from Kubernetes objects.
+type builder struct {
+	// m is the model being built.
+	m *mdl.Model
+	// env is the name of the deployment environment.
+	env string
+	// cluster is the root deployment node.
+	cluster *mdl.DeploymentNode
+	// namespaces indexes the namespace deployment nodes by name.
+	namespaces map[string]*mdl.DeploymentNode
+	// workloads lists the workload objects indexed by namespace.
+	workloads map[string][]*Object
+	// instances counts the container instances per container ID.
+	instances map[string]int
+	// seq is used to generate element IDs.
+	seq int
+}
+
+// Design returns the design with the given name that describes the given
+// Kubernetes objects. The objects are deployed in the deployment environment
+// env.
+func Design(name, env string, objs []*Object) (*mdl.Design, error) {
+	b := &builder{
+		m:          &mdl.Model{},
+		env:        env,
+		namespaces: make(map[string]*mdl.DeploymentNode),
+		workloads:  make(map[string][]*Object),
+		instances:  make(map[string]int),
+	}
+	b.cluster = &mdl.DeploymentNode{
+		ID:          b.newID(),
+		Name:        "Kubernetes",
+		Technology:  "Kubernetes",
+		Environment: env,
+		Tags:        "Element,Deployment Node",
+	}
+	b.m.DeploymentNodes = []*mdl.DeploymentNode{b.cluster}
+	seen := make(map[string]bool)
+	for _, o := range objs {
+		key := o.Kind + ":" + o.Metadata.Namespace + "/" + o.Metadata.Name
+		if o.Kind == "Namespace" {
+			key = o.Kind + ":" + o.Metadata.Name
+		}
+		if seen[key] {
+			return nil, fmt.Errorf("duplicate %s %q", o.Kind, o.Metadata.Name)
+		}
+		seen[key] = true
+	}
+	for _, o := range objs {
+		if o.Kind == "Namespace" {
+			b.namespace(o.Metadata.Name)
+		}
+	}
+	for _, o := range objs {
+		if o.Workload != nil {
+			b.workload(o)
+		}
+	}
+	for _, o := range objs {
+		switch {
+		case o.Service != nil:
+			b.service(o)
+		case o.Ingress != nil:
+			b.ingress(o)
+		}
+	}
+	return &mdl.Design{Name: name, Model: b.m, Views: b.views()}, nil
+}
+
+// namespace returns the deployment node corresponding to the namespace with
+// the given name creating it if needed.
+func (b *builder) namespace(name string) *mdl.DeploymentNode {
+	if n, ok := b.namespaces[name]; ok {
+		return n
+	}
+	n := &mdl.DeploymentNode{
+		ID:          b.newID(),
+		Name:        name,
+		Technology:  "Kubernetes Namespace",
+		Environment: b.env,
+		Tags:        "Element,Deployment Node",
+	}
+	b.cluster.Children = append(b.cluster.Children, n)
+	b.namespaces[name] = n
+	return n
+}
+
+// workload adds the deployment node corresponding to the given Deployment,
+// StatefulSet or DaemonSet and the instances of the containers of its pods.
+func (b *builder) workload(o *Object) {
+	ns := b.namespace(o.Metadata.Namespace)
+	n := &mdl.DeploymentNode{
+		ID:          b.newID(),
+		Name:        o.Metadata.Name,
+		Technology:  o.Kind,
+		Environment: b.env,
+		Tags:        "Element,Deployment Node",
+	}
+	if o.Kind != "DaemonSet" {
+		replicas := 1
+		if o.Workload.Replicas != nil {
+			replicas = *o.Workload.Replicas
+		}
+		n.Instances = &replicas
+	}
+	ns.Children = append(ns.Children, n)
+	b.workloads[o.Metadata.Namespace] = append(b.workloads[o.Metadata.Namespace], o)
+
+	sys := b.system(o)
+	app := label(o, "app.kubernetes.io/name")
+	if app == "" {
+		app = o.Metadata.Name
+	}
+	containers := o.Workload.Template.Spec.Containers
+	for _, c := range containers {
+		name := app
+		if c != mainContainer(containers, app) {
+			name = app + "-" + c.Name
+		}
+		ctr := b.container(sys, name, c.Image)
+		b.instances[ctr.ID]++
+		ci := &mdl.ContainerInstance{
+			ID:          b.newID(),
+			Tags:        "Container Instance",
+			ContainerID: ctr.ID,
+			InstanceID:  b.instances[ctr.ID],
+			Environment: b.env,
+		}
+		if hc := healthCheck(o, c); hc != nil {
+			ci.HealthChecks = []*mdl.HealthCheck{hc}
+		}
+		n.ContainerInstances = append(n.ContainerInstances, ci)
+	}
+}
+
+// service adds the infrastructure node corresponding to the given service.
+func (b *builder) service(o *Object) {
+	var (
+		spec  = o.Service
+		props = make(map[string]string)
+	)
+	typ := spec.Type
+	if typ == "" {
+		typ = "ClusterIP"
+	}
+	props["type"] = typ
+	var ports []string
+	for _, p := range spec.Ports {
+		proto := p.Protocol
+		if proto == "" {
+			proto = "TCP"
+		}
+		port := strconv.Itoa(p.Port) + "/" + proto
+		if p.TargetPort != "" && string(p.TargetPort) != strconv.Itoa(p.Port) {
+			port += " -> " + string(p.TargetPort)
+		}
+		ports = append(ports, port)
+	}
+	if len(ports) > 0 {
+		props["ports"] = strings.Join(ports, ", ")
+	}
+	var targets []string
+	if len(spec.Selector) > 0 {
+		for _, w := range b.workloads[o.Metadata.Namespace] {
+			if matches(spec.Selector, w.Workload.Template.Metadata) {
+				targets = append(targets, w.Metadata.Name)
+			}
+		}
+	}
+	var desc string
+	if len(targets) > 0 {
+		desc = "Routes traffic to " + strings.Join(targets, ", ") + "."
+	}
+	b.infrastructureNode(o, desc, "Kubernetes Service", props)
+}
+
+// ingress adds the infrastructure node corresponding to the given ingress.
+func (b *builder) ingress(o *Object) {
+	var (
+		spec   = o.Ingress
+		props  = make(map[string]string)
+		routes []string
+		hosts  []string
+	)
+	if spec.IngressClassName != "" {
+		props["class"] = spec.IngressClassName
+	}
+	for _, r := range spec.Rules {
+		if r.Host != "" {
+			hosts = append(hosts, r.Host)
+		}
+		if r.HTTP == nil {
+			continue
+		}
+		for _, p := range r.HTTP.Paths {
+			if svc := p.Backend.service(); svc != "" {
+				routes = append(routes, r.Host+p.Path+" to "+svc)
+			}
+		}
+	}
+	if svc := spec.DefaultBackend.service(); svc != "" {
+		routes = append(routes, "default to "+svc)
+	}
+	if len(hosts) > 0 {
+		props["hosts"] = strings.Join(hosts, ", ")
+	}
+	var desc string
+	if len(routes) > 0 {
+		desc = "Routes " + strings.Join(routes, ", ") + "."
+	}
+	b.infrastructureNode(o, desc, "Kubernetes Ingress", props)
+}
+
+// infrastructureNode adds an infrastructure node for o to the deployment
+// node of its namespace.
+func (b *builder) infrastructureNode(o *Object, desc, tech string, props map[string]string) {
+	ns := b.namespace(o.Metadata.Namespace)
+	ns.InfrastructureNodes = append(ns.InfrastructureNodes, &mdl.InfrastructureNode{
+		ID:          b.newID(),
+		Name:        uniqueName(ns, o),
+		Description: desc,
+		Technology:  tech,
+		Tags:        "Element,Infrastructure Node",
+		Properties:  props,
+		Environment: b.env,
+	})
+}
+
+// system returns the software system that contains the containers of the
+// given workload creating it if needed.
+func (b *builder) system(o *Object) *mdl.SoftwareSystem {
+	name := label(o, "app.kubernetes.io/part-of")
+	if name == "" {
+		name = o.Metadata.Namespace
+	}
+	for _, s := range b.m.Systems {
+		if s.Name == name {
+			return s
+		}
+	}
+	s := &mdl.SoftwareSystem{ID: b.newID(), Name: name, Tags: "Element,Software System"}
+	b.m.Systems = append(b.m.Systems, s)
+	return s
+}
+
+// container returns the container of s with the given name creating it if
+// needed.
+func (b *builder) container(s *mdl.SoftwareSystem, name, image string) *mdl.Container {
+	for _, c := range s.Containers {
+		if c.Name == name {
+			return c
+		}
+	}
+	c := &mdl.Container{ID: b.newID(), Name: name, Technology: image, Tags: "Element,Container"}
+	s.Containers = append(s.Containers, c)
+	return c
+}
+
+// views returns the deployment view listing all the elements of the
+// environment.
+func (b *builder) views() *mdl.Views {
+	props := &mdl.ViewProps{
+		Key:         strings.Join(strings.Fields(b.env), "") + "Deployment",
+		Description: "Kubernetes deployment of the " + b.env + " environment.",
+	}
+	var add func(n *mdl.DeploymentNode)
+	add = func(n *mdl.DeploymentNode) {
+		props.ElementViews = append(props.ElementViews, &mdl.ElementView{ID: n.ID})
+		for _, inf := range n.InfrastructureNodes {
+			props.ElementViews = append(props.ElementViews, &mdl.ElementView{ID: inf.ID})
+		}
+		for _, ci := range n.ContainerInstances {
+			props.ElementViews = append(props.ElementViews, &mdl.ElementView{ID: ci.ID})
+		}
+		for _, c := range n.Children {
+			add(c)
+		}
+	}
+	add(b.cluster)
+	return &mdl.Views{
+		DeploymentViews: []*mdl.DeploymentView{{ViewProps: props, Environment: b.env}},
+	}
+}
+
+// uniqueName returns the name of the infrastructure node corresponding to o.
+// The name is suffixed with the kind of o if another element of the namespace
+// deployment node ns already uses the name of o, e.g. when a service and an
+// ingress share the same name.
+func uniqueName(ns *mdl.DeploymentNode, o *Object) string {
+	taken := make(map[string]bool)
+	for _, n := range ns.Children {
+		taken[n.Name] = true
+	}
+	for _, n := range ns.InfrastructureNodes {
+		taken[n.Name] = true
+	}
+	name := o.Metadata.Name
+	if !taken[name] {
+		return name
+	}
+	name = fmt.Sprintf("%s (%s)", o.Metadata.Name, o.Kind)
+	res := name
+	for i := 2; taken[res]; i++ {
+		res = fmt.Sprintf("%s %d", name, i)
+	}
+	return res
+}
+
+// newID returns a new unique element ID.
+func (b *builder) newID() string {
+	b.seq++
+	return strconv.Itoa(b.seq)
+}
+
+// healthCheck returns the health check corresponding to the readiness probe
+// of container c of workload o if any.
+func healthCheck(o *Object, c *Container) *mdl.HealthCheck {
+	p := c.ReadinessProbe
+	if p == nil {
+		return nil
+	}
+	host := o.Metadata.Name
+	var url string
+	headers := make(map[string]string)
+	switch {
+	case p.HTTPGet != nil:
+		if p.HTTPGet.Host != "" {
+			host = p.HTTPGet.Host
+		}
+		scheme := strings.ToLower(p.HTTPGet.Scheme)
+		if scheme == "" {
+			scheme = "http"
+		}
+		url = fmt.Sprintf("%s://%s:%d%s", scheme, host, port(c, p.HTTPGet.Port), p.HTTPGet.Path)
+		for _, h := range p.HTTPGet.HTTPHeaders {
+			headers[h.Name] = h.Value
+		}
+	case p.TCPSocket != nil:
+		if p.TCPSocket.Host != "" {
+			host = p.TCPSocket.Host
+		}
+		url = fmt.Sprintf("tcp://%s:%d", host, port(c, p.TCPSocket.Port))
+	case p.GRPC != nil:
+		url = fmt.Sprintf("grpc://%s:%d", host, p.GRPC.Port)
+	default:
+		return nil // exec probes cannot be represented
+	}
+	interval := p.PeriodSeconds
+	if interval == 0 {
+		interval = 10
+	}
+	timeout := p.TimeoutSeconds
+	if timeout == 0 {
+		timeout = 1
+	}
+	hc := &mdl.HealthCheck{
+		Name:     "Readiness",
+		URL:      url,
+		Interval: interval,
+		Timeout:  timeout * 1000,
+	}
+	if len(headers) > 0 {
+		hc.Headers = headers
+	}
+	return hc
+}
+
+// port returns the number of the port p of container c, p may be a port
+// number or the name of a port of c.
+func port(c *Container, p Port) int {
+	if n := p.Number(); n != 0 {
+		return n
+	}
+	for _, cp := range c.Ports {
+		if cp.Name == string(p) {
+			return cp.ContainerPort
+		}
+	}
+	return 0
+}
+
+// mainContainer returns the container named after the application if any,
+// the first container otherwise.
+func mainContainer(cs []*Container, app string) *Container {
+	for _, c := range cs {
+		if c.Name == app {
+			return c
+		}
+	}
+	return cs[0]
+}
+
+// label returns the value of the label with the given key set on the
+// workload or on its pod template.
+func label(o *Object, key string) string {
+	if v := o.Metadata.Labels[key]; v != "" {
+		return v
+	}
+	if m := o.Workload.Template.Metadata; m != nil {
+		return m.Labels[key]
+	}
+	return ""
+}
+
+// matches returns true if the labels of m match the given selector.
+func matches(selector map[string]string, m *Metadata) bool {
+	if m == nil {
+		return false
+	}
+	for k, v := range selector {
+		if m.Labels[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+// service returns the name of the service the backend routes to if any.
+func (b *Backend) service() string {
+	switch {
+	case b == nil:
+		return ""
+	case b.Service != nil:
+		return b.Service.Name
+	default:
+		return b.ServiceName
+	}
+}
@@ -0,0 +1,89 @@
+package k8s
+
+import (
+	"path/filepath"
+	"testing"
+
+	"goa.design/model/mdl"
+)
+
+func TestDesign(t *testing.T) {
+	objs, err := Load(filepath.Join("testdata", "web.yaml"))
+	if err != nil {
+		t.Fatalf("failed to load manifests: %s", err)
+	}
+	d, err := Design("Shop", "Production", objs)
+	if err != nil {
+		t.Fatalf("failed to build design: %s", err)
+	}
+	ns := namespaceNode(t, d, "shop")
+
+	t.Run("infrastructure nodes", func(t *testing.T) {
+		want := map[string]string{
+			"web":           "Kubernetes Service",
+			"web (Ingress)": "Kubernetes Ingress",
+		}
+		if len(ns.InfrastructureNodes) != len(want) {
+			t.Fatalf("got %d infrastructure nodes, want %d", len(ns.InfrastructureNodes), len(want))
+		}
+		for _, n := range ns.InfrastructureNodes {
+			if tech, ok := want[n.Name]; !ok || n.Technology != tech {
+				t.Errorf("unexpected infrastructure node %q with technology %q", n.Name, n.Technology)
+			}
+		}
+	})
+
+	t.Run("workloads", func(t *testing.T) {
+		if len(ns.Children) != 1 {
+			t.Fatalf("got %d workload nodes, want 1", len(ns.Children))
+		}
+		n := ns.Children[0]
+		if n.Instances == nil || *n.Instances != 2 {
+			t.Errorf("got instances %v, want 2", n.Instances)
+		}
+		if len(n.ContainerInstances) != 1 {
+			t.Fatalf("got %d container instances, want 1", len(n.ContainerInstances))
+		}
+		hcs := n.ContainerInstances[0].HealthChecks
+		if len(hcs) != 1 || hcs[0].URL != "http://api:8080/healthz" {
+			t.Errorf("got health checks %v, want http://api:8080/healthz", hcs)
+		}
+		if len(d.Model.Systems) != 1 || d.Model.Systems[0].Name != "Shop" {
+			t.Errorf("got systems %v, want Shop", d.Model.Systems)
+		}
+	})
+}
+
+func TestUniqueName(t *testing.T) {
+	ns := &mdl.DeploymentNode{
+		Children:            []*mdl.DeploymentNode{{Name: "web"}},
+		InfrastructureNodes: []*mdl.InfrastructureNode{{Name: "api"}, {Name: "api (Service)"}},
+	}
+	tests := []struct {
+		name, kind, want string
+	}{
+		{"db", "Service", "db"},
+		{"web", "Service", "web (Service)"},
+		{"api", "Service", "api (Service) 2"},
+		{"api", "Ingress", "api (Ingress)"},
+	}
+	for _, tt := range tests {
+		o := &Object{Kind: tt.kind, Metadata: &Metadata{Name: tt.name}}
+		if got := uniqueName(ns, o); got != tt.want {
+			t.Errorf("%s %s: got %q, want %q", tt.kind, tt.name, got, tt.want)
+		}
+	}
+}
+
+// namespaceNode returns the deployment node of the namespace with the given
+// name.
+func namespaceNode(t *testing.T, d *mdl.Design, name string) *mdl.DeploymentNode {
+	t.Helper()
+	for _, n := range d.Model.DeploymentNodes[0].Children {
+		if n.Name == name {
+			return n
+		}
+	}
+	t.Fatalf("namespace %q not found", name)
+	return nil
+}
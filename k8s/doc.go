@@ -0,0 +1,24 @@
+/*
+Package k8s builds the deployment model of a software architecture design from
+Kubernetes manifests.
+
+Load reads the Namespace, Deployment, StatefulSet, DaemonSet, Service and
+Ingress objects defined in the YAML files of a directory, other objects are
+ignored. Design maps the objects to a deployment environment as follows:
+
+	Cluster                 -> Deployment Node (root of the environment)
+	Namespace               -> Deployment Node
+	Deployment, StatefulSet -> Deployment Node, the number of replicas is
+	or DaemonSet               mapped to the number of instances
+	Pod container           -> Container Instance
+	Service, Ingress        -> Infrastructure Node
+
+The containers of the pods are added to software systems named after the
+"app.kubernetes.io/part-of" label of the workload or after its namespace if
+the label is not set. Readiness probes that use HTTP, TCP or gRPC are mapped
+to health checks. Infrastructure nodes whose name is already used in their
+namespace, e.g. a service and an ingress both named "web", are suffixed with
+the kind of the object ("web (Ingress)"). The design also contains one deployment view that includes
+all the elements of the environment.
+*/
+package k8s
@@ -0,0 +1,315 @@
+package k8s
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"gopkg.in/yaml.v3"
+)
+
+type (
+	// Object is a Kubernetes object supported by this package.
+	Object struct {
+		// Kind of object, e.g. "Deployment".
+		Kind string
+		// Metadata of object.
+		Metadata *Metadata
+		// Workload is the specification of Deployment, StatefulSet and
+		// DaemonSet objects.
+		Workload *WorkloadSpec
+		// Service is the specification of Service objects.
+		Service *ServiceSpec
+		// Ingress is the specification of Ingress objects.
+		Ingress *IngressSpec
+	}
+
+	// Metadata is the object metadata.
+	Metadata struct {
+		// Name of object.
+		Name string `yaml:"name"`
+		// Namespace of object, "default" if empty.
+		Namespace string `yaml:"namespace"`
+		// Labels of object.
+		Labels map[string]string `yaml:"labels"`
+		// Annotations of object.
+		Annotations map[string]string `yaml:"annotations"`
+	}
+
+	// WorkloadSpec is the specification of a workload.
+	WorkloadSpec struct {
+		// Replicas is the desired number of pods, nil means 1.
+		Replicas *int `yaml:"replicas"`
+		// Template describes the pods.
+		Template struct {
+			// Metadata of pods.
+			Metadata *Metadata `yaml:"metadata"`
+			// Spec of pods.
+			Spec struct {
+				// Containers of pods.
+				Containers []*Container `yaml:"containers"`
+			} `yaml:"spec"`
+		} `yaml:"template"`
+	}
+
+	// Container describes a pod container.
+	Container struct {
+		// Name of container.
+		Name string `yaml:"name"`
+		// Image run by container.
+		Image string `yaml:"image"`
+		// Ports exposed by container.
+		Ports []*ContainerPort `yaml:"ports"`
+		// ReadinessProbe of container if any.
+		ReadinessProbe *Probe `yaml:"readinessProbe"`
+	}
+
+	// ContainerPort is a port exposed by a container.
+	ContainerPort struct {
+		// Name of port if any.
+		Name string `yaml:"name"`
+		// ContainerPort is the port number.
+		ContainerPort int `yaml:"containerPort"`
+	}
+
+	// Probe describes a container probe.
+	Probe struct {
+		// HTTPGet describes a HTTP probe.
+		HTTPGet *struct {
+			// Path to request.
+			Path string `yaml:"path"`
+			// Port number or name.
+			Port Port `yaml:"port"`
+			// Host name, defaults to the pod IP.
+			Host string `yaml:"host"`
+			// Scheme is "HTTP" or "HTTPS".
+			Scheme string `yaml:"scheme"`
+			// HTTPHeaders lists the request headers.
+			HTTPHeaders []struct {
+				Name  string `yaml:"name"`
+				Value string `yaml:"value"`
+			} `yaml:"httpHeaders"`
+		} `yaml:"httpGet"`
+		// TCPSocket describes a TCP probe.
+		TCPSocket *struct {
+			// Port number or name.
+			Port Port `yaml:"port"`
+			// Host name, defaults to the pod IP.
+			Host string `yaml:"host"`
+		} `yaml:"tcpSocket"`
+		// GRPC describes a gRPC probe.
+		GRPC *struct {
+			// Port number.
+			Port int `yaml:"port"`
+		} `yaml:"grpc"`
+		// PeriodSeconds is the probe interval, defaults to 10.
+		PeriodSeconds int `yaml:"periodSeconds"`
+		// TimeoutSeconds is the probe timeout, defaults to 1.
+		TimeoutSeconds int `yaml:"timeoutSeconds"`
+	}
+
+	// ServiceSpec is the specification of a service.
+	ServiceSpec struct {
+		// Type of service, defaults to "ClusterIP".
+		Type string `yaml:"type"`
+		// Selector matching the labels of the pods backing the service.
+		Selector map[string]string `yaml:"selector"`
+		// Ports exposed by the service.
+		Ports []*struct {
+			// Name of port if any.
+			Name string `yaml:"name"`
+			// Port number.
+			Port int `yaml:"port"`
+			// TargetPort is the number or name of the pod port.
+			TargetPort Port `yaml:"targetPort"`
+			// Protocol of port, defaults to "TCP".
+			Protocol string `yaml:"protocol"`
+		} `yaml:"ports"`
+	}
+
+	// IngressSpec is the specification of an ingress.
+	IngressSpec struct {
+		// IngressClassName is the name of the ingress class if any.
+		IngressClassName string `yaml:"ingressClassName"`
+		// DefaultBackend handles the requests that match no rule.
+		DefaultBackend *Backend `yaml:"defaultBackend"`
+		// Rules list the host rules.
+		Rules []*struct {
+			// Host matched by rule if any.
+			Host string `yaml:"host"`
+			// HTTP lists the paths of the rule.
+			HTTP *struct {
+				Paths []*struct {
+					// Path matched by rule.
+					Path string `yaml:"path"`
+					// Backend handling the requests.
+					Backend *Backend `yaml:"backend"`
+				} `yaml:"paths"`
+			} `yaml:"http"`
+		} `yaml:"rules"`
+	}
+
+	// Backend is an ingress backend.
+	Backend struct {
+		// Service backend (networking.k8s.io/v1).
+		Service *struct {
+			// Name of service.
+			Name string `yaml:"name"`
+		} `yaml:"service"`
+		// ServiceName is the name of the service (extensions/v1beta1).
+		ServiceName string `yaml:"serviceName"`
+	}
+
+	// Port is a port number or name.
+	Port string
+
+	// manifest is the generic representation of a Kubernetes object.
+	manifest struct {
+		Kind     string      `yaml:"kind"`
+		Metadata *Metadata   `yaml:"metadata"`
+		Spec     yaml.Node   `yaml:"spec"`
+		Items    []yaml.Node `yaml:"items"`
+	}
+)
+
+// Load reads the objects defined in the file at the given path or in all the
+// files with extension .yaml or .yml found under the given directory.
+func Load(path string) ([]*Object, error) {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !fi.IsDir() {
+		return readFile(path)
+	}
+	var res []*Object
+	err = filepath.Walk(path, func(p string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if fi.IsDir() {
+			return nil
+		}
+		if ext := filepath.Ext(p); ext != ".yaml" && ext != ".yml" {
+			return nil
+		}
+		objs, err := readFile(p)
+		if err != nil {
+			return err
+		}
+		res = append(res, objs...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(res) == 0 {
+		return nil, fmt.Errorf("no Kubernetes manifest found in %q", path)
+	}
+	return res, nil
+}
+
+// Parse reads the supported objects defined in the given YAML content. The
+// content may contain multiple documents and List objects.
+func Parse(b []byte) ([]*Object, error) {
+	var res []*Object
+	dec := yaml.NewDecoder(bytes.NewReader(b))
+	for {
+		var m manifest
+		if err := dec.Decode(&m); err != nil {
+			if errors.Is(err, io.EOF) {
+				return res, nil
+			}
+			return nil, err
+		}
+		objs, err := m.objects()
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, objs...)
+	}
+}
+
+// UnmarshalYAML reads port numbers and names.
+func (p *Port) UnmarshalYAML(n *yaml.Node) error {
+	*p = Port(n.Value)
+	return nil
+}
+
+// Number returns the port number or 0 if p is a port name.
+func (p Port) Number() int {
+	n, err := strconv.Atoi(string(p))
+	if err != nil {
+		return 0
+	}
+	return n
+}
+
+// objects returns the supported objects described by m.
+func (m *manifest) objects() ([]*Object, error) {
+	if m.Kind == "List" || len(m.Items) > 0 {
+		var res []*Object
+		for _, item := range m.Items {
+			var im manifest
+			if err := item.Decode(&im); err != nil {
+				return nil, err
+			}
+			objs, err := im.objects()
+			if err != nil {
+				return nil, err
+			}
+			res = append(res, objs...)
+		}
+		return res, nil
+	}
+	if m.Metadata == nil || m.Metadata.Name == "" {
+		if m.Kind == "" {
+			return nil, nil // empty document
+		}
+		return nil, fmt.Errorf("%s object is missing metadata.name", m.Kind)
+	}
+	if m.Metadata.Namespace == "" {
+		m.Metadata.Namespace = "default"
+	}
+	obj := &Object{Kind: m.Kind, Metadata: m.Metadata}
+	var spec interface{}
+	switch m.Kind {
+	case "Namespace":
+		return []*Object{obj}, nil
+	case "Deployment", "StatefulSet", "DaemonSet":
+		obj.Workload = &WorkloadSpec{}
+		spec = obj.Workload
+	case "Service":
+		obj.Service = &ServiceSpec{}
+		spec = obj.Service
+	case "Ingress":
+		obj.Ingress = &IngressSpec{}
+		spec = obj.Ingress
+	default:
+		return nil, nil
+	}
+	if m.Spec.Kind != 0 {
+		if err := m.Spec.Decode(spec); err != nil {
+			return nil, fmt.Errorf("%s %s: %s", m.Kind, m.Metadata.Name, err)
+		}
+	}
+	return []*Object{obj}, nil
+}
+
+// readFile reads the objects defined in the file at the given path.
+func readFile(path string) ([]*Object, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	objs, err := Parse(b)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s", path, err)
+	}
+	return objs, nil
+}
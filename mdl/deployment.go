@@ -28,8 +28,13 @@ type (
 		// ContainerInstances describe instances of containers deployed in
 		// deployment node.
 		ContainerInstances []*ContainerInstance `json:"containerInstances,omitempty"`
+		// Owner is the name of the team that owns the element if any.
+		Owner string `json:"owner,omitempty"`
 		// Set of arbitrary name-value properties (shown in diagram tooltips).
 		Properties map[string]string `json:"properties,omitempty"`
+		// Perspectives describe the element from different points of view
+		// (e.g. security or operations) if any.
+		Perspectives []*Perspective `json:"perspectives,omitempty"`
 		// Relationships is the set of relationships from this element to other
 		// elements.
 		Relationships []*Relationship `json:"relationships,omitempty"`
@@ -49,8 +54,13 @@ type (
 		Tags string `json:"tags,omitempty"`
 		// URL where more information about this element can be found.
 		URL string `json:"url,omitempty"`
+		// Owner is the name of the team that owns the element if any.
+		Owner string `json:"owner,omitempty"`
 		// Set of arbitrary name-value properties (shown in diagram tooltips).
 		Properties map[string]string `json:"properties,omitempty"`
+		// Perspectives describe the element from different points of view
+		// (e.g. security or operations) if any.
+		Perspectives []*Perspective `json:"perspectives,omitempty"`
 		// Relationships is the set of relationships from this element to other
 		// elements.
 		Relationships []*Relationship `json:"relationships,omitempty"`
@@ -68,8 +78,13 @@ type (
 		Tags string `json:"tags,omitempty"`
 		// URL where more information about this element can be found.
 		URL string `json:"url,omitempty"`
+		// Owner is the name of the team that owns the element if any.
+		Owner string `json:"owner,omitempty"`
 		// Set of arbitrary name-value properties (shown in diagram tooltips).
 		Properties map[string]string `json:"properties,omitempty"`
+		// Perspectives describe the element from different points of view
+		// (e.g. security or operations) if any.
+		Perspectives []*Perspective `json:"perspectives,omitempty"`
 		// Relationships is the set of relationships from this element to other
 		// elements.
 		Relationships []*Relationship `json:"relationships,omitempty"`
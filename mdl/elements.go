@@ -20,8 +20,15 @@ type (
 		Tags string `json:"tags,omitempty"`
 		// URL where more information about this element can be found.
 		URL string `json:"url,omitempty"`
+		// Group the element belongs to if any.
+		Group string `json:"group,omitempty"`
+		// Owner is the name of the team that owns the element if any.
+		Owner string `json:"owner,omitempty"`
 		// Set of arbitrary name-value properties (shown in diagram tooltips).
 		Properties map[string]string `json:"properties,omitempty"`
+		// Perspectives describe the element from different points of view
+		// (e.g. security or operations) if any.
+		Perspectives []*Perspective `json:"perspectives,omitempty"`
 		// Relationships is the set of relationships from this element to other
 		// elements.
 		Relationships []*Relationship `json:"relationships,omitempty"`
@@ -43,8 +50,15 @@ type (
 		Tags string `json:"tags,omitempty"`
 		// URL where more information about this element can be found.
 		URL string `json:"url,omitempty"`
+		// Group the element belongs to if any.
+		Group string `json:"group,omitempty"`
+		// Owner is the name of the team that owns the element if any.
+		Owner string `json:"owner,omitempty"`
 		// Set of arbitrary name-value properties (shown in diagram tooltips).
 		Properties map[string]string `json:"properties,omitempty"`
+		// Perspectives describe the element from different points of view
+		// (e.g. security or operations) if any.
+		Perspectives []*Perspective `json:"perspectives,omitempty"`
 		// Relationships is the set of relationships from this element to other
 		// elements.
 		Relationships []*Relationship `json:"relationships,omitempty"`
@@ -68,8 +82,15 @@ type (
 		Tags string `json:"tags,omitempty"`
 		// URL where more information about this element can be found.
 		URL string `json:"url,omitempty"`
+		// Group the element belongs to if any.
+		Group string `json:"group,omitempty"`
+		// Owner is the name of the team that owns the element if any.
+		Owner string `json:"owner,omitempty"`
 		// Set of arbitrary name-value properties (shown in diagram tooltips).
 		Properties map[string]string `json:"properties,omitempty"`
+		// Perspectives describe the element from different points of view
+		// (e.g. security or operations) if any.
+		Perspectives []*Perspective `json:"perspectives,omitempty"`
 		// Relationships is the set of relationships from this element to other
 		// elements.
 		Relationships []*Relationship `json:"relationships,omitempty"`
@@ -91,11 +112,56 @@ type (
 		Tags string `json:"tags,omitempty"`
 		// URL where more information about this element can be found.
 		URL string `json:"url,omitempty"`
+		// Group the element belongs to if any.
+		Group string `json:"group,omitempty"`
+		// Owner is the name of the team that owns the element if any.
+		Owner string `json:"owner,omitempty"`
 		// Set of arbitrary name-value properties (shown in diagram tooltips).
 		Properties map[string]string `json:"properties,omitempty"`
+		// Perspectives describe the element from different points of view
+		// (e.g. security or operations) if any.
+		Perspectives []*Perspective `json:"perspectives,omitempty"`
 		// Relationships is the set of relationships from this element to other
 		// elements.
 		Relationships []*Relationship `json:"relationships,omitempty"`
+		// CodeElements list the code elements within the component.
+		CodeElements []*CodeElement `json:"codeElements,omitempty"`
+	}
+
+	// CodeElement represents a code element (type, interface, function etc.)
+	// of a component.
+	CodeElement struct {
+		// ID of element.
+		ID string `json:"id"`
+		// Name of element.
+		Name string `json:"name,omitempty"`
+		// Description of element if any.
+		Description string `json:"description,omitempty"`
+		// Technology used by element if any (e.g. "Go struct").
+		Technology string `json:"technology,omitempty"`
+		// Tags attached to element as comma separated list if any.
+		Tags string `json:"tags,omitempty"`
+		// URL where more information about this element can be found.
+		URL string `json:"url,omitempty"`
+		// Owner is the name of the team that owns the element if any.
+		Owner string `json:"owner,omitempty"`
+		// Set of arbitrary name-value properties (shown in diagram tooltips).
+		Properties map[string]string `json:"properties,omitempty"`
+		// Perspectives describe the element from different points of view
+		// (e.g. security or operations) if any.
+		Perspectives []*Perspective `json:"perspectives,omitempty"`
+		// Relationships is the set of relationships from this element to other
+		// elements.
+		Relationships []*Relationship `json:"relationships,omitempty"`
+	}
+
+	// Perspective is a named description of an element or relationship from a
+	// given point of view, for example "Security" or "Operations".
+	Perspective struct {
+		// Name of perspective.
+		Name string `json:"name"`
+		// Description of element or relationship from this perspective.
+		Description string `json:"description"`
 	}
 
 	// LocationKind is the enum for possible locations.
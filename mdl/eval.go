@@ -35,6 +35,10 @@ func ModelizeDesign(d *expr.Design) *Design {
 	if name := m.Enterprise; name != "" {
 		model.Enterprise = &Enterprise{Name: name}
 	}
+	model.Teams = make([]*Team, len(m.Teams))
+	for i, t := range m.Teams {
+		model.Teams[i] = &Team{Name: t.Name, Contact: t.Contact, Channel: t.Channel, OnCall: t.OnCall}
+	}
 	model.People = make([]*Person, len(m.People))
 	for i, p := range m.People {
 		model.People[i] = modelizePerson(p)
@@ -78,6 +82,13 @@ func ModelizeDesign(d *expr.Design) *Design {
 			ContainerID:                cv.ContainerID,
 		}
 	}
+	views.CodeViews = make([]*CodeView, len(v.CodeViews))
+	for i, cv := range v.CodeViews {
+		views.CodeViews[i] = &CodeView{
+			ViewProps:   modelizeProps(cv.Props()),
+			ComponentID: cv.ComponentID,
+		}
+	}
 	views.DynamicViews = make([]*DynamicView, len(v.DynamicViews))
 	for i, dv := range v.DynamicViews {
 		views.DynamicViews[i] = &DynamicView{
@@ -127,7 +138,10 @@ func modelizePerson(p *expr.Person) *Person {
 		Technology:    p.Element.Technology,
 		Tags:          p.Element.Tags,
 		URL:           p.Element.URL,
+		Group:         p.Element.Group,
+		Owner:         p.Element.Owner,
 		Properties:    p.Element.Properties,
+		Perspectives:  modelizePerspectives(p.Element.Perspectives),
 		Relationships: modelizeRelationships(p.Relationships),
 		Location:      LocationKind(p.Location),
 	}
@@ -141,6 +155,8 @@ func modelizeRelationships(rels []*expr.Relationship) []*Relationship {
 			Description:          r.Description,
 			Tags:                 r.Tags,
 			URL:                  r.URL,
+			Properties:           r.Properties,
+			Perspectives:         modelizePerspectives(r.Perspectives),
 			SourceID:             r.Source.ID,
 			DestinationID:        r.Destination.ID,
 			Technology:           r.Technology,
@@ -151,6 +167,17 @@ func modelizeRelationships(rels []*expr.Relationship) []*Relationship {
 	return res
 }
 
+func modelizePerspectives(ps []*expr.Perspective) []*Perspective {
+	if len(ps) == 0 {
+		return nil
+	}
+	res := make([]*Perspective, len(ps))
+	for i, p := range ps {
+		res[i] = &Perspective{Name: p.Name, Description: p.Description}
+	}
+	return res
+}
+
 func modelizeSystem(sys *expr.SoftwareSystem) *SoftwareSystem {
 	return &SoftwareSystem{
 		ID:            sys.ID,
@@ -159,7 +186,10 @@ func modelizeSystem(sys *expr.SoftwareSystem) *SoftwareSystem {
 		Technology:    sys.Technology,
 		Tags:          sys.Tags,
 		URL:           sys.URL,
+		Group:         sys.Group,
+		Owner:         sys.Owner,
 		Properties:    sys.Properties,
+		Perspectives:  modelizePerspectives(sys.Perspectives),
 		Relationships: modelizeRelationships(sys.Relationships),
 		Location:      LocationKind(sys.Location),
 		Containers:    modelizeContainers(sys.Containers),
@@ -176,7 +206,10 @@ func modelizeContainers(cs []*expr.Container) []*Container {
 			Technology:    c.Technology,
 			Tags:          c.Tags,
 			URL:           c.URL,
+			Group:         c.Group,
+			Owner:         c.Owner,
 			Properties:    c.Properties,
+			Perspectives:  modelizePerspectives(c.Perspectives),
 			Relationships: modelizeRelationships(c.Relationships),
 			Components:    modelizeComponents(c.Components),
 		}
@@ -194,7 +227,30 @@ func modelizeComponents(cs []*expr.Component) []*Component {
 			Technology:    c.Technology,
 			Tags:          c.Tags,
 			URL:           c.URL,
+			Group:         c.Group,
+			Owner:         c.Owner,
+			Properties:    c.Properties,
+			Perspectives:  modelizePerspectives(c.Perspectives),
+			Relationships: modelizeRelationships(c.Relationships),
+			CodeElements:  modelizeCodeElements(c.CodeElements),
+		}
+	}
+	return res
+}
+
+func modelizeCodeElements(ces []*expr.CodeElement) []*CodeElement {
+	res := make([]*CodeElement, len(ces))
+	for i, c := range ces {
+		res[i] = &CodeElement{
+			ID:            c.ID,
+			Name:          c.Name,
+			Description:   c.Description,
+			Technology:    c.Technology,
+			Tags:          c.Tags,
+			URL:           c.URL,
+			Owner:         c.Owner,
 			Properties:    c.Properties,
+			Perspectives:  modelizePerspectives(c.Perspectives),
 			Relationships: modelizeRelationships(c.Relationships),
 		}
 	}
@@ -214,7 +270,9 @@ func modelizeDeploymentNodes(dns []*expr.DeploymentNode) []*DeploymentNode {
 				Technology:    inf.Technology,
 				Tags:          inf.Tags,
 				URL:           inf.URL,
+				Owner:         inf.Owner,
 				Properties:    inf.Properties,
+				Perspectives:  modelizePerspectives(inf.Perspectives),
 				Relationships: modelizeRelationships(inf.Relationships),
 				Environment:   inf.Environment,
 			}
@@ -225,7 +283,9 @@ func modelizeDeploymentNodes(dns []*expr.DeploymentNode) []*DeploymentNode {
 				ID:            ci.ID,
 				Tags:          ci.Tags,
 				URL:           ci.URL,
+				Owner:         ci.Owner,
 				Properties:    ci.Properties,
+				Perspectives:  modelizePerspectives(ci.Perspectives),
 				Relationships: modelizeRelationships(ci.Relationships),
 				ContainerID:   ci.ContainerID,
 				InstanceID:    ci.InstanceID,
@@ -245,6 +305,9 @@ func modelizeDeploymentNodes(dns []*expr.DeploymentNode) []*DeploymentNode {
 			Instances:           dn.Instances,
 			Tags:                dn.Tags,
 			URL:                 dn.URL,
+			Owner:               dn.Owner,
+			Properties:          dn.Properties,
+			Perspectives:        modelizePerspectives(dn.Perspectives),
 		}
 	}
 	return res
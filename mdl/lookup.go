@@ -0,0 +1,242 @@
+package mdl
+
+// Element returns the person, software system, container, component, code
+// element, deployment node, infrastructure node or container instance with the
+// given ID if any, nil otherwise.
+func (m *Model) Element(id string) interface{} {
+	for _, p := range m.People {
+		if p.ID == id {
+			return p
+		}
+	}
+	for _, s := range m.Systems {
+		if s.ID == id {
+			return s
+		}
+		for _, c := range s.Containers {
+			if c.ID == id {
+				return c
+			}
+			for _, cmp := range c.Components {
+				if cmp.ID == id {
+					return cmp
+				}
+				for _, ce := range cmp.CodeElements {
+					if ce.ID == id {
+						return ce
+					}
+				}
+			}
+		}
+	}
+	if e := findDeploymentElement(m.DeploymentNodes, id); e != nil {
+		return e
+	}
+	return nil
+}
+
+// Parent returns the parent of the element with the given ID if any, nil
+// otherwise. The parent of a container is a software system, the parent of a
+// component is a container, the parent of a code element is a component and
+// the parent of a deployment node (if any), infrastructure node or container
+// instance is a deployment node.
+func (m *Model) Parent(id string) interface{} {
+	for _, s := range m.Systems {
+		for _, c := range s.Containers {
+			if c.ID == id {
+				return s
+			}
+			for _, cmp := range c.Components {
+				if cmp.ID == id {
+					return c
+				}
+				for _, ce := range cmp.CodeElements {
+					if ce.ID == id {
+						return cmp
+					}
+				}
+			}
+		}
+	}
+	if p := findDeploymentParent(m.DeploymentNodes, id); p != nil {
+		return p
+	}
+	return nil
+}
+
+// Group returns the name of the group the element with the given ID belongs
+// to if any, the empty string otherwise.
+func (m *Model) Group(id string) string {
+	switch e := m.Element(id).(type) {
+	case *Person:
+		return e.Group
+	case *SoftwareSystem:
+		return e.Group
+	case *Container:
+		return e.Group
+	case *Component:
+		return e.Group
+	}
+	return ""
+}
+
+// Owner returns the team that owns the element with the given ID if any, nil
+// otherwise. Elements that do not define an owner inherit the owner of their
+// parent, container instances inherit the owner of their container.
+func (m *Model) Owner(id string) *Team {
+	for id != "" {
+		var owner string
+		switch e := m.Element(id).(type) {
+		case *Person:
+			owner = e.Owner
+		case *SoftwareSystem:
+			owner = e.Owner
+		case *Container:
+			owner = e.Owner
+		case *Component:
+			owner = e.Owner
+		case *CodeElement:
+			owner = e.Owner
+		case *DeploymentNode:
+			owner = e.Owner
+		case *InfrastructureNode:
+			owner = e.Owner
+		case *ContainerInstance:
+			if e.Owner == "" {
+				id = e.ContainerID
+				continue
+			}
+			owner = e.Owner
+		default:
+			return nil
+		}
+		if owner != "" {
+			return m.Team(owner)
+		}
+		id = elementID(m.Parent(id))
+	}
+	return nil
+}
+
+// Team returns the team with the given name if any, nil otherwise.
+func (m *Model) Team(name string) *Team {
+	for _, t := range m.Teams {
+		if t.Name == name {
+			return t
+		}
+	}
+	return nil
+}
+
+// Relationship returns the relationship with the given ID if any, nil
+// otherwise.
+func (m *Model) Relationship(id string) *Relationship {
+	var res *Relationship
+	m.IterateRelationships(func(r *Relationship) {
+		if r.ID == id {
+			res = r
+		}
+	})
+	return res
+}
+
+// IterateRelationships calls visitor on all the relationships of all the
+// elements in the model.
+func (m *Model) IterateRelationships(visitor func(r *Relationship)) {
+	visit := func(rels []*Relationship) {
+		for _, r := range rels {
+			visitor(r)
+		}
+	}
+	for _, p := range m.People {
+		visit(p.Relationships)
+	}
+	for _, s := range m.Systems {
+		visit(s.Relationships)
+		for _, c := range s.Containers {
+			visit(c.Relationships)
+			for _, cmp := range c.Components {
+				visit(cmp.Relationships)
+				for _, ce := range cmp.CodeElements {
+					visit(ce.Relationships)
+				}
+			}
+		}
+	}
+	var visitNodes func([]*DeploymentNode)
+	visitNodes = func(nodes []*DeploymentNode) {
+		for _, n := range nodes {
+			visit(n.Relationships)
+			for _, inf := range n.InfrastructureNodes {
+				visit(inf.Relationships)
+			}
+			for _, ci := range n.ContainerInstances {
+				visit(ci.Relationships)
+			}
+			visitNodes(n.Children)
+		}
+	}
+	visitNodes(m.DeploymentNodes)
+}
+
+func findDeploymentElement(nodes []*DeploymentNode, id string) interface{} {
+	for _, n := range nodes {
+		if n.ID == id {
+			return n
+		}
+		for _, inf := range n.InfrastructureNodes {
+			if inf.ID == id {
+				return inf
+			}
+		}
+		for _, ci := range n.ContainerInstances {
+			if ci.ID == id {
+				return ci
+			}
+		}
+		if e := findDeploymentElement(n.Children, id); e != nil {
+			return e
+		}
+	}
+	return nil
+}
+
+func findDeploymentParent(nodes []*DeploymentNode, id string) *DeploymentNode {
+	for _, n := range nodes {
+		for _, c := range n.Children {
+			if c.ID == id {
+				return n
+			}
+		}
+		for _, inf := range n.InfrastructureNodes {
+			if inf.ID == id {
+				return n
+			}
+		}
+		for _, ci := range n.ContainerInstances {
+			if ci.ID == id {
+				return n
+			}
+		}
+		if p := findDeploymentParent(n.Children, id); p != nil {
+			return p
+		}
+	}
+	return nil
+}
+
+// elementID returns the ID of the given software system, container, component
+// or deployment node, the empty string if e is nil.
+func elementID(e interface{}) string {
+	switch p := e.(type) {
+	case *SoftwareSystem:
+		return p.ID
+	case *Container:
+		return p.ID
+	case *Component:
+		return p.ID
+	case *DeploymentNode:
+		return p.ID
+	}
+	return ""
+}
@@ -16,6 +16,8 @@ type (
 		Systems []*SoftwareSystem `json:"softwareSystems,omitempty"`
 		// DeploymentNodes list the deployment nodes.
 		DeploymentNodes []*DeploymentNode `json:"deploymentNodes,omitempty"`
+		// Teams lists the teams that own elements.
+		Teams []*Team `json:"teams,omitempty"`
 	}
 
 	// Enterprise describes a named enterprise / organization.
@@ -24,6 +26,18 @@ type (
 		Name string `json:"name"`
 	}
 
+	// Team describes a team that owns elements.
+	Team struct {
+		// Name of team.
+		Name string `json:"name"`
+		// Contact is the email address or name of the person to contact.
+		Contact string `json:"contact,omitempty"`
+		// Channel is the chat channel of the team.
+		Channel string `json:"channel,omitempty"`
+		// OnCall is the URL of the team on-call schedule or paging service.
+		OnCall string `json:"onCall,omitempty"`
+	}
+
 	// alias to call original json.Unmarshal
 	_model Model
 )
@@ -44,6 +58,10 @@ func (m *Model) MarshalJSON() ([]byte, error) {
 			sort.Slice(c.Components, func(i, j int) bool { return c.Components[i].Name < c.Components[j].Name })
 			for _, cmp := range c.Components {
 				sort.Slice(cmp.Relationships, func(i, j int) bool { return cmp.Relationships[i].ID < cmp.Relationships[j].ID })
+				sort.Slice(cmp.CodeElements, func(i, j int) bool { return cmp.CodeElements[i].Name < cmp.CodeElements[j].Name })
+				for _, ce := range cmp.CodeElements {
+					sort.Slice(ce.Relationships, func(i, j int) bool { return ce.Relationships[i].ID < ce.Relationships[j].ID })
+				}
 			}
 		}
 	}
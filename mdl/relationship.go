@@ -16,6 +16,11 @@ type (
 		Tags string `json:"tags,omitempty"`
 		// URL where more information can be found.
 		URL string `json:"url,omitempty"`
+		// Set of arbitrary name-value properties (shown in diagram tooltips).
+		Properties map[string]string `json:"properties,omitempty"`
+		// Perspectives describe the relationship from different points of
+		// view (e.g. security or operations) if any.
+		Perspectives []*Perspective `json:"perspectives,omitempty"`
 		// SourceID is the ID of the source element.
 		SourceID string `json:"sourceId"`
 		// DestinationID is ID the destination element.
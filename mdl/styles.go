@@ -0,0 +1,121 @@
+package mdl
+
+import "strings"
+
+// ElementStyle returns the style that applies to elements with the given comma
+// separated list of tags. The result merges the styles defined for each tag in
+// order so that the properties of styles associated with tags that appear
+// later in the list override the ones that appear earlier. ElementStyle
+// returns nil if there is no style defined for any of the tags.
+func (s *Styles) ElementStyle(tags string) *ElementStyle {
+	if s == nil {
+		return nil
+	}
+	var res *ElementStyle
+	for _, tag := range strings.Split(tags, ",") {
+		for _, es := range s.Elements {
+			if es.Tag != tag {
+				continue
+			}
+			if res == nil {
+				res = &ElementStyle{}
+			}
+			res.Merge(es)
+		}
+	}
+	return res
+}
+
+// RelationshipStyle returns the style that applies to relationships with the
+// given comma separated list of tags. See ElementStyle for details on how
+// styles are merged.
+func (s *Styles) RelationshipStyle(tags string) *RelationshipStyle {
+	if s == nil {
+		return nil
+	}
+	var res *RelationshipStyle
+	for _, tag := range strings.Split(tags, ",") {
+		for _, rs := range s.Relationships {
+			if rs.Tag != tag {
+				continue
+			}
+			if res == nil {
+				res = &RelationshipStyle{}
+			}
+			res.Merge(rs)
+		}
+	}
+	return res
+}
+
+// Merge copies the properties defined in other into es, the properties that
+// other does not define are left unchanged.
+func (es *ElementStyle) Merge(other *ElementStyle) {
+	es.Tag = other.Tag
+	if other.Width != nil {
+		es.Width = other.Width
+	}
+	if other.Height != nil {
+		es.Height = other.Height
+	}
+	if other.Background != "" {
+		es.Background = other.Background
+	}
+	if other.Stroke != "" {
+		es.Stroke = other.Stroke
+	}
+	if other.Color != "" {
+		es.Color = other.Color
+	}
+	if other.FontSize != nil {
+		es.FontSize = other.FontSize
+	}
+	if other.Shape != ShapeUndefined {
+		es.Shape = other.Shape
+	}
+	if other.Icon != "" {
+		es.Icon = other.Icon
+	}
+	if other.Border != BorderUndefined {
+		es.Border = other.Border
+	}
+	if other.Opacity != nil {
+		es.Opacity = other.Opacity
+	}
+	if other.Metadata != nil {
+		es.Metadata = other.Metadata
+	}
+	if other.Description != nil {
+		es.Description = other.Description
+	}
+}
+
+// Merge copies the properties defined in other into rs, the properties that
+// other does not define are left unchanged.
+func (rs *RelationshipStyle) Merge(other *RelationshipStyle) {
+	rs.Tag = other.Tag
+	if other.Thickness != nil {
+		rs.Thickness = other.Thickness
+	}
+	if other.Color != "" {
+		rs.Color = other.Color
+	}
+	if other.FontSize != nil {
+		rs.FontSize = other.FontSize
+	}
+	if other.Width != nil {
+		rs.Width = other.Width
+	}
+	if other.Dashed != nil {
+		rs.Dashed = other.Dashed
+	}
+	if other.Routing != RoutingUndefined {
+		rs.Routing = other.Routing
+	}
+	if other.Position != nil {
+		rs.Position = other.Position
+	}
+	if other.Opacity != nil {
+		rs.Opacity = other.Opacity
+	}
+}
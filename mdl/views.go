@@ -17,6 +17,8 @@ type (
 		ContainerViews []*ContainerView `json:"containerViews,omitempty"`
 		// ComponentViews lists the component views.
 		ComponentViews []*ComponentView `json:"componentViews,omitempty"`
+		// CodeViews lists the code views.
+		CodeViews []*CodeView `json:"codeViews,omitempty"`
 		// DynamicViews lists the dynamic views.
 		DynamicViews []*DynamicView `json:"dynamicViews,omitempty"`
 		// DeploymentViews lists the deployment views.
@@ -70,6 +72,13 @@ type (
 		ContainerID string `json:"containerId"`
 	}
 
+	// CodeView describes a code view for a specific component.
+	CodeView struct {
+		*ViewProps
+		// The ID of the component this view is associated with.
+		ComponentID string `json:"componentId"`
+	}
+
 	// DynamicView describes a dynamic view for a specified scope.
 	DynamicView struct {
 		*ViewProps
@@ -263,12 +272,18 @@ type (
 	// BorderKind is the enum used to represent element border styles.
 	BorderKind int
 
+	// View is the common interface for all views.
+	View interface {
+		Props() *ViewProps
+	}
+
 	// for calling json.Marshal.
 	_views          Views
 	_landscapeView  LandscapeView
 	_contextView    ContextView
 	_containerView  ContainerView
 	_componentView  ComponentView
+	_codeView       CodeView
 	_dynamicView    DynamicView
 	_deploymentView DeploymentView
 	_filteredView   FilteredView
@@ -340,6 +355,35 @@ const (
 	BorderDotted
 )
 
+// All returns all the views in a single slice.
+func (v *Views) All() (vs []View) {
+	for _, lv := range v.LandscapeViews {
+		vs = append(vs, lv)
+	}
+	for _, cv := range v.ContextViews {
+		vs = append(vs, cv)
+	}
+	for _, cv := range v.ContainerViews {
+		vs = append(vs, cv)
+	}
+	for _, cv := range v.ComponentViews {
+		vs = append(vs, cv)
+	}
+	for _, cv := range v.CodeViews {
+		vs = append(vs, cv)
+	}
+	for _, dv := range v.DynamicViews {
+		vs = append(vs, dv)
+	}
+	for _, dv := range v.DeploymentViews {
+		vs = append(vs, dv)
+	}
+	return
+}
+
+// Props returns the underlying properties object.
+func (v *ViewProps) Props() *ViewProps { return v }
+
 // MarshalJSON guarantees the order of elements in generated JSON arrays that
 // correspond to sets.
 func (v *Views) MarshalJSON() ([]byte, error) {
@@ -347,6 +391,7 @@ func (v *Views) MarshalJSON() ([]byte, error) {
 	sort.Slice(v.ContextViews, func(i, j int) bool { return v.ContextViews[i].Key < v.ContextViews[j].Key })
 	sort.Slice(v.ContainerViews, func(i, j int) bool { return v.ContainerViews[i].Key < v.ContainerViews[j].Key })
 	sort.Slice(v.ComponentViews, func(i, j int) bool { return v.ComponentViews[i].Key < v.ComponentViews[j].Key })
+	sort.Slice(v.CodeViews, func(i, j int) bool { return v.CodeViews[i].Key < v.CodeViews[j].Key })
 	sort.Slice(v.DynamicViews, func(i, j int) bool { return v.DynamicViews[i].Key < v.DynamicViews[j].Key })
 	sort.Slice(v.DeploymentViews, func(i, j int) bool { return v.DeploymentViews[i].Key < v.DeploymentViews[j].Key })
 	sort.Slice(v.FilteredViews, func(i, j int) bool { return v.FilteredViews[i].Key < v.FilteredViews[j].Key })
@@ -386,6 +431,14 @@ func (v *ComponentView) MarshalJSON() ([]byte, error) {
 	return json.Marshal(&vv)
 }
 
+// MarshalJSON guarantees the order of elements in generated JSON arrays that
+// correspond to sets.
+func (v *CodeView) MarshalJSON() ([]byte, error) {
+	sortViews(v.ViewProps)
+	vv := _codeView(*v)
+	return json.Marshal(&vv)
+}
+
 // MarshalJSON guarantees the order of elements in generated JSON arrays that
 // correspond to sets.
 func (v *DynamicView) MarshalJSON() ([]byte, error) {
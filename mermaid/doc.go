@@ -0,0 +1,15 @@
+/*
+Package mermaid renders the views of a software architecture design as
+Mermaid (https://mermaid-js.github.io) flowcharts.
+
+Render produces one flowchart per system landscape, system context, container,
+component, dynamic and deployment view defined in the design. The elements of
+a view are rendered as nodes whose shapes and colors reflect the element
+styles defined in the design and the relationships are rendered as links
+styled after the relationship styles. Software systems, containers and
+deployment nodes that contain elements of the view are rendered as subgraphs.
+
+The resulting sources can be embedded directly in Markdown documents using a
+"mermaid" fenced code block, both GitHub and GitLab render such blocks inline.
+*/
+package mermaid
@@ -0,0 +1,481 @@
+package mermaid
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+
+	"goa.design/model/mdl"
+)
+
+type (
+	// renderer renders a single view.
+	renderer struct {
+		design *mdl.Design
+		view   mdl.View
+		styles *mdl.Styles
+		buf    *bytes.Buffer
+		// inView indexes the IDs of the elements present in the view.
+		inView map[string]bool
+		// rendered records the elements already rendered.
+		rendered map[string]bool
+		// classes lists the style classes indexed by element ID.
+		classes map[string]string
+		// groups counts the group subgraphs rendered so far.
+		groups int
+	}
+)
+
+// Render renders all the views of the given design. It returns the Mermaid
+// flowchart source of each view indexed by view key.
+func Render(d *mdl.Design) map[string]string {
+	res := make(map[string]string)
+	if d.Views == nil {
+		return res
+	}
+	for _, v := range d.Views.All() {
+		res[v.Props().Key] = RenderView(d, v)
+	}
+	return res
+}
+
+// RenderView renders the given view of the given design and returns the
+// corresponding Mermaid flowchart source.
+func RenderView(d *mdl.Design, v mdl.View) string {
+	r := &renderer{
+		design:   d,
+		view:     v,
+		buf:      &bytes.Buffer{},
+		inView:   make(map[string]bool),
+		rendered: make(map[string]bool),
+		classes:  make(map[string]string),
+	}
+	if d.Views != nil {
+		r.styles = d.Views.Styles
+	}
+	for _, ev := range v.Props().ElementViews {
+		r.inView[ev.ID] = true
+	}
+	r.render()
+	return r.buf.String()
+}
+
+func (r *renderer) render() {
+	props := r.view.Props()
+	title := props.Title
+	if title == "" {
+		title = props.Description
+	}
+	if title != "" {
+		r.line("---")
+		r.line("title: %s", strings.ReplaceAll(title, "\n", " "))
+		r.line("---")
+	}
+	r.line("flowchart %s", direction(props.AutoLayout))
+	switch v := r.view.(type) {
+	case *mdl.LandscapeView:
+		r.renderEnterprise(v.EnterpriseBoundaryVisible)
+	case *mdl.ContextView:
+		r.renderEnterprise(v.EnterpriseBoundaryVisible)
+	case *mdl.ContainerView:
+		r.renderScoped(v.SoftwareSystemID, v.SystemBoundariesVisible)
+	case *mdl.ComponentView:
+		r.renderScoped(v.ContainerID, v.ContainerBoundariesVisible)
+	case *mdl.CodeView:
+		r.renderScoped(v.ComponentID, nil)
+	case *mdl.DynamicView:
+		r.renderScoped(v.ElementID, nil)
+	case *mdl.DeploymentView:
+		r.renderDeployment()
+	}
+	r.renderRelationships()
+	r.renderClasses()
+}
+
+// renderEnterprise renders the elements of a system landscape or system
+// context view, grouping internal elements in an enterprise subgraph if
+// requested.
+func (r *renderer) renderEnterprise(visible *bool) {
+	ent := r.design.Model.Enterprise
+	if visible == nil || !*visible || ent == nil {
+		r.renderAll()
+		return
+	}
+	r.line("    subgraph enterprise[%s]", quote(ent.Name))
+	var ids []string
+	for _, ev := range r.view.Props().ElementViews {
+		switch e := r.design.Model.Element(ev.ID).(type) {
+		case *mdl.Person:
+			if e.Location != mdl.LocationExternal {
+				ids = append(ids, e.ID)
+			}
+		case *mdl.SoftwareSystem:
+			if e.Location != mdl.LocationExternal {
+				ids = append(ids, e.ID)
+			}
+		}
+	}
+	r.renderGrouped(ids, "        ")
+	r.line("    end")
+	r.line("    style enterprise stroke-dasharray: 5 5")
+	r.renderAll()
+}
+
+// renderScoped renders the elements of a view scoped to the element with the
+// given ID. Elements that are children of the scope are rendered in a
+// subgraph. Other elements are rendered in the subgraph of their parent if
+// boundaries is true.
+func (r *renderer) renderScoped(scopeID string, boundaries *bool) {
+	m := r.design.Model
+	groups := make(map[string][]string)
+	var parents []string
+	for _, ev := range r.view.Props().ElementViews {
+		p := m.Parent(ev.ID)
+		if p == nil {
+			continue
+		}
+		pid, _, _ := info(p)
+		if pid != scopeID && (boundaries == nil || !*boundaries) {
+			continue
+		}
+		if _, ok := groups[pid]; !ok {
+			parents = append(parents, pid)
+		}
+		groups[pid] = append(groups[pid], ev.ID)
+	}
+	sort.Strings(parents)
+	for _, pid := range parents {
+		// Elements rendered as subgraphs cannot be rendered as nodes as
+		// well: Mermaid requires unique identifiers.
+		r.rendered[pid] = true
+	}
+	for _, pid := range parents {
+		_, name, _ := info(m.Element(pid))
+		r.line("    subgraph %s[%s]", alias(pid), quote(name))
+		r.renderGrouped(groups[pid], "        ")
+		r.line("    end")
+		r.line("    style %s stroke-dasharray: 5 5", alias(pid))
+	}
+	r.renderAll()
+}
+
+// renderDeployment renders the deployment nodes of a deployment view as
+// nested subgraphs containing the infrastructure nodes and container
+// instances.
+func (r *renderer) renderDeployment() {
+	for _, ev := range r.view.Props().ElementViews {
+		if n, ok := r.design.Model.Element(ev.ID).(*mdl.DeploymentNode); ok {
+			if r.design.Model.Parent(n.ID) == nil {
+				r.renderNode(n, "    ")
+			}
+		}
+	}
+	r.renderAll()
+}
+
+func (r *renderer) renderNode(n *mdl.DeploymentNode, indent string) {
+	r.rendered[n.ID] = true
+	label := "<b>" + n.Name + "</b>"
+	if n.Instances != nil && *n.Instances > 1 {
+		label += fmt.Sprintf(" x%d", *n.Instances)
+	}
+	if n.Technology != "" {
+		label += "<br/>[" + n.Technology + "]"
+	}
+	r.line("%ssubgraph %s[%s]", indent, alias(n.ID), quote(label))
+	for _, c := range n.Children {
+		if r.inView[c.ID] {
+			r.renderNode(c, indent+"    ")
+		}
+	}
+	for _, inf := range n.InfrastructureNodes {
+		if r.inView[inf.ID] {
+			r.renderElement(inf, indent+"    ")
+		}
+	}
+	for _, ci := range n.ContainerInstances {
+		if r.inView[ci.ID] {
+			r.renderElement(ci, indent+"    ")
+		}
+	}
+	r.line("%send", indent)
+}
+
+// renderAll renders all the elements in the view that haven't been rendered
+// yet.
+func (r *renderer) renderAll() {
+	var ids []string
+	for _, ev := range r.view.Props().ElementViews {
+		if !r.rendered[ev.ID] && r.design.Model.Element(ev.ID) != nil {
+			ids = append(ids, ev.ID)
+		}
+	}
+	r.renderGrouped(ids, "    ")
+}
+
+// renderGrouped renders the elements with the given IDs. Elements that belong
+// to a group are rendered in a dashed subgraph, elements with the same group
+// name but different parents belong to different groups.
+func (r *renderer) renderGrouped(ids []string, indent string) {
+	m := r.design.Model
+	groups := make(map[string][]string)
+	var keys, names []string
+	for _, id := range ids {
+		g := m.Group(id)
+		if g == "" {
+			r.renderElement(m.Element(id), indent)
+			continue
+		}
+		pid, _, _ := info(m.Parent(id))
+		key := pid + "/" + g
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
+			names = append(names, g)
+		}
+		groups[key] = append(groups[key], id)
+	}
+	for i, key := range keys {
+		r.groups++
+		galias := fmt.Sprintf("group%d", r.groups)
+		r.line("%ssubgraph %s[%s]", indent, galias, quote(names[i]))
+		for _, id := range groups[key] {
+			r.renderElement(m.Element(id), indent+"    ")
+		}
+		r.line("%send", indent)
+		r.line("%sstyle %s stroke-dasharray: 5 5", indent, galias)
+	}
+}
+
+func (r *renderer) renderElement(e interface{}, indent string) {
+	id, _, _ := info(e)
+	if r.rendered[id] {
+		return
+	}
+	r.rendered[id] = true
+	var (
+		name, kind, tech, desc, tags string
+		shape                        = mdl.ShapeBox
+	)
+	switch a := e.(type) {
+	case *mdl.Person:
+		name, kind, desc, tags = a.Name, "Person", a.Description, a.Tags
+		shape = mdl.ShapePerson
+	case *mdl.SoftwareSystem:
+		name, kind, desc, tags = a.Name, "Software System", a.Description, a.Tags
+	case *mdl.Container:
+		name, kind, tech, desc, tags = a.Name, "Container", a.Technology, a.Description, a.Tags
+	case *mdl.Component:
+		name, kind, tech, desc, tags = a.Name, "Component", a.Technology, a.Description, a.Tags
+	case *mdl.CodeElement:
+		name, kind, tech, desc, tags = a.Name, "Code Element", a.Technology, a.Description, a.Tags
+	case *mdl.InfrastructureNode:
+		name, kind, tech, desc, tags = a.Name, "Infrastructure Node", a.Technology, a.Description, a.Tags
+	case *mdl.ContainerInstance:
+		c, ok := r.design.Model.Element(a.ContainerID).(*mdl.Container)
+		if !ok {
+			return
+		}
+		name, kind, tech, desc, tags = c.Name, "Container", c.Technology, c.Description, c.Tags
+	case *mdl.DeploymentNode:
+		r.renderNode(a, indent)
+		return
+	default:
+		return
+	}
+	es := r.styles.ElementStyle(tags)
+	if es != nil && es.Shape != mdl.ShapeUndefined {
+		shape = es.Shape
+	}
+	label := "<b>" + name + "</b><br/>[" + kind
+	if tech != "" {
+		label += ": " + tech
+	}
+	label += "]"
+	if desc != "" {
+		label += "<br/>" + desc
+	}
+	open, close := delimiters(shape)
+	r.line("%s%s%s%s%s", indent, alias(id), open, quote(label), close)
+	if es != nil {
+		r.classes[id] = classDef(es)
+	}
+}
+
+func (r *renderer) renderRelationships() {
+	_, dynamic := r.view.(*mdl.DynamicView)
+	var styles []string
+	for _, rv := range r.view.Props().RelationshipViews {
+		rel := r.design.Model.Relationship(rv.ID)
+		if rel == nil {
+			continue
+		}
+		desc := rel.Description
+		if rv.Description != "" {
+			desc = rv.Description
+		}
+		if dynamic && rv.Order != "" {
+			desc = rv.Order + ". " + desc
+		}
+		if rel.Technology != "" {
+			desc += "<br/>[" + rel.Technology + "]"
+		}
+		arrow := "-->"
+		if rel.InteractionStyle == mdl.InteractionAsynchronous {
+			arrow = "-.->"
+		}
+		if desc == "" {
+			r.line("    %s %s %s", alias(rel.SourceID), arrow, alias(rel.DestinationID))
+		} else {
+			r.line("    %s %s|%s| %s", alias(rel.SourceID), arrow, quote(desc), alias(rel.DestinationID))
+		}
+		var style string
+		if rs := r.styles.RelationshipStyle(rel.Tags); rs != nil {
+			style = linkStyle(rs)
+		}
+		styles = append(styles, style)
+	}
+	for i, s := range styles {
+		if s != "" {
+			r.line("    linkStyle %d %s", i, s)
+		}
+	}
+}
+
+// renderClasses renders the class definitions corresponding to the styles of
+// the elements in the view and assigns them to the elements.
+func (r *renderer) renderClasses() {
+	names := make(map[string]string)
+	var defs []string
+	for _, ev := range r.view.Props().ElementViews {
+		def, ok := r.classes[ev.ID]
+		if !ok || def == "" {
+			continue
+		}
+		name, ok := names[def]
+		if !ok {
+			name = fmt.Sprintf("style%d", len(defs))
+			names[def] = name
+			defs = append(defs, def)
+			r.line("    classDef %s %s", name, def)
+		}
+		r.line("    class %s %s", alias(ev.ID), name)
+	}
+}
+
+func (r *renderer) line(format string, a ...interface{}) {
+	fmt.Fprintf(r.buf, format+"\n", a...)
+}
+
+// direction returns the flowchart direction corresponding to the given
+// layout.
+func direction(l *mdl.AutoLayout) string {
+	if l == nil {
+		return "TB"
+	}
+	switch l.RankDirection {
+	case mdl.RankBottomTop:
+		return "BT"
+	case mdl.RankLeftRight:
+		return "LR"
+	case mdl.RankRightLeft:
+		return "RL"
+	default:
+		return "TB"
+	}
+}
+
+// delimiters returns the Mermaid node delimiters corresponding to the given
+// shape.
+func delimiters(shape mdl.ShapeKind) (string, string) {
+	switch shape {
+	case mdl.ShapeRoundedBox, mdl.ShapePerson, mdl.ShapeRobot:
+		return "(", ")"
+	case mdl.ShapeCircle:
+		return "((", "))"
+	case mdl.ShapeEllipse:
+		return "([", "])"
+	case mdl.ShapeCylinder, mdl.ShapePipe:
+		return "[(", ")]"
+	case mdl.ShapeHexagon:
+		return "{{", "}}"
+	case mdl.ShapeComponent, mdl.ShapeFolder:
+		return "[[", "]]"
+	default:
+		return "[", "]"
+	}
+}
+
+// classDef returns the Mermaid class definition corresponding to the given
+// element style.
+func classDef(es *mdl.ElementStyle) string {
+	var props []string
+	if es.Background != "" {
+		props = append(props, "fill:"+es.Background)
+	}
+	if es.Color != "" {
+		props = append(props, "color:"+es.Color)
+	}
+	if es.Stroke != "" {
+		props = append(props, "stroke:"+es.Stroke)
+	}
+	switch es.Border {
+	case mdl.BorderDashed:
+		props = append(props, "stroke-dasharray:5 5")
+	case mdl.BorderDotted:
+		props = append(props, "stroke-dasharray:2 2")
+	}
+	return strings.Join(props, ",")
+}
+
+// linkStyle returns the Mermaid link style corresponding to the given
+// relationship style.
+func linkStyle(rs *mdl.RelationshipStyle) string {
+	var props []string
+	if rs.Color != "" {
+		props = append(props, "stroke:"+rs.Color, "color:"+rs.Color)
+	}
+	if rs.Thickness != nil {
+		props = append(props, fmt.Sprintf("stroke-width:%dpx", *rs.Thickness))
+	}
+	if rs.Dashed != nil && *rs.Dashed {
+		props = append(props, "stroke-dasharray:5 5")
+	}
+	return strings.Join(props, ",")
+}
+
+// alias returns a valid Mermaid node identifier for the element with the
+// given ID.
+func alias(id string) string {
+	return "e" + id
+}
+
+// quote returns a Mermaid string literal for s.
+func quote(s string) string {
+	s = strings.ReplaceAll(s, `"`, "#quot;")
+	s = strings.ReplaceAll(s, "\n", "<br/>")
+	return `"` + s + `"`
+}
+
+// info returns the ID, name and tags of the given element.
+func info(e interface{}) (id, name, tags string) {
+	switch a := e.(type) {
+	case *mdl.Person:
+		return a.ID, a.Name, a.Tags
+	case *mdl.SoftwareSystem:
+		return a.ID, a.Name, a.Tags
+	case *mdl.Container:
+		return a.ID, a.Name, a.Tags
+	case *mdl.Component:
+		return a.ID, a.Name, a.Tags
+	case *mdl.CodeElement:
+		return a.ID, a.Name, a.Tags
+	case *mdl.DeploymentNode:
+		return a.ID, a.Name, a.Tags
+	case *mdl.InfrastructureNode:
+		return a.ID, a.Name, a.Tags
+	case *mdl.ContainerInstance:
+		return a.ID, "", a.Tags
+	}
+	return "", "", ""
+}
@@ -0,0 +1,170 @@
+package mermaid
+
+import (
+	"strings"
+	"testing"
+
+	. "goa.design/model/dsl"
+	"goa.design/model/internal/testutil"
+)
+
+// library defines the design used to test the Mermaid renderer.
+func library() {
+	Design("Library", "A lending library.", func() {
+		Enterprise("City")
+		var Mailer = SoftwareSystem("Mailer", "Sends e-mails.", func() {
+			External()
+			Tag("External")
+		})
+		var Catalog = SoftwareSystem("Catalog", "Lists books.\nTracks loans.", func() {
+			Container("App", "Web app.", "Elm", func() {
+				Uses("API", "Calls", "JSON")
+			})
+			Container("API", "REST API.", "Go", func() {
+				Uses("Database", "Reads and writes", "SQL")
+				Uses("Events", "Publishes loans", Asynchronous)
+				Uses(Mailer, "Sends reminders", "SMTP", Asynchronous)
+				Component("Search", "Searches books.", "Go", func() {
+					Uses("Catalog/Database", "Queries", "SQL")
+				})
+				Component("Loans", "Manages loans.", "Go", func() {
+					Uses("Catalog/Events", "Publishes", Asynchronous)
+				})
+			})
+			Container("Database", "Stores books.", "SQLite", func() {
+				Tag("Database")
+			})
+			Container("Events", "Streams events.", "Kafka", func() {
+				Tag("Stream")
+			})
+		})
+		Person("Member", `Borrows "books".`, func() {
+			External()
+			Uses(Catalog, "Searches")
+			Uses("Catalog/App", "Uses", "HTTPS")
+		})
+		Person("Librarian", "Manages the catalog.", func() {
+			Tag("Staff")
+			Uses("Catalog/App", "Edits books", "HTTPS")
+		})
+		DeploymentEnvironment("Production", func() {
+			DeploymentNode("Data center", "", "On premise", func() {
+				InfrastructureNode("Firewall", "Filters traffic.", "pfSense")
+				DeploymentNode("Server", "", "Ubuntu", func() {
+					Instances(2)
+					ContainerInstance("Catalog/App")
+					ContainerInstance("Catalog/API")
+				})
+				DeploymentNode("Storage", "", "ZFS", func() {
+					ContainerInstance("Catalog/Database")
+				})
+			})
+		})
+		Views(func() {
+			SystemLandscapeView("landscape", "City systems.", func() {
+				AddAll()
+				EnterpriseBoundaryVisible()
+				AutoLayout(RankBottomTop)
+			})
+			SystemContextView(Catalog, "context", func() {
+				Title("Catalog\ncontext")
+				AddAll()
+			})
+			ContainerView(Catalog, "containers", "Catalog containers.", func() {
+				AddAll()
+				AutoLayout(RankRightLeft)
+			})
+			ComponentView("Catalog/API", "components", "API components.", func() {
+				AddAll()
+				ContainerBoundariesVisible()
+			})
+			DynamicView(Catalog, "loan", "Borrowing a book.", func() {
+				Link("Member", "Catalog/App", "Uses")
+				Link("Catalog/App", "Catalog/API", "Calls")
+				Link("Catalog/API", "Catalog/Database", "Reads and writes")
+			})
+			DeploymentView(Catalog, "Production", "production", "Production deployment.", func() {
+				AddAll()
+			})
+			Styles(func() {
+				ElementStyle("Database", func() {
+					Shape(ShapeCylinder)
+					Background("#1168bd")
+					Color("#ffffff")
+				})
+				ElementStyle("Stream", func() {
+					Shape(ShapePipe)
+					Background("#1168bd")
+					Color("#ffffff")
+				})
+				ElementStyle("External", func() {
+					Shape(ShapeHexagon)
+					Stroke("#999999")
+					Border(BorderDotted)
+				})
+				ElementStyle("Staff", func() {
+					Shape(ShapeRobot)
+				})
+				RelationshipStyle("Asynchronous", func() {
+					Color("#ff8800")
+					Thickness(4)
+					Dashed()
+				})
+			})
+		})
+	})
+}
+
+func TestRender(t *testing.T) {
+	views := Render(testutil.RunDSL(t, library))
+	keys := []string{"components", "containers", "context", "landscape", "loan", "production"}
+	if len(views) != len(keys) {
+		t.Errorf("got %d views, want %d", len(views), len(keys))
+	}
+	for _, key := range keys {
+		key := key
+		t.Run(key, func(t *testing.T) {
+			src, ok := views[key]
+			if !ok {
+				t.Fatalf("view %q not rendered", key)
+			}
+			testutil.AssertGolden(t, key+".mmd", src)
+		})
+	}
+}
+
+func TestRenderEdgeCases(t *testing.T) {
+	views := Render(testutil.RunDSL(t, library))
+	tests := []struct {
+		name, view, want string
+		absent           bool
+	}{
+		{"enterprise subgraph", "landscape", `subgraph enterprise["City"]`, false},
+		{"bottom top direction", "landscape", "flowchart BT", false},
+		{"escaped quotes", "landscape", `Borrows #quot;books#quot;.`, false},
+		{"newline in description", "context", `Lists books.<br/>Tracks loans.`, false},
+		{"newline in title", "context", "title: Catalog context\n", false},
+		{"robot shape", "context", `("<b>Librarian</b><br/>[Person]<br/>Manages the catalog.")`, false},
+		{"hexagon shape", "context", `{{"<b>Mailer</b><br/>[Software System]<br/>Sends e-mails."}}`, false},
+		{"dotted border", "context", "stroke:#999999,stroke-dasharray:2 2", false},
+		{"right left direction", "containers", "flowchart RL", false},
+		{"system subgraph", "containers", `subgraph e1a8eq48["Catalog"]`, false},
+		{"asynchronous arrow", "containers", `-.->|"Publishes loans"|`, false},
+		{"link style thickness", "containers", "stroke:#ff8800,color:#ff8800,stroke-width:4px,stroke-dasharray:5 5", false},
+		{"shared class", "containers", "class e18p5vh6 style1", false},
+		{"no duplicate class", "containers", "classDef style2", true},
+		{"container boundary", "components", `subgraph e1a65lqd["API"]`, false},
+		{"boundary not rendered as node", "components", `e1a65lqd["<b>API</b>`, true},
+		{"instances label", "production", `"<b>Server</b> x2<br/>[Ubuntu]"`, false},
+		{"infrastructure node", "production", `["<b>Firewall</b><br/>[Infrastructure Node: pfSense]<br/>Filters traffic."]`, false},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			got := views[tt.view]
+			if strings.Contains(got, tt.want) == tt.absent {
+				t.Errorf("%s view: got contains %q = %v, want %v:\n%s", tt.view, tt.want, tt.absent, !tt.absent, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,380 @@
+package otel
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"goa.design/model/mdl"
+)
+
+type (
+	// step is an interaction between two elements.
+	step struct {
+		// src is the ID of the source element.
+		src string
+		// dest is the ID of the destination element.
+		dest string
+		// spans lists the spans that describe the interaction, the client
+		// span first if any.
+		spans []*Span
+	}
+)
+
+// Merge adds to d one dynamic view per distinct sequence of interactions
+// found in the given traces. mapping maps service names to element paths
+// ("<Software System>", "<Software System>/<Container>" or
+// "<Software System>/<Container>/<Component>" or "<Person>"). Services that
+// are not in mapping are mapped to the element with the same name if any.
+// Merge returns the names of the services that could not be mapped.
+func Merge(d *mdl.Design, traces []*Trace, mapping map[string]string) ([]string, error) {
+	if d.Views == nil {
+		d.Views = &mdl.Views{}
+	}
+	var (
+		elements = make(map[string]string)
+		unmapped = make(map[string]bool)
+		seen     = make(map[string]bool)
+		keys     = make(map[string]bool)
+	)
+	for _, v := range d.Views.DynamicViews {
+		keys[v.Key] = true
+	}
+	resolve := func(service string) string {
+		if id, ok := elements[service]; ok {
+			return id
+		}
+		var id string
+		if path, ok := mapping[service]; ok {
+			if id = find(d.Model, path); id == "" {
+				unmapped[service] = true
+			}
+		} else if id = findByName(d.Model, service); id == "" {
+			unmapped[service] = true
+		}
+		elements[service] = id
+		return id
+	}
+	for _, t := range traces {
+		steps := interactions(t, resolve)
+		if len(steps) == 0 {
+			continue
+		}
+		var sig []string
+		for _, s := range steps {
+			sig = append(sig, s.src+">"+s.dest)
+		}
+		if seen[strings.Join(sig, ",")] {
+			continue
+		}
+		seen[strings.Join(sig, ",")] = true
+
+		root := t.Root()
+		v := &mdl.DynamicView{
+			ViewProps: &mdl.ViewProps{
+				Key:         uniqueKey(keys, viewKey(root)),
+				Description: fmt.Sprintf("Interactions of %s %q (trace %s).", root.Service, root.Name, t.ID),
+			},
+			ElementID: scope(d.Model, steps),
+		}
+		in := make(map[string]bool)
+		for i, s := range steps {
+			for _, id := range []string{s.src, s.dest} {
+				if !in[id] {
+					v.ElementViews = append(v.ElementViews, &mdl.ElementView{ID: id})
+					in[id] = true
+				}
+			}
+			r := relationship(d, s)
+			v.RelationshipViews = append(v.RelationshipViews, &mdl.RelationshipView{
+				ID:    r.ID,
+				Order: strconv.Itoa(i + 1),
+			})
+		}
+		d.Views.DynamicViews = append(d.Views.DynamicViews, v)
+	}
+	var res []string
+	for s := range unmapped {
+		res = append(res, s)
+	}
+	sort.Strings(res)
+	if len(seen) == 0 {
+		return res, fmt.Errorf("no interaction between mapped elements found in traces")
+	}
+	return res, nil
+}
+
+// interactions returns the interactions between elements described by the
+// spans of t in order. resolve maps service names to element IDs, it returns
+// an empty string if the service cannot be mapped. Consecutive identical
+// interactions are merged.
+func interactions(t *Trace, resolve func(string) string) []*step {
+	spans := make(map[string]*Span, len(t.Spans))
+	remote := make(map[string]bool)
+	for _, s := range t.Spans {
+		spans[s.ID] = s
+	}
+	for _, s := range t.Spans {
+		if p, ok := spans[s.ParentID]; ok && p.Service != s.Service {
+			remote[p.ID] = true
+		}
+	}
+	var res []*step
+	add := func(src, dest string, spans ...*Span) {
+		srcID, destID := resolve(src), resolve(dest)
+		if srcID == "" || destID == "" || srcID == destID {
+			return
+		}
+		if n := len(res); n > 0 && res[n-1].src == srcID && res[n-1].dest == destID {
+			return
+		}
+		res = append(res, &step{src: srcID, dest: destID, spans: spans})
+	}
+	for _, s := range t.Spans {
+		if p, ok := spans[s.ParentID]; ok && p.Service != s.Service {
+			add(p.Service, s.Service, p, s)
+			continue
+		}
+		peer := s.Attributes["peer.service"]
+		if peer != "" && !remote[s.ID] && (s.Kind == SpanKindClient || s.Kind == SpanKindProducer) {
+			add(s.Service, peer, s)
+		}
+	}
+	return res
+}
+
+// relationship returns the relationship between the source and destination
+// of s creating it if needed. New relationships are added to the static views
+// of d that include both elements.
+func relationship(d *mdl.Design, s *step) *mdl.Relationship {
+	m := d.Model
+	var res *mdl.Relationship
+	m.IterateRelationships(func(r *mdl.Relationship) {
+		if res == nil && r.SourceID == s.src && r.DestinationID == s.dest && r.LinkedRelationshipID == "" {
+			res = r
+		}
+	})
+	if res != nil {
+		return res
+	}
+	var (
+		tech  string
+		async bool
+	)
+	for _, sp := range s.spans {
+		if t, a := technology(sp); t != "" || a {
+			tech, async = t, a
+			break
+		}
+	}
+	res = &mdl.Relationship{
+		ID:            "otel:" + s.src + ":" + s.dest,
+		Description:   "Uses",
+		Tags:          "Relationship",
+		SourceID:      s.src,
+		DestinationID: s.dest,
+		Technology:    tech,
+	}
+	if async {
+		res.Tags += ",Asynchronous"
+		res.InteractionStyle = mdl.InteractionAsynchronous
+	}
+	switch e := m.Element(s.src).(type) {
+	case *mdl.Person:
+		e.Relationships = append(e.Relationships, res)
+	case *mdl.SoftwareSystem:
+		e.Relationships = append(e.Relationships, res)
+	case *mdl.Container:
+		e.Relationships = append(e.Relationships, res)
+	case *mdl.Component:
+		e.Relationships = append(e.Relationships, res)
+	}
+	for _, v := range staticViews(d, s) {
+		v.RelationshipViews = append(v.RelationshipViews, &mdl.RelationshipView{ID: res.ID})
+	}
+	return res
+}
+
+// staticViews returns the properties of the landscape, context, container and
+// component views of d that include both elements of s.
+func staticViews(d *mdl.Design, s *step) []*mdl.ViewProps {
+	var all []*mdl.ViewProps
+	for _, v := range d.Views.LandscapeViews {
+		all = append(all, v.ViewProps)
+	}
+	for _, v := range d.Views.ContextViews {
+		all = append(all, v.ViewProps)
+	}
+	for _, v := range d.Views.ContainerViews {
+		all = append(all, v.ViewProps)
+	}
+	for _, v := range d.Views.ComponentViews {
+		all = append(all, v.ViewProps)
+	}
+	var res []*mdl.ViewProps
+	for _, v := range all {
+		var src, dest bool
+		for _, ev := range v.ElementViews {
+			src = src || ev.ID == s.src
+			dest = dest || ev.ID == s.dest
+		}
+		if src && dest {
+			res = append(res, v)
+		}
+	}
+	return res
+}
+
+// technology returns the technology of the interaction described by s and
+// whether it is asynchronous.
+func technology(s *Span) (string, bool) {
+	a := s.Attributes
+	switch {
+	case a["messaging.system"] != "":
+		return a["messaging.system"], true
+	case s.Kind == SpanKindProducer || s.Kind == SpanKindConsumer:
+		return "", true
+	case a["rpc.system"] == "grpc":
+		return "gRPC", false
+	case a["rpc.system"] != "":
+		return a["rpc.system"], false
+	case a["db.system"] != "":
+		return a["db.system"], false
+	case a["http.method"] != "" || a["http.request.method"] != "":
+		return "HTTP", false
+	}
+	return "", false
+}
+
+// scope returns the ID of the scope of the dynamic view that contains the
+// given steps: the software system if all elements are containers of the
+// same system, the container if all elements are components of the same
+// container and the empty string (global scope) otherwise.
+func scope(m *mdl.Model, steps []*step) string {
+	var parent string
+	for _, s := range steps {
+		for _, id := range []string{s.src, s.dest} {
+			var pid string
+			switch p := m.Parent(id).(type) {
+			case *mdl.SoftwareSystem:
+				pid = p.ID
+			case *mdl.Container:
+				pid = p.ID
+			}
+			if pid == "" || parent != "" && pid != parent {
+				return ""
+			}
+			parent = pid
+		}
+	}
+	return parent
+}
+
+// find returns the ID of the element with the given path if any.
+func find(m *mdl.Model, path string) string {
+	parts := strings.Split(path, "/")
+	if len(parts) == 1 {
+		for _, p := range m.People {
+			if p.Name == parts[0] {
+				return p.ID
+			}
+		}
+	}
+	for _, s := range m.Systems {
+		if s.Name != parts[0] {
+			continue
+		}
+		if len(parts) == 1 {
+			return s.ID
+		}
+		for _, c := range s.Containers {
+			if c.Name != parts[1] {
+				continue
+			}
+			if len(parts) == 2 {
+				return c.ID
+			}
+			for _, cmp := range c.Components {
+				if cmp.Name == parts[2] && len(parts) == 3 {
+					return cmp.ID
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// findByName returns the ID of the container, component, software system or
+// person whose name matches the given service name ignoring case, spaces,
+// dashes and underscores if any.
+func findByName(m *mdl.Model, service string) string {
+	name := normalize(service)
+	for _, s := range m.Systems {
+		for _, c := range s.Containers {
+			if normalize(c.Name) == name {
+				return c.ID
+			}
+		}
+	}
+	for _, s := range m.Systems {
+		for _, c := range s.Containers {
+			for _, cmp := range c.Components {
+				if normalize(cmp.Name) == name {
+					return cmp.ID
+				}
+			}
+		}
+	}
+	for _, s := range m.Systems {
+		if normalize(s.Name) == name {
+			return s.ID
+		}
+	}
+	for _, p := range m.People {
+		if normalize(p.Name) == name {
+			return p.ID
+		}
+	}
+	return ""
+}
+
+// normalize returns the lower case version of s with spaces, dashes and
+// underscores removed.
+func normalize(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r == ' ' || r == '-' || r == '_' {
+			return -1
+		}
+		return unicode.ToLower(r)
+	}, s)
+}
+
+// viewKey returns the key of the view built from the trace with the given
+// root span.
+func viewKey(root *Span) string {
+	words := strings.FieldsFunc(root.Name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	var key string
+	for _, w := range words {
+		r := []rune(strings.ToLower(w))
+		key += string(unicode.ToUpper(r[0])) + string(r[1:])
+	}
+	if key == "" {
+		key = "Trace"
+	}
+	return key
+}
+
+// uniqueKey returns a key derived from key that is not in keys and adds it
+// to keys.
+func uniqueKey(keys map[string]bool, key string) string {
+	res := key
+	for i := 2; keys[res]; i++ {
+		res = key + strconv.Itoa(i)
+	}
+	keys[res] = true
+	return res
+}
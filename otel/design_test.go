@@ -0,0 +1,172 @@
+package otel
+
+import (
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"goa.design/model/mdl"
+)
+
+func TestLoad(t *testing.T) {
+	traces, err := Load(filepath.Join("testdata", "traces.json"))
+	if err != nil {
+		t.Fatalf("failed to load traces: %s", err)
+	}
+	if len(traces) != 3 {
+		t.Fatalf("got %d traces, want 3", len(traces))
+	}
+	for i, id := range []string{"t1", "t2", "t3"} {
+		if traces[i].ID != id {
+			t.Errorf("got trace %d ID %q, want %q", i, traces[i].ID, id)
+		}
+	}
+	t1 := traces[0]
+	if len(t1.Spans) != 7 {
+		t.Fatalf("got %d spans in t1, want 7", len(t1.Spans))
+	}
+	root := t1.Root()
+	if root.ID != "a1" || root.Service != "frontend" || root.Kind != SpanKindServer {
+		t.Errorf("got root span %+v", root)
+	}
+	if s := t1.Spans[len(t1.Spans)-1]; s.ID != "c2" || s.Attributes["messaging.system"] != "kafka" {
+		t.Errorf("got last span %+v, want c2 with kafka messaging system", s)
+	}
+	if k := traces[2].Spans[1].Kind; k != SpanKindClient {
+		t.Errorf("got span kind %d for string encoded kind, want %d", k, SpanKindClient)
+	}
+}
+
+func TestMerge(t *testing.T) {
+	traces, err := Load(filepath.Join("testdata", "traces.json"))
+	if err != nil {
+		t.Fatalf("failed to load traces: %s", err)
+	}
+	d := design()
+	unmapped, err := Merge(d, traces, map[string]string{
+		"payments-svc": "Shop/Payments",
+		"ledger":       "Bank",
+	})
+	if err != nil {
+		t.Fatalf("failed to merge traces: %s", err)
+	}
+	if got := strings.Join(unmapped, ","); got != "cache" {
+		t.Errorf("got unmapped services %q, want %q", got, "cache")
+	}
+
+	// t2 has the same signature as t1 and is skipped.
+	views := d.Views.DynamicViews
+	if len(views) != 2 {
+		t.Fatalf("got %d dynamic views, want 2", len(views))
+	}
+	if views[0].Key != "GetCheckout" || views[1].Key != "GetCheckout2" {
+		t.Errorf("got view keys %q and %q, want GetCheckout and GetCheckout2", views[0].Key, views[1].Key)
+	}
+	if !strings.Contains(views[0].Description, "(trace t1)") {
+		t.Errorf("got description %q, want reference to trace t1", views[0].Description)
+	}
+
+	cases := []struct {
+		Src, Dest string
+		Tech      string
+		Async     bool
+	}{
+		{"Frontend", "Checkout", "", false}, // existing relationship
+		{"Checkout", "Payments", "gRPC", false},
+		{"Payments", "Bank", "kafka", true},
+	}
+	v := views[0]
+	if len(v.RelationshipViews) != len(cases) {
+		t.Fatalf("got %d links, want %d", len(v.RelationshipViews), len(cases))
+	}
+	for i, c := range cases {
+		rv := v.RelationshipViews[i]
+		if rv.Order != strconv.Itoa(i+1) {
+			t.Errorf("got link %d order %q, want %q", i, rv.Order, strconv.Itoa(i+1))
+		}
+		r := d.Model.Relationship(rv.ID)
+		if r == nil {
+			t.Fatalf("relationship %q not found", rv.ID)
+		}
+		if src, dest := name(d.Model, r.SourceID), name(d.Model, r.DestinationID); src != c.Src || dest != c.Dest {
+			t.Errorf("got link %d %s -> %s, want %s -> %s", i, src, dest, c.Src, c.Dest)
+		}
+		if r.Technology != c.Tech {
+			t.Errorf("got link %d technology %q, want %q", i, r.Technology, c.Tech)
+		}
+		if async := r.InteractionStyle == mdl.InteractionAsynchronous; async != c.Async {
+			t.Errorf("got link %d asynchronous %v, want %v", i, async, c.Async)
+		}
+	}
+
+	// The existing relationship is reused and new relationships are added to
+	// the container view.
+	if id := v.RelationshipViews[0].ID; id != "frontend-checkout" {
+		t.Errorf("got relationship %q for first link, want existing relationship", id)
+	}
+	rvs := d.Views.ContainerViews[0].RelationshipViews
+	if len(rvs) != 2 || rvs[1].ID != v.RelationshipViews[1].ID {
+		t.Errorf("got relationships %+v in container view, want the existing one and Checkout -> Payments", rvs)
+	}
+
+	// Global scope as Bank is not a container of Shop.
+	if v.ElementID != "" {
+		t.Errorf("got scope %q, want global", v.ElementID)
+	}
+	if views[1].ElementID != "shop" {
+		t.Errorf("got scope %q, want shop", views[1].ElementID)
+	}
+}
+
+func TestMergeNoInteraction(t *testing.T) {
+	traces, err := Load(filepath.Join("testdata", "traces.json"))
+	if err != nil {
+		t.Fatalf("failed to load traces: %s", err)
+	}
+	unmapped, err := Merge(&mdl.Design{Model: &mdl.Model{}}, traces, nil)
+	if err == nil {
+		t.Fatal("got no error, want error")
+	}
+	if got := strings.Join(unmapped, ","); got != "cache,checkout,frontend,ledger,payments-svc" {
+		t.Errorf("got unmapped services %q", got)
+	}
+}
+
+// design returns a design with a Shop system made of the Frontend, Checkout
+// and Payments containers and a Bank system. Frontend uses Checkout and the
+// container view includes the Frontend, Checkout and Payments containers.
+func design() *mdl.Design {
+	frontend := &mdl.Container{ID: "frontend", Name: "Frontend", Relationships: []*mdl.Relationship{
+		{ID: "frontend-checkout", SourceID: "frontend", DestinationID: "checkout", Description: "Places orders"},
+	}}
+	return &mdl.Design{
+		Model: &mdl.Model{Systems: []*mdl.SoftwareSystem{
+			{ID: "shop", Name: "Shop", Containers: []*mdl.Container{
+				frontend,
+				{ID: "checkout", Name: "Checkout"},
+				{ID: "payments", Name: "Payments"},
+			}},
+			{ID: "bank", Name: "Bank"},
+		}},
+		Views: &mdl.Views{ContainerViews: []*mdl.ContainerView{{
+			ViewProps: &mdl.ViewProps{
+				Key:               "containers",
+				ElementViews:      []*mdl.ElementView{{ID: "frontend"}, {ID: "checkout"}, {ID: "payments"}},
+				RelationshipViews: []*mdl.RelationshipView{{ID: "frontend-checkout"}},
+			},
+			SoftwareSystemID: "shop",
+		}}},
+	}
+}
+
+// name returns the name of the element with the given ID.
+func name(m *mdl.Model, id string) string {
+	switch e := m.Element(id).(type) {
+	case *mdl.SoftwareSystem:
+		return e.Name
+	case *mdl.Container:
+		return e.Name
+	}
+	return id
+}
@@ -0,0 +1,22 @@
+/*
+Package otel builds dynamic views of a software architecture design from
+OpenTelemetry traces.
+
+Load reads traces exported using the OTLP JSON encoding, for example by the
+OpenTelemetry Collector file exporter. Merge maps the services that produced
+the spans (identified by the "service.name" resource attribute) to elements
+of a design and adds one dynamic view per distinct call sequence:
+
+	service.name                         -> Element (via mapping or by name)
+	Span whose parent belongs to another -> Link from the parent service
+	service                                 element to the span service element
+	Client or producer span with a       -> Link to the peer service element
+	"peer.service" attribute and no
+	child span in another service
+
+The links are ordered using the span start times. Relationships that do not
+exist in the model are created, their technology is derived from the span
+attributes (HTTP, gRPC, messaging and database systems), and are added to the
+static views that include both ends.
+*/
+package otel
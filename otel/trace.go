@@ -0,0 +1,214 @@
+package otel
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+)
+
+type (
+	// Trace is a set of spans sharing the same trace ID.
+	Trace struct {
+		// ID of trace.
+		ID string
+		// Spans of trace sorted by start time.
+		Spans []*Span
+	}
+
+	// Span is a single operation in a trace.
+	Span struct {
+		// ID of span.
+		ID string
+		// ParentID is the ID of the parent span if any.
+		ParentID string
+		// Name of span.
+		Name string
+		// Kind of span.
+		Kind SpanKind
+		// Service is the value of the "service.name" resource attribute.
+		Service string
+		// Start is the span start time in nanoseconds since the epoch.
+		Start uint64
+		// Attributes of span.
+		Attributes map[string]string
+	}
+
+	// SpanKind is the kind of a span.
+	SpanKind int
+
+	// export is the OTLP JSON encoding of a trace export request.
+	export struct {
+		ResourceSpans []*struct {
+			Resource *struct {
+				Attributes []*attribute `json:"attributes"`
+			} `json:"resource"`
+			ScopeSpans []*struct {
+				Spans []*span `json:"spans"`
+			} `json:"scopeSpans"`
+			// InstrumentationLibrarySpans is the field used by older
+			// versions of the protocol.
+			InstrumentationLibrarySpans []*struct {
+				Spans []*span `json:"spans"`
+			} `json:"instrumentationLibrarySpans"`
+		} `json:"resourceSpans"`
+	}
+
+	// span is the OTLP JSON encoding of a span.
+	span struct {
+		TraceID           string       `json:"traceId"`
+		SpanID            string       `json:"spanId"`
+		ParentSpanID      string       `json:"parentSpanId"`
+		Name              string       `json:"name"`
+		Kind              SpanKind     `json:"kind"`
+		StartTimeUnixNano json.Number  `json:"startTimeUnixNano"`
+		Attributes        []*attribute `json:"attributes"`
+	}
+
+	// attribute is the OTLP JSON encoding of a key value pair.
+	attribute struct {
+		Key   string `json:"key"`
+		Value struct {
+			StringValue *string      `json:"stringValue"`
+			IntValue    *json.Number `json:"intValue"`
+			DoubleValue *json.Number `json:"doubleValue"`
+			BoolValue   *bool        `json:"boolValue"`
+		} `json:"value"`
+	}
+)
+
+const (
+	// SpanKindUnspecified is the default span kind.
+	SpanKindUnspecified SpanKind = iota
+	// SpanKindInternal indicates an internal operation.
+	SpanKindInternal
+	// SpanKindServer indicates the server side handling of a request.
+	SpanKindServer
+	// SpanKindClient indicates a request to a remote service.
+	SpanKindClient
+	// SpanKindProducer indicates the initiator of an asynchronous request.
+	SpanKindProducer
+	// SpanKindConsumer indicates the receiver of an asynchronous request.
+	SpanKindConsumer
+)
+
+// spanKinds lists the names of the span kinds used in the JSON encoding.
+var spanKinds = map[string]SpanKind{
+	"SPAN_KIND_UNSPECIFIED": SpanKindUnspecified,
+	"SPAN_KIND_INTERNAL":    SpanKindInternal,
+	"SPAN_KIND_SERVER":      SpanKindServer,
+	"SPAN_KIND_CLIENT":      SpanKindClient,
+	"SPAN_KIND_PRODUCER":    SpanKindProducer,
+	"SPAN_KIND_CONSUMER":    SpanKindConsumer,
+}
+
+// Load reads the traces from the OTLP JSON file at the given path. The file
+// may contain multiple export requests, one per line. The traces are sorted
+// by start time.
+func Load(path string) ([]*Trace, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var (
+		traces []*Trace
+		index  = make(map[string]*Trace)
+		dec    = json.NewDecoder(f)
+	)
+	for {
+		var e export
+		if err := dec.Decode(&e); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("%s: %s", path, err)
+		}
+		for _, rs := range e.ResourceSpans {
+			var service string
+			if rs.Resource != nil {
+				service = attributes(rs.Resource.Attributes)["service.name"]
+			}
+			scopes := append(rs.ScopeSpans, rs.InstrumentationLibrarySpans...)
+			for _, ss := range scopes {
+				for _, s := range ss.Spans {
+					t, ok := index[s.TraceID]
+					if !ok {
+						t = &Trace{ID: s.TraceID}
+						index[s.TraceID] = t
+						traces = append(traces, t)
+					}
+					start, _ := strconv.ParseUint(s.StartTimeUnixNano.String(), 10, 64)
+					t.Spans = append(t.Spans, &Span{
+						ID:         s.SpanID,
+						ParentID:   s.ParentSpanID,
+						Name:       s.Name,
+						Kind:       s.Kind,
+						Service:    service,
+						Start:      start,
+						Attributes: attributes(s.Attributes),
+					})
+				}
+			}
+		}
+	}
+	if len(traces) == 0 {
+		return nil, fmt.Errorf("%s: no span found", path)
+	}
+	for _, t := range traces {
+		sort.SliceStable(t.Spans, func(i, j int) bool { return t.Spans[i].Start < t.Spans[j].Start })
+	}
+	sort.SliceStable(traces, func(i, j int) bool { return traces[i].Spans[0].Start < traces[j].Spans[0].Start })
+	return traces, nil
+}
+
+// Root returns the root span of the trace, the first span if the trace has no
+// root span.
+func (t *Trace) Root() *Span {
+	for _, s := range t.Spans {
+		if s.ParentID == "" {
+			return s
+		}
+	}
+	return t.Spans[0]
+}
+
+// UnmarshalJSON reads the span kind encoded as an integer or as a string.
+func (k *SpanKind) UnmarshalJSON(b []byte) error {
+	var name string
+	if err := json.Unmarshal(b, &name); err == nil {
+		kind, ok := spanKinds[name]
+		if !ok {
+			return fmt.Errorf("unknown span kind %q", name)
+		}
+		*k = kind
+		return nil
+	}
+	var i int
+	if err := json.Unmarshal(b, &i); err != nil {
+		return fmt.Errorf("invalid span kind %s", string(b))
+	}
+	*k = SpanKind(i)
+	return nil
+}
+
+// attributes returns the string representation of the given attributes
+// indexed by key.
+func attributes(attrs []*attribute) map[string]string {
+	res := make(map[string]string, len(attrs))
+	for _, a := range attrs {
+		v := a.Value
+		switch {
+		case v.StringValue != nil:
+			res[a.Key] = *v.StringValue
+		case v.IntValue != nil:
+			res[a.Key] = v.IntValue.String()
+		case v.DoubleValue != nil:
+			res[a.Key] = v.DoubleValue.String()
+		case v.BoolValue != nil:
+			res[a.Key] = strconv.FormatBool(*v.BoolValue)
+		}
+	}
+	return res
+}
@@ -0,0 +1,31 @@
+/*
+Package plantuml renders the views of a software architecture design using
+the C4-PlantUML library (https://github.com/plantuml-stdlib/C4-PlantUML).
+
+Render produces one PlantUML document per system landscape, system context,
+container, component, dynamic and deployment view defined in the design.
+Element and relationship tags that have a corresponding style in the design
+are mapped to C4-PlantUML tags so that the colors, shapes and line styles of
+the rendered diagrams match the ones defined in the DSL. The automatic layout
+rank direction of a view (if any) is mapped to the corresponding C4-PlantUML
+layout macro.
+
+C4-PlantUML uses the same library for system landscape and system context
+diagrams so the documents of system context views include a comment that
+records the software system of the view, e.g. "' SystemContextView(eb82y15)".
+
+The documents use the PlantUML standard library include syntax so they can be
+rendered by any PlantUML server without network access.
+
+Load performs the reverse operation: it parses C4-PlantUML diagrams and
+returns the corresponding design. Person, System, Container and Component
+macros (including the Db, Queue and _Ext variants) define elements, Rel macros
+(including the directional, Back, BiRel and RelIndex variants) define
+relationships and System_Boundary and Container_Boundary define the parent
+of the containers and components they enclose. Db and Queue variants are
+tagged "Database" and "Queue" and _Ext variants are tagged "External". Each
+diagram produces a view whose kind depends on the included library, diagrams
+that include C4_Context produce system context views if they contain the
+comment described above and system landscape views otherwise.
+*/
+package plantuml
@@ -0,0 +1,877 @@
+package plantuml
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"goa.design/model/mdl"
+)
+
+type (
+	// parser builds a design from C4-PlantUML diagrams.
+	parser struct {
+		// m is the model being built.
+		m *mdl.Model
+		// elements indexes the elements by alias.
+		elements map[string]interface{}
+		// rels indexes the relationships by source ID, destination ID and
+		// description.
+		rels map[string]*mdl.Relationship
+		// diagrams lists the parsed diagrams.
+		diagrams []*diagram
+		// tags records the tags that require a style.
+		tags map[string]bool
+		// seq is used to generate element IDs.
+		seq int
+	}
+
+	// diagram describes a single @startuml/@enduml block.
+	diagram struct {
+		// file is the path to the file containing the diagram.
+		file string
+		// name is the name given to @startuml if any.
+		name string
+		// title is the diagram title if any.
+		title string
+		// library is the name of the included C4-PlantUML library.
+		library string
+		// layout is the rank direction set with a layout macro if any.
+		layout mdl.RankDirectionKind
+		// elements lists the IDs of the elements declared in the diagram.
+		elements []string
+		// rels lists the relationships declared in the diagram.
+		rels []*rel
+		// systems lists the IDs of the software systems used as boundaries.
+		systems []string
+		// containers lists the IDs of the containers used as boundaries.
+		containers []string
+		// enterprise is true if the diagram defines an enterprise boundary.
+		enterprise bool
+		// context is the alias of the software system of a system context
+		// diagram, see contextRegexp.
+		context string
+		// stack is the stack of boundaries enclosing the current line.
+		stack []*boundary
+		// pending is the boundary whose opening brace has not been read yet.
+		pending *boundary
+		// lines lists the diagram lines indexed by line number.
+		lines map[int]string
+	}
+
+	// boundary is a boundary macro.
+	boundary struct {
+		// kind is "system", "container", "enterprise" or "boundary".
+		kind string
+		// id is the ID of the software system or container.
+		id string
+		// name is the label of a generic boundary, generic boundaries are
+		// imported as element groups.
+		name string
+	}
+
+	// rel is a relationship declared in a diagram, resolved once all the
+	// diagrams have been parsed.
+	rel struct {
+		// line is the line number of the declaration.
+		line int
+		// index is the RelIndex index if any.
+		index string
+		// src and dest are the aliases of the source and destination.
+		src, dest string
+		// args are the remaining arguments.
+		args *arguments
+		// r is the resolved relationship.
+		r *mdl.Relationship
+	}
+
+	// arguments are the arguments of a macro.
+	arguments struct {
+		// positional lists the positional arguments.
+		positional []string
+		// named indexes the named arguments by name without the "$" prefix.
+		named map[string]string
+	}
+)
+
+var (
+	// macroRegexp matches macro calls optionally followed by an opening
+	// brace.
+	macroRegexp = regexp.MustCompile(`^([A-Za-z_]+)\s*\((.*)\)\s*(\{)?\s*$`)
+	// relRegexp matches relationship macro names.
+	relRegexp = regexp.MustCompile(`^(Rel|BiRel|RelIndex)(_Back)?(_(U|Up|D|Down|L|Left|R|Right|Neighbor))?(_Back)?$`)
+	// elementRegexp matches element macro names.
+	elementRegexp = regexp.MustCompile(`^(Person|System|Container|Component)(Db|Queue)?(_Ext)?$`)
+	// contextRegexp matches the comment that Render adds to system context
+	// diagrams to record the alias of the software system.
+	contextRegexp = regexp.MustCompile(`^'\s*SystemContextView\((\w+)\)\s*$`)
+	// extensions lists the extensions of PlantUML files.
+	extensions = map[string]bool{".puml": true, ".plantuml": true, ".iuml": true, ".pu": true, ".wsd": true}
+)
+
+// Load reads the C4-PlantUML diagrams in the file at the given path or in the
+// PlantUML files of the given directory and returns the corresponding design.
+// Elements with the same alias in different diagrams are the same element.
+// Each diagram produces a view whose kind depends on the included
+// C4-PlantUML library. Elements declared in generic Boundary macros belong to
+// the group named after the boundary label.
+func Load(path string) (*mdl.Design, error) {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	var paths []string
+	if fi.IsDir() {
+		err := filepath.Walk(path, func(p string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if !info.IsDir() && extensions[strings.ToLower(filepath.Ext(p))] {
+				paths = append(paths, p)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		if len(paths) == 0 {
+			return nil, fmt.Errorf("no PlantUML file found in %q", path)
+		}
+		sort.Strings(paths)
+	} else {
+		paths = []string{path}
+	}
+	p := &parser{
+		m:        &mdl.Model{},
+		elements: make(map[string]interface{}),
+		rels:     make(map[string]*mdl.Relationship),
+		tags:     make(map[string]bool),
+	}
+	for _, path := range paths {
+		b, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		p.parse(path, string(b))
+	}
+	// Process diagrams from the most abstract to the most detailed so that
+	// containers and components are created in the boundaries that define
+	// them.
+	sort.SliceStable(p.diagrams, func(i, j int) bool {
+		return level(p.diagrams[i].library) < level(p.diagrams[j].library)
+	})
+	for _, d := range p.diagrams {
+		nums := make([]int, 0, len(d.lines))
+		for n := range d.lines {
+			nums = append(nums, n)
+		}
+		sort.Ints(nums)
+		for _, n := range nums {
+			if err := p.parseLine(d, d.lines[n], n); err != nil {
+				return nil, fmt.Errorf("%s:%d: %s", d.file, n, err)
+			}
+		}
+	}
+	if err := p.resolve(); err != nil {
+		return nil, err
+	}
+	name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	return &mdl.Design{Name: name, Model: p.m, Views: p.views()}, nil
+}
+
+// parse splits the given file content into diagrams.
+func (p *parser) parse(file, content string) {
+	var (
+		d         *diagram
+		inComment bool
+	)
+	for i, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if inComment {
+			if idx := strings.Index(line, "'/"); idx >= 0 {
+				inComment = false
+				line = strings.TrimSpace(line[idx+2:])
+			} else {
+				continue
+			}
+		}
+		if strings.HasPrefix(line, "/'") {
+			if idx := strings.Index(line[2:], "'/"); idx >= 0 {
+				line = strings.TrimSpace(line[idx+4:])
+			} else {
+				inComment = true
+				continue
+			}
+		}
+		if m := contextRegexp.FindStringSubmatch(line); m != nil && d != nil {
+			d.context = m[1]
+			continue
+		}
+		if line == "" || strings.HasPrefix(line, "'") {
+			continue
+		}
+		if strings.HasPrefix(line, "@startuml") {
+			d = &diagram{
+				file:  file,
+				name:  strings.TrimSpace(strings.TrimPrefix(line, "@startuml")),
+				lines: make(map[int]string),
+			}
+			continue
+		}
+		if d == nil {
+			continue
+		}
+		if strings.HasPrefix(line, "@enduml") {
+			p.diagrams = append(p.diagrams, d)
+			d = nil
+			continue
+		}
+		if strings.HasPrefix(line, "!include") {
+			for _, lib := range []string{"C4_Dynamic", "C4_Deployment", "C4_Component", "C4_Container", "C4_Context"} {
+				if strings.Contains(line, lib) {
+					d.library = lib
+					break
+				}
+			}
+			continue
+		}
+		d.lines[i+1] = line
+	}
+	if d != nil {
+		// Be lenient with missing @enduml.
+		p.diagrams = append(p.diagrams, d)
+	}
+}
+
+// parseLine parses a single line of a diagram.
+func (p *parser) parseLine(d *diagram, line string, num int) error {
+	switch {
+	case strings.HasPrefix(line, "title "):
+		d.title = strings.TrimSpace(strings.TrimPrefix(line, "title "))
+		return nil
+	case strings.HasPrefix(line, "LAYOUT_LEFT_RIGHT") || strings.HasPrefix(line, "LAYOUT_LANDSCAPE"):
+		d.layout = mdl.RankLeftRight
+		return nil
+	case strings.HasPrefix(line, "LAYOUT_TOP_DOWN"):
+		d.layout = mdl.RankTopBottom
+		return nil
+	case line == "{":
+		if d.pending != nil {
+			d.stack = append(d.stack, d.pending)
+			d.pending = nil
+		}
+		return nil
+	case line == "}":
+		if len(d.stack) > 0 {
+			d.stack = d.stack[:len(d.stack)-1]
+		}
+		return nil
+	}
+	match := macroRegexp.FindStringSubmatch(line)
+	if match == nil {
+		return nil
+	}
+	name, args, open := match[1], parseArgs(match[2]), match[3] != ""
+	if m := elementRegexp.FindStringSubmatch(name); m != nil {
+		return p.element(d, m[1], m[2], m[3] != "", args)
+	}
+	if m := relRegexp.FindStringSubmatch(name); m != nil {
+		back := m[2] != "" || m[5] != ""
+		r := &rel{line: num, args: args}
+		if m[1] == "RelIndex" {
+			if len(args.positional) > 0 {
+				r.index = args.positional[0]
+				args.positional = args.positional[1:]
+			}
+		}
+		if len(args.positional) < 2 {
+			return fmt.Errorf("%s: missing source or destination", name)
+		}
+		r.src, r.dest = args.positional[0], args.positional[1]
+		args.positional = args.positional[2:]
+		if back {
+			r.src, r.dest = r.dest, r.src
+		}
+		d.rels = append(d.rels, r)
+		if m[1] == "BiRel" {
+			d.rels = append(d.rels, &rel{line: num, src: r.dest, dest: r.src, args: args})
+		}
+		return nil
+	}
+	var b *boundary
+	switch name {
+	case "System_Boundary":
+		if len(args.positional) < 1 {
+			return fmt.Errorf("%s: missing alias", name)
+		}
+		s := p.system(args.positional[0], label(args, 1), "", "", false)
+		if s.Group == "" {
+			s.Group = d.group()
+		}
+		d.systems = append(d.systems, s.ID)
+		b = &boundary{kind: "system", id: s.ID}
+	case "Container_Boundary":
+		if len(args.positional) < 1 {
+			return fmt.Errorf("%s: missing alias", name)
+		}
+		c := p.container(d, args.positional[0], label(args, 1), "", "")
+		if c.Group == "" {
+			c.Group = d.group()
+		}
+		d.containers = append(d.containers, c.ID)
+		b = &boundary{kind: "container", id: c.ID}
+	case "Enterprise_Boundary":
+		if p.m.Enterprise == nil {
+			p.m.Enterprise = &mdl.Enterprise{Name: label(args, 1)}
+		}
+		d.enterprise = true
+		b = &boundary{kind: "enterprise"}
+	case "Boundary":
+		b = &boundary{kind: "boundary", name: label(args, 1)}
+	default:
+		return nil
+	}
+	if open {
+		d.stack = append(d.stack, b)
+	} else {
+		d.pending = b
+	}
+	return nil
+}
+
+// element creates or updates the element declared with the given macro.
+func (p *parser) element(d *diagram, kind, variant string, ext bool, args *arguments) error {
+	if len(args.positional) < 1 {
+		return fmt.Errorf("%s: missing alias", kind)
+	}
+	alias := args.positional[0]
+	name := label(args, 1)
+	tags := elementTags(variant, ext, args)
+	for _, t := range tags {
+		p.tags[t] = true
+	}
+	var id string
+	switch kind {
+	case "Person":
+		desc := args.get("descr", 2)
+		pe, ok := p.elements[alias].(*mdl.Person)
+		if !ok {
+			pe = &mdl.Person{
+				ID:          p.newID(),
+				Name:        p.uniqueTopLevelName(name),
+				Description: desc,
+				Tags:        strings.Join(append([]string{"Element", "Person"}, tags...), ","),
+				URL:         args.named["link"],
+				Location:    p.location(d, ext),
+			}
+			p.m.People = append(p.m.People, pe)
+			p.elements[alias] = pe
+		}
+		if pe.Group == "" {
+			pe.Group = d.group()
+		}
+		id = pe.ID
+	case "System":
+		s := p.system(alias, name, args.get("descr", 2), args.named["link"], true)
+		s.Tags = mergeTags(s.Tags, tags)
+		if s.Location == mdl.LocationUndefined {
+			s.Location = p.location(d, ext)
+		}
+		if s.Group == "" {
+			s.Group = d.group()
+		}
+		id = s.ID
+	case "Container":
+		c := p.container(d, alias, name, args.get("techn", 2), args.get("descr", 3))
+		c.Tags = mergeTags(c.Tags, tags)
+		if c.URL == "" {
+			c.URL = args.named["link"]
+		}
+		if c.Group == "" {
+			c.Group = d.group()
+		}
+		id = c.ID
+	case "Component":
+		c := p.component(d, alias, name, args.get("techn", 2), args.get("descr", 3))
+		c.Tags = mergeTags(c.Tags, tags)
+		if c.URL == "" {
+			c.URL = args.named["link"]
+		}
+		if c.Group == "" {
+			c.Group = d.group()
+		}
+		id = c.ID
+	}
+	d.elements = append(d.elements, id)
+	return nil
+}
+
+// system returns the software system with the given alias creating it if
+// needed. declared is true if the system is declared with a System macro (as
+// opposed to a boundary).
+func (p *parser) system(alias, name, desc, url string, declared bool) *mdl.SoftwareSystem {
+	if s, ok := p.elements[alias].(*mdl.SoftwareSystem); ok {
+		if s.Description == "" {
+			s.Description = desc
+		}
+		if s.URL == "" {
+			s.URL = url
+		}
+		return s
+	}
+	s := &mdl.SoftwareSystem{
+		ID:          p.newID(),
+		Name:        p.uniqueTopLevelName(name),
+		Description: desc,
+		Tags:        "Element,Software System",
+		URL:         url,
+	}
+	p.m.Systems = append(p.m.Systems, s)
+	p.elements[alias] = s
+	return s
+}
+
+// container returns the container with the given alias creating it in the
+// enclosing system boundary if needed.
+func (p *parser) container(d *diagram, alias, name, tech, desc string) *mdl.Container {
+	if c, ok := p.elements[alias].(*mdl.Container); ok {
+		if c.Description == "" {
+			c.Description = desc
+		}
+		if c.Technology == "" {
+			c.Technology = tech
+		}
+		return c
+	}
+	s := p.enclosingSystem(d)
+	var names []string
+	for _, c := range s.Containers {
+		names = append(names, c.Name)
+	}
+	c := &mdl.Container{
+		ID:          p.newID(),
+		Name:        unique(names, name),
+		Description: desc,
+		Technology:  tech,
+		Tags:        "Element,Container",
+	}
+	s.Containers = append(s.Containers, c)
+	p.elements[alias] = c
+	return c
+}
+
+// component returns the component with the given alias creating it in the
+// enclosing container boundary if needed.
+func (p *parser) component(d *diagram, alias, name, tech, desc string) *mdl.Component {
+	if c, ok := p.elements[alias].(*mdl.Component); ok {
+		if c.Description == "" {
+			c.Description = desc
+		}
+		if c.Technology == "" {
+			c.Technology = tech
+		}
+		return c
+	}
+	cont := p.enclosingContainer(d)
+	var names []string
+	for _, c := range cont.Components {
+		names = append(names, c.Name)
+	}
+	c := &mdl.Component{
+		ID:          p.newID(),
+		Name:        unique(names, name),
+		Description: desc,
+		Technology:  tech,
+		Tags:        "Element,Component",
+	}
+	cont.Components = append(cont.Components, c)
+	p.elements[alias] = c
+	return c
+}
+
+// enclosingSystem returns the software system of the innermost system
+// boundary enclosing the current line. It returns a software system named
+// after the diagram file if there is none.
+func (p *parser) enclosingSystem(d *diagram) *mdl.SoftwareSystem {
+	for i := len(d.stack) - 1; i >= 0; i-- {
+		if d.stack[i].kind == "system" {
+			return p.m.Element(d.stack[i].id).(*mdl.SoftwareSystem)
+		}
+		if d.stack[i].kind == "container" {
+			return p.m.Parent(d.stack[i].id).(*mdl.SoftwareSystem)
+		}
+	}
+	name := strings.TrimSuffix(filepath.Base(d.file), filepath.Ext(d.file))
+	s := p.system("file:"+d.file, name, "", "", false)
+	d.systems = append(d.systems, s.ID)
+	return s
+}
+
+// enclosingContainer returns the container of the innermost container
+// boundary enclosing the current line. It returns a container named after
+// the diagram file if there is none.
+func (p *parser) enclosingContainer(d *diagram) *mdl.Container {
+	for i := len(d.stack) - 1; i >= 0; i-- {
+		if d.stack[i].kind == "container" {
+			return p.m.Element(d.stack[i].id).(*mdl.Container)
+		}
+	}
+	name := strings.TrimSuffix(filepath.Base(d.file), filepath.Ext(d.file))
+	c := p.container(d, "file:"+d.file+":container", name, "", "")
+	d.containers = append(d.containers, c.ID)
+	return c
+}
+
+// group returns the label of the innermost generic boundary enclosing the
+// current line if it is not nested in a system or container boundary, the
+// empty string otherwise.
+func (d *diagram) group() string {
+	for i := len(d.stack) - 1; i >= 0; i-- {
+		switch d.stack[i].kind {
+		case "boundary":
+			return d.stack[i].name
+		case "system", "container":
+			return ""
+		}
+	}
+	return ""
+}
+
+// location returns the location of a person or software system declared in
+// d.
+func (p *parser) location(d *diagram, ext bool) mdl.LocationKind {
+	if ext {
+		return mdl.LocationExternal
+	}
+	for _, b := range d.stack {
+		if b.kind == "enterprise" {
+			return mdl.LocationInternal
+		}
+	}
+	return mdl.LocationUndefined
+}
+
+// resolve creates the relationships declared in the diagrams.
+func (p *parser) resolve() error {
+	for _, d := range p.diagrams {
+		for _, r := range d.rels {
+			src, dest := idOf(p.elements[r.src]), idOf(p.elements[r.dest])
+			if src == "" {
+				return fmt.Errorf("%s:%d: unknown element %q", d.file, r.line, r.src)
+			}
+			if dest == "" {
+				return fmt.Errorf("%s:%d: unknown element %q", d.file, r.line, r.dest)
+			}
+			desc := r.args.get("label", 0)
+			key := src + ":" + dest + ":" + desc
+			rel, ok := p.rels[key]
+			if !ok {
+				tags := []string{"Relationship"}
+				if t := r.args.named["tags"]; t != "" {
+					tags = append(tags, strings.Split(t, "+")...)
+				}
+				rel = &mdl.Relationship{
+					ID:            p.newID(),
+					Description:   desc,
+					Tags:          strings.Join(tags, ","),
+					URL:           r.args.named["link"],
+					SourceID:      src,
+					DestinationID: dest,
+					Technology:    r.args.get("techn", 1),
+				}
+				p.rels[key] = rel
+				switch e := p.elements[r.src].(type) {
+				case *mdl.Person:
+					e.Relationships = append(e.Relationships, rel)
+				case *mdl.SoftwareSystem:
+					e.Relationships = append(e.Relationships, rel)
+				case *mdl.Container:
+					e.Relationships = append(e.Relationships, rel)
+				case *mdl.Component:
+					e.Relationships = append(e.Relationships, rel)
+				}
+			}
+			r.r = rel
+		}
+	}
+	return nil
+}
+
+// views returns the views corresponding to the diagrams.
+func (p *parser) views() *mdl.Views {
+	var (
+		vs   = &mdl.Views{}
+		keys = make(map[string]bool)
+	)
+	for i, d := range p.diagrams {
+		props := &mdl.ViewProps{Key: p.key(keys, d, i), Title: d.title}
+		if d.layout != mdl.RankUndefined {
+			props.AutoLayout = &mdl.AutoLayout{RankDirection: d.layout}
+		}
+		elements := d.elements
+		if d.library == "C4_Container" {
+			// Container boundaries are rendered as containers.
+			elements = append(append([]string{}, elements...), d.containers...)
+		}
+		in := make(map[string]bool)
+		for _, id := range elements {
+			if !in[id] && allowed(d.library, p.m.Element(id)) {
+				props.ElementViews = append(props.ElementViews, &mdl.ElementView{ID: id})
+				in[id] = true
+			}
+		}
+		for j, r := range d.rels {
+			if !in[r.r.SourceID] || !in[r.r.DestinationID] {
+				continue
+			}
+			rv := &mdl.RelationshipView{ID: r.r.ID}
+			if d.library == "C4_Dynamic" {
+				rv.Order = r.index
+				if rv.Order == "" {
+					rv.Order = strconv.Itoa(j + 1)
+				}
+			}
+			props.RelationshipViews = append(props.RelationshipViews, rv)
+		}
+		switch d.library {
+		case "C4_Deployment":
+			continue
+		case "C4_Dynamic":
+			v := &mdl.DynamicView{ViewProps: props}
+			if len(d.containers) > 0 {
+				v.ElementID = d.containers[0]
+			} else if len(d.systems) > 0 {
+				v.ElementID = d.systems[0]
+			}
+			vs.DynamicViews = append(vs.DynamicViews, v)
+		case "C4_Component":
+			if len(d.containers) == 0 {
+				continue
+			}
+			vs.ComponentViews = append(vs.ComponentViews, &mdl.ComponentView{ViewProps: props, ContainerID: d.containers[0]})
+		case "C4_Container":
+			if len(d.systems) == 0 {
+				continue
+			}
+			vs.ContainerViews = append(vs.ContainerViews, &mdl.ContainerView{ViewProps: props, SoftwareSystemID: d.systems[0]})
+		default:
+			if s, ok := p.elements[d.context].(*mdl.SoftwareSystem); ok {
+				v := &mdl.ContextView{ViewProps: props, SoftwareSystemID: s.ID}
+				if d.enterprise {
+					visible := true
+					v.EnterpriseBoundaryVisible = &visible
+				}
+				vs.ContextViews = append(vs.ContextViews, v)
+				continue
+			}
+			v := &mdl.LandscapeView{ViewProps: props}
+			if d.enterprise {
+				visible := true
+				v.EnterpriseBoundaryVisible = &visible
+			}
+			vs.LandscapeViews = append(vs.LandscapeViews, v)
+		}
+	}
+	var styles []*mdl.ElementStyle
+	if p.tags["Database"] {
+		styles = append(styles, &mdl.ElementStyle{Tag: "Database", Shape: mdl.ShapeCylinder})
+	}
+	if p.tags["Queue"] {
+		styles = append(styles, &mdl.ElementStyle{Tag: "Queue", Shape: mdl.ShapePipe})
+	}
+	if p.tags["External"] {
+		styles = append(styles, &mdl.ElementStyle{Tag: "External", Background: "#999999", Color: "#ffffff"})
+	}
+	if len(styles) > 0 {
+		vs.Styles = &mdl.Styles{Elements: styles}
+	}
+	return vs
+}
+
+// level returns the level of detail of the diagrams including the given
+// library.
+func level(library string) int {
+	switch library {
+	case "C4_Container":
+		return 1
+	case "C4_Component":
+		return 2
+	case "C4_Dynamic", "C4_Deployment":
+		return 3
+	}
+	return 0
+}
+
+// allowed returns true if the element e can be added to the view that
+// corresponds to a diagram including the given library.
+func allowed(library string, e interface{}) bool {
+	switch e.(type) {
+	case *mdl.Person, *mdl.SoftwareSystem:
+		return true
+	case *mdl.Container:
+		return library != "C4_Context" && library != ""
+	case *mdl.Component:
+		return library == "C4_Component" || library == "C4_Dynamic"
+	}
+	return false
+}
+
+// key returns a unique view key for the i-th diagram d.
+func (p *parser) key(keys map[string]bool, d *diagram, i int) string {
+	name := d.name
+	if name == "" {
+		name = strings.TrimSuffix(filepath.Base(d.file), filepath.Ext(d.file))
+	}
+	key := strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' || r == '-' {
+			return r
+		}
+		return '_'
+	}, name)
+	res := key
+	for n := 2; keys[res]; n++ {
+		res = key + strconv.Itoa(n)
+	}
+	keys[res] = true
+	return res
+}
+
+// uniqueTopLevelName returns a name derived from name that is not used by
+// any person or software system.
+func (p *parser) uniqueTopLevelName(name string) string {
+	var names []string
+	for _, pe := range p.m.People {
+		names = append(names, pe.Name)
+	}
+	for _, s := range p.m.Systems {
+		names = append(names, s.Name)
+	}
+	return unique(names, name)
+}
+
+// newID returns a new unique element ID.
+func (p *parser) newID() string {
+	p.seq++
+	return strconv.Itoa(p.seq)
+}
+
+// get returns the named argument with the given name or the positional
+// argument at the given index if there is no such named argument.
+func (a *arguments) get(name string, i int) string {
+	if v, ok := a.named[name]; ok {
+		return v
+	}
+	if i < len(a.positional) {
+		return a.positional[i]
+	}
+	return ""
+}
+
+// parseArgs parses the comma separated arguments of a macro.
+func parseArgs(s string) *arguments {
+	var (
+		res   = &arguments{named: make(map[string]string)}
+		parts []string
+		cur   strings.Builder
+		quote bool
+		depth int
+	)
+	for _, r := range s {
+		switch {
+		case r == '"':
+			quote = !quote
+		case quote:
+		case r == '(':
+			depth++
+		case r == ')':
+			depth--
+		case r == ',' && depth == 0:
+			parts = append(parts, cur.String())
+			cur.Reset()
+			continue
+		}
+		cur.WriteRune(r)
+	}
+	parts = append(parts, cur.String())
+	for _, part := range parts {
+		part = strings.TrimSpace(part)
+		if strings.HasPrefix(part, "$") {
+			if i := strings.Index(part, "="); i > 0 {
+				res.named[strings.TrimSpace(part[1:i])] = unquote(part[i+1:])
+				continue
+			}
+		}
+		res.positional = append(res.positional, unquote(part))
+	}
+	return res
+}
+
+// unquote removes the surrounding quotes of s if any.
+func unquote(s string) string {
+	s = strings.TrimSpace(s)
+	if len(s) >= 2 && s[0] == '"' && s[len(s)-1] == '"' {
+		s = s[1 : len(s)-1]
+	}
+	return strings.ReplaceAll(s, `\n`, " ")
+}
+
+// label returns the label argument at index i (or the named "label" argument)
+// made suitable for use as an element name.
+func label(a *arguments, i int) string {
+	return strings.TrimSpace(strings.ReplaceAll(a.get("label", i), "/", "-"))
+}
+
+// elementTags returns the tags of an element declared with a macro with the
+// given variant (Db or Queue) and external suffix.
+func elementTags(variant string, ext bool, args *arguments) []string {
+	var tags []string
+	switch variant {
+	case "Db":
+		tags = append(tags, "Database")
+	case "Queue":
+		tags = append(tags, "Queue")
+	}
+	if ext {
+		tags = append(tags, "External")
+	}
+	if t := args.named["tags"]; t != "" {
+		tags = append(tags, strings.Split(t, "+")...)
+	}
+	return tags
+}
+
+// mergeTags adds the given tags to the comma separated list tags.
+func mergeTags(tags string, add []string) string {
+	existing := strings.Split(tags, ",")
+	for _, t := range add {
+		found := false
+		for _, e := range existing {
+			if e == t {
+				found = true
+				break
+			}
+		}
+		if !found {
+			existing = append(existing, t)
+		}
+	}
+	return strings.Join(existing, ",")
+}
+
+// unique returns a name derived from name that is not in names.
+func unique(names []string, name string) string {
+	taken := make(map[string]bool, len(names))
+	for _, n := range names {
+		taken[n] = true
+	}
+	res := name
+	for i := 2; taken[res]; i++ {
+		res = fmt.Sprintf("%s %d", name, i)
+	}
+	return res
+}
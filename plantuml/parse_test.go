@@ -0,0 +1,142 @@
+package plantuml
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"goa.design/model/internal/testutil"
+	"goa.design/model/mdl"
+)
+
+func TestLoadRoundTrip(t *testing.T) {
+	dir, err := ioutil.TempDir("", "plantuml")
+	if err != nil {
+		t.Fatalf("failed to create temporary directory: %s", err)
+	}
+	defer os.RemoveAll(dir)
+	for key, src := range Render(testutil.RunDSL(t, shop)) {
+		if err := ioutil.WriteFile(filepath.Join(dir, key+".puml"), []byte(src), 0644); err != nil {
+			t.Fatalf("failed to write %s: %s", key, err)
+		}
+	}
+	d, err := Load(dir)
+	if err != nil {
+		t.Fatalf("failed to load diagrams: %s", err)
+	}
+	m := d.Model
+	shop := findSystem(t, m, "Shop")
+
+	t.Run("views", func(t *testing.T) {
+		vs := d.Views
+		if len(vs.LandscapeViews) != 1 || vs.LandscapeViews[0].Key != "landscape" {
+			t.Errorf("got landscape views %v, want landscape", vs.LandscapeViews)
+		}
+		if len(vs.ContextViews) != 1 || vs.ContextViews[0].Key != "context" || vs.ContextViews[0].SoftwareSystemID != shop.ID {
+			t.Errorf("got context views %v, want context view of Shop", vs.ContextViews)
+		}
+		if len(vs.ContainerViews) != 1 || vs.ContainerViews[0].Key != "containers" || vs.ContainerViews[0].SoftwareSystemID != shop.ID {
+			t.Errorf("got container views %v, want container view of Shop", vs.ContainerViews)
+		}
+		if len(vs.DynamicViews) != 1 || vs.DynamicViews[0].Key != "checkout" {
+			t.Errorf("got dynamic views %v, want checkout", vs.DynamicViews)
+		}
+		if l := vs.ContextViews[0].AutoLayout; l == nil || l.RankDirection != mdl.RankLeftRight {
+			t.Errorf("got context view layout %v, want left right", l)
+		}
+	})
+
+	t.Run("elements", func(t *testing.T) {
+		if len(m.People) != 2 {
+			t.Fatalf("got %d people, want 2", len(m.People))
+		}
+		for _, p := range m.People {
+			if p.Name == "Customer" && (p.Description != "Buys things. Pays with cards." || p.Location != mdl.LocationExternal) {
+				t.Errorf("got Customer description %q and location %v, want description on one line and external location", p.Description, p.Location)
+			}
+		}
+		payments := findSystem(t, m, "Payments")
+		if payments.Location != mdl.LocationExternal || !hasTag(payments.Tags, "External") {
+			t.Errorf("got Payments location %v and tags %q, want external", payments.Location, payments.Tags)
+		}
+		tests := []struct {
+			name, tech, desc, group string
+			tag                     string
+		}{
+			{"Web", "Go", "Serves the storefront.", "", ""},
+			{"Worker", "", "Processes orders.", "Backend", "Worker"},
+			{"Database", "PostgreSQL", "Stores orders.", "Backend", "Database"},
+			{"Queue", "RabbitMQ", "Queues orders.", "", "Queue"},
+		}
+		if len(shop.Containers) != len(tests) {
+			t.Errorf("got %d containers, want %d", len(shop.Containers), len(tests))
+		}
+		for _, tt := range tests {
+			c := findContainer(t, shop, tt.name)
+			if c.Technology != tt.tech || c.Description != tt.desc || c.Group != tt.group {
+				t.Errorf("%s: got technology %q, description %q and group %q", tt.name, c.Technology, c.Description, c.Group)
+			}
+			if tt.tag != "" && !hasTag(c.Tags, tt.tag) {
+				t.Errorf("%s: got tags %q, want %q", tt.name, c.Tags, tt.tag)
+			}
+		}
+	})
+
+	t.Run("relationships", func(t *testing.T) {
+		worker := findContainer(t, shop, "Worker")
+		if len(worker.Relationships) != 2 {
+			t.Fatalf("got %d Worker relationships, want 2", len(worker.Relationships))
+		}
+		for _, r := range worker.Relationships {
+			if r.Description == "Reads orders" && (r.Technology != "" || r.DestinationID != findContainer(t, shop, "Database").ID) {
+				t.Errorf("got relationship %q [%q] to %q", r.Description, r.Technology, r.DestinationID)
+			}
+		}
+		web := findContainer(t, shop, "Web")
+		var found bool
+		for _, r := range web.Relationships {
+			if r.Description == "Charges cards" && r.Technology == "HTTPS" {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("relationship from Web to Payments not imported")
+		}
+	})
+}
+
+// findSystem returns the software system with the given name.
+func findSystem(t *testing.T, m *mdl.Model, name string) *mdl.SoftwareSystem {
+	t.Helper()
+	for _, s := range m.Systems {
+		if s.Name == name {
+			return s
+		}
+	}
+	t.Fatalf("software system %q not found", name)
+	return nil
+}
+
+// findContainer returns the container of s with the given name.
+func findContainer(t *testing.T, s *mdl.SoftwareSystem, name string) *mdl.Container {
+	t.Helper()
+	for _, c := range s.Containers {
+		if c.Name == name {
+			return c
+		}
+	}
+	t.Fatalf("container %q not found", name)
+	return nil
+}
+
+// hasTag returns true if the comma separated list of tags contains tag.
+func hasTag(tags, tag string) bool {
+	for _, t := range strings.Split(tags, ",") {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,578 @@
+package plantuml
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+
+	"goa.design/model/mdl"
+)
+
+type (
+	// renderer renders a single view.
+	renderer struct {
+		design *mdl.Design
+		view   mdl.View
+		buf    *bytes.Buffer
+		// inView indexes the IDs of the elements present in the view.
+		inView map[string]bool
+		// rendered records the elements already rendered.
+		rendered map[string]bool
+		// groups counts the group boundaries rendered so far.
+		groups int
+	}
+)
+
+// Render renders all the views of the given design. It returns the PlantUML
+// source of each view indexed by view key.
+func Render(d *mdl.Design) map[string]string {
+	res := make(map[string]string)
+	if d.Views == nil {
+		return res
+	}
+	for _, v := range d.Views.All() {
+		res[v.Props().Key] = RenderView(d, v)
+	}
+	return res
+}
+
+// RenderView renders the given view of the given design and returns the
+// corresponding PlantUML source.
+func RenderView(d *mdl.Design, v mdl.View) string {
+	r := &renderer{
+		design:   d,
+		view:     v,
+		buf:      &bytes.Buffer{},
+		inView:   make(map[string]bool),
+		rendered: make(map[string]bool),
+	}
+	for _, ev := range v.Props().ElementViews {
+		r.inView[ev.ID] = true
+	}
+	r.render()
+	return r.buf.String()
+}
+
+func (r *renderer) render() {
+	props := r.view.Props()
+	r.line("@startuml %s", props.Key)
+	r.line("!include <C4/%s>", library(r.view))
+	if v, ok := r.view.(*mdl.ContextView); ok {
+		// C4-PlantUML uses the same library for system landscape and system
+		// context diagrams, record the software system so that Load can
+		// tell them apart.
+		r.line("' SystemContextView(%s)", alias(v.SoftwareSystemID))
+	}
+	if l := props.AutoLayout; l != nil {
+		switch l.RankDirection {
+		case mdl.RankLeftRight, mdl.RankRightLeft:
+			r.line("LAYOUT_LEFT_RIGHT()")
+		case mdl.RankTopBottom, mdl.RankBottomTop:
+			r.line("LAYOUT_TOP_DOWN()")
+		}
+	}
+	if props.Title != "" {
+		r.line("title %s", props.Title)
+	} else if props.Description != "" {
+		r.line("title %s", props.Description)
+	}
+	r.line("")
+	r.renderTags()
+	switch v := r.view.(type) {
+	case *mdl.LandscapeView:
+		r.renderEnterprise(v.EnterpriseBoundaryVisible)
+	case *mdl.ContextView:
+		r.renderEnterprise(v.EnterpriseBoundaryVisible)
+	case *mdl.ContainerView:
+		r.renderScoped(v.SoftwareSystemID, v.SystemBoundariesVisible)
+	case *mdl.ComponentView:
+		r.renderScoped(v.ContainerID, v.ContainerBoundariesVisible)
+	case *mdl.CodeView:
+		r.renderScoped(v.ComponentID, nil)
+	case *mdl.DynamicView:
+		r.renderScoped(v.ElementID, nil)
+	case *mdl.DeploymentView:
+		r.renderDeployment()
+	}
+	r.line("")
+	r.renderRelationships()
+	r.line("@enduml")
+}
+
+// renderTags renders the AddElementTag and AddRelTag statements for all the
+// tags that have a style and that are used by elements or relationships in
+// the view. Tags whose style has no C4-PlantUML equivalent (for example a
+// style that only sets a cylinder shape, rendered with the Db macros) are
+// omitted.
+func (r *renderer) renderTags() {
+	styles := r.design.Views.Styles
+	if styles == nil {
+		return
+	}
+	used := make(map[string]bool)
+	for _, ev := range r.view.Props().ElementViews {
+		e := r.design.Model.Element(ev.ID)
+		if ci, ok := e.(*mdl.ContainerInstance); ok {
+			// Container instances are rendered with the container tags.
+			e = r.design.Model.Element(ci.ContainerID)
+		}
+		for _, t := range strings.Split(tagsOf(e), ",") {
+			used[t] = true
+		}
+	}
+	for _, es := range styles.Elements {
+		if !used[es.Tag] {
+			continue
+		}
+		if args := elementTagArgs(es); len(args) > 0 {
+			r.line("AddElementTag(%s)", strings.Join(append([]string{quote(es.Tag)}, args...), ", "))
+		}
+	}
+	used = make(map[string]bool)
+	for _, rv := range r.view.Props().RelationshipViews {
+		if rel := r.design.Model.Relationship(rv.ID); rel != nil {
+			for _, t := range strings.Split(rel.Tags, ",") {
+				used[t] = true
+			}
+		}
+	}
+	for _, rs := range styles.Relationships {
+		if !used[rs.Tag] {
+			continue
+		}
+		if args := relTagArgs(rs); len(args) > 0 {
+			r.line("AddRelTag(%s)", strings.Join(append([]string{quote(rs.Tag)}, args...), ", "))
+		}
+	}
+	r.line("")
+}
+
+// renderEnterprise renders the elements of a system landscape or system
+// context view, grouping internal elements in an enterprise boundary if
+// requested.
+func (r *renderer) renderEnterprise(visible *bool) {
+	ent := r.design.Model.Enterprise
+	if visible == nil || !*visible || ent == nil {
+		r.renderAll()
+		return
+	}
+	r.line("Enterprise_Boundary(enterprise, %s) {", quote(ent.Name))
+	var ids []string
+	for _, ev := range r.view.Props().ElementViews {
+		switch e := r.design.Model.Element(ev.ID).(type) {
+		case *mdl.Person:
+			if e.Location != mdl.LocationExternal {
+				ids = append(ids, e.ID)
+			}
+		case *mdl.SoftwareSystem:
+			if e.Location != mdl.LocationExternal {
+				ids = append(ids, e.ID)
+			}
+		}
+	}
+	r.renderGrouped(ids, "    ")
+	r.line("}")
+	r.renderAll()
+}
+
+// renderScoped renders the elements of a view scoped to the element with the
+// given ID. Elements that are children of the scope are rendered in a
+// boundary. Other elements are rendered in the boundary of their parent if
+// boundaries is true.
+func (r *renderer) renderScoped(scopeID string, boundaries *bool) {
+	m := r.design.Model
+	groups := make(map[string][]string)
+	var parents []string
+	for _, ev := range r.view.Props().ElementViews {
+		p := m.Parent(ev.ID)
+		if p == nil {
+			continue
+		}
+		pid := idOf(p)
+		if pid != scopeID && (boundaries == nil || !*boundaries) {
+			continue
+		}
+		if _, ok := groups[pid]; !ok {
+			parents = append(parents, pid)
+		}
+		groups[pid] = append(groups[pid], ev.ID)
+	}
+	sort.Strings(parents)
+	for _, pid := range parents {
+		// Elements rendered as boundaries cannot be rendered as elements as
+		// well: PlantUML requires unique aliases.
+		r.rendered[pid] = true
+	}
+	for _, pid := range parents {
+		macro, typ := "System_Boundary", ""
+		switch m.Element(pid).(type) {
+		case *mdl.Container:
+			macro = "Container_Boundary"
+		case *mdl.Component:
+			macro, typ = "Boundary", quote("Component")
+		}
+		r.line("%s(%s) {", macro, args(alias(pid), quote(nameOf(m.Element(pid))), typ))
+		r.renderGrouped(groups[pid], "    ")
+		r.line("}")
+	}
+	r.renderAll()
+}
+
+// renderDeployment renders the deployment nodes of a deployment view and the
+// infrastructure nodes and container instances they contain.
+func (r *renderer) renderDeployment() {
+	for _, ev := range r.view.Props().ElementViews {
+		if n, ok := r.design.Model.Element(ev.ID).(*mdl.DeploymentNode); ok {
+			if r.design.Model.Parent(n.ID) == nil {
+				r.renderNode(n, "")
+			}
+		}
+	}
+	r.renderAll()
+}
+
+func (r *renderer) renderNode(n *mdl.DeploymentNode, indent string) {
+	r.rendered[n.ID] = true
+	label := n.Name
+	if n.Instances != nil && *n.Instances > 1 {
+		label = fmt.Sprintf("%s (x%d)", n.Name, *n.Instances)
+	}
+	r.line("%sDeployment_Node(%s) {", indent, args(alias(n.ID), quote(label), quote(n.Technology), quote(n.Description), tagsArg(r.styledTags(n.Tags))))
+	for _, c := range n.Children {
+		if r.inView[c.ID] {
+			r.renderNode(c, indent+"    ")
+		}
+	}
+	for _, inf := range n.InfrastructureNodes {
+		if r.inView[inf.ID] {
+			r.renderElement(inf, indent+"    ")
+		}
+	}
+	for _, ci := range n.ContainerInstances {
+		if r.inView[ci.ID] {
+			r.renderElement(ci, indent+"    ")
+		}
+	}
+	r.line("%s}", indent)
+}
+
+// renderAll renders all the elements in the view that haven't been rendered
+// yet.
+func (r *renderer) renderAll() {
+	var ids []string
+	for _, ev := range r.view.Props().ElementViews {
+		if !r.rendered[ev.ID] && r.design.Model.Element(ev.ID) != nil {
+			ids = append(ids, ev.ID)
+		}
+	}
+	r.renderGrouped(ids, "")
+}
+
+// renderGrouped renders the elements with the given IDs. Elements that belong
+// to a group are rendered in a boundary, elements with the same group name but
+// different parents belong to different groups.
+func (r *renderer) renderGrouped(ids []string, indent string) {
+	m := r.design.Model
+	groups := make(map[string][]string)
+	var keys, names []string
+	for _, id := range ids {
+		g := m.Group(id)
+		if g == "" {
+			r.renderElement(m.Element(id), indent)
+			continue
+		}
+		key := idOf(m.Parent(id)) + "/" + g
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
+			names = append(names, g)
+		}
+		groups[key] = append(groups[key], id)
+	}
+	for i, key := range keys {
+		r.groups++
+		r.line("%sBoundary(%s) {", indent, args(fmt.Sprintf("group%d", r.groups), quote(names[i]), quote("Group")))
+		for _, id := range groups[key] {
+			r.renderElement(m.Element(id), indent+"    ")
+		}
+		r.line("%s}", indent)
+	}
+}
+
+func (r *renderer) renderElement(e interface{}, indent string) {
+	id := idOf(e)
+	if r.rendered[id] {
+		return
+	}
+	r.rendered[id] = true
+	var styles *mdl.Styles
+	if r.design.Views != nil {
+		styles = r.design.Views.Styles
+	}
+	suffix := func(tags string) string {
+		if es := styles.ElementStyle(tags); es != nil {
+			switch es.Shape {
+			case mdl.ShapeCylinder:
+				return "Db"
+			case mdl.ShapePipe:
+				return "Queue"
+			}
+		}
+		return ""
+	}
+	ext := func(loc mdl.LocationKind) string {
+		if loc == mdl.LocationExternal {
+			return "_Ext"
+		}
+		return ""
+	}
+	var stmt string
+	switch a := e.(type) {
+	case *mdl.Person:
+		stmt = "Person" + ext(a.Location) + "(" + args(alias(a.ID), quote(a.Name), quote(a.Description), tagsArg(r.styledTags(a.Tags))) + ")"
+	case *mdl.SoftwareSystem:
+		stmt = "System" + suffix(a.Tags) + ext(a.Location) + "(" + args(alias(a.ID), quote(a.Name), quote(a.Description), tagsArg(r.styledTags(a.Tags))) + ")"
+	case *mdl.Container:
+		var loc mdl.LocationKind
+		if s, ok := r.design.Model.Parent(a.ID).(*mdl.SoftwareSystem); ok {
+			loc = s.Location
+		}
+		stmt = "Container" + suffix(a.Tags) + ext(loc) + "(" + args(alias(a.ID), quote(a.Name), quote(a.Technology), quote(a.Description), tagsArg(r.styledTags(a.Tags))) + ")"
+	case *mdl.Component:
+		stmt = "Component" + suffix(a.Tags) + "(" + args(alias(a.ID), quote(a.Name), quote(a.Technology), quote(a.Description), tagsArg(r.styledTags(a.Tags))) + ")"
+	case *mdl.CodeElement:
+		// C4-PlantUML does not define macros for code elements.
+		stmt = "Component(" + args(alias(a.ID), quote(a.Name), quote(a.Technology), quote(a.Description), tagsArg(r.styledTags(a.Tags))) + ")"
+	case *mdl.InfrastructureNode:
+		stmt = "Node(" + args(alias(a.ID), quote(a.Name), quote(a.Technology), quote(a.Description), tagsArg(r.styledTags(a.Tags))) + ")"
+	case *mdl.ContainerInstance:
+		c, ok := r.design.Model.Element(a.ContainerID).(*mdl.Container)
+		if !ok {
+			return
+		}
+		stmt = "Container" + suffix(c.Tags) + "(" + args(alias(a.ID), quote(c.Name), quote(c.Technology), quote(c.Description), tagsArg(r.styledTags(c.Tags))) + ")"
+	case *mdl.DeploymentNode:
+		r.renderNode(a, indent)
+		return
+	default:
+		return
+	}
+	r.line("%s%s", indent, stmt)
+}
+
+func (r *renderer) renderRelationships() {
+	_, dynamic := r.view.(*mdl.DynamicView)
+	for _, rv := range r.view.Props().RelationshipViews {
+		rel := r.design.Model.Relationship(rv.ID)
+		if rel == nil {
+			continue
+		}
+		desc := rel.Description
+		if rv.Description != "" {
+			desc = rv.Description
+		}
+		a := args(alias(rel.SourceID), alias(rel.DestinationID), quote(desc), quote(rel.Technology), tagsArg(r.styledRelTags(rel.Tags)))
+		if dynamic && rv.Order != "" {
+			r.line("RelIndex(%s, %s)", quote(rv.Order), a)
+			continue
+		}
+		r.line("Rel(%s)", a)
+	}
+}
+
+// elementTagArgs returns the AddElementTag named arguments corresponding to
+// the given element style.
+func elementTagArgs(es *mdl.ElementStyle) []string {
+	var args []string
+	if es.Background != "" {
+		args = append(args, "$bgColor="+quote(es.Background))
+	}
+	if es.Color != "" {
+		args = append(args, "$fontColor="+quote(es.Color))
+	}
+	if es.Stroke != "" {
+		args = append(args, "$borderColor="+quote(es.Stroke))
+	}
+	switch es.Shape {
+	case mdl.ShapeRoundedBox:
+		args = append(args, "$shape=RoundedBoxShape()")
+	case mdl.ShapeHexagon:
+		args = append(args, "$shape=EightSidedShape()")
+	}
+	return args
+}
+
+// relTagArgs returns the AddRelTag named arguments corresponding to the given
+// relationship style.
+func relTagArgs(rs *mdl.RelationshipStyle) []string {
+	var args []string
+	if rs.Color != "" {
+		args = append(args, "$textColor="+quote(rs.Color), "$lineColor="+quote(rs.Color))
+	}
+	if rs.Dashed != nil && *rs.Dashed {
+		args = append(args, "$lineStyle=DashedLine()")
+	}
+	return args
+}
+
+// styledTags returns the subset of the given comma separated tags that have a
+// corresponding element style rendered with AddElementTag.
+func (r *renderer) styledTags(tags string) []string {
+	if r.design.Views == nil || r.design.Views.Styles == nil {
+		return nil
+	}
+	var res []string
+	for _, t := range strings.Split(tags, ",") {
+		for _, es := range r.design.Views.Styles.Elements {
+			if es.Tag == t && len(elementTagArgs(es)) > 0 {
+				res = append(res, t)
+				break
+			}
+		}
+	}
+	return res
+}
+
+// styledRelTags returns the subset of the given comma separated tags that have
+// a corresponding relationship style rendered with AddRelTag.
+func (r *renderer) styledRelTags(tags string) []string {
+	if r.design.Views == nil || r.design.Views.Styles == nil {
+		return nil
+	}
+	var res []string
+	for _, t := range strings.Split(tags, ",") {
+		for _, rs := range r.design.Views.Styles.Relationships {
+			if rs.Tag == t && len(relTagArgs(rs)) > 0 {
+				res = append(res, t)
+				break
+			}
+		}
+	}
+	return res
+}
+
+func (r *renderer) line(format string, a ...interface{}) {
+	fmt.Fprintf(r.buf, format+"\n", a...)
+}
+
+// library returns the name of the C4-PlantUML library that defines the macros
+// used to render the given view.
+func library(v mdl.View) string {
+	switch v.(type) {
+	case *mdl.ContainerView:
+		return "C4_Container"
+	case *mdl.ComponentView, *mdl.CodeView:
+		return "C4_Component"
+	case *mdl.DynamicView:
+		return "C4_Dynamic"
+	case *mdl.DeploymentView:
+		return "C4_Deployment"
+	default:
+		return "C4_Context"
+	}
+}
+
+// alias returns a valid PlantUML identifier for the element with the given
+// ID.
+func alias(id string) string {
+	return "e" + id
+}
+
+// quote returns a PlantUML string literal for s.
+func quote(s string) string {
+	s = strings.ReplaceAll(s, `"`, `'`)
+	s = strings.ReplaceAll(s, "\n", `\n`)
+	return `"` + s + `"`
+}
+
+// args joins the non empty arguments with commas. Empty string literals that
+// are not followed by a non empty positional argument are omitted as well so
+// that macros use their default values. Named arguments (e.g. "$tags=...")
+// do not need the preceding positional arguments.
+func args(as ...string) string {
+	last := -1
+	for i, a := range as {
+		if a != "" && a != `""` && !strings.HasPrefix(a, "$") {
+			last = i
+		}
+	}
+	var res []string
+	for i, a := range as {
+		if a == "" || i > last && a == `""` {
+			continue
+		}
+		res = append(res, a)
+	}
+	return strings.Join(res, ", ")
+}
+
+func tagsArg(tags []string) string {
+	if len(tags) == 0 {
+		return ""
+	}
+	return "$tags=" + quote(strings.Join(tags, "+"))
+}
+
+func idOf(e interface{}) string {
+	switch a := e.(type) {
+	case *mdl.Person:
+		return a.ID
+	case *mdl.SoftwareSystem:
+		return a.ID
+	case *mdl.Container:
+		return a.ID
+	case *mdl.Component:
+		return a.ID
+	case *mdl.CodeElement:
+		return a.ID
+	case *mdl.DeploymentNode:
+		return a.ID
+	case *mdl.InfrastructureNode:
+		return a.ID
+	case *mdl.ContainerInstance:
+		return a.ID
+	}
+	return ""
+}
+
+func nameOf(e interface{}) string {
+	switch a := e.(type) {
+	case *mdl.Person:
+		return a.Name
+	case *mdl.SoftwareSystem:
+		return a.Name
+	case *mdl.Container:
+		return a.Name
+	case *mdl.Component:
+		return a.Name
+	case *mdl.CodeElement:
+		return a.Name
+	case *mdl.DeploymentNode:
+		return a.Name
+	case *mdl.InfrastructureNode:
+		return a.Name
+	}
+	return ""
+}
+
+func tagsOf(e interface{}) string {
+	switch a := e.(type) {
+	case *mdl.Person:
+		return a.Tags
+	case *mdl.SoftwareSystem:
+		return a.Tags
+	case *mdl.Container:
+		return a.Tags
+	case *mdl.Component:
+		return a.Tags
+	case *mdl.CodeElement:
+		return a.Tags
+	case *mdl.DeploymentNode:
+		return a.Tags
+	case *mdl.InfrastructureNode:
+		return a.Tags
+	case *mdl.ContainerInstance:
+		return a.Tags
+	}
+	return ""
+}
@@ -0,0 +1,169 @@
+package plantuml
+
+import (
+	"strings"
+	"testing"
+
+	. "goa.design/model/dsl"
+	"goa.design/model/internal/testutil"
+)
+
+// shop defines the design used to test the PlantUML renderer and loader.
+func shop() {
+	Design("Shop", "An online shop.", func() {
+		Enterprise("Acme")
+		var Payments = SoftwareSystem("Payments", `Processes "card" payments.`, func() {
+			External()
+			Tag("External")
+		})
+		var Shop = SoftwareSystem("Shop", "Sells things.", func() {
+			Container("Web", "Serves the storefront.", "Go", func() {
+				Uses("Database", "Reads and writes", "SQL")
+				Uses("Queue", "Publishes orders", "AMQP", Asynchronous)
+				Uses(Payments, "Charges cards", "HTTPS", Asynchronous)
+				Component("Cart", "Manages shopping carts.", "Go", func() {
+					Uses("Shop/Database", "Stores carts", "SQL")
+				})
+			})
+			Group("Backend", func() {
+				Container("Worker", "Processes orders.", func() {
+					Tag("Worker")
+					Uses("Database", "Reads orders")
+					Uses("Queue", "Consumes orders", Asynchronous)
+				})
+				Container("Database", "Stores orders.", "PostgreSQL", func() {
+					Tag("Database")
+				})
+			})
+			Container("Queue", "Queues orders.", "RabbitMQ", func() {
+				Tag("Queue")
+			})
+		})
+		Person("Customer", "Buys things.\nPays with cards.", func() {
+			External()
+			Uses(Shop, "Browses and buys")
+			Uses("Shop/Web", "Visits", "HTTPS")
+		})
+		Person("Clerk", "Ships orders.", func() {
+			Uses("Shop/Worker", "Monitors")
+		})
+		DeploymentEnvironment("Production", func() {
+			DeploymentNode("Cloud", "", "AWS", func() {
+				InfrastructureNode("Load Balancer", "Routes traffic.", "ELB")
+				DeploymentNode("Cluster", "", "Kubernetes", func() {
+					Instances(3)
+					ContainerInstance("Shop/Web")
+				})
+				DeploymentNode("RDS", "", "Amazon RDS", func() {
+					ContainerInstance("Shop/Database")
+				})
+			})
+		})
+		Views(func() {
+			SystemLandscapeView("landscape", "Acme systems.", func() {
+				AddAll()
+				EnterpriseBoundaryVisible()
+				AutoLayout(RankTopBottom)
+			})
+			SystemContextView(Shop, "context", "Shop context.", func() {
+				AddAll()
+				AutoLayout(RankLeftRight)
+			})
+			ContainerView(Shop, "containers", "Shop containers.", func() {
+				Title("Shop containers")
+				AddAll()
+			})
+			ComponentView("Shop/Web", "components", "Web components.", func() {
+				AddAll()
+				ContainerBoundariesVisible()
+			})
+			DynamicView(Shop, "checkout", "Checkout.", func() {
+				Link("Customer", "Shop/Web", "Visits")
+				Link("Shop/Web", "Shop/Database", "Reads and writes")
+			})
+			DeploymentView(Shop, "Production", "production", "Production deployment.", func() {
+				AddAll()
+			})
+			Styles(func() {
+				ElementStyle("Database", func() {
+					Shape(ShapeCylinder)
+					Background("#438dd5")
+				})
+				ElementStyle("Queue", func() {
+					Shape(ShapePipe)
+				})
+				ElementStyle("External", func() {
+					Background("#999999")
+					Color("#ffffff")
+					Stroke("#666666")
+				})
+				ElementStyle("Worker", func() {
+					Shape(ShapeRoundedBox)
+					Border(BorderDashed)
+				})
+				RelationshipStyle("Asynchronous", func() {
+					Dashed()
+					Color("#ff0000")
+				})
+			})
+		})
+	})
+}
+
+func TestRender(t *testing.T) {
+	views := Render(testutil.RunDSL(t, shop))
+	keys := []string{"checkout", "components", "containers", "context", "landscape", "production"}
+	if len(views) != len(keys) {
+		t.Errorf("got %d views, want %d", len(views), len(keys))
+	}
+	for _, key := range keys {
+		key := key
+		t.Run(key, func(t *testing.T) {
+			src, ok := views[key]
+			if !ok {
+				t.Fatalf("view %q not rendered", key)
+			}
+			testutil.AssertGolden(t, key+".puml", src)
+		})
+	}
+}
+
+func TestRenderEdgeCases(t *testing.T) {
+	views := Render(testutil.RunDSL(t, shop))
+	tests := []struct {
+		name, view, want string
+		absent           bool
+	}{
+		{"enterprise boundary", "landscape", `Enterprise_Boundary(enterprise, "Acme") {`, false},
+		{"external person", "landscape", `Person_Ext(e`, false},
+		{"top down layout", "landscape", "LAYOUT_TOP_DOWN()", false},
+		{"context view comment", "context", "' SystemContextView(eb82y15)", false},
+		{"external system style tags", "context", `AddElementTag("External", $bgColor="#999999", $fontColor="#ffffff", $borderColor="#666666")`, false},
+		{"quotes in description", "context", `"Processes 'card' payments."`, false},
+		{"newline in description", "context", `"Buys things.\nPays with cards."`, false},
+		{"title over description", "containers", "title Shop containers\n", false},
+		{"group boundary", "containers", `Boundary(group1, "Backend", "Group") {`, false},
+		{"empty technology", "containers", `Container(er9fi1w, "Worker", "", "Processes orders.", $tags="Worker")`, false},
+		{"relationship without technology", "containers", `Rel(er9fi1w, e1oxurfb, "Reads orders")`, false},
+		{"cylinder shape", "containers", `ContainerDb(e1oxurfb, "Database", "PostgreSQL", "Stores orders.", $tags="Database")`, false},
+		{"pipe shape", "containers", `ContainerQueue(e1aa6cn1, "Queue", "RabbitMQ", "Queues orders.")`, false},
+		{"shape only style", "containers", `AddElementTag("Queue"`, true},
+		{"relationship style", "containers", `AddRelTag("Asynchronous", $textColor="#ff0000", $lineColor="#ff0000", $lineStyle=DashedLine())`, false},
+		{"container boundary", "components", "Container_Boundary(eaj7u30", false},
+		{"boundary not rendered as element", "components", "Container(eaj7u30,", true},
+		{"dynamic library", "checkout", "!include <C4/C4_Dynamic>", false},
+		{"instances label", "production", `"Cluster (x3)"`, false},
+		{"infrastructure node", "production", `Node(e`, false},
+		{"container instance macro", "production", `ContainerDb(e1f547w3, "Database", "PostgreSQL", "Stores orders.", $tags="Database")`, false},
+		{"container instance tags", "production", `AddElementTag("Database", $bgColor="#438dd5")`, false},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			got := views[tt.view]
+			if strings.Contains(got, tt.want) == tt.absent {
+				t.Errorf("%s view: got contains %q = %v, want %v:\n%s", tt.view, tt.want, tt.absent, !tt.absent, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,239 @@
+package scan
+
+import (
+	"strings"
+
+	"goa.design/model/mdl"
+)
+
+// Design returns a design that describes the software system and container
+// with the given names whose components are the given packages.
+func Design(system, container string, pkgs []*Package) *mdl.Design {
+	d := &mdl.Design{Name: system, Model: &mdl.Model{}, Views: &mdl.Views{}}
+	Merge(d, system, container, pkgs)
+	return d
+}
+
+// Merge adds the components corresponding to the given packages and the
+// relationships corresponding to their imports to the container with the
+// given name of the software system with the given name in d. The software
+// system and container are created if they do not exist. Merge also adds the
+// new elements and relationships to the component views of the container or
+// creates a component view if there is none.
+func Merge(d *mdl.Design, system, container string, pkgs []*Package) {
+	sys := findSystem(d.Model, system)
+	if sys == nil {
+		sys = &mdl.SoftwareSystem{
+			ID:   "scan:" + system,
+			Name: system,
+			Tags: "Element,Software System",
+		}
+		d.Model.Systems = append(d.Model.Systems, sys)
+	}
+	cont := findContainer(sys, container)
+	if cont == nil {
+		cont = &mdl.Container{
+			ID:         "scan:" + system + "/" + container,
+			Name:       container,
+			Technology: "Go",
+			Tags:       "Element,Container",
+		}
+		sys.Containers = append(sys.Containers, cont)
+	}
+
+	var (
+		components = make(map[string]*mdl.Component)
+		added      []string
+	)
+	for _, p := range pkgs {
+		c := findComponent(cont, p)
+		if c == nil {
+			c = &mdl.Component{
+				ID:          "scan:" + p.Path,
+				Name:        p.Name,
+				Description: p.Doc,
+				Technology:  "Go",
+				Tags:        "Element,Component",
+				Properties:  map[string]string{"package": p.Path},
+			}
+			cont.Components = append(cont.Components, c)
+			added = append(added, c.ID)
+		}
+		components[p.Path] = c
+	}
+	for _, p := range pkgs {
+		src := components[p.Path]
+	imports:
+		for _, imp := range p.Imports {
+			dest := components[imp]
+			for _, r := range src.Relationships {
+				if r.DestinationID == dest.ID {
+					continue imports
+				}
+			}
+			r := &mdl.Relationship{
+				ID:            "scan:" + p.Path + "->" + imp,
+				Description:   "Uses",
+				Tags:          "Relationship",
+				SourceID:      src.ID,
+				DestinationID: dest.ID,
+				Technology:    "Go",
+			}
+			src.Relationships = append(src.Relationships, r)
+			added = append(added, r.ID)
+		}
+	}
+
+	var views []*mdl.ComponentView
+	for _, v := range d.Views.ComponentViews {
+		if v.ContainerID == cont.ID {
+			views = append(views, v)
+		}
+	}
+	if len(views) == 0 {
+		v := &mdl.ComponentView{
+			ViewProps: &mdl.ViewProps{
+				Key:         strings.Join(strings.Fields(container), "") + "Components",
+				Description: "Packages of the " + container + " container.",
+			},
+			ContainerID: cont.ID,
+		}
+		for _, c := range cont.Components {
+			v.ElementViews = append(v.ElementViews, &mdl.ElementView{ID: c.ID})
+		}
+		d.Views.ComponentViews = append(d.Views.ComponentViews, v)
+		views = []*mdl.ComponentView{v}
+	}
+	for _, v := range views {
+		addToView(d.Model, v.ViewProps, added)
+	}
+}
+
+// Extension returns a design that only describes the components
+// corresponding to the given packages and the relationships corresponding to
+// their imports. The components belong to the container with the given name
+// of the software system with the given name. The DSL generated from the
+// returned design extends d: the model DSL merges software systems,
+// containers and components that have the same names. Components that
+// correspond to existing components of d (see Merge) use the same names and
+// relationships that already exist in d are omitted. The returned design
+// includes a component view only if d does not define one for the container.
+func Extension(d *mdl.Design, system, container string, pkgs []*Package) *mdl.Design {
+	ext := Design(system, container, pkgs)
+	ext.Name = d.Name
+	sys := findSystem(d.Model, system)
+	if sys == nil {
+		return ext
+	}
+	cont := findContainer(sys, container)
+	if cont == nil {
+		return ext
+	}
+	ext.Model.Systems[0].Tags = ""
+	ec := ext.Model.Systems[0].Containers[0]
+	ec.Technology = ""
+	ec.Tags = ""
+
+	existing := make(map[string]*mdl.Component)
+	for _, p := range pkgs {
+		if c := findComponent(cont, p); c != nil {
+			existing["scan:"+p.Path] = c
+		}
+	}
+	for _, c := range ec.Components {
+		e, ok := existing[c.ID]
+		if !ok {
+			continue
+		}
+		c.Name = e.Name
+		var rels []*mdl.Relationship
+	rels:
+		for _, r := range c.Relationships {
+			if dest, ok := existing[r.DestinationID]; ok {
+				for _, er := range e.Relationships {
+					if er.DestinationID == dest.ID {
+						continue rels
+					}
+				}
+			}
+			rels = append(rels, r)
+		}
+		c.Relationships = rels
+	}
+	if d.Views != nil {
+		for _, v := range d.Views.ComponentViews {
+			if v.ContainerID == cont.ID {
+				ext.Views.ComponentViews = nil
+				break
+			}
+		}
+	}
+	for _, v := range ext.Views.ComponentViews {
+		var rvs []*mdl.RelationshipView
+		for _, rv := range v.RelationshipViews {
+			if ext.Model.Relationship(rv.ID) != nil {
+				rvs = append(rvs, rv)
+			}
+		}
+		v.RelationshipViews = rvs
+	}
+	return ext
+}
+
+// addToView adds the elements and relationships with the given IDs to the
+// view. Relationships are added only if both their source and destination
+// are in the view.
+func addToView(m *mdl.Model, v *mdl.ViewProps, ids []string) {
+	in := make(map[string]bool)
+	for _, ev := range v.ElementViews {
+		in[ev.ID] = true
+	}
+	for _, id := range ids {
+		if m.Element(id) != nil && !in[id] {
+			v.ElementViews = append(v.ElementViews, &mdl.ElementView{ID: id})
+			in[id] = true
+		}
+	}
+	for _, id := range ids {
+		if r := m.Relationship(id); r != nil && in[r.SourceID] && in[r.DestinationID] {
+			v.RelationshipViews = append(v.RelationshipViews, &mdl.RelationshipView{ID: id})
+		}
+	}
+}
+
+// findSystem returns the software system with the given name if any.
+func findSystem(m *mdl.Model, name string) *mdl.SoftwareSystem {
+	for _, s := range m.Systems {
+		if s.Name == name {
+			return s
+		}
+	}
+	return nil
+}
+
+// findContainer returns the container of s with the given name if any.
+func findContainer(s *mdl.SoftwareSystem, name string) *mdl.Container {
+	for _, c := range s.Containers {
+		if c.Name == name {
+			return c
+		}
+	}
+	return nil
+}
+
+// findComponent returns the component of c that corresponds to p: the
+// component whose "package" property is the import path of p or else the
+// component with the same name.
+func findComponent(c *mdl.Container, p *Package) *mdl.Component {
+	for _, cmp := range c.Components {
+		if cmp.Properties["package"] == p.Path {
+			return cmp
+		}
+	}
+	for _, cmp := range c.Components {
+		if cmp.Name == p.Name {
+			return cmp
+		}
+	}
+	return nil
+}
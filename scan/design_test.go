@@ -0,0 +1,128 @@
+package scan
+
+import (
+	"testing"
+
+	"goa.design/model/mdl"
+)
+
+// testPackages returns the packages used by the design tests: api imports
+// store and util, store imports util.
+func testPackages() []*Package {
+	return []*Package{
+		{Path: "app/api", Name: "api", Doc: "HTTP API.", Imports: []string{"app/store", "app/util"}},
+		{Path: "app/store", Name: "store", Doc: "Data store.", Imports: []string{"app/util"}},
+		{Path: "app/util", Name: "util"},
+	}
+}
+
+func TestDesign(t *testing.T) {
+	d := Design("Shop", "Backend", testPackages())
+	if len(d.Model.Systems) != 1 || len(d.Model.Systems[0].Containers) != 1 {
+		t.Fatalf("got model %+v, want one system with one container", d.Model)
+	}
+	c := d.Model.Systems[0].Containers[0]
+	if c.Name != "Backend" || len(c.Components) != 3 {
+		t.Fatalf("got container %q with %d components, want Backend with 3", c.Name, len(c.Components))
+	}
+	api := c.Components[0]
+	if api.Name != "api" || api.Description != "HTTP API." || api.Properties["package"] != "app/api" {
+		t.Errorf("got component %+v", api)
+	}
+	if len(api.Relationships) != 2 {
+		t.Errorf("got %d relationships for api, want 2", len(api.Relationships))
+	}
+	if len(d.Views.ComponentViews) != 1 {
+		t.Fatalf("got %d component views, want 1", len(d.Views.ComponentViews))
+	}
+	v := d.Views.ComponentViews[0]
+	if v.Key != "BackendComponents" || len(v.ElementViews) != 3 || len(v.RelationshipViews) != 3 {
+		t.Errorf("got view %q with %d elements and %d relationships, want BackendComponents with 3 and 3", v.Key, len(v.ElementViews), len(v.RelationshipViews))
+	}
+}
+
+func TestMerge(t *testing.T) {
+	d := existing()
+	Merge(d, "Shop", "Backend", testPackages())
+	c := d.Model.Systems[0].Containers[0]
+	if len(c.Components) != 3 {
+		t.Fatalf("got %d components, want 3", len(c.Components))
+	}
+	if c.Components[0].Name != "API" || c.Components[0].Description != "Hand written." {
+		t.Errorf("existing component was modified: %+v", c.Components[0])
+	}
+	if n := len(c.Components[0].Relationships); n != 2 {
+		t.Errorf("got %d relationships for API, want 2", n)
+	}
+	// Only the new util component is added to the existing view.
+	v := d.Views.ComponentViews[0]
+	if len(d.Views.ComponentViews) != 1 || len(v.ElementViews) != 2 || v.ElementViews[1].ID != "scan:app/util" {
+		t.Errorf("got %d component views, first with elements %+v, want 1 with api and util", len(d.Views.ComponentViews), v.ElementViews)
+	}
+}
+
+func TestExtension(t *testing.T) {
+	d := existing()
+	ext := Extension(d, "Shop", "Backend", testPackages())
+	if ext.Name != "Existing" {
+		t.Errorf("got design name %q, want %q", ext.Name, "Existing")
+	}
+	sys := ext.Model.Systems[0]
+	c := sys.Containers[0]
+	if sys.Tags != "" || c.Technology != "" || c.Tags != "" {
+		t.Errorf("got system tags %q, container technology %q and tags %q, want existing values to be kept", sys.Tags, c.Technology, c.Tags)
+	}
+	if len(c.Components) != 3 {
+		t.Fatalf("got %d components, want 3", len(c.Components))
+	}
+	api := c.Components[0]
+	if api.Name != "API" {
+		t.Errorf("got name %q for component of existing package, want %q", api.Name, "API")
+	}
+	if len(api.Relationships) != 1 || api.Relationships[0].DestinationID != "scan:app/util" {
+		t.Errorf("got relationships %+v, want only the relationship to util", api.Relationships)
+	}
+	if len(ext.Views.ComponentViews) != 0 {
+		t.Errorf("got %d component views, want none as the design defines one", len(ext.Views.ComponentViews))
+	}
+
+	ext = Extension(&mdl.Design{Name: "New", Model: &mdl.Model{}}, "Shop", "Backend", testPackages())
+	if len(ext.Views.ComponentViews) != 1 {
+		t.Errorf("got %d component views, want 1", len(ext.Views.ComponentViews))
+	}
+	if tech := ext.Model.Systems[0].Containers[0].Technology; tech != "Go" {
+		t.Errorf("got container technology %q, want Go", tech)
+	}
+}
+
+// existing returns a design whose Backend container contains the component
+// API that corresponds to the app/api package and uses the store component.
+func existing() *mdl.Design {
+	api := &mdl.Component{
+		ID:          "api",
+		Name:        "API",
+		Description: "Hand written.",
+		Properties:  map[string]string{"package": "app/api"},
+		Relationships: []*mdl.Relationship{
+			{ID: "api-store", SourceID: "api", DestinationID: "store", Description: "Reads"},
+		},
+	}
+	store := &mdl.Component{ID: "store", Name: "store"}
+	return &mdl.Design{
+		Name: "Existing",
+		Model: &mdl.Model{Systems: []*mdl.SoftwareSystem{{
+			ID:   "shop",
+			Name: "Shop",
+			Containers: []*mdl.Container{{
+				ID:         "backend",
+				Name:       "Backend",
+				Technology: "Java",
+				Components: []*mdl.Component{api, store},
+			}},
+		}}},
+		Views: &mdl.Views{ComponentViews: []*mdl.ComponentView{{
+			ViewProps:   &mdl.ViewProps{Key: "backend", ElementViews: []*mdl.ElementView{{ID: "api"}}},
+			ContainerID: "backend",
+		}}},
+	}
+}
@@ -0,0 +1,23 @@
+/*
+Package scan builds the components of a container from the packages of a Go
+module.
+
+Load uses golang.org/x/tools/go/packages to load the packages matching the
+given patterns and their imports. Design, Merge and Extension map the
+packages to components of a container:
+
+	Package                       -> Component
+	Package documentation         -> Description of component
+	Import of another package     -> "Uses" relationship between components
+
+Only the imports of packages that are part of the loaded set produce
+relationships. Merge adds the components and relationships to an existing
+design: components are matched using their "package" property and existing
+elements are left unchanged. Design creates a new design that contains the
+software system, the container, the components and a component view.
+Extension creates a design that only contains the components and their
+relationships so that the corresponding DSL can be evaluated together with
+the DSL of an existing design, the model DSL merges elements with the same
+names.
+*/
+package scan
@@ -0,0 +1,104 @@
+package scan
+
+import (
+	"fmt"
+	"go/doc"
+	"go/parser"
+	"go/token"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// Package is a Go package mapped to a component.
+type Package struct {
+	// Path is the package import path.
+	Path string
+	// Name is the name of the component, the last element of the import path
+	// unless it is not unique.
+	Name string
+	// Doc is the synopsis of the package documentation if any.
+	Doc string
+	// Imports lists the import paths of the loaded packages imported by the
+	// package sorted alphabetically.
+	Imports []string
+}
+
+// Load loads the Go packages matching the given patterns relative to the
+// directory dir. Packages whose import path matches exclude are ignored if
+// exclude is not nil.
+func Load(dir string, exclude *regexp.Regexp, patterns ...string) ([]*Package, error) {
+	cfg := &packages.Config{
+		Mode: packages.NeedName | packages.NeedFiles | packages.NeedImports | packages.NeedModule,
+		Dir:  dir,
+	}
+	pkgs, err := packages.Load(cfg, patterns...)
+	if err != nil {
+		return nil, err
+	}
+	selected := make(map[string]*packages.Package)
+	for _, p := range pkgs {
+		if len(p.Errors) > 0 {
+			return nil, fmt.Errorf("%s: %s", p.PkgPath, p.Errors[0])
+		}
+		if exclude != nil && exclude.MatchString(p.PkgPath) {
+			continue
+		}
+		selected[p.PkgPath] = p
+	}
+	if len(selected) == 0 {
+		return nil, fmt.Errorf("no package matching %s", strings.Join(patterns, " "))
+	}
+
+	paths := make([]string, 0, len(selected))
+	for p := range selected {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	names := make(map[string]int)
+	for _, p := range paths {
+		names[path.Base(p)]++
+	}
+	res := make([]*Package, len(paths))
+	for i, p := range paths {
+		pkg := selected[p]
+		name := path.Base(p)
+		if names[name] > 1 {
+			name = strings.ReplaceAll(relPath(pkg), "/", "-")
+		}
+		var imports []string
+		for imp := range pkg.Imports {
+			if _, ok := selected[imp]; ok && imp != p {
+				imports = append(imports, imp)
+			}
+		}
+		sort.Strings(imports)
+		res[i] = &Package{Path: p, Name: name, Doc: synopsis(pkg.GoFiles), Imports: imports}
+	}
+	return res, nil
+}
+
+// relPath returns the import path of p relative to its module path.
+func relPath(p *packages.Package) string {
+	if p.Module == nil || p.Module.Path == p.PkgPath {
+		return p.PkgPath
+	}
+	return strings.TrimPrefix(p.PkgPath, p.Module.Path+"/")
+}
+
+// synopsis returns the first sentence of the package documentation found in
+// the given files.
+func synopsis(files []string) string {
+	fset := token.NewFileSet()
+	for _, f := range files {
+		file, err := parser.ParseFile(fset, f, nil, parser.PackageClauseOnly|parser.ParseComments)
+		if err != nil || file.Doc == nil {
+			continue
+		}
+		return doc.Synopsis(file.Doc.Text())
+	}
+	return ""
+}
@@ -0,0 +1,78 @@
+package scan
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+)
+
+const app = "goa.design/model/scan/testdata/app/"
+
+func TestLoad(t *testing.T) {
+	pkgs, err := Load("testdata", nil, "./app/...")
+	if err != nil {
+		t.Fatalf("failed to load packages: %s", err)
+	}
+	cases := []struct {
+		Path    string
+		Name    string
+		Doc     string
+		Imports []string
+	}{
+		{app + "api", "api", "Package api exposes the HTTP API.", []string{app + "internal/util", app + "store"}},
+		{app + "internal/util", "scan-testdata-app-internal-util", "", nil},
+		{app + "store", "store", "Package store persists the data.", []string{app + "store/util"}},
+		{app + "store/util", "scan-testdata-app-store-util", "Package util provides store helpers.", nil},
+	}
+	if len(pkgs) != len(cases) {
+		t.Fatalf("got %d packages, want %d", len(pkgs), len(cases))
+	}
+	for i, c := range cases {
+		p := pkgs[i]
+		if p.Path != c.Path {
+			t.Errorf("got package %d path %q, want %q", i, p.Path, c.Path)
+			continue
+		}
+		if p.Name != c.Name {
+			t.Errorf("%s: got name %q, want %q", c.Path, p.Name, c.Name)
+		}
+		if p.Doc != c.Doc {
+			t.Errorf("%s: got doc %q, want %q", c.Path, p.Doc, c.Doc)
+		}
+		if strings.Join(p.Imports, ",") != strings.Join(c.Imports, ",") {
+			t.Errorf("%s: got imports %v, want %v", c.Path, p.Imports, c.Imports)
+		}
+	}
+}
+
+func TestLoadPatterns(t *testing.T) {
+	pkgs, err := Load("testdata", nil, "./app/api", "./app/store")
+	if err != nil {
+		t.Fatalf("failed to load packages: %s", err)
+	}
+	if len(pkgs) != 2 {
+		t.Fatalf("got %d packages, want 2", len(pkgs))
+	}
+	if imps := pkgs[0].Imports; len(imps) != 1 || imps[0] != app+"store" {
+		t.Errorf("got imports %v, want only the imports of loaded packages", imps)
+	}
+}
+
+func TestLoadExclude(t *testing.T) {
+	pkgs, err := Load("testdata", regexp.MustCompile("/internal/|/util$"), "./app/...")
+	if err != nil {
+		t.Fatalf("failed to load packages: %s", err)
+	}
+	var names []string
+	for _, p := range pkgs {
+		names = append(names, p.Name)
+	}
+	if got := strings.Join(names, ","); got != "api,store" {
+		t.Errorf("got packages %s, want api,store", got)
+	}
+
+	_, err = Load("testdata", regexp.MustCompile("app"), "./app/...")
+	if err == nil || !strings.Contains(err.Error(), "no package matching ./app/...") {
+		t.Errorf("got error %v, want no package matching error", err)
+	}
+}
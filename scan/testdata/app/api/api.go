@@ -0,0 +1,7 @@
+// Package api exposes the HTTP API.
+package api
+
+import (
+	_ "goa.design/model/scan/testdata/app/internal/util"
+	_ "goa.design/model/scan/testdata/app/store"
+)
@@ -0,0 +1,4 @@
+// Package store persists the data. It uses a database.
+package store
+
+import _ "goa.design/model/scan/testdata/app/store/util"
@@ -0,0 +1,2 @@
+// Package util provides store helpers.
+package util
@@ -0,0 +1,567 @@
+package stz
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"goa.design/model/mdl"
+)
+
+type (
+	// dslWriter writes the Structurizr DSL representation of a workspace.
+	dslWriter struct {
+		w      *Workspace
+		buf    *bytes.Buffer
+		indent string
+	}
+
+	// groupedElement is an element written by write that belongs to group.
+	groupedElement struct {
+		group string
+		write func()
+	}
+)
+
+// defaultTags lists the tags automatically added by Structurizr, they are
+// omitted from the DSL representation.
+var defaultTags = map[string]bool{
+	"Element":             true,
+	"Person":              true,
+	"Software System":     true,
+	"Container":           true,
+	"Component":           true,
+	"Deployment Node":     true,
+	"Infrastructure Node": true,
+	"Container Instance":  true,
+	"Relationship":        true,
+}
+
+// DSL returns the Structurizr DSL representation of the workspace (see
+// https://github.com/structurizr/dsl). Elements and relationships are
+// identified using their IDs prefixed with "e" and "r" respectively.
+func (w *Workspace) DSL() string {
+	d := &dslWriter{w: w, buf: &bytes.Buffer{}}
+	d.block(strings.TrimSpace("workspace "+args(w.Name, w.Description)), func() {
+		if w.Model != nil {
+			d.block("model", d.writeModel)
+		}
+		if w.Views != nil {
+			d.block("views", d.writeViews)
+		}
+	})
+	return d.buf.String()
+}
+
+func (d *dslWriter) writeModel() {
+	m := d.w.Model
+	d.line("!impliedRelationships false")
+	internal := func() {
+		var elems []groupedElement
+		for _, p := range m.People {
+			if m.Enterprise == nil || p.Location != mdl.LocationExternal {
+				p := p
+				elems = append(elems, groupedElement{p.Group, func() { d.writePerson(p) }})
+			}
+		}
+		for _, s := range m.Systems {
+			if m.Enterprise == nil || s.Location != mdl.LocationExternal {
+				s := s
+				elems = append(elems, groupedElement{s.Group, func() { d.writeSystem(s) }})
+			}
+		}
+		d.writeGrouped(elems)
+	}
+	if m.Enterprise != nil {
+		d.block("enterprise "+quote(m.Enterprise.Name), internal)
+		var elems []groupedElement
+		for _, p := range m.People {
+			if p.Location == mdl.LocationExternal {
+				p := p
+				elems = append(elems, groupedElement{p.Group, func() { d.writePerson(p) }})
+			}
+		}
+		for _, s := range m.Systems {
+			if s.Location == mdl.LocationExternal {
+				s := s
+				elems = append(elems, groupedElement{s.Group, func() { d.writeSystem(s) }})
+			}
+		}
+		d.writeGrouped(elems)
+	} else {
+		internal()
+	}
+	for _, env := range environments(m.DeploymentNodes) {
+		d.block("deploymentEnvironment "+quote(env), func() {
+			for _, n := range m.DeploymentNodes {
+				if n.Environment == env {
+					d.writeDeploymentNode(n)
+				}
+			}
+		})
+	}
+	m.IterateRelationships(func(r *mdl.Relationship) {
+		if r.LinkedRelationshipID != "" {
+			// Relationships between container instances are created
+			// automatically by Structurizr.
+			return
+		}
+		stmt := fmt.Sprintf("r%s = e%s -> e%s", r.ID, r.SourceID, r.DestinationID)
+		if a := args(r.Description, r.Technology); a != "" {
+			stmt += " " + a
+		}
+		d.elementBlock(stmt, relationshipTags(r), r.URL, r.Properties, r.Perspectives, nil)
+	})
+}
+
+func (d *dslWriter) writePerson(p *mdl.Person) {
+	stmt := fmt.Sprintf("e%s = person %s", p.ID, args(p.Name, p.Description))
+	d.elementBlock(stmt, p.Tags, p.URL, p.Properties, p.Perspectives, nil)
+}
+
+func (d *dslWriter) writeSystem(s *mdl.SoftwareSystem) {
+	stmt := fmt.Sprintf("e%s = softwareSystem %s", s.ID, args(s.Name, s.Description))
+	var containers func()
+	if len(s.Containers) > 0 {
+		containers = func() {
+			elems := make([]groupedElement, len(s.Containers))
+			for i, c := range s.Containers {
+				c := c
+				elems[i] = groupedElement{c.Group, func() { d.writeContainer(c) }}
+			}
+			d.writeGrouped(elems)
+		}
+	}
+	d.elementBlock(stmt, s.Tags, s.URL, s.Properties, s.Perspectives, containers)
+}
+
+func (d *dslWriter) writeContainer(c *mdl.Container) {
+	stmt := fmt.Sprintf("e%s = container %s", c.ID, args(c.Name, c.Description, c.Technology))
+	var components func()
+	if len(c.Components) > 0 {
+		components = func() {
+			elems := make([]groupedElement, len(c.Components))
+			for i, cmp := range c.Components {
+				cmp := cmp
+				elems[i] = groupedElement{cmp.Group, func() {
+					stmt := fmt.Sprintf("e%s = component %s", cmp.ID, args(cmp.Name, cmp.Description, cmp.Technology))
+					d.elementBlock(stmt, cmp.Tags, cmp.URL, cmp.Properties, cmp.Perspectives, nil)
+				}}
+			}
+			d.writeGrouped(elems)
+		}
+	}
+	d.elementBlock(stmt, c.Tags, c.URL, c.Properties, c.Perspectives, components)
+}
+
+func (d *dslWriter) writeDeploymentNode(n *mdl.DeploymentNode) {
+	stmt := fmt.Sprintf("e%s = deploymentNode %s", n.ID, args(n.Name, n.Description, n.Technology))
+	d.elementBlock(stmt, n.Tags, n.URL, n.Properties, n.Perspectives, func() {
+		if n.Instances != nil && *n.Instances != 1 {
+			d.line("instances %d", *n.Instances)
+		}
+		for _, c := range n.Children {
+			d.writeDeploymentNode(c)
+		}
+		for _, inf := range n.InfrastructureNodes {
+			stmt := fmt.Sprintf("e%s = infrastructureNode %s", inf.ID, args(inf.Name, inf.Description, inf.Technology))
+			d.elementBlock(stmt, inf.Tags, inf.URL, inf.Properties, inf.Perspectives, nil)
+		}
+		for _, ci := range n.ContainerInstances {
+			stmt := fmt.Sprintf("e%s = containerInstance e%s", ci.ID, ci.ContainerID)
+			var checks func()
+			if len(ci.HealthChecks) > 0 {
+				checks = func() {
+					for _, hc := range ci.HealthChecks {
+						d.line("healthCheck %s %d %d", args(hc.Name, hc.URL), hc.Interval, hc.Timeout)
+					}
+				}
+			}
+			d.elementBlock(stmt, ci.Tags, ci.URL, ci.Properties, ci.Perspectives, checks)
+		}
+	})
+}
+
+// writeGrouped writes the given elements. Elements that belong to a group are
+// written in a "group" block, groups are written in order of first appearance
+// after the elements that do not belong to any group.
+func (d *dslWriter) writeGrouped(elems []groupedElement) {
+	var groups []string
+	seen := make(map[string]bool)
+	for _, e := range elems {
+		if e.group == "" {
+			e.write()
+			continue
+		}
+		if !seen[e.group] {
+			seen[e.group] = true
+			groups = append(groups, e.group)
+		}
+	}
+	for _, g := range groups {
+		g := g
+		d.block("group "+quote(g), func() {
+			for _, e := range elems {
+				if e.group == g {
+					e.write()
+				}
+			}
+		})
+	}
+}
+
+// elementBlock writes the given statement followed by a block containing the
+// tags, URL, properties and perspectives if any and the content written by the
+// given function.
+func (d *dslWriter) elementBlock(stmt, tags, url string, props map[string]string, persps []*mdl.Perspective, fn func()) {
+	tags = customTags(tags)
+	if tags == "" && url == "" && len(props) == 0 && len(persps) == 0 && fn == nil {
+		d.line("%s", stmt)
+		return
+	}
+	d.block(stmt, func() {
+		if tags != "" {
+			d.line("tags %s", quote(tags))
+		}
+		if url != "" {
+			d.line("url %s", url)
+		}
+		if len(props) > 0 {
+			d.block("properties", func() {
+				keys := make([]string, 0, len(props))
+				for k := range props {
+					keys = append(keys, k)
+				}
+				sort.Strings(keys)
+				for _, k := range keys {
+					d.line("%s %s", quote(k), quote(props[k]))
+				}
+			})
+		}
+		if len(persps) > 0 {
+			d.block("perspectives", func() {
+				for _, p := range persps {
+					d.line("%s %s", quote(p.Name), quote(p.Description))
+				}
+			})
+		}
+		if fn != nil {
+			fn()
+		}
+	})
+}
+
+func (d *dslWriter) writeViews() {
+	v := d.w.Views
+	for _, lv := range v.LandscapeViews {
+		d.writeView("systemLandscape", "", lv.ViewProps, lv.EnterpriseBoundaryVisible)
+	}
+	for _, cv := range v.ContextViews {
+		d.writeView("systemContext", "e"+cv.SoftwareSystemID, cv.ViewProps, cv.EnterpriseBoundaryVisible)
+	}
+	for _, cv := range v.ContainerViews {
+		d.writeView("container", "e"+cv.SoftwareSystemID, cv.ViewProps, nil)
+	}
+	for _, cv := range v.ComponentViews {
+		d.writeView("component", "e"+cv.ContainerID, cv.ViewProps, nil)
+	}
+	for _, dv := range v.DynamicViews {
+		scope := "*"
+		if dv.ElementID != "" {
+			scope = "e" + dv.ElementID
+		}
+		d.writeView("dynamic", scope, dv.ViewProps, nil)
+	}
+	for _, dv := range v.DeploymentViews {
+		scope := "*"
+		if dv.SoftwareSystemID != "" {
+			scope = "e" + dv.SoftwareSystemID
+		}
+		d.writeView("deployment", scope+" "+quote(dv.Environment), dv.ViewProps, nil)
+	}
+	for _, fv := range v.FilteredViews {
+		d.line("filtered %s %s %s %s", quote(fv.BaseKey), strings.ToLower(fv.Mode),
+			quote(strings.Join(fv.Tags, ",")), args(fv.Key, fv.Description))
+	}
+	if c := v.Configuration; c != nil {
+		d.writeConfiguration(c)
+	}
+}
+
+func (d *dslWriter) writeView(kind, scope string, props *mdl.ViewProps, enterpriseBoundary *bool) {
+	stmt := kind
+	if scope != "" {
+		stmt += " " + scope
+	}
+	stmt += " " + args(props.Key, props.Description)
+	d.block(stmt, func() {
+		if props.Title != "" {
+			d.line("title %s", quote(props.Title))
+		}
+		if kind == "dynamic" {
+			rvs := make([]*mdl.RelationshipView, len(props.RelationshipViews))
+			copy(rvs, props.RelationshipViews)
+			sort.SliceStable(rvs, func(i, j int) bool { return lessOrder(rvs[i].Order, rvs[j].Order) })
+			for _, rv := range rvs {
+				r := d.w.Model.Relationship(rv.ID)
+				if r == nil {
+					continue
+				}
+				desc := rv.Description
+				if desc == "" {
+					desc = r.Description
+				}
+				d.line("e%s -> e%s %s", r.SourceID, r.DestinationID, quote(desc))
+			}
+		} else {
+			if len(props.ElementViews) > 0 {
+				ids := make([]string, len(props.ElementViews))
+				for i, ev := range props.ElementViews {
+					ids[i] = "e" + ev.ID
+				}
+				d.line("include %s", strings.Join(ids, " "))
+			}
+			if len(props.RelationshipViews) > 0 {
+				ids := make([]string, 0, len(props.RelationshipViews))
+				for _, rv := range props.RelationshipViews {
+					if r := d.w.Model.Relationship(rv.ID); r != nil && r.LinkedRelationshipID == "" {
+						ids = append(ids, "r"+rv.ID)
+					}
+				}
+				if len(ids) > 0 {
+					d.line("include %s", strings.Join(ids, " "))
+				}
+			}
+			for _, step := range props.Animations {
+				if len(step.Elements) == 0 {
+					continue
+				}
+				var ids []string
+				seen := make(map[string]bool)
+				for _, id := range step.Elements {
+					if !seen[id] {
+						seen[id] = true
+						ids = append(ids, "e"+id)
+					}
+				}
+				d.line("animation { %s }", strings.Join(ids, " "))
+			}
+		}
+		if enterpriseBoundary != nil && !*enterpriseBoundary {
+			d.line("enterpriseBoundary false")
+		}
+		if l := props.AutoLayout; l != nil {
+			stmt := "autoLayout"
+			switch l.RankDirection {
+			case mdl.RankTopBottom:
+				stmt += " tb"
+			case mdl.RankBottomTop:
+				stmt += " bt"
+			case mdl.RankLeftRight:
+				stmt += " lr"
+			case mdl.RankRightLeft:
+				stmt += " rl"
+			}
+			if l.RankSep != nil {
+				if l.RankDirection == mdl.RankUndefined {
+					stmt += " tb"
+				}
+				stmt += fmt.Sprintf(" %d", *l.RankSep)
+				if l.NodeSep != nil {
+					stmt += fmt.Sprintf(" %d", *l.NodeSep)
+				}
+			}
+			d.line("%s", stmt)
+		}
+	})
+}
+
+func (d *dslWriter) writeConfiguration(c *Configuration) {
+	if s := c.Styles; s != nil && (len(s.Elements) > 0 || len(s.Relationships) > 0) {
+		d.block("styles", func() {
+			for _, es := range s.Elements {
+				d.block("element "+quote(es.Tag), func() {
+					if es.Shape != mdl.ShapeUndefined {
+						d.line("shape %s", enum(es.Shape))
+					}
+					if es.Icon != "" {
+						d.line("icon %s", quote(es.Icon))
+					}
+					d.optInt("width", es.Width)
+					d.optInt("height", es.Height)
+					if es.Background != "" {
+						d.line("background %s", es.Background)
+					}
+					if es.Color != "" {
+						d.line("color %s", es.Color)
+					}
+					if es.Stroke != "" {
+						d.line("stroke %s", es.Stroke)
+					}
+					d.optInt("fontSize", es.FontSize)
+					if es.Border != mdl.BorderUndefined {
+						d.line("border %s", strings.ToLower(enum(es.Border)))
+					}
+					d.optInt("opacity", es.Opacity)
+					d.optBool("metadata", es.Metadata)
+					d.optBool("description", es.Description)
+				})
+			}
+			for _, rs := range s.Relationships {
+				d.block("relationship "+quote(rs.Tag), func() {
+					d.optInt("thickness", rs.Thickness)
+					if rs.Color != "" {
+						d.line("color %s", rs.Color)
+					}
+					d.optBool("dashed", rs.Dashed)
+					if rs.Routing != mdl.RoutingUndefined {
+						d.line("routing %s", enum(rs.Routing))
+					}
+					d.optInt("fontSize", rs.FontSize)
+					d.optInt("width", rs.Width)
+					d.optInt("position", rs.Position)
+					d.optInt("opacity", rs.Opacity)
+				})
+			}
+		})
+	}
+	for _, t := range c.Themes {
+		d.line("theme %s", t)
+	}
+	if b := c.Branding; b != nil {
+		d.block("branding", func() {
+			if b.Logo != "" {
+				d.line("logo %s", quote(b.Logo))
+			}
+			if f := b.Font; f != nil {
+				d.line("font %s", args(f.Name, f.URL))
+			}
+		})
+	}
+	if t := c.Terminology; t != nil {
+		d.block("terminology", func() {
+			terms := []struct{ kw, val string }{
+				{"enterprise", t.Enterprise},
+				{"person", t.Person},
+				{"softwareSystem", t.SoftwareSystem},
+				{"container", t.Container},
+				{"component", t.Component},
+				{"deploymentNode", t.DeploymentNode},
+				{"relationship", t.Relationship},
+			}
+			for _, term := range terms {
+				if term.val != "" {
+					d.line("%s %s", term.kw, quote(term.val))
+				}
+			}
+		})
+	}
+}
+
+func (d *dslWriter) optInt(kw string, v *int) {
+	if v != nil {
+		d.line("%s %d", kw, *v)
+	}
+}
+
+func (d *dslWriter) optBool(kw string, v *bool) {
+	if v != nil {
+		d.line("%s %t", kw, *v)
+	}
+}
+
+// block writes a block starting with the given statement and whose content
+// is written by fn.
+func (d *dslWriter) block(stmt string, fn func()) {
+	d.line("%s {", stmt)
+	d.indent += "    "
+	fn()
+	d.indent = d.indent[4:]
+	d.line("}")
+}
+
+func (d *dslWriter) line(format string, a ...interface{}) {
+	d.buf.WriteString(d.indent)
+	fmt.Fprintf(d.buf, format+"\n", a...)
+}
+
+// environments returns the names of the deployment environments in the order
+// they are first defined.
+func environments(nodes []*mdl.DeploymentNode) []string {
+	var envs []string
+	seen := make(map[string]bool)
+	for _, n := range nodes {
+		if !seen[n.Environment] {
+			seen[n.Environment] = true
+			envs = append(envs, n.Environment)
+		}
+	}
+	return envs
+}
+
+// lessOrder compares two dynamic view relationship orders. Orders that are
+// numbers are compared numerically.
+func lessOrder(a, b string) bool {
+	na, erra := strconv.Atoi(a)
+	nb, errb := strconv.Atoi(b)
+	if erra == nil && errb == nil {
+		return na < nb
+	}
+	return a < b
+}
+
+// customTags removes the tags automatically added by Structurizr from the
+// given comma separated list of tags.
+func customTags(tags string) string {
+	var res []string
+	for _, t := range strings.Split(tags, ",") {
+		if t = strings.TrimSpace(t); t != "" && !defaultTags[t] {
+			res = append(res, t)
+		}
+	}
+	return strings.Join(res, ",")
+}
+
+// relationshipTags returns the tags of r including the tag that records its
+// interaction style. The Goa DSL only tags asynchronous relationships.
+func relationshipTags(r *mdl.Relationship) string {
+	if r.InteractionStyle != mdl.InteractionSynchronous {
+		return r.Tags
+	}
+	for _, t := range strings.Split(r.Tags, ",") {
+		if strings.TrimSpace(t) == "Synchronous" {
+			return r.Tags
+		}
+	}
+	return strings.TrimSuffix(r.Tags, ",") + ",Synchronous"
+}
+
+// args returns the quoted and space separated list of arguments omitting
+// trailing empty values.
+func args(vals ...string) string {
+	last := len(vals) - 1
+	for last >= 0 && vals[last] == "" {
+		last--
+	}
+	res := make([]string, last+1)
+	for i := 0; i <= last; i++ {
+		res[i] = quote(vals[i])
+	}
+	return strings.Join(res, " ")
+}
+
+// quote returns a Structurizr DSL string literal for s.
+func quote(s string) string {
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	s = strings.ReplaceAll(s, "\n", " ")
+	return `"` + s + `"`
+}
+
+// enum returns the string representation of the given enum value.
+func enum(v json.Marshaler) string {
+	b, _ := v.MarshalJSON()
+	return strings.Trim(string(b), `"`)
+}
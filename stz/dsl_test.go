@@ -0,0 +1,104 @@
+package stz_test
+
+import (
+	"testing"
+
+	"goa.design/goa/v3/eval"
+	goaexpr "goa.design/goa/v3/expr"
+	. "goa.design/model/dsl"
+	"goa.design/model/expr"
+	"goa.design/model/internal/testutil"
+	"goa.design/model/stz"
+)
+
+func TestDSL(t *testing.T) {
+	w := runDSL(t, func() {
+		Enterprise("Bakery")
+		var Bank = SoftwareSystem("Bank", "Takes \"payments\".", func() {
+			External()
+		})
+		var Shop = SoftwareSystem("Shop", "Sells bread.", func() {
+			URL("https://shop.example.com")
+			Container("Web", "Web app.", "React", func() {
+				Uses("API", "Calls", "HTTPS", Synchronous)
+			})
+			Container("API", "Backend.", "Go", func() {
+				Prop("tier", "1")
+				Uses("Queue", "Publishes orders", "AMQP", Asynchronous, func() {
+					Tag("Event")
+				})
+				Uses(Bank, "Charges cards", "HTTPS", func() {
+					Prop("timeout", "10s")
+				})
+				Component("Orders", "Manages orders.", "Go")
+			})
+			Group("Data", func() {
+				Container("Queue", "Stores orders.", "RabbitMQ", func() {
+					Tag("Queue")
+				})
+			})
+		})
+		Person("Baker", "Bakes bread.", func() {
+			Uses(Shop, "Reads orders from")
+		})
+		DeploymentEnvironment("Production", func() {
+			DeploymentNode("Cloud", "", "AWS", func() {
+				InfrastructureNode("Gateway", "Routes requests.", "API Gateway")
+				DeploymentNode("Server", "", "Linux", func() {
+					Instances(3)
+					ContainerInstance("Shop/Web")
+					ContainerInstance("Shop/API", func() {
+						HealthCheck("Ping", func() {
+							URL("https://shop.example.com/ping")
+							Interval(60)
+							Timeout(1000)
+						})
+					})
+				})
+			})
+		})
+		Views(func() {
+			SystemContextView(Shop, "context", "Shop context.", func() {
+				AddAll()
+				AutoLayout(RankLeftRight)
+			})
+			ContainerView(Shop, "containers", "Shop containers.", func() {
+				AddAll()
+				Title("Shop containers")
+			})
+			DeploymentView(Shop, "Production", "production", "Production deployment.", func() {
+				AddAll()
+			})
+			Styles(func() {
+				ElementStyle("Queue", func() {
+					Shape(ShapePipe)
+				})
+				RelationshipStyle("Event", func() {
+					Dashed()
+				})
+			})
+		})
+	})
+	testutil.AssertGolden(t, "workspace.dsl", w.DSL())
+}
+
+// runDSL resets the DSL engine, evaluates a design defined with the given DSL
+// and returns the corresponding workspace.
+func runDSL(t *testing.T, dsl func()) *stz.Workspace {
+	t.Helper()
+	eval.Reset()
+	goaexpr.Root = &goaexpr.RootExpr{GeneratedTypes: &goaexpr.GeneratedRoot{}}
+	expr.Root = &expr.Design{Model: &expr.Model{}, Views: &expr.Views{}}
+	expr.Registry = make(map[string]interface{})
+	for _, r := range []eval.Root{goaexpr.Root, goaexpr.Root.GeneratedTypes, expr.Root} {
+		if err := eval.Register(r); err != nil {
+			t.Fatalf("failed to register root: %s", err)
+		}
+	}
+	Design("Test", "A test workspace.", dsl)
+	w, err := stz.RunDSL()
+	if err != nil {
+		t.Fatalf("failed to run DSL: %s", err)
+	}
+	return w
+}
@@ -16,7 +16,9 @@ func RunDSL() (*Workspace, error) {
 }
 
 // WorkspaceFromDesign returns a Structurizr workspace initialized from the
-// given design.
+// given design. Teams and code elements are not supported by Structurizr, the
+// name of the team that owns an element is stored in its "owner" property and
+// code elements are omitted.
 func WorkspaceFromDesign(d *expr.Design) *Workspace {
 	design := mdl.ModelizeDesign(d)
 	v := design.Views
@@ -25,7 +27,7 @@ func WorkspaceFromDesign(d *expr.Design) *Workspace {
 		Name:        d.Name,
 		Description: d.Description,
 		Version:     d.Version,
-		Model:       design.Model,
+		Model:       structurizrModel(design.Model),
 		Views: &Views{
 			LandscapeViews:  v.LandscapeViews,
 			ContextViews:    v.ContextViews,
@@ -38,3 +40,80 @@ func WorkspaceFromDesign(d *expr.Design) *Workspace {
 		},
 	}
 }
+
+// structurizrModel removes the information that Structurizr does not support
+// from m. Teams are omitted and the name of the team that owns an element is
+// stored in its "owner" property instead. Code elements are omitted together
+// with the relationships to them.
+func structurizrModel(m *mdl.Model) *mdl.Model {
+	m.Teams = nil
+	code := make(map[string]bool)
+	for _, s := range m.Systems {
+		for _, c := range s.Containers {
+			for _, cmp := range c.Components {
+				for _, ce := range cmp.CodeElements {
+					code[ce.ID] = true
+				}
+				cmp.CodeElements = nil
+			}
+		}
+	}
+	for _, p := range m.People {
+		p.Properties, p.Owner = ownerProperty(p.Properties, p.Owner), ""
+		p.Relationships = withoutCode(p.Relationships, code)
+	}
+	for _, s := range m.Systems {
+		s.Properties, s.Owner = ownerProperty(s.Properties, s.Owner), ""
+		s.Relationships = withoutCode(s.Relationships, code)
+		for _, c := range s.Containers {
+			c.Properties, c.Owner = ownerProperty(c.Properties, c.Owner), ""
+			c.Relationships = withoutCode(c.Relationships, code)
+			for _, cmp := range c.Components {
+				cmp.Properties, cmp.Owner = ownerProperty(cmp.Properties, cmp.Owner), ""
+				cmp.Relationships = withoutCode(cmp.Relationships, code)
+			}
+		}
+	}
+	var nodes func([]*mdl.DeploymentNode)
+	nodes = func(ns []*mdl.DeploymentNode) {
+		for _, n := range ns {
+			n.Properties, n.Owner = ownerProperty(n.Properties, n.Owner), ""
+			for _, inf := range n.InfrastructureNodes {
+				inf.Properties, inf.Owner = ownerProperty(inf.Properties, inf.Owner), ""
+			}
+			for _, ci := range n.ContainerInstances {
+				ci.Properties, ci.Owner = ownerProperty(ci.Properties, ci.Owner), ""
+			}
+			nodes(n.Children)
+		}
+	}
+	nodes(m.DeploymentNodes)
+	return m
+}
+
+// ownerProperty returns a copy of props with the "owner" property set to owner
+// if owner is not empty, props otherwise.
+func ownerProperty(props map[string]string, owner string) map[string]string {
+	if owner == "" {
+		return props
+	}
+	res := map[string]string{"owner": owner}
+	for k, v := range props {
+		if k != "owner" {
+			res[k] = v
+		}
+	}
+	return res
+}
+
+// withoutCode returns the relationships in rels whose destination is not one
+// of the given code elements.
+func withoutCode(rels []*mdl.Relationship, code map[string]bool) []*mdl.Relationship {
+	var res []*mdl.Relationship
+	for _, r := range rels {
+		if !code[r.DestinationID] {
+			res = append(res, r)
+		}
+	}
+	return res
+}
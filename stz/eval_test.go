@@ -0,0 +1,62 @@
+package stz_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	. "goa.design/model/dsl"
+)
+
+func TestWorkspaceFromDesign(t *testing.T) {
+	w := runDSL(t, func() {
+		var Ops = Team("Ops", func() {
+			Contact("ops@example.com")
+		})
+		SoftwareSystem("Shop", "The shop.", func() {
+			Owner(Ops)
+			Container("API", "Backend.", "Go", func() {
+				Component("Orders", "Manages orders.", "Go", func() {
+					Prop("owner", "nobody")
+					Prop("tier", "1")
+					Owner(Ops)
+					var Basket = CodeElement("Basket", "Holds items.", "Go struct")
+					Uses(Basket, "Fills")
+				})
+				Component("Payments", "Charges cards.", "Go", func() {
+					Uses("Orders", "Reads")
+				})
+			})
+		})
+		DeploymentEnvironment("Production", func() {
+			DeploymentNode("Cloud", func() {
+				Owner(Ops)
+				ContainerInstance("Shop/API")
+			})
+		})
+	})
+	b, err := json.Marshal(w)
+	if err != nil {
+		t.Fatalf("failed to serialize workspace: %s", err)
+	}
+	js := string(b)
+	for _, s := range []string{`"teams"`, `"ops@example.com"`, `"codeElements"`, `"Basket"`, `"Fills"`} {
+		if strings.Contains(js, s) {
+			t.Errorf("workspace contains %s:\n%s", s, js)
+		}
+	}
+	// The owner of the software system, component and deployment node is
+	// only recorded in their properties.
+	if n := strings.Count(js, `"owner":"Ops"`); n != 3 {
+		t.Errorf("got %d owners, want 3:\n%s", n, js)
+	}
+	if n := strings.Count(js, `"properties":{"owner":"Ops"`); n != 3 {
+		t.Errorf("got %d owner properties, want 3:\n%s", n, js)
+	}
+	if !strings.Contains(js, `"properties":{"owner":"Ops","tier":"1"}`) {
+		t.Errorf("workspace does not contain component properties:\n%s", js)
+	}
+	if !strings.Contains(js, `"description":"Reads"`) {
+		t.Errorf("workspace does not contain relationship between components:\n%s", js)
+	}
+}
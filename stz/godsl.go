@@ -0,0 +1,37 @@
+package stz
+
+import (
+	"goa.design/model/dslgen"
+	"goa.design/model/mdl"
+)
+
+// Design returns the design described by the workspace.
+func (w *Workspace) Design() *mdl.Design {
+	d := &mdl.Design{
+		Name:        w.Name,
+		Description: w.Description,
+		Version:     w.Version,
+		Model:       w.Model,
+	}
+	if v := w.Views; v != nil {
+		d.Views = &mdl.Views{
+			LandscapeViews:  v.LandscapeViews,
+			ContextViews:    v.ContextViews,
+			ContainerViews:  v.ContainerViews,
+			ComponentViews:  v.ComponentViews,
+			DynamicViews:    v.DynamicViews,
+			DeploymentViews: v.DeploymentViews,
+			FilteredViews:   v.FilteredViews,
+		}
+		if v.Configuration != nil {
+			d.Views.Styles = v.Configuration.Styles
+		}
+	}
+	return d
+}
+
+// GoDSL returns the Go source code of a package named pkg that describes the
+// workspace using the model DSL.
+func (w *Workspace) GoDSL(pkg string) ([]byte, error) {
+	return dslgen.Generate(w.Design(), pkg)
+}
@@ -0,0 +1,31 @@
+package stz
+
+import (
+	"testing"
+
+	"goa.design/model/mdl"
+)
+
+func TestGoDSLFilteredViews(t *testing.T) {
+	w := &Workspace{
+		Name:  "Test",
+		Model: &mdl.Model{Systems: []*mdl.SoftwareSystem{{ID: "1", Name: "System"}}},
+		Views: &Views{
+			LandscapeViews: []*mdl.LandscapeView{{ViewProps: &mdl.ViewProps{Key: "landscape"}}},
+			FilteredViews: []*mdl.FilteredView{{
+				Key:         "filtered",
+				Description: "No infra.",
+				BaseKey:     "landscape",
+				Mode:        "Exclude",
+				Tags:        []string{"infra"},
+			}},
+		},
+	}
+	d := w.Design()
+	if d.Views == nil || len(d.Views.FilteredViews) != 1 {
+		t.Fatalf("got design views %+v, want one filtered view", d.Views)
+	}
+	if _, err := w.GoDSL("design"); err == nil {
+		t.Error("got no error, want error for filtered view")
+	}
+}
@@ -0,0 +1,990 @@
+package stz
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"goa.design/model/mdl"
+)
+
+type (
+	// dslParser parses the Structurizr DSL into a workspace.
+	dslParser struct {
+		lines []*dslLine
+		pos   int
+		w     *Workspace
+		// idents maps lowercase element and relationship identifiers to IDs.
+		idents map[string]string
+		// elements indexes the elements by ID.
+		elements map[string]interface{}
+		// parents maps element IDs to the IDs of their parents.
+		parents map[string]string
+		// rels lists the relationships declared in the model.
+		rels []*dslRelationship
+		// instances lists the container instances declared in the model.
+		instances []*dslInstance
+		// internal records the IDs of the people and software systems
+		// declared in the enterprise block.
+		internal map[string]bool
+		// inEnterprise is true while parsing the enterprise block.
+		inEnterprise bool
+		// group is the name of the group being parsed if any.
+		group string
+		// hierarchical is true if identifiers are hierarchical.
+		hierarchical bool
+		// implied is true if implied relationships must be created.
+		implied bool
+		// seq is used to generate element and relationship IDs.
+		seq int
+		// keys counts the views of each kind to generate default view keys.
+		keys map[string]int
+	}
+
+	// dslLine is a single DSL statement.
+	dslLine struct {
+		tokens []*dslToken
+		file   string
+		num    int
+	}
+
+	// dslToken is a single token, quoted is true if the token was enclosed
+	// in double quotes.
+	dslToken struct {
+		val    string
+		quoted bool
+	}
+
+	// dslScope is the element whose block is being parsed.
+	dslScope struct {
+		id     string
+		ident  string
+		env    string
+		parent *dslScope
+	}
+
+	// dslRelationship is a relationship whose ends are resolved once the
+	// whole model has been parsed.
+	dslRelationship struct {
+		rel   *mdl.Relationship
+		src   string
+		dest  string
+		scope *dslScope
+		line  *dslLine
+	}
+
+	// dslInstance is a container instance whose container is resolved once
+	// the whole model has been parsed.
+	dslInstance struct {
+		ci        *mdl.ContainerInstance
+		container string
+		scope     *dslScope
+		line      *dslLine
+	}
+)
+
+// ParseDSL parses the Structurizr DSL file (see
+// https://github.com/structurizr/dsl) at the given path and returns the
+// corresponding workspace. Included files and directories are resolved
+// relative to the including file.
+//
+// Views list the elements and relationships they include explicitly, the
+// "include *" expression is expanded following the Structurizr rules. Implied
+// relationships are created unless disabled with "!impliedRelationships
+// false". Statements that have no equivalent in the model (for example
+// software system instances, custom views, themes or documentation) are
+// ignored.
+func ParseDSL(path string) (*Workspace, error) {
+	lines, err := readDSL(path, make(map[string]string), nil)
+	if err != nil {
+		return nil, err
+	}
+	p := &dslParser{
+		lines:    lines,
+		w:        &Workspace{Model: &mdl.Model{}, Views: &Views{}},
+		idents:   make(map[string]string),
+		elements: make(map[string]interface{}),
+		parents:  make(map[string]string),
+		internal: make(map[string]bool),
+		implied:  true,
+		keys:     make(map[string]int),
+	}
+	if err := p.parseWorkspace(); err != nil {
+		return nil, err
+	}
+	return p.w, nil
+}
+
+// readDSL reads and tokenizes the DSL file at path, processing includes and
+// constants. stack contains the paths of the files being included and is used
+// to detect include cycles.
+func readDSL(path string, consts map[string]string, stack []string) ([]*dslLine, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range stack {
+		if s == abs {
+			return nil, fmt.Errorf("%s: include cycle", path)
+		}
+	}
+	stack = append(stack, abs)
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		res     []*dslLine
+		comment bool
+		cont    string
+		num     int
+	)
+	scanner := bufio.NewScanner(bytes.NewReader(b))
+	scanner.Buffer(make([]byte, 64*1024), 10*1024*1024)
+	for scanner.Scan() {
+		num++
+		text := strings.TrimSpace(scanner.Text())
+		if comment {
+			if strings.HasSuffix(text, "*/") {
+				comment = false
+			}
+			continue
+		}
+		if strings.HasPrefix(text, "/*") {
+			comment = !strings.HasSuffix(text, "*/")
+			continue
+		}
+		if strings.HasSuffix(text, "\\") {
+			cont += strings.TrimSuffix(text, "\\") + " "
+			continue
+		}
+		text, cont = cont+text, ""
+		if text == "" || strings.HasPrefix(text, "#") || strings.HasPrefix(text, "//") {
+			continue
+		}
+		l := &dslLine{file: path, num: num}
+		if l.tokens, err = tokenize(text); err != nil {
+			return nil, l.errorf("%s", err)
+		}
+		for _, t := range l.tokens {
+			t.val = substitute(t.val, consts)
+		}
+		switch strings.ToLower(l.tokens[0].val) {
+		case "!include":
+			if len(l.tokens) < 2 {
+				return nil, l.errorf("missing include path")
+			}
+			inc := l.tokens[1].val
+			if !filepath.IsAbs(inc) && !strings.Contains(inc, "://") {
+				inc = filepath.Join(filepath.Dir(path), inc)
+			}
+			included, err := readInclude(inc, consts, stack)
+			if err != nil {
+				return nil, err
+			}
+			res = append(res, included...)
+			continue
+		case "!constant", "!const", "!var":
+			if len(l.tokens) < 3 {
+				return nil, l.errorf("%s requires a name and a value", l.tokens[0].val)
+			}
+			consts[l.tokens[1].val] = l.tokens[2].val
+			continue
+		}
+		res = append(res, l.split()...)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// readInclude reads the included file or all the files in the included
+// directory.
+func readInclude(path string, consts map[string]string, stack []string) ([]*dslLine, error) {
+	if strings.Contains(path, "://") {
+		return nil, fmt.Errorf("%s: remote includes are not supported", path)
+	}
+	fi, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !fi.IsDir() {
+		return readDSL(path, consts, stack)
+	}
+	var files []string
+	err = filepath.Walk(path, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			files = append(files, p)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+	var res []*dslLine
+	for _, f := range files {
+		lines, err := readDSL(f, consts, stack)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, lines...)
+	}
+	return res, nil
+}
+
+// tokenize splits a line into tokens separated by whitespace, double quotes
+// group tokens containing whitespace.
+func tokenize(text string) ([]*dslToken, error) {
+	var (
+		res []*dslToken
+		cur strings.Builder
+		tok *dslToken
+	)
+	flush := func() {
+		if tok != nil {
+			tok.val = cur.String()
+			res = append(res, tok)
+			tok = nil
+			cur.Reset()
+		}
+	}
+	rs := []rune(text)
+	for i := 0; i < len(rs); i++ {
+		r := rs[i]
+		switch {
+		case tok != nil && tok.quoted:
+			switch {
+			case r == '\\' && i+1 < len(rs) && rs[i+1] == 'n':
+				i++
+				cur.WriteRune('\n')
+			case r == '\\' && i+1 < len(rs) && (rs[i+1] == '"' || rs[i+1] == '\\'):
+				i++
+				cur.WriteRune(rs[i])
+			case r == '"':
+				flush()
+			default:
+				cur.WriteRune(r)
+			}
+		case r == ' ' || r == '\t':
+			flush()
+		case r == '"' && tok == nil:
+			tok = &dslToken{quoted: true}
+		default:
+			if tok == nil {
+				tok = &dslToken{}
+			}
+			cur.WriteRune(r)
+		}
+	}
+	if tok != nil && tok.quoted {
+		return nil, fmt.Errorf("unterminated string")
+	}
+	flush()
+	return res, nil
+}
+
+// substitute replaces the ${NAME} references to constants in s.
+func substitute(s string, consts map[string]string) string {
+	if !strings.Contains(s, "${") {
+		return s
+	}
+	for k, v := range consts {
+		s = strings.ReplaceAll(s, "${"+k+"}", v)
+	}
+	return s
+}
+
+// parseWorkspace parses the top level workspace block.
+func (p *dslParser) parseWorkspace() error {
+	l := p.next()
+	if l == nil {
+		return fmt.Errorf("empty workspace definition")
+	}
+	if l.keyword() != "workspace" || !l.opens() {
+		return l.errorf("expected workspace block")
+	}
+	args := l.args()[1:]
+	if len(args) > 0 && strings.ToLower(args[0]) == "extends" {
+		return l.errorf("extending workspaces is not supported")
+	}
+	p.w.Name = arg(args, 0)
+	p.w.Description = arg(args, 1)
+	return p.block(func(l *dslLine) error {
+		switch l.keyword() {
+		case "name":
+			p.w.Name = arg(l.args(), 1)
+		case "description":
+			p.w.Description = arg(l.args(), 1)
+		case "!identifiers":
+			p.hierarchical = strings.ToLower(arg(l.args(), 1)) == "hierarchical"
+		case "!impliedrelationships":
+			p.implied = arg(l.args(), 1) != "false"
+		case "model":
+			if err := p.block(func(l *dslLine) error { return p.modelStatement(l, nil) }); err != nil {
+				return err
+			}
+			return p.finalizeModel()
+		case "views":
+			return p.block(p.viewsStatement)
+		default:
+			return p.skip(l)
+		}
+		return nil
+	})
+}
+
+// modelStatement parses a single statement of the model block or of an
+// element block.
+func (p *dslParser) modelStatement(l *dslLine, scope *dslScope) error {
+	var ident string
+	args := l.args()
+	if len(args) > 2 && args[1] == "=" && !l.tokens[1].quoted {
+		ident, args = args[0], args[2:]
+	}
+	offset := len(l.args()) - len(args)
+	for i, t := range args {
+		if t == "->" && !l.tokens[offset+i].quoted {
+			return p.relationship(l, ident, args, i, scope)
+		}
+	}
+	kw := strings.ToLower(args[0])
+	args = args[1:]
+	var scopeID string
+	if scope != nil {
+		scopeID = scope.id
+	}
+	switch kw {
+	case "enterprise":
+		p.w.Model.Enterprise = &mdl.Enterprise{Name: arg(args, 0)}
+		p.inEnterprise = true
+		defer func() { p.inEnterprise = false }()
+		return p.block(func(l *dslLine) error { return p.modelStatement(l, scope) })
+	case "group":
+		prev := p.group
+		p.group = arg(args, 0)
+		defer func() { p.group = prev }()
+		return p.block(func(l *dslLine) error { return p.modelStatement(l, scope) })
+	case "person":
+		e := &mdl.Person{ID: p.newID(), Name: arg(args, 0), Description: arg(args, 1), Tags: tags("Element,Person", arg(args, 2)), Group: p.group}
+		p.w.Model.People = append(p.w.Model.People, e)
+		p.internal[e.ID] = p.inEnterprise
+		return p.element(l, e.ID, ident, "", scope)
+	case "softwaresystem":
+		e := &mdl.SoftwareSystem{ID: p.newID(), Name: arg(args, 0), Description: arg(args, 1), Tags: tags("Element,Software System", arg(args, 2)), Group: p.group}
+		p.w.Model.Systems = append(p.w.Model.Systems, e)
+		p.internal[e.ID] = p.inEnterprise
+		return p.element(l, e.ID, ident, "", scope)
+	case "container":
+		s, ok := p.elements[scopeID].(*mdl.SoftwareSystem)
+		if !ok {
+			return l.errorf("container must be declared in a software system")
+		}
+		e := &mdl.Container{ID: p.newID(), Name: arg(args, 0), Description: arg(args, 1), Technology: arg(args, 2), Tags: tags("Element,Container", arg(args, 3)), Group: p.group}
+		s.Containers = append(s.Containers, e)
+		return p.element(l, e.ID, ident, "", scope)
+	case "component":
+		c, ok := p.elements[scopeID].(*mdl.Container)
+		if !ok {
+			return l.errorf("component must be declared in a container")
+		}
+		e := &mdl.Component{ID: p.newID(), Name: arg(args, 0), Description: arg(args, 1), Technology: arg(args, 2), Tags: tags("Element,Component", arg(args, 3)), Group: p.group}
+		c.Components = append(c.Components, e)
+		return p.element(l, e.ID, ident, "", scope)
+	case "deploymentenvironment":
+		env := &dslScope{env: arg(args, 0), parent: scope}
+		return p.block(func(l *dslLine) error { return p.modelStatement(l, env) })
+	case "deploymentnode":
+		if scope == nil || scope.env == "" {
+			return l.errorf("deployment node must be declared in a deployment environment")
+		}
+		e := &mdl.DeploymentNode{ID: p.newID(), Name: arg(args, 0), Description: arg(args, 1), Technology: arg(args, 2), Tags: tags("Element,Deployment Node", arg(args, 3)), Environment: scope.env}
+		if n := arg(args, 4); n != "" {
+			i, err := instances(n)
+			if err != nil {
+				return l.errorf("invalid number of instances %q", n)
+			}
+			e.Instances = &i
+		}
+		if parent, ok := p.elements[scopeID].(*mdl.DeploymentNode); ok {
+			parent.Children = append(parent.Children, e)
+		} else {
+			p.w.Model.DeploymentNodes = append(p.w.Model.DeploymentNodes, e)
+		}
+		return p.element(l, e.ID, ident, scope.env, scope)
+	case "infrastructurenode":
+		n, ok := p.elements[scopeID].(*mdl.DeploymentNode)
+		if !ok {
+			return l.errorf("infrastructure node must be declared in a deployment node")
+		}
+		e := &mdl.InfrastructureNode{ID: p.newID(), Name: arg(args, 0), Description: arg(args, 1), Technology: arg(args, 2), Tags: tags("Element,Infrastructure Node", arg(args, 3)), Environment: scope.env}
+		n.InfrastructureNodes = append(n.InfrastructureNodes, e)
+		return p.element(l, e.ID, ident, scope.env, scope)
+	case "containerinstance":
+		n, ok := p.elements[scopeID].(*mdl.DeploymentNode)
+		if !ok {
+			return l.errorf("container instance must be declared in a deployment node")
+		}
+		t := arg(args, 1)
+		if len(args) > 2 {
+			t = args[2] // containerInstance <identifier> [deploymentGroups] [tags]
+		}
+		e := &mdl.ContainerInstance{ID: p.newID(), Tags: tags("Container Instance", t), Environment: scope.env}
+		n.ContainerInstances = append(n.ContainerInstances, e)
+		p.instances = append(p.instances, &dslInstance{ci: e, container: arg(args, 0), scope: scope, line: l})
+		return p.element(l, e.ID, ident, scope.env, scope)
+	case "instances":
+		n, ok := p.elements[scopeID].(*mdl.DeploymentNode)
+		if !ok {
+			return l.errorf("instances must be declared in a deployment node")
+		}
+		i, err := instances(arg(args, 0))
+		if err != nil {
+			return l.errorf("invalid number of instances %q", arg(args, 0))
+		}
+		n.Instances = &i
+		return p.skip(l)
+	case "healthcheck":
+		ci, ok := p.elements[scopeID].(*mdl.ContainerInstance)
+		if !ok {
+			return l.errorf("health check must be declared in a container instance")
+		}
+		hc := &mdl.HealthCheck{Name: arg(args, 0), URL: arg(args, 1), Interval: 60}
+		if v := arg(args, 2); v != "" {
+			hc.Interval, _ = strconv.Atoi(v)
+		}
+		if v := arg(args, 3); v != "" {
+			hc.Timeout, _ = strconv.Atoi(v)
+		}
+		ci.HealthChecks = append(ci.HealthChecks, hc)
+		return p.skip(l)
+	case "description", "technology", "tags", "url", "properties", "perspectives":
+		if scope == nil || scope.id == "" {
+			return l.errorf("%s must appear in an element block", args[0])
+		}
+		return p.setting(l, p.elements[scope.id], kw, args)
+	case "!identifiers":
+		p.hierarchical = strings.ToLower(arg(args, 0)) == "hierarchical"
+	case "!impliedrelationships":
+		p.implied = arg(args, 0) != "false"
+	default:
+		return p.skip(l)
+	}
+	return nil
+}
+
+// element registers the element with the given ID and parses its block if
+// any.
+func (p *dslParser) element(l *dslLine, id, ident, env string, scope *dslScope) error {
+	p.elements[id] = p.w.Model.Element(id)
+	if scope != nil && scope.id != "" {
+		p.parents[id] = scope.id
+	}
+	s := &dslScope{id: id, env: env, parent: scope}
+	if ident != "" {
+		s.ident = ident
+		if p.hierarchical {
+			for sc := scope; sc != nil; sc = sc.parent {
+				if sc.ident != "" {
+					s.ident = sc.ident + "." + ident
+					break
+				}
+			}
+		}
+		p.idents[strings.ToLower(s.ident)] = id
+	}
+	if !l.opens() {
+		return nil
+	}
+	// Groups only apply to the elements declared directly in them.
+	group := p.group
+	p.group = ""
+	defer func() { p.group = group }()
+	return p.block(func(l *dslLine) error { return p.modelStatement(l, s) })
+}
+
+// relationship parses a relationship statement, idx is the index of the
+// arrow in args.
+func (p *dslParser) relationship(l *dslLine, ident string, args []string, idx int, scope *dslScope) error {
+	var src string
+	switch idx {
+	case 0:
+		if scope == nil || scope.id == "" {
+			return l.errorf("missing relationship source")
+		}
+	case 1:
+		src = args[0]
+	default:
+		return l.errorf("invalid relationship")
+	}
+	if src == "this" || src == "" {
+		if scope == nil || scope.id == "" {
+			return l.errorf("this must appear in an element block")
+		}
+		src = "this"
+	}
+	rest := args[idx+1:]
+	if len(rest) == 0 {
+		return l.errorf("missing relationship destination")
+	}
+	r := &mdl.Relationship{
+		ID:          p.newID(),
+		Description: arg(rest, 1),
+		Technology:  arg(rest, 2),
+		Tags:        tags("Relationship", arg(rest, 3)),
+	}
+	p.rels = append(p.rels, &dslRelationship{rel: r, src: src, dest: rest[0], scope: scope, line: l})
+	if ident != "" {
+		p.idents[strings.ToLower(ident)] = r.ID
+	}
+	if !l.opens() {
+		return nil
+	}
+	return p.block(func(l *dslLine) error {
+		switch kw := l.keyword(); kw {
+		case "description", "technology", "tags", "url", "properties", "perspectives":
+			return p.setting(l, r, kw, l.args()[1:])
+		default:
+			return p.skip(l)
+		}
+	})
+}
+
+// setting applies a description, technology, tags, url, properties or
+// perspectives statement to the given element or relationship.
+func (p *dslParser) setting(l *dslLine, e interface{}, kw string, args []string) error {
+	var desc, tech, tgs, u *string
+	var props *map[string]string
+	var persps *[]*mdl.Perspective
+	switch e := e.(type) {
+	case *mdl.Person:
+		desc, tgs, u, props, persps = &e.Description, &e.Tags, &e.URL, &e.Properties, &e.Perspectives
+	case *mdl.SoftwareSystem:
+		desc, tgs, u, props, persps = &e.Description, &e.Tags, &e.URL, &e.Properties, &e.Perspectives
+	case *mdl.Container:
+		desc, tech, tgs, u, props, persps = &e.Description, &e.Technology, &e.Tags, &e.URL, &e.Properties, &e.Perspectives
+	case *mdl.Component:
+		desc, tech, tgs, u, props, persps = &e.Description, &e.Technology, &e.Tags, &e.URL, &e.Properties, &e.Perspectives
+	case *mdl.DeploymentNode:
+		desc, tech, tgs, u, props, persps = &e.Description, &e.Technology, &e.Tags, &e.URL, &e.Properties, &e.Perspectives
+	case *mdl.InfrastructureNode:
+		desc, tech, tgs, u, props, persps = &e.Description, &e.Technology, &e.Tags, &e.URL, &e.Properties, &e.Perspectives
+	case *mdl.ContainerInstance:
+		tgs, u, props, persps = &e.Tags, &e.URL, &e.Properties, &e.Perspectives
+	case *mdl.Relationship:
+		desc, tech, tgs, u, props, persps = &e.Description, &e.Technology, &e.Tags, &e.URL, &e.Properties, &e.Perspectives
+	}
+	switch kw {
+	case "description":
+		if desc != nil {
+			*desc = arg(args, 0)
+		}
+	case "technology":
+		if tech != nil {
+			*tech = arg(args, 0)
+		}
+	case "url":
+		if u != nil {
+			*u = arg(args, 0)
+		}
+	case "tags":
+		if tgs != nil {
+			for _, t := range args {
+				*tgs = tags(*tgs, t)
+			}
+		}
+		if r, ok := e.(*mdl.Relationship); ok {
+			r.InteractionStyle = interactionStyle(r.Tags)
+		}
+	case "properties":
+		return p.block(func(l *dslLine) error {
+			if props != nil {
+				if *props == nil {
+					*props = make(map[string]string)
+				}
+				(*props)[l.tokens[0].val] = arg(l.args(), 1)
+			}
+			return p.skip(l)
+		})
+	case "perspectives":
+		return p.block(func(l *dslLine) error {
+			if persps != nil {
+				*persps = append(*persps, &mdl.Perspective{Name: l.tokens[0].val, Description: arg(l.args(), 1)})
+			}
+			return p.skip(l)
+		})
+	}
+	return nil
+}
+
+// finalizeModel resolves the container instances and relationships and
+// creates the implied relationships.
+func (p *dslParser) finalizeModel() error {
+	m := p.w.Model
+	counts := make(map[string]int)
+	for _, inst := range p.instances {
+		id, ok := p.resolve(inst.container, inst.scope)
+		if !ok {
+			return inst.line.errorf("unknown container %q", inst.container)
+		}
+		if _, ok := p.elements[id].(*mdl.Container); !ok {
+			return inst.line.errorf("%q is not a container", inst.container)
+		}
+		inst.ci.ContainerID = id
+		counts[id]++
+		inst.ci.InstanceID = counts[id]
+	}
+	for _, dr := range p.rels {
+		src := dr.scope.elementID()
+		if dr.src != "this" {
+			id, ok := p.resolve(dr.src, dr.scope)
+			if !ok {
+				return dr.line.errorf("unknown element %q", dr.src)
+			}
+			src = id
+		}
+		dest, ok := p.resolve(dr.dest, dr.scope)
+		if !ok {
+			return dr.line.errorf("unknown element %q", dr.dest)
+		}
+		dr.rel.SourceID, dr.rel.DestinationID = src, dest
+		// Tags may be set in the relationship block.
+		dr.rel.InteractionStyle = interactionStyle(dr.rel.Tags)
+		if !p.addRelationship(dr.rel) {
+			return dr.line.errorf("invalid relationship source %q", dr.src)
+		}
+	}
+	if p.implied {
+		var rels []*mdl.Relationship
+		m.IterateRelationships(func(r *mdl.Relationship) { rels = append(rels, r) })
+		for _, r := range rels {
+			p.addImpliedRelationships(r)
+		}
+	}
+	p.addInstanceRelationships()
+	if m.Enterprise != nil {
+		for _, e := range m.People {
+			e.Location = location(p.internal[e.ID])
+		}
+		for _, e := range m.Systems {
+			e.Location = location(p.internal[e.ID])
+		}
+	}
+	return nil
+}
+
+// addRelationship adds r to the relationships of its source, it returns false
+// if the source cannot have relationships.
+func (p *dslParser) addRelationship(r *mdl.Relationship) bool {
+	switch e := p.elements[r.SourceID].(type) {
+	case *mdl.Person:
+		e.Relationships = append(e.Relationships, r)
+	case *mdl.SoftwareSystem:
+		e.Relationships = append(e.Relationships, r)
+	case *mdl.Container:
+		e.Relationships = append(e.Relationships, r)
+	case *mdl.Component:
+		e.Relationships = append(e.Relationships, r)
+	case *mdl.DeploymentNode:
+		e.Relationships = append(e.Relationships, r)
+	case *mdl.InfrastructureNode:
+		e.Relationships = append(e.Relationships, r)
+	case *mdl.ContainerInstance:
+		e.Relationships = append(e.Relationships, r)
+	default:
+		return false
+	}
+	return true
+}
+
+// addImpliedRelationships creates the relationships implied by r between the
+// ancestors of its source and destination unless a relationship already
+// exists between them.
+func (p *dslParser) addImpliedRelationships(r *mdl.Relationship) {
+	if _, ok := p.elements[r.SourceID].(*mdl.ContainerInstance); ok {
+		return
+	}
+	srcs := p.ancestors(r.SourceID)
+	dests := p.ancestors(r.DestinationID)
+	for _, src := range srcs {
+		for _, dest := range dests {
+			if src == r.SourceID && dest == r.DestinationID {
+				continue
+			}
+			if src == dest || p.isAncestor(src, dest) || p.isAncestor(dest, src) || p.related(src, dest) {
+				continue
+			}
+			p.addRelationship(&mdl.Relationship{
+				ID:               p.newID(),
+				Description:      r.Description,
+				Technology:       r.Technology,
+				Tags:             r.Tags,
+				SourceID:         src,
+				DestinationID:    dest,
+				InteractionStyle: r.InteractionStyle,
+			})
+		}
+	}
+}
+
+// addInstanceRelationships replicates the relationships between containers
+// to the container instances deployed in the same environment.
+func (p *dslParser) addInstanceRelationships() {
+	for _, src := range p.instances {
+		for _, dest := range p.instances {
+			if src.ci.Environment != dest.ci.Environment {
+				continue
+			}
+			c := p.elements[src.ci.ContainerID].(*mdl.Container)
+			for _, r := range c.Relationships {
+				if r.DestinationID != dest.ci.ContainerID {
+					continue
+				}
+				src.ci.Relationships = append(src.ci.Relationships, &mdl.Relationship{
+					ID:                   p.newID(),
+					Description:          r.Description,
+					Technology:           r.Technology,
+					Tags:                 r.Tags,
+					SourceID:             src.ci.ID,
+					DestinationID:        dest.ci.ID,
+					InteractionStyle:     r.InteractionStyle,
+					LinkedRelationshipID: r.ID,
+				})
+			}
+		}
+	}
+}
+
+// ancestors returns the ID of the element followed by the IDs of its
+// ancestors.
+func (p *dslParser) ancestors(id string) []string {
+	res := []string{id}
+	for parent, ok := p.parents[id]; ok; parent, ok = p.parents[parent] {
+		res = append(res, parent)
+	}
+	return res
+}
+
+// isAncestor returns true if the element with ID a is an ancestor of the
+// element with ID b.
+func (p *dslParser) isAncestor(a, b string) bool {
+	for _, id := range p.ancestors(b)[1:] {
+		if id == a {
+			return true
+		}
+	}
+	return false
+}
+
+// related returns true if there is a relationship from src to dest.
+func (p *dslParser) related(src, dest string) bool {
+	var found bool
+	p.w.Model.IterateRelationships(func(r *mdl.Relationship) {
+		if r.SourceID == src && r.DestinationID == dest {
+			found = true
+		}
+	})
+	return found
+}
+
+// resolve returns the ID of the element or relationship with the given
+// identifier. Identifiers are looked up relative to the enclosing elements
+// when hierarchical identifiers are enabled.
+func (p *dslParser) resolve(ident string, scope *dslScope) (string, bool) {
+	ident = strings.ToLower(ident)
+	if p.hierarchical {
+		for s := scope; s != nil; s = s.parent {
+			if s.ident == "" {
+				continue
+			}
+			if id, ok := p.idents[strings.ToLower(s.ident)+"."+ident]; ok {
+				return id, true
+			}
+		}
+	}
+	id, ok := p.idents[ident]
+	return id, ok
+}
+
+// newID returns a new unique element or relationship ID.
+func (p *dslParser) newID() string {
+	p.seq++
+	return strconv.Itoa(p.seq)
+}
+
+// next returns the next statement or nil if there is none.
+func (p *dslParser) next() *dslLine {
+	if p.pos >= len(p.lines) {
+		return nil
+	}
+	l := p.lines[p.pos]
+	p.pos++
+	return l
+}
+
+// block calls fn for each statement of the block opened by the previous
+// statement until the closing brace.
+func (p *dslParser) block(fn func(l *dslLine) error) error {
+	for {
+		l := p.next()
+		if l == nil {
+			return fmt.Errorf("unexpected end of file, missing '}'")
+		}
+		if l.closes() {
+			return nil
+		}
+		if err := fn(l); err != nil {
+			return err
+		}
+	}
+}
+
+// skip skips the block opened by l if any.
+func (p *dslParser) skip(l *dslLine) error {
+	if !l.opens() {
+		return nil
+	}
+	return p.block(p.skip)
+}
+
+// elementID returns the ID of the innermost element of the scope.
+func (s *dslScope) elementID() string {
+	for ; s != nil; s = s.parent {
+		if s.id != "" {
+			return s.id
+		}
+	}
+	return ""
+}
+
+// split splits single line blocks such as "animation { a b }" into separate
+// statements.
+func (l *dslLine) split() []*dslLine {
+	n := len(l.tokens)
+	last := l.tokens[n-1]
+	if n < 3 || last.val != "}" || last.quoted {
+		return []*dslLine{l}
+	}
+	for i, t := range l.tokens[:n-1] {
+		if t.val == "{" && !t.quoted {
+			res := []*dslLine{{tokens: l.tokens[:i+1], file: l.file, num: l.num}}
+			if i+1 < n-1 {
+				res = append(res, &dslLine{tokens: l.tokens[i+1 : n-1], file: l.file, num: l.num})
+			}
+			return append(res, &dslLine{tokens: []*dslToken{last}, file: l.file, num: l.num})
+		}
+	}
+	return []*dslLine{l}
+}
+
+// keyword returns the first token of the line in lower case.
+func (l *dslLine) keyword() string {
+	return strings.ToLower(l.tokens[0].val)
+}
+
+// args returns the values of the tokens of the line omitting the trailing
+// opening brace.
+func (l *dslLine) args() []string {
+	toks := l.tokens
+	if l.opens() {
+		toks = toks[:len(toks)-1]
+	}
+	res := make([]string, len(toks))
+	for i, t := range toks {
+		res[i] = t.val
+	}
+	return res
+}
+
+// opens returns true if the line opens a block.
+func (l *dslLine) opens() bool {
+	t := l.tokens[len(l.tokens)-1]
+	return t.val == "{" && !t.quoted
+}
+
+// closes returns true if the line closes a block.
+func (l *dslLine) closes() bool {
+	return len(l.tokens) == 1 && l.tokens[0].val == "}" && !l.tokens[0].quoted
+}
+
+// errorf returns an error that includes the position of the line.
+func (l *dslLine) errorf(format string, a ...interface{}) error {
+	return fmt.Errorf("%s:%d: %s", l.file, l.num, fmt.Sprintf(format, a...))
+}
+
+// arg returns the i-th argument or the empty string if there is none.
+func arg(args []string, i int) string {
+	if i < len(args) {
+		return args[i]
+	}
+	return ""
+}
+
+// tags appends the comma separated tags in extra to the comma separated
+// tags in existing omitting duplicates.
+func tags(existing, extra string) string {
+	res := existing
+	for _, t := range strings.Split(extra, ",") {
+		if t = strings.TrimSpace(t); t != "" && !hasTag(res, t) {
+			if res != "" {
+				res += ","
+			}
+			res += t
+		}
+	}
+	return res
+}
+
+// hasTag returns true if the comma separated list of tags contains tag.
+func hasTag(tags, tag string) bool {
+	for _, t := range strings.Split(tags, ",") {
+		if strings.TrimSpace(t) == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// interactionStyle returns the interaction style corresponding to the
+// "Synchronous" or "Asynchronous" tag if present.
+func interactionStyle(tags string) mdl.InteractionStyleKind {
+	switch {
+	case hasTag(tags, "Asynchronous"):
+		return mdl.InteractionAsynchronous
+	case hasTag(tags, "Synchronous"):
+		return mdl.InteractionSynchronous
+	}
+	return mdl.InteractionUndefined
+}
+
+// instances returns the number of deployment node instances described by n.
+// n is either a number or a range such as "1..3" or "1..N", the upper bound
+// is used when it is a number and the lower bound otherwise.
+func instances(n string) (int, error) {
+	if i := strings.Index(n, ".."); i >= 0 {
+		if max, err := strconv.Atoi(n[i+2:]); err == nil {
+			return max, nil
+		}
+		n = n[:i]
+	}
+	return strconv.Atoi(n)
+}
+
+// location returns the location corresponding to the given enterprise
+// membership.
+func location(internal bool) mdl.LocationKind {
+	if internal {
+		return mdl.LocationInternal
+	}
+	return mdl.LocationExternal
+}
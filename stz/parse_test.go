@@ -0,0 +1,266 @@
+package stz_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/kylelemons/godebug/diff"
+	. "goa.design/model/dsl"
+	"goa.design/model/mdl"
+	"goa.design/model/stz"
+)
+
+func TestParseDSL(t *testing.T) {
+	w, err := stz.ParseDSL(filepath.Join("testdata", "workspace.dsl"))
+	if err != nil {
+		t.Fatalf("failed to parse DSL: %s", err)
+	}
+	want := strings.Join([]string{
+		"component Shop/API/Orders: Manages orders. Go",
+		"container Shop/API: Backend. Go [Element,Container]",
+		"container Shop/Web: Web app. React [Element,Container]",
+		"container instance Cloud/Shop/API instance",
+		"container instance Cloud/Shop/Web instance",
+		"deployment node Production/Cloud:   x4",
+		"person Customer: A customer. [Element,Person,Buyer]",
+		"relationship Customer -> Shop/Web: Browses HTTPS (undefined)",
+		"relationship Customer -> Shop: Browses HTTPS (undefined)",
+		"relationship Shop -> Bank: Charges cards  (undefined)",
+		"relationship Shop/API -> Bank: Charges cards  (undefined)",
+		"relationship Shop/Web -> Shop/API/Orders: Places orders JSON/HTTPS (async)",
+		"relationship Shop/Web -> Shop/API: Places orders JSON/HTTPS (async)",
+		"relationship Shop/Web instance -> Shop/API instance: Places orders JSON/HTTPS (async)",
+		"system Bank: External bank. [Element,Software System,External]",
+		"system Shop: The shop. [Element,Software System]",
+		`view containers "": [Bank Customer Shop/API Shop/Web]`,
+		`view context "": [Bank Customer Shop]`,
+		"workspace Shop: An online shop.",
+	}, "\n")
+	if got := summary(w); got != want {
+		t.Errorf("unexpected workspace:\n%s", diff.Diff(want, got))
+	}
+	for _, s := range w.Model.Systems {
+		if s.Name == "Shop" && s.Group != "Internal" {
+			t.Errorf("got group %q for Shop, want %q", s.Group, "Internal")
+		}
+	}
+	if len(w.Views.ContainerViews) != 1 || w.Views.ContainerViews[0].AutoLayout == nil {
+		t.Errorf("got container views %+v, want one view with automatic layout", w.Views.ContainerViews)
+	}
+	fvs := w.Views.FilteredViews
+	if len(fvs) != 1 || fvs[0].BaseKey != "containers" || fvs[0].Key != "buyers" || fvs[0].Mode != "Include" {
+		t.Errorf("got filtered views %+v", fvs)
+	}
+}
+
+func TestParseDSLErrors(t *testing.T) {
+	cases := []struct {
+		Name string
+		File string
+		Want string
+	}{
+		{"include-cycle", "cycle.dsl", "include cycle"},
+		{"unknown-element", "unknown.dsl", `unknown.dsl:4: unknown element "b"`},
+		{"missing-file", "missing.dsl", "no such file"},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			_, err := stz.ParseDSL(filepath.Join("testdata", c.File))
+			if err == nil {
+				t.Fatalf("got no error, want error containing %q", c.Want)
+			}
+			if !strings.Contains(err.Error(), c.Want) {
+				t.Errorf("got error %q, want error containing %q", err.Error(), c.Want)
+			}
+		})
+	}
+}
+
+func TestDSLRoundTrip(t *testing.T) {
+	w := runDSL(t, func() {
+		var user = Person("User", "A user.", func() {
+			Tag("Customer")
+			Uses("Shop", "Buys from", "HTTPS")
+		})
+		var shop = SoftwareSystem("Shop", "The shop.", func() {
+			Container("Web", "Web app.", "React", func() {
+				Uses("API", "Calls", "HTTPS", Synchronous)
+			})
+			Container("API", "Backend.", "Go", func() {
+				Tag("infra")
+				Uses("Queue", "Publishes orders", "AMQP", Asynchronous)
+				Component("Orders", "Manages orders.", "Go")
+				Component("Payments", "Manages payments.", "Go", func() {
+					Uses("Orders", "Reads")
+				})
+			})
+			Container("Queue", "Order queue.", "RabbitMQ")
+		})
+		SoftwareSystem("Bank", "External bank.", func() {
+			External()
+		})
+		DeploymentEnvironment("Production", func() {
+			DeploymentNode("Cloud", "AWS", "Amazon", func() {
+				Instances(2)
+				DeploymentNode("Cluster", "Kubernetes", "EKS", func() {
+					Instances(8)
+					ContainerInstance("Shop/Web")
+					ContainerInstance("Shop/API")
+				})
+				DeploymentNode("Broker", "", "Amazon MQ", func() {
+					ContainerInstance("Shop/Queue")
+				})
+			})
+		})
+		Views(func() {
+			SystemLandscapeView("landscape", "All systems.", func() {
+				AddAll()
+				AutoLayout(RankLeftRight)
+			})
+			SystemContextView(shop, "context", func() {
+				Title("Shop context")
+				Add(user)
+				AddNeighbors(shop)
+			})
+			ContainerView(shop, "containers", func() {
+				AddContainers()
+			})
+			ComponentView("Shop/API", "components", func() {
+				AddComponents()
+			})
+			DeploymentView(Global, "Production", "deployment", func() {
+				AddAll()
+			})
+			Styles(func() {
+				ElementStyle("infra", func() {
+					Background("#ff0000")
+				})
+			})
+		})
+	})
+
+	w.Views.FilteredViews = append(w.Views.FilteredViews, &mdl.FilteredView{
+		Key:         "containers-no-infra",
+		Description: "Containers without infrastructure.",
+		BaseKey:     "containers",
+		Mode:        "Exclude",
+		Tags:        []string{"infra"},
+	})
+
+	parsed := parseDSL(t, w.DSL())
+	if got, want := summary(parsed), summary(w); got != want {
+		t.Errorf("parsed workspace does not match original:\n%s", diff.Diff(want, got))
+	}
+	fvs := parsed.Views.FilteredViews
+	if len(fvs) != 1 {
+		t.Fatalf("got %d filtered views, want 1", len(fvs))
+	}
+	if fv := fvs[0]; fv.BaseKey != "containers" || fv.Key != "containers-no-infra" || fv.Mode != "Exclude" || len(fv.Tags) != 1 || fv.Tags[0] != "infra" {
+		t.Errorf("got filtered view %+v", fv)
+	}
+
+	// Element identifiers are assigned by the parser so compare the second
+	// round trip with the first.
+	src := parsed.DSL()
+	if got := parseDSL(t, src).DSL(); got != src {
+		t.Errorf("workspace does not round trip through the Structurizr DSL:\n%s", diff.Diff(src, got))
+	}
+}
+
+// parseDSL writes src to a temporary file and parses it.
+func parseDSL(t *testing.T, src string) *stz.Workspace {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "workspace.dsl")
+	if err := ioutil.WriteFile(path, []byte(src), 0644); err != nil {
+		t.Fatal(err)
+	}
+	w, err := stz.ParseDSL(path)
+	if err != nil {
+		t.Fatalf("failed to parse DSL: %s\n%s", err, src)
+	}
+	return w
+}
+
+// summary returns a description of the elements, relationships and views of
+// w that does not depend on element identifiers.
+func summary(w *stz.Workspace) string {
+	m := w.Model
+	var lines []string
+	add := func(format string, a ...interface{}) { lines = append(lines, fmt.Sprintf(format, a...)) }
+	names := make(map[string]string)
+	add("workspace %s: %s", w.Name, w.Description)
+	for _, p := range m.People {
+		names[p.ID] = p.Name
+		add("person %s: %s [%s]", p.Name, p.Description, p.Tags)
+	}
+	for _, s := range m.Systems {
+		names[s.ID] = s.Name
+		add("system %s: %s [%s]", s.Name, s.Description, s.Tags)
+		for _, c := range s.Containers {
+			names[c.ID] = s.Name + "/" + c.Name
+			add("container %s: %s %s [%s]", names[c.ID], c.Description, c.Technology, c.Tags)
+			for _, cmp := range c.Components {
+				names[cmp.ID] = names[c.ID] + "/" + cmp.Name
+				add("component %s: %s %s", names[cmp.ID], cmp.Description, cmp.Technology)
+			}
+		}
+	}
+	var addNode func(n *mdl.DeploymentNode)
+	addNode = func(n *mdl.DeploymentNode) {
+		names[n.ID] = n.Name
+		instances := 1
+		if n.Instances != nil {
+			instances = *n.Instances
+		}
+		add("deployment node %s/%s: %s %s x%d", n.Environment, n.Name, n.Description, n.Technology, instances)
+		for _, c := range n.Children {
+			addNode(c)
+		}
+		for _, ci := range n.ContainerInstances {
+			names[ci.ID] = names[ci.ContainerID] + " instance"
+			add("container instance %s/%s", n.Name, names[ci.ID])
+		}
+	}
+	for _, n := range m.DeploymentNodes {
+		addNode(n)
+	}
+	name := func(id string) string { return names[id] }
+	styles := map[mdl.InteractionStyleKind]string{
+		mdl.InteractionUndefined:    "undefined",
+		mdl.InteractionSynchronous:  "sync",
+		mdl.InteractionAsynchronous: "async",
+	}
+	m.IterateRelationships(func(r *mdl.Relationship) {
+		add("relationship %s -> %s: %s %s (%s)", name(r.SourceID), name(r.DestinationID), r.Description, r.Technology, styles[r.InteractionStyle])
+	})
+	var views []*mdl.ViewProps
+	for _, v := range w.Views.LandscapeViews {
+		views = append(views, v.ViewProps)
+	}
+	for _, v := range w.Views.ContextViews {
+		views = append(views, v.ViewProps)
+	}
+	for _, v := range w.Views.ContainerViews {
+		views = append(views, v.ViewProps)
+	}
+	for _, v := range w.Views.ComponentViews {
+		views = append(views, v.ViewProps)
+	}
+	for _, v := range w.Views.DeploymentViews {
+		views = append(views, v.ViewProps)
+	}
+	for _, props := range views {
+		names := make([]string, len(props.ElementViews))
+		for i, ev := range props.ElementViews {
+			names[i] = name(ev.ID)
+		}
+		sort.Strings(names)
+		add("view %s %q: %v", props.Key, props.Title, names)
+	}
+	sort.Strings(lines)
+	return strings.Join(lines, "\n")
+}
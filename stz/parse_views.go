@@ -0,0 +1,618 @@
+package stz
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"goa.design/model/mdl"
+)
+
+// dslView accumulates the content of a view while its block is parsed.
+type dslView struct {
+	props *mdl.ViewProps
+	// kind is the DSL keyword used to declare the view.
+	kind string
+	// scope is the ID of the element in scope if any.
+	scope string
+	// env is the deployment environment of deployment views.
+	env string
+	// in records the IDs of the elements included in the view.
+	in map[string]bool
+	// excluded records the IDs of the relationships excluded from the view.
+	excluded map[string]bool
+	// order is the order of the last relationship added to a dynamic view.
+	order int
+	// enterpriseBoundary is set by the enterpriseBoundary statement.
+	enterpriseBoundary *bool
+}
+
+// viewsStatement parses a single statement of the views block.
+func (p *dslParser) viewsStatement(l *dslLine) error {
+	args := l.args()
+	kw := l.keyword()
+	v := &dslView{kind: kw, props: &mdl.ViewProps{}, in: make(map[string]bool), excluded: make(map[string]bool)}
+	views := p.w.Views
+	var keyIdx int
+	switch kw {
+	case "systemlandscape":
+		keyIdx = 1
+		lv := &mdl.LandscapeView{ViewProps: v.props}
+		views.LandscapeViews = append(views.LandscapeViews, lv)
+		defer func() { lv.EnterpriseBoundaryVisible = v.enterpriseBoundary }()
+	case "systemcontext":
+		keyIdx = 2
+		id, err := p.viewScope(l, arg(args, 1), "software system")
+		if err != nil {
+			return err
+		}
+		v.scope = id
+		cv := &mdl.ContextView{ViewProps: v.props, SoftwareSystemID: id}
+		views.ContextViews = append(views.ContextViews, cv)
+		defer func() { cv.EnterpriseBoundaryVisible = v.enterpriseBoundary }()
+	case "container":
+		keyIdx = 2
+		id, err := p.viewScope(l, arg(args, 1), "software system")
+		if err != nil {
+			return err
+		}
+		v.scope = id
+		views.ContainerViews = append(views.ContainerViews, &mdl.ContainerView{ViewProps: v.props, SoftwareSystemID: id})
+	case "component":
+		keyIdx = 2
+		id, err := p.viewScope(l, arg(args, 1), "container")
+		if err != nil {
+			return err
+		}
+		v.scope = id
+		views.ComponentViews = append(views.ComponentViews, &mdl.ComponentView{ViewProps: v.props, ContainerID: id})
+	case "dynamic":
+		keyIdx = 2
+		if s := arg(args, 1); s != "*" {
+			id, err := p.viewScope(l, s, "")
+			if err != nil {
+				return err
+			}
+			v.scope = id
+		}
+		views.DynamicViews = append(views.DynamicViews, &mdl.DynamicView{ViewProps: v.props, ElementID: v.scope})
+	case "deployment":
+		keyIdx = 3
+		if s := arg(args, 1); s != "*" {
+			id, err := p.viewScope(l, s, "software system")
+			if err != nil {
+				return err
+			}
+			v.scope = id
+		}
+		v.env = arg(args, 2)
+		views.DeploymentViews = append(views.DeploymentViews, &mdl.DeploymentView{ViewProps: v.props, SoftwareSystemID: v.scope, Environment: v.env})
+	case "filtered":
+		fv := &mdl.FilteredView{
+			BaseKey:     arg(args, 1),
+			Mode:        "Include",
+			Key:         arg(args, 4),
+			Description: arg(args, 5),
+		}
+		if strings.ToLower(arg(args, 2)) == "exclude" {
+			fv.Mode = "Exclude"
+		}
+		for _, t := range strings.Split(arg(args, 3), ",") {
+			if t = strings.TrimSpace(t); t != "" {
+				fv.Tags = append(fv.Tags, t)
+			}
+		}
+		if fv.Key == "" {
+			fv.Key = p.viewKey(kw)
+		}
+		views.FilteredViews = append(views.FilteredViews, fv)
+		return p.skip(l)
+	case "styles":
+		return p.block(p.stylesStatement)
+	default:
+		return p.skip(l)
+	}
+	v.props.Key = arg(args, keyIdx)
+	v.props.Description = arg(args, keyIdx+1)
+	if v.props.Key == "" {
+		v.props.Key = p.viewKey(kw)
+	}
+	if l.opens() {
+		if err := p.block(func(l *dslLine) error { return p.viewStatement(v, l) }); err != nil {
+			return err
+		}
+	}
+	if v.kind != "dynamic" {
+		p.w.Model.IterateRelationships(func(r *mdl.Relationship) {
+			if v.in[r.SourceID] && v.in[r.DestinationID] && !v.excluded[r.ID] {
+				v.props.RelationshipViews = append(v.props.RelationshipViews, &mdl.RelationshipView{ID: r.ID})
+			}
+		})
+	}
+	return nil
+}
+
+// viewStatement parses a single statement of a view block.
+func (p *dslParser) viewStatement(v *dslView, l *dslLine) error {
+	args := l.args()
+	switch l.keyword() {
+	case "include":
+		for _, a := range args[1:] {
+			if err := p.include(v, l, a); err != nil {
+				return err
+			}
+		}
+	case "exclude":
+		expr := strings.Join(args[1:], " ")
+		if !strings.Contains(expr, "->") {
+			for _, a := range args[1:] {
+				p.exclude(v, a)
+			}
+			break
+		}
+		p.exclude(v, expr)
+	case "autolayout":
+		al := &mdl.AutoLayout{RankDirection: mdl.RankTopBottom}
+		switch strings.ToLower(arg(args, 1)) {
+		case "bt":
+			al.RankDirection = mdl.RankBottomTop
+		case "lr":
+			al.RankDirection = mdl.RankLeftRight
+		case "rl":
+			al.RankDirection = mdl.RankRightLeft
+		}
+		if n, err := strconv.Atoi(arg(args, 2)); err == nil {
+			al.RankSep = &n
+		}
+		if n, err := strconv.Atoi(arg(args, 3)); err == nil {
+			al.NodeSep = &n
+		}
+		v.props.AutoLayout = al
+	case "animation":
+		return p.block(func(l *dslLine) error {
+			step := &mdl.AnimationStep{Order: len(v.props.Animations) + 1}
+			for _, a := range l.args() {
+				id, ok := p.resolve(a, nil)
+				if !ok {
+					return l.errorf("unknown element %q", a)
+				}
+				step.Elements = append(step.Elements, id)
+			}
+			v.props.Animations = append(v.props.Animations, step)
+			return p.skip(l)
+		})
+	case "enterpriseboundary":
+		v.enterpriseBoundary = boolPtr(arg(args, 1))
+	case "title":
+		v.props.Title = arg(args, 1)
+	case "description":
+		v.props.Description = arg(args, 1)
+	default:
+		if v.kind == "dynamic" {
+			if len(args) == 0 {
+				// Parallel sequence, the relationships are added in order.
+				return p.block(func(l *dslLine) error { return p.viewStatement(v, l) })
+			}
+			return p.dynamicRelationship(v, l, args)
+		}
+		return p.skip(l)
+	}
+	return nil
+}
+
+// include adds the elements described by the given expression to the view.
+func (p *dslParser) include(v *dslView, l *dslLine, expr string) error {
+	if expr == "*" {
+		p.includeDefault(v)
+		return nil
+	}
+	if strings.Contains(expr, "==") || strings.Contains(expr, "->") {
+		return nil // Expressions are not supported.
+	}
+	id, ok := p.resolve(expr, nil)
+	if !ok {
+		return l.errorf("unknown element %q", expr)
+	}
+	if _, ok := p.elements[id]; !ok {
+		if r := p.w.Model.Relationship(id); r != nil {
+			p.add(v, r.SourceID)
+			p.add(v, r.DestinationID)
+		}
+		return nil
+	}
+	p.add(v, id)
+	return nil
+}
+
+// includeDefault adds the elements included by "include *" to the view.
+func (p *dslParser) includeDefault(v *dslView) {
+	m := p.w.Model
+	switch v.kind {
+	case "systemlandscape":
+		for _, e := range m.People {
+			p.add(v, e.ID)
+		}
+		for _, e := range m.Systems {
+			p.add(v, e.ID)
+		}
+	case "systemcontext":
+		p.add(v, v.scope)
+		p.addRelated(v, v.scope, false)
+	case "container":
+		s := p.elements[v.scope].(*mdl.SoftwareSystem)
+		for _, c := range s.Containers {
+			p.add(v, c.ID)
+		}
+		for _, c := range s.Containers {
+			p.addRelated(v, c.ID, false)
+		}
+	case "component":
+		c := p.elements[v.scope].(*mdl.Container)
+		for _, cmp := range c.Components {
+			p.add(v, cmp.ID)
+		}
+		for _, cmp := range c.Components {
+			p.addRelated(v, cmp.ID, true)
+		}
+	case "deployment":
+		for _, n := range m.DeploymentNodes {
+			if n.Environment == v.env {
+				p.addNode(v, n)
+			}
+		}
+	}
+}
+
+// addRelated adds the people and software systems (and containers if
+// containers is true) that have a relationship with the element with the
+// given ID.
+func (p *dslParser) addRelated(v *dslView, id string, containers bool) {
+	p.w.Model.IterateRelationships(func(r *mdl.Relationship) {
+		other := ""
+		switch id {
+		case r.SourceID:
+			other = r.DestinationID
+		case r.DestinationID:
+			other = r.SourceID
+		default:
+			return
+		}
+		switch p.elements[other].(type) {
+		case *mdl.Person, *mdl.SoftwareSystem:
+			p.add(v, other)
+		case *mdl.Container:
+			if containers && other != v.scope {
+				p.add(v, other)
+			}
+		}
+	})
+}
+
+// addNode adds the deployment node and its descendants to the deployment view
+// if they contain instances of containers of the software system in scope.
+// It returns true if the node was added.
+func (p *dslParser) addNode(v *dslView, n *mdl.DeploymentNode) bool {
+	var added bool
+	for _, ci := range n.ContainerInstances {
+		if v.scope == "" || p.parents[ci.ContainerID] == v.scope {
+			p.add(v, ci.ID)
+			added = true
+		}
+	}
+	for _, c := range n.Children {
+		if p.addNode(v, c) {
+			added = true
+		}
+	}
+	if v.scope == "" || added {
+		for _, inf := range n.InfrastructureNodes {
+			p.add(v, inf.ID)
+		}
+		p.add(v, n.ID)
+		return true
+	}
+	return false
+}
+
+// add adds the element with the given ID to the view. Adding a deployment
+// element also adds its parent deployment nodes and adding a deployment node
+// adds its content.
+func (p *dslParser) add(v *dslView, id string) {
+	if v.in[id] {
+		return
+	}
+	v.in[id] = true
+	v.props.ElementViews = append(v.props.ElementViews, &mdl.ElementView{ID: id})
+	switch e := p.elements[id].(type) {
+	case *mdl.DeploymentNode:
+		if v.kind == "deployment" {
+			for _, ci := range e.ContainerInstances {
+				if v.scope == "" || p.parents[ci.ContainerID] == v.scope {
+					p.add(v, ci.ID)
+				}
+			}
+			for _, inf := range e.InfrastructureNodes {
+				p.add(v, inf.ID)
+			}
+			for _, c := range e.Children {
+				p.add(v, c.ID)
+			}
+		}
+	case *mdl.InfrastructureNode, *mdl.ContainerInstance:
+	default:
+		return
+	}
+	for parent, ok := p.parents[id]; ok && !v.in[parent]; parent, ok = p.parents[parent] {
+		v.in[parent] = true
+		v.props.ElementViews = append(v.props.ElementViews, &mdl.ElementView{ID: parent})
+	}
+}
+
+// exclude removes the elements or relationships described by the given
+// expression from the view.
+func (p *dslParser) exclude(v *dslView, expr string) {
+	switch {
+	case strings.Contains(expr, "->"):
+		parts := strings.SplitN(expr, "->", 2)
+		src, dest := strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1])
+		srcID, _ := p.resolve(src, nil)
+		destID, _ := p.resolve(dest, nil)
+		p.w.Model.IterateRelationships(func(r *mdl.Relationship) {
+			if (src == "*" || r.SourceID == srcID) && (dest == "*" || r.DestinationID == destID) {
+				v.excluded[r.ID] = true
+			}
+		})
+	case strings.HasPrefix(strings.ToLower(expr), "element.tag=="):
+		var ids []string
+		for _, t := range strings.Split(expr[len("element.tag=="):], ",") {
+			for _, ev := range v.props.ElementViews {
+				if hasTag(p.elementTags(ev.ID), strings.TrimSpace(t)) {
+					ids = append(ids, ev.ID)
+				}
+			}
+		}
+		for _, id := range ids {
+			p.remove(v, id)
+		}
+	case strings.HasPrefix(strings.ToLower(expr), "relationship.tag=="):
+		for _, t := range strings.Split(expr[len("relationship.tag=="):], ",") {
+			p.w.Model.IterateRelationships(func(r *mdl.Relationship) {
+				if hasTag(r.Tags, strings.TrimSpace(t)) {
+					v.excluded[r.ID] = true
+				}
+			})
+		}
+	default:
+		id, ok := p.resolve(expr, nil)
+		if !ok {
+			return
+		}
+		if _, ok := p.elements[id]; ok {
+			p.remove(v, id)
+		} else {
+			v.excluded[id] = true
+		}
+	}
+}
+
+// remove removes the element with the given ID from the view.
+func (p *dslParser) remove(v *dslView, id string) {
+	delete(v.in, id)
+	evs := v.props.ElementViews[:0]
+	for _, ev := range v.props.ElementViews {
+		if ev.ID != id {
+			evs = append(evs, ev)
+		}
+	}
+	v.props.ElementViews = evs
+}
+
+// elementTags returns the tags of the element with the given ID.
+func (p *dslParser) elementTags(id string) string {
+	switch e := p.elements[id].(type) {
+	case *mdl.Person:
+		return e.Tags
+	case *mdl.SoftwareSystem:
+		return e.Tags
+	case *mdl.Container:
+		return e.Tags
+	case *mdl.Component:
+		return e.Tags
+	case *mdl.DeploymentNode:
+		return e.Tags
+	case *mdl.InfrastructureNode:
+		return e.Tags
+	case *mdl.ContainerInstance:
+		return e.Tags
+	}
+	return ""
+}
+
+// dynamicRelationship parses a relationship statement of a dynamic view:
+// either "source -> destination [description] [technology]" or
+// "identifier [description]".
+func (p *dslParser) dynamicRelationship(v *dslView, l *dslLine, args []string) error {
+	var (
+		r    *mdl.Relationship
+		desc string
+	)
+	if arg(args, 1) == "->" {
+		src, ok := p.resolve(args[0], nil)
+		if !ok {
+			return l.errorf("unknown element %q", args[0])
+		}
+		dest, ok := p.resolve(arg(args, 2), nil)
+		if !ok {
+			return l.errorf("unknown element %q", arg(args, 2))
+		}
+		desc = arg(args, 3)
+		p.w.Model.IterateRelationships(func(rel *mdl.Relationship) {
+			if rel.SourceID == src && rel.DestinationID == dest && (r == nil || rel.Description == desc && r.Description != desc) {
+				r = rel
+			}
+		})
+		if r == nil {
+			return l.errorf("no relationship from %q to %q", args[0], arg(args, 2))
+		}
+	} else {
+		id, ok := p.resolve(args[0], nil)
+		if ok {
+			r = p.w.Model.Relationship(id)
+		}
+		if r == nil {
+			return l.errorf("unknown relationship %q", args[0])
+		}
+		desc = arg(args, 1)
+	}
+	v.order++
+	rv := &mdl.RelationshipView{ID: r.ID, Order: strconv.Itoa(v.order)}
+	if desc != r.Description {
+		rv.Description = desc
+	}
+	v.props.RelationshipViews = append(v.props.RelationshipViews, rv)
+	p.add(v, r.SourceID)
+	p.add(v, r.DestinationID)
+	return p.skip(l)
+}
+
+// stylesStatement parses a single statement of the styles block.
+func (p *dslParser) stylesStatement(l *dslLine) error {
+	views := p.w.Views
+	if views.Configuration == nil {
+		views.Configuration = &Configuration{}
+	}
+	if views.Configuration.Styles == nil {
+		views.Configuration.Styles = &mdl.Styles{}
+	}
+	styles := views.Configuration.Styles
+	tag := arg(l.args(), 1)
+	switch l.keyword() {
+	case "element":
+		es := &mdl.ElementStyle{Tag: tag}
+		styles.Elements = append(styles.Elements, es)
+		return p.block(func(l *dslLine) error {
+			val := arg(l.args(), 1)
+			switch l.keyword() {
+			case "shape":
+				setEnum(&es.Shape, val, "Box", "RoundedBox", "Circle", "Ellipse", "Hexagon", "Cylinder", "Pipe", "Person",
+					"Robot", "Folder", "WebBrowser", "MobileDevicePortrait", "MobileDeviceLandscape", "Component")
+			case "icon":
+				es.Icon = val
+			case "width":
+				es.Width = intPtr(val)
+			case "height":
+				es.Height = intPtr(val)
+			case "background":
+				es.Background = val
+			case "color", "colour":
+				es.Color = val
+			case "stroke":
+				es.Stroke = val
+			case "fontsize":
+				es.FontSize = intPtr(val)
+			case "border":
+				setEnum(&es.Border, val, "Solid", "Dashed", "Dotted")
+			case "opacity":
+				es.Opacity = intPtr(val)
+			case "metadata":
+				es.Metadata = boolPtr(val)
+			case "description":
+				es.Description = boolPtr(val)
+			}
+			return p.skip(l)
+		})
+	case "relationship":
+		rs := &mdl.RelationshipStyle{Tag: tag}
+		styles.Relationships = append(styles.Relationships, rs)
+		return p.block(func(l *dslLine) error {
+			val := arg(l.args(), 1)
+			switch l.keyword() {
+			case "thickness":
+				rs.Thickness = intPtr(val)
+			case "color", "colour":
+				rs.Color = val
+			case "dashed":
+				rs.Dashed = boolPtr(val)
+			case "style":
+				dashed := strings.ToLower(val) != "solid"
+				rs.Dashed = &dashed
+			case "routing":
+				setEnum(&rs.Routing, val, "Direct", "Curved", "Orthogonal")
+			case "fontsize":
+				rs.FontSize = intPtr(val)
+			case "width":
+				rs.Width = intPtr(val)
+			case "position":
+				rs.Position = intPtr(val)
+			case "opacity":
+				rs.Opacity = intPtr(val)
+			}
+			return p.skip(l)
+		})
+	default:
+		return p.skip(l)
+	}
+}
+
+// viewScope returns the ID of the element in scope of a view. kind is the
+// expected kind of element if any.
+func (p *dslParser) viewScope(l *dslLine, ident, kind string) (string, error) {
+	id, ok := p.resolve(ident, nil)
+	if !ok {
+		return "", l.errorf("unknown element %q", ident)
+	}
+	switch p.elements[id].(type) {
+	case *mdl.SoftwareSystem:
+		if kind == "software system" || kind == "" {
+			return id, nil
+		}
+	case *mdl.Container:
+		if kind == "container" || kind == "" {
+			return id, nil
+		}
+	}
+	return "", l.errorf("%q is not a valid view scope", ident)
+}
+
+// viewKey returns a default key for a view of the given kind.
+func (p *dslParser) viewKey(kind string) string {
+	name := map[string]string{
+		"systemlandscape": "SystemLandscape",
+		"systemcontext":   "SystemContext",
+		"container":       "Container",
+		"component":       "Component",
+		"dynamic":         "Dynamic",
+		"deployment":      "Deployment",
+		"filtered":        "Filtered",
+	}[kind]
+	p.keys[name]++
+	return fmt.Sprintf("%s-%03d", name, p.keys[name])
+}
+
+// setEnum sets the enum value v using the name that matches val ignoring
+// case.
+func setEnum(v json.Unmarshaler, val string, names ...string) {
+	for _, n := range names {
+		if strings.EqualFold(n, val) {
+			v.UnmarshalJSON([]byte(strconv.Quote(n)))
+			return
+		}
+	}
+}
+
+// intPtr returns a pointer to the integer value of s or nil if s is not an
+// integer.
+func intPtr(s string) *int {
+	i, err := strconv.Atoi(s)
+	if err != nil {
+		return nil
+	}
+	return &i
+}
+
+// boolPtr returns a pointer to the boolean value of s.
+func boolPtr(s string) *bool {
+	b := strings.ToLower(s) != "false"
+	return &b
+}
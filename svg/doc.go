@@ -0,0 +1,16 @@
+/*
+Package svg renders the views of a software architecture design as SVG
+documents without requiring a browser.
+
+Render lays out the elements of each view, draws them using the shapes and
+styles defined in the design and draws the relationships between them.
+Elements are positioned using, in order of precedence, the positions given
+in the layout passed to Render (typically the layout saved by the graphical
+editor), the positions defined in the design and finally an automatic
+layered layout that honors the automatic layout settings of the view.
+
+The resulting SVG documents embed the view metadata and element positions in
+a JSON script block identical to the one produced by the graphical editor so
+that the editor can load them and the layouts can be refined interactively.
+*/
+package svg
@@ -0,0 +1,290 @@
+package svg
+
+import (
+	"sort"
+
+	"goa.design/model/mdl"
+)
+
+const (
+	// defaultRankSep is the default separation between ranks in pixels.
+	defaultRankSep = 200
+	// defaultNodeSep is the default separation between nodes in pixels.
+	defaultNodeSep = 150
+	// sweeps is the number of ordering passes used to reduce crossings.
+	sweeps = 4
+)
+
+// autoLayout computes the positions of the given nodes using a simple
+// layered layout: nodes are assigned ranks so that relationships point to
+// higher ranks whenever possible, nodes in each rank are then ordered to
+// reduce crossings and finally positioned according to the rank direction
+// and separations given in l.
+func autoLayout(nodes []*node, edges []*edge, l *mdl.AutoLayout) {
+	if len(nodes) == 0 {
+		return
+	}
+	index := make(map[string]int, len(nodes))
+	for i, n := range nodes {
+		index[n.id] = i
+	}
+	succ := make([][]int, len(nodes))
+	pred := make([][]int, len(nodes))
+	for _, e := range edges {
+		from, ok := index[e.from]
+		if !ok {
+			continue
+		}
+		to, ok := index[e.to]
+		if !ok || from == to {
+			continue
+		}
+		succ[from] = append(succ[from], to)
+	}
+
+	// Remove cycles by ignoring back edges found during a depth first
+	// traversal.
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make([]int, len(nodes))
+	dag := make([][]int, len(nodes))
+	var visit func(i int)
+	visit = func(i int) {
+		state[i] = visiting
+		for _, j := range succ[i] {
+			if state[j] == visiting {
+				continue
+			}
+			dag[i] = append(dag[i], j)
+			pred[j] = append(pred[j], i)
+			if state[j] == unvisited {
+				visit(j)
+			}
+		}
+		state[i] = visited
+	}
+	for i := range nodes {
+		if state[i] == unvisited {
+			visit(i)
+		}
+	}
+
+	// Assign ranks using the longest path from the sources.
+	ranks := make([]int, len(nodes))
+	indegree := make([]int, len(nodes))
+	for i := range nodes {
+		indegree[i] = len(pred[i])
+	}
+	var queue []int
+	for i := range nodes {
+		if indegree[i] == 0 {
+			queue = append(queue, i)
+		}
+	}
+	maxRank := 0
+	for len(queue) > 0 {
+		i := queue[0]
+		queue = queue[1:]
+		for _, j := range dag[i] {
+			if ranks[i]+1 > ranks[j] {
+				ranks[j] = ranks[i] + 1
+			}
+			indegree[j]--
+			if indegree[j] == 0 {
+				queue = append(queue, j)
+			}
+		}
+		if ranks[i] > maxRank {
+			maxRank = ranks[i]
+		}
+	}
+
+	// Order nodes within each rank, keeping nodes that belong to the same
+	// group together initially then using the barycenter heuristic.
+	layers := make([][]int, maxRank+1)
+	for i := range nodes {
+		layers[ranks[i]] = append(layers[ranks[i]], i)
+	}
+	pos := make([]float64, len(nodes))
+	for _, layer := range layers {
+		sort.SliceStable(layer, func(a, b int) bool {
+			return nodes[layer[a]].group < nodes[layer[b]].group
+		})
+		for p, i := range layer {
+			pos[i] = float64(p)
+		}
+	}
+	for s := 0; s < sweeps; s++ {
+		down := s%2 == 0
+		for r := range layers {
+			layer := layers[r]
+			if !down {
+				layer = layers[len(layers)-1-r]
+			}
+			bary := make(map[int]float64, len(layer))
+			for _, i := range layer {
+				adj := pred[i]
+				if !down {
+					adj = dag[i]
+				}
+				if len(adj) == 0 {
+					bary[i] = pos[i]
+					continue
+				}
+				var sum float64
+				for _, j := range adj {
+					sum += pos[j]
+				}
+				bary[i] = sum / float64(len(adj))
+			}
+			sort.SliceStable(layer, func(a, b int) bool { return bary[layer[a]] < bary[layer[b]] })
+			for p, i := range layer {
+				pos[i] = float64(p)
+			}
+		}
+	}
+
+	// Compute coordinates.
+	var (
+		dir     = mdl.RankTopBottom
+		rankSep = defaultRankSep
+		nodeSep = defaultNodeSep
+	)
+	if l != nil {
+		if l.RankDirection != mdl.RankUndefined {
+			dir = l.RankDirection
+		}
+		if l.RankSep != nil {
+			rankSep = *l.RankSep
+		}
+		if l.NodeSep != nil {
+			nodeSep = *l.NodeSep
+		}
+	}
+	horizontal := dir == mdl.RankLeftRight || dir == mdl.RankRightLeft
+	reverse := dir == mdl.RankBottomTop || dir == mdl.RankRightLeft
+	// along returns the size of a node along the rank axis, across its size
+	// along the axis orthogonal to ranks.
+	along := func(n *node) float64 {
+		if horizontal {
+			return float64(n.width)
+		}
+		return float64(n.height)
+	}
+	across := func(n *node) float64 {
+		if horizontal {
+			return float64(n.height)
+		}
+		return float64(n.width)
+	}
+	var maxWidth float64
+	widths := make([]float64, len(layers))
+	for r, layer := range layers {
+		for p, i := range layer {
+			if p > 0 {
+				widths[r] += float64(nodeSep)
+			}
+			widths[r] += across(nodes[i])
+		}
+		if widths[r] > maxWidth {
+			maxWidth = widths[r]
+		}
+	}
+	var offset float64
+	for k := range layers {
+		r := k
+		if reverse {
+			r = len(layers) - 1 - k
+		}
+		layer := layers[r]
+		var thickness float64
+		for _, i := range layer {
+			if t := along(nodes[i]); t > thickness {
+				thickness = t
+			}
+		}
+		c := (maxWidth - widths[r]) / 2
+		for _, i := range layer {
+			n := nodes[i]
+			a := offset + thickness/2
+			b := c + across(n)/2
+			if horizontal {
+				n.x, n.y = a, b
+			} else {
+				n.x, n.y = b, a
+			}
+			c += across(n) + float64(nodeSep)
+		}
+		offset += thickness + float64(rankSep)
+	}
+}
+
+type (
+	// ViewLayout describes the positions and sizes of the elements of a view
+	// once laid out.
+	ViewLayout struct {
+		// Elements lists the boxes of the elements rendered as shapes
+		// indexed by element ID.
+		Elements map[string]*Box
+		// Boundaries lists the elements rendered as boundaries around other
+		// elements, outermost boundaries first.
+		Boundaries []*Boundary
+	}
+
+	// Box describes the position and size of a rectangular area. X and Y are
+	// the coordinates of the top left corner.
+	Box struct {
+		X, Y, Width, Height float64
+	}
+
+	// Boundary describes a boundary drawn around a set of elements.
+	Boundary struct {
+		Box
+		// ID of the element rendered as a boundary. The boundary of the
+		// enterprise (in system landscape and system context views) has
+		// ID "__enterprise__". The IDs of element group boundaries are of
+		// the form "group:<parent ID>/<group name>".
+		ID string
+		// Name of boundary.
+		Name string
+		// ParentID is the ID of the enclosing boundary if any.
+		ParentID string
+	}
+)
+
+// LayoutView lays out the given view of the given design the same way
+// RenderView does. It makes it possible for other renderers to reuse the
+// positions saved by the graphical editor and the automatic layout.
+func LayoutView(d *mdl.Design, v mdl.View, layout Layout) *ViewLayout {
+	a := arrange(d, v, layout)
+	res := &ViewLayout{Elements: make(map[string]*Box, len(a.nodes))}
+	for _, n := range a.nodes {
+		w, h := float64(n.width), float64(n.height)
+		res.Elements[n.id] = &Box{X: n.x - w/2, Y: n.y - h/2, Width: w, Height: h}
+	}
+	gs := make([]*group, 0, len(a.groups))
+	for _, g := range a.groups {
+		if g.x1 > g.x0 {
+			gs = append(gs, g)
+		}
+	}
+	sort.Slice(gs, func(i, j int) bool {
+		di, dj := depth(a.groups, gs[i]), depth(a.groups, gs[j])
+		if di != dj {
+			return di < dj
+		}
+		return gs[i].id < gs[j].id
+	})
+	for _, g := range gs {
+		res.Boundaries = append(res.Boundaries, &Boundary{
+			Box:      Box{X: g.x0, Y: g.y0, Width: g.x1 - g.x0, Height: g.y1 - g.y0},
+			ID:       g.id,
+			Name:     g.name,
+			ParentID: g.parent,
+		})
+	}
+	return res
+}
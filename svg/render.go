@@ -0,0 +1,813 @@
+package svg
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"html"
+	"math"
+	"sort"
+	"strings"
+
+	"goa.design/model/mdl"
+)
+
+type (
+	// Layout lists the positions of the centers of the elements of a view
+	// indexed by element ID.
+	Layout map[string]*Position
+
+	// Position is the position of the center of an element.
+	Position struct {
+		// X is the horizontal coordinate.
+		X float64 `json:"x"`
+		// Y is the vertical coordinate.
+		Y float64 `json:"y"`
+	}
+
+	// node is an element rendered as a shape.
+	node struct {
+		id, title, sub, desc string
+		style                *mdl.ElementStyle
+		width, height        int
+		fontSize             int
+		group                string
+		x, y                 float64
+	}
+
+	// edge is a relationship rendered as a line.
+	edge struct {
+		id, from, to, label string
+		style               *mdl.RelationshipStyle
+		routing             mdl.RoutingKind
+	}
+
+	// arrangement is the result of laying out a view.
+	arrangement struct {
+		nodes     []*node
+		nodesByID map[string]*node
+		edges     []*edge
+		groups    map[string]*group
+	}
+
+	// group is an element or an element group rendered as a boundary around
+	// other elements.
+	group struct {
+		id, name string
+		parent   string
+		style    *mdl.ElementStyle
+		// bounding box computed from the content
+		x0, y0, x1, y1 float64
+	}
+
+	// metadata is the view information embedded in the SVG document, it
+	// has the same structure as the one written by the graphical editor.
+	metadata struct {
+		Name        string             `json:"name"`
+		Description string             `json:"description,omitempty"`
+		Version     string             `json:"version,omitempty"`
+		Elements    []*elementMetadata `json:"elements"`
+		Layout      map[string]*point  `json:"layout"`
+	}
+
+	// elementMetadata is the element information embedded in the SVG
+	// document.
+	elementMetadata struct {
+		ID           string             `json:"id"`
+		Tags         string             `json:"tags,omitempty"`
+		Owner        string             `json:"owner,omitempty"`
+		Location     mdl.LocationKind   `json:"location,omitempty"`
+		Properties   map[string]string  `json:"properties,omitempty"`
+		Perspectives []*mdl.Perspective `json:"perspectives,omitempty"`
+	}
+
+	// point is a position rounded to the nearest pixel.
+	point struct {
+		X int `json:"x"`
+		Y int `json:"y"`
+	}
+)
+
+const (
+	// margin is the space left around the diagram in pixels.
+	margin = 50
+	// groupPadding is the space between a group boundary and its content.
+	groupPadding = 25
+	// groupFontSize is the font size used to render group names.
+	groupFontSize = 20
+	// labelWidth is the default width of relationship labels.
+	labelWidth = 200
+)
+
+var (
+	// defaultElementStyle is the style applied to elements that do not
+	// override it, it matches the graphical editor defaults.
+	defaultElementStyle = &mdl.ElementStyle{
+		Background: "#ffffff",
+		Color:      "#666666",
+		Stroke:     "#999999",
+		Shape:      mdl.ShapeBox,
+	}
+
+	// defaultRelationshipStyle is the style applied to relationships that
+	// do not override it, it matches the graphical editor defaults.
+	defaultRelationshipStyle = &mdl.RelationshipStyle{
+		Color: "#999999",
+	}
+)
+
+// Render renders all the views of the given design. layouts contains the
+// positions of the elements of each view indexed by view key, it may be nil.
+// Render returns the SVG documents indexed by view key.
+func Render(d *mdl.Design, layouts map[string]Layout) map[string][]byte {
+	res := make(map[string][]byte)
+	if d.Views == nil {
+		return res
+	}
+	for _, v := range d.Views.All() {
+		key := v.Props().Key
+		res[key] = RenderView(d, v, layouts[key])
+	}
+	return res
+}
+
+// RenderView renders the given view of the given design using the element
+// positions given in layout if any and returns the corresponding SVG
+// document.
+func RenderView(d *mdl.Design, v mdl.View, layout Layout) []byte {
+	a := arrange(d, v, layout)
+	r := &renderer{buf: &bytes.Buffer{}}
+	r.render(d, v.Props(), a.nodes, a.nodesByID, a.edges, a.groups)
+	return r.buf.Bytes()
+}
+
+// arrange computes the nodes, edges and groups used to render the given view
+// and positions them.
+func arrange(d *mdl.Design, v mdl.View, layout Layout) *arrangement {
+	var styles *mdl.Styles
+	if d.Views != nil {
+		styles = d.Views.Styles
+	}
+	props := v.Props()
+	groups := viewGroups(d, v)
+
+	// Build nodes.
+	var nodes []*node
+	nodesByID := make(map[string]*node)
+	autoLayoutNeeded := false
+	for _, ev := range props.ElementViews {
+		if _, ok := groups[ev.ID]; ok {
+			continue
+		}
+		n := newNode(d, styles, ev.ID)
+		if n == nil {
+			continue
+		}
+		if p := d.Model.Parent(ev.ID); p != nil {
+			if _, ok := groups[idOf(p)]; ok {
+				n.group = idOf(p)
+			}
+		}
+		if n.group == "" && groups["__enterprise__"] != nil && isInternal(d.Model.Element(ev.ID)) {
+			n.group = "__enterprise__"
+		}
+		if name := d.Model.Group(ev.ID); name != "" {
+			// Element groups are rendered inside the boundary of the
+			// grouped elements.
+			gid := "group:" + idOf(d.Model.Parent(ev.ID)) + "/" + name
+			if _, ok := groups[gid]; !ok {
+				groups[gid] = &group{id: gid, name: name, parent: n.group}
+			}
+			n.group = gid
+		}
+		switch {
+		case layout[ev.ID] != nil:
+			n.x, n.y = layout[ev.ID].X, layout[ev.ID].Y
+		case ev.X != nil && ev.Y != nil:
+			n.x = float64(*ev.X) + float64(n.width)/2
+			n.y = float64(*ev.Y) + float64(n.height)/2
+		default:
+			autoLayoutNeeded = true
+		}
+		nodes = append(nodes, n)
+		nodesByID[n.id] = n
+	}
+
+	// Build edges.
+	var edges []*edge
+	_, dynamic := v.(*mdl.DynamicView)
+	for _, rv := range props.RelationshipViews {
+		r := d.Model.Relationship(rv.ID)
+		if r == nil || nodesByID[r.SourceID] == nil || nodesByID[r.DestinationID] == nil {
+			continue
+		}
+		label := r.Description
+		if rv.Description != "" {
+			label = rv.Description
+		}
+		if dynamic && rv.Order != "" {
+			label = rv.Order + ": " + label
+		}
+		if r.Technology != "" {
+			label += "\n[" + r.Technology + "]"
+		}
+		style := styles.RelationshipStyle(r.Tags)
+		if style == nil {
+			style = &mdl.RelationshipStyle{}
+		}
+		routing := style.Routing
+		if rv.Routing != mdl.RoutingUndefined {
+			routing = rv.Routing
+		}
+		edges = append(edges, &edge{
+			id:      r.ID,
+			from:    r.SourceID,
+			to:      r.DestinationID,
+			label:   label,
+			style:   style,
+			routing: routing,
+		})
+	}
+
+	// Compute positions if needed, preserving the positions that are set.
+	if autoLayoutNeeded {
+		saved := make(map[string]Position)
+		for _, ev := range props.ElementViews {
+			if n := nodesByID[ev.ID]; n != nil && (layout[ev.ID] != nil || ev.X != nil && ev.Y != nil) {
+				saved[n.id] = Position{n.x, n.y}
+			}
+		}
+		autoLayout(nodes, edges, props.AutoLayout)
+		for id, p := range saved {
+			nodesByID[id].x, nodesByID[id].y = p.X, p.Y
+		}
+	}
+	computeGroupBounds(groups, nodes)
+
+	return &arrangement{nodes: nodes, nodesByID: nodesByID, edges: edges, groups: groups}
+}
+
+// renderer writes SVG elements.
+type renderer struct {
+	buf *bytes.Buffer
+}
+
+func (r *renderer) render(d *mdl.Design, props *mdl.ViewProps, nodes []*node, nodesByID map[string]*node, edges []*edge, groups map[string]*group) {
+	// Compute bounding box.
+	x0, y0, x1, y1 := math.Inf(1), math.Inf(1), math.Inf(-1), math.Inf(-1)
+	extend := func(ax0, ay0, ax1, ay1 float64) {
+		x0, y0 = math.Min(x0, ax0), math.Min(y0, ay0)
+		x1, y1 = math.Max(x1, ax1), math.Max(y1, ay1)
+	}
+	for _, n := range nodes {
+		extend(n.x-float64(n.width)/2, n.y-float64(n.height)/2, n.x+float64(n.width)/2, n.y+float64(n.height)/2)
+	}
+	for _, g := range groups {
+		if g.x1 > g.x0 {
+			extend(g.x0, g.y0, g.x1, g.y1)
+		}
+	}
+	if math.IsInf(x0, 1) {
+		x0, y0, x1, y1 = 0, 0, 0, 0
+	}
+	x0, y0, x1, y1 = x0-margin, y0-margin, x1+margin, y1+margin
+	title := props.Title
+	if title == "" {
+		title = props.Key
+	}
+	titleY := y0 - 20
+	y0 -= 60
+
+	r.line(`<svg xmlns="http://www.w3.org/2000/svg" id="graph" width="%d" height="%d" viewBox="%d %d %d %d">`,
+		int(x1-x0), int(y1-y0), int(x0), int(y0), int(x1-x0), int(y1-y0))
+
+	// Metadata used by the graphical editor.
+	md := &metadata{
+		Name:        title,
+		Description: props.Description,
+		Version:     d.Version,
+		Elements:    []*elementMetadata{},
+		Layout:      make(map[string]*point),
+	}
+	for _, n := range nodes {
+		em := &elementMetadata{ID: n.id}
+		switch e := d.Model.Element(n.id).(type) {
+		case *mdl.Person:
+			em.Tags, em.Location, em.Properties, em.Perspectives = e.Tags, e.Location, e.Properties, e.Perspectives
+		case *mdl.SoftwareSystem:
+			em.Tags, em.Location, em.Properties, em.Perspectives = e.Tags, e.Location, e.Properties, e.Perspectives
+		case *mdl.Container:
+			em.Tags, em.Properties, em.Perspectives = e.Tags, e.Properties, e.Perspectives
+		case *mdl.Component:
+			em.Tags, em.Properties, em.Perspectives = e.Tags, e.Properties, e.Perspectives
+		case *mdl.CodeElement:
+			em.Tags, em.Properties, em.Perspectives = e.Tags, e.Properties, e.Perspectives
+		case *mdl.DeploymentNode:
+			em.Tags, em.Properties, em.Perspectives = e.Tags, e.Properties, e.Perspectives
+		case *mdl.InfrastructureNode:
+			em.Tags, em.Properties, em.Perspectives = e.Tags, e.Properties, e.Perspectives
+		case *mdl.ContainerInstance:
+			em.Tags, em.Properties, em.Perspectives = e.Tags, e.Properties, e.Perspectives
+		}
+		if t := d.Model.Owner(n.id); t != nil {
+			em.Owner = t.Name
+		}
+		md.Elements = append(md.Elements, em)
+		md.Layout[n.id] = &point{int(math.Round(n.x)), int(math.Round(n.y))}
+	}
+	b, _ := json.MarshalIndent(md, "", "  ")
+	r.line(`<script type="application/json"><![CDATA[%s]]></script>`, strings.ReplaceAll(string(b), "]]>", "]]]]><![CDATA[>"))
+
+	r.line(`<defs><marker id="arrow" viewBox="0 0 10 10" refX="10" refY="5" markerUnits="userSpaceOnUse" markerWidth="20" markerHeight="20" orient="auto"><path d="M 0 0 L 10 5 L 0 10 z" fill="context-stroke"/></marker></defs>`)
+	r.line(`<text x="%d" y="%d" font-family="Arial, sans-serif" font-size="32" font-weight="bold" fill="#333333">%s</text>`,
+		int(x0+margin), int(titleY), esc(title))
+
+	// Groups, outermost first.
+	gs := make([]*group, 0, len(groups))
+	for _, g := range groups {
+		if g.x1 > g.x0 {
+			gs = append(gs, g)
+		}
+	}
+	sort.Slice(gs, func(i, j int) bool {
+		di, dj := depth(groups, gs[i]), depth(groups, gs[j])
+		if di != dj {
+			return di < dj
+		}
+		return gs[i].id < gs[j].id
+	})
+	for _, g := range gs {
+		r.renderGroup(g)
+	}
+
+	// Edges then nodes so that arrows are not hidden by labels.
+	counts := make(map[string]int)
+	for _, e := range edges {
+		counts[e.from+"/"+e.to]++
+	}
+	indices := make(map[string]int)
+	for _, e := range edges {
+		k := e.from + "/" + e.to
+		r.renderEdge(e, nodesByID[e.from], nodesByID[e.to], indices[k], counts[k])
+		indices[k]++
+	}
+	for _, n := range nodes {
+		r.renderNode(n)
+	}
+	r.line("</svg>")
+}
+
+func (r *renderer) renderGroup(g *group) {
+	stroke, color, fill := "#999999", "#666666", "none"
+	if g.style != nil {
+		if g.style.Stroke != "" {
+			stroke = g.style.Stroke
+		}
+		if g.style.Color != "" {
+			color = g.style.Color
+		}
+		if g.style.Background != "" {
+			fill = g.style.Background
+		}
+	}
+	r.line(`<g class="group" id="%s">`, esc(g.id))
+	r.line(`<rect x="%.1f" y="%.1f" width="%.1f" height="%.1f" rx="5" ry="5" fill="%s" fill-opacity="0.1" stroke="%s" stroke-width="2" stroke-dasharray="8 4"/>`,
+		g.x0, g.y0, g.x1-g.x0, g.y1-g.y0, esc(fill), esc(stroke))
+	r.line(`<text x="%.1f" y="%.1f" font-family="Arial, sans-serif" font-size="%d" fill="%s">%s</text>`,
+		g.x0+groupPadding, g.y1-groupFontSize/2, groupFontSize, esc(color), esc(g.name))
+	r.line("</g>")
+}
+
+func (r *renderer) renderNode(n *node) {
+	s := n.style
+	w, h := float64(n.width), float64(n.height)
+	opacity := 0.9
+	if s.Opacity != nil {
+		opacity = float64(*s.Opacity) / 100
+	}
+	attrs := fmt.Sprintf(`fill="%s" stroke="%s" stroke-width="%.1f" opacity="%.2f"`, esc(s.Background), esc(s.Stroke), w/70, opacity)
+	switch s.Border {
+	case mdl.BorderDashed:
+		attrs += ` stroke-dasharray="10 5"`
+	case mdl.BorderDotted:
+		attrs += ` stroke-dasharray="2 3"`
+	}
+	r.line(`<g class="node" id="%s" transform="translate(%.1f,%.1f)">`, esc(n.id), n.x, n.y)
+	shape, decorations, labelOffset := shapeElements(s.Shape, w, h)
+	r.line(strings.Replace(shape, "/>", " "+attrs+"/>", 1))
+	for _, dec := range decorations {
+		r.line(strings.Replace(dec, "/>", fmt.Sprintf(` fill="none" stroke="%s" stroke-width="%.1f"/>`, esc(s.Stroke), w/70), 1))
+	}
+
+	// Text is vertically centered in the shape (minus the label offset).
+	fs := float64(n.fontSize)
+	titleLines := wrap(n.title, w-40, fs, true)
+	descLines := wrap(n.desc, w-40, fs, false)
+	total := float64(len(titleLines))*(fs+2) + 0.75*fs + 20 + float64(len(descLines))*(fs+2)
+	y := labelOffset/2 - total/2
+	r.line(`<g font-family="Arial, sans-serif" text-anchor="middle" fill="%s">`, esc(s.Color))
+	for _, l := range titleLines {
+		y += fs + 2
+		r.line(`<text x="0" y="%.1f" font-size="%dpx" font-weight="bold">%s</text>`, y, n.fontSize, esc(l))
+	}
+	y += 0.75*fs + 4
+	r.line(`<text x="0" y="%.1f" font-size="%.1fpx">[%s]</text>`, y, 0.75*fs, esc(n.sub))
+	y += 16
+	for _, l := range descLines {
+		y += fs + 2
+		r.line(`<text x="0" y="%.1f" font-size="%dpx">%s</text>`, y, n.fontSize, esc(l))
+	}
+	r.line("</g>")
+	r.line("</g>")
+}
+
+func (r *renderer) renderEdge(e *edge, from, to *node, index, count int) {
+	color := defaultRelationshipStyle.Color
+	if e.style.Color != "" {
+		color = e.style.Color
+	}
+	thickness := 3
+	if e.style.Thickness != nil {
+		thickness = *e.style.Thickness
+	}
+	fontSize := 22
+	if e.style.FontSize != nil {
+		fontSize = *e.style.FontSize
+	}
+	width := labelWidth
+	if e.style.Width != nil {
+		width = *e.style.Width
+	}
+	opacity := 1.0
+	if e.style.Opacity != nil {
+		opacity = float64(*e.style.Opacity) / 100
+	}
+	dashed := e.style.Dashed == nil || *e.style.Dashed
+
+	// Compute the intermediate vertices, edges with the same source and
+	// destination are spread apart.
+	var vertices []Position
+	if count > 1 {
+		spread := float64(index) - float64(count-1)/2
+		mx, my := (from.x+to.x)/2, (from.y+to.y)/2
+		if math.Abs(from.x-to.x) > math.Abs(from.y-to.y) {
+			my += spread * 70
+		} else {
+			mx += spread * 200
+		}
+		vertices = append(vertices, Position{mx, my})
+	} else if e.routing == mdl.RoutingOrthogonal {
+		if math.Abs(to.x-from.x) > math.Abs(to.y-from.y) {
+			vertices = append(vertices, Position{from.x, to.y})
+		} else {
+			vertices = append(vertices, Position{to.x, from.y})
+		}
+	}
+	first, last := Position{to.x, to.y}, Position{from.x, from.y}
+	if len(vertices) > 0 {
+		first, last = vertices[0], vertices[len(vertices)-1]
+	}
+	pts := []Position{from.intersect(first)}
+	pts = append(pts, vertices...)
+	pts = append(pts, to.intersect(last))
+
+	var d strings.Builder
+	for i, p := range pts {
+		if i == 0 {
+			fmt.Fprintf(&d, "M %.1f %.1f", p.X, p.Y)
+		} else {
+			fmt.Fprintf(&d, " L %.1f %.1f", p.X, p.Y)
+		}
+	}
+	attrs := fmt.Sprintf(`fill="none" stroke="%s" stroke-width="%d" opacity="%.2f" marker-end="url(#arrow)"`, esc(color), thickness, opacity)
+	if dashed {
+		attrs += ` stroke-dasharray="10 8"`
+	}
+	r.line(`<g class="edge" id="%s" data-from="%s" data-to="%s">`, esc(e.id), esc(e.from), esc(e.to))
+	r.line(`<path d="%s" %s/>`, d.String(), attrs)
+
+	// Label
+	if e.label != "" {
+		pos := 50
+		if e.style.Position != nil {
+			pos = *e.style.Position
+		}
+		lp := along(pts, float64(pos)/100)
+		if len(vertices) > 0 && count > 1 {
+			lp = vertices[0]
+		}
+		fs := float64(fontSize)
+		var lines []string
+		for _, part := range strings.Split(e.label, "\n") {
+			lines = append(lines, wrap(part, float64(width), fs, false)...)
+		}
+		var maxW float64
+		for _, l := range lines {
+			maxW = math.Max(maxW, textWidth(l, fs, false))
+		}
+		h := float64(len(lines)) * (fs + 2)
+		r.line(`<rect x="%.1f" y="%.1f" width="%.1f" height="%.1f" fill="#ffffff" fill-opacity="0.8"/>`,
+			lp.X-maxW/2-fs/2, lp.Y-h/2-4, maxW+fs, h+8)
+		y := lp.Y - h/2
+		for _, l := range lines {
+			y += fs + 2
+			r.line(`<text x="%.1f" y="%.1f" font-family="Arial, sans-serif" font-size="%dpx" text-anchor="middle" fill="%s">%s</text>`,
+				lp.X, y-4, fontSize, esc(color), esc(l))
+		}
+	}
+	r.line("</g>")
+}
+
+func (r *renderer) line(format string, a ...interface{}) {
+	fmt.Fprintf(r.buf, format+"\n", a...)
+}
+
+// newNode creates a node for the element with the given ID.
+func newNode(d *mdl.Design, styles *mdl.Styles, id string) *node {
+	var name, tech, desc, tags string
+	switch e := d.Model.Element(id).(type) {
+	case *mdl.Person:
+		name, desc, tags = e.Name, e.Description, e.Tags
+	case *mdl.SoftwareSystem:
+		name, desc, tags = e.Name, e.Description, e.Tags
+	case *mdl.Container:
+		name, tech, desc, tags = e.Name, e.Technology, e.Description, e.Tags
+	case *mdl.Component:
+		name, tech, desc, tags = e.Name, e.Technology, e.Description, e.Tags
+	case *mdl.CodeElement:
+		name, tech, desc, tags = e.Name, e.Technology, e.Description, e.Tags
+	case *mdl.DeploymentNode:
+		name, tech, desc, tags = e.Name, e.Technology, e.Description, e.Tags
+	case *mdl.InfrastructureNode:
+		name, tech, desc, tags = e.Name, e.Technology, e.Description, e.Tags
+	case *mdl.ContainerInstance:
+		c, ok := d.Model.Element(e.ContainerID).(*mdl.Container)
+		if !ok {
+			return nil
+		}
+		name, tech, desc, tags = c.Name, c.Technology, c.Description, c.Tags
+	default:
+		return nil
+	}
+	ts := strings.Split(tags, ",")
+	sub := ts[len(ts)-1]
+	if tech != "" {
+		sub += ": " + tech
+	}
+	style := &mdl.ElementStyle{}
+	*style = *defaultElementStyle
+	if es := styles.ElementStyle(tags); es != nil {
+		style.Merge(es)
+	}
+	n := &node{id: id, title: name, sub: sub, desc: desc, style: style, width: 300, height: 300, fontSize: 22}
+	if style.Width != nil {
+		n.width = *style.Width
+	}
+	if style.Height != nil {
+		n.height = *style.Height
+	}
+	if style.FontSize != nil {
+		n.fontSize = *style.FontSize
+	}
+	return n
+}
+
+// viewGroups returns the elements of the view rendered as boundaries around
+// their children indexed by ID.
+func viewGroups(d *mdl.Design, v mdl.View) map[string]*group {
+	var (
+		m      = d.Model
+		props  = v.Props()
+		res    = make(map[string]*group)
+		inView = make(map[string]bool)
+	)
+	for _, ev := range props.ElementViews {
+		inView[ev.ID] = true
+	}
+	add := func(id string) {
+		if _, ok := res[id]; ok {
+			return
+		}
+		g := &group{id: id}
+		switch e := m.Element(id).(type) {
+		case *mdl.SoftwareSystem:
+			g.name = e.Name
+		case *mdl.Container:
+			g.name = e.Name
+		case *mdl.Component:
+			g.name = e.Name
+		case *mdl.DeploymentNode:
+			g.name = e.Name
+			if e.Technology != "" {
+				g.name += " [" + e.Technology + "]"
+			}
+			if e.Instances != nil && *e.Instances > 1 {
+				g.name += fmt.Sprintf(" x%d", *e.Instances)
+			}
+			g.style = d.Views.Styles.ElementStyle(e.Tags)
+		}
+		res[id] = g
+	}
+	scoped := func(scope string, boundaries *bool) {
+		for _, ev := range props.ElementViews {
+			p := m.Parent(ev.ID)
+			if p == nil {
+				continue
+			}
+			pid := idOf(p)
+			if pid == scope && !inView[pid] || boundaries != nil && *boundaries {
+				add(pid)
+			}
+		}
+	}
+	switch vv := v.(type) {
+	case *mdl.LandscapeView:
+		if m.Enterprise != nil && (vv.EnterpriseBoundaryVisible == nil || *vv.EnterpriseBoundaryVisible) {
+			res["__enterprise__"] = &group{id: "__enterprise__", name: m.Enterprise.Name}
+		}
+	case *mdl.ContextView:
+		if m.Enterprise != nil && (vv.EnterpriseBoundaryVisible == nil || *vv.EnterpriseBoundaryVisible) {
+			res["__enterprise__"] = &group{id: "__enterprise__", name: m.Enterprise.Name}
+		}
+	case *mdl.ContainerView:
+		scoped(vv.SoftwareSystemID, vv.SystemBoundariesVisible)
+	case *mdl.ComponentView:
+		scoped(vv.ContainerID, vv.ContainerBoundariesVisible)
+	case *mdl.CodeView:
+		scoped(vv.ComponentID, nil)
+	case *mdl.DynamicView:
+		scoped(vv.ElementID, nil)
+	case *mdl.DeploymentView:
+		for _, ev := range props.ElementViews {
+			if p, ok := m.Parent(ev.ID).(*mdl.DeploymentNode); ok && inView[p.ID] {
+				add(p.ID)
+			}
+		}
+		for id, g := range res {
+			if p, ok := m.Parent(id).(*mdl.DeploymentNode); ok {
+				if _, ok := res[p.ID]; ok {
+					g.parent = p.ID
+				}
+			}
+		}
+	}
+	return res
+}
+
+// computeGroupBounds computes the bounding boxes of the groups from the
+// positions of the nodes they contain, nested groups first.
+func computeGroupBounds(groups map[string]*group, nodes []*node) {
+	for _, g := range groups {
+		g.x0, g.y0, g.x1, g.y1 = math.Inf(1), math.Inf(1), math.Inf(-1), math.Inf(-1)
+	}
+	extend := func(g *group, x0, y0, x1, y1 float64) {
+		g.x0, g.y0 = math.Min(g.x0, x0-groupPadding), math.Min(g.y0, y0-groupPadding)
+		g.x1, g.y1 = math.Max(g.x1, x1+groupPadding), math.Max(g.y1, y1+groupPadding+groupFontSize*1.5)
+	}
+	for _, n := range nodes {
+		if g := groups[n.group]; g != nil {
+			extend(g, n.x-float64(n.width)/2, n.y-float64(n.height)/2, n.x+float64(n.width)/2, n.y+float64(n.height)/2)
+		}
+	}
+	gs := make([]*group, 0, len(groups))
+	for _, g := range groups {
+		gs = append(gs, g)
+	}
+	sort.Slice(gs, func(i, j int) bool { return depth(groups, gs[i]) > depth(groups, gs[j]) })
+	for _, g := range gs {
+		if p := groups[g.parent]; p != nil && g.x1 > g.x0 {
+			extend(p, g.x0, g.y0, g.x1, g.y1)
+		}
+	}
+	for _, g := range gs {
+		if g.x1 < g.x0 {
+			g.x0, g.y0, g.x1, g.y1 = 0, 0, 0, 0
+		}
+	}
+}
+
+// depth returns the nesting level of g.
+func depth(groups map[string]*group, g *group) int {
+	d := 0
+	for p := groups[g.parent]; p != nil; p = groups[p.parent] {
+		d++
+	}
+	return d
+}
+
+// intersect returns the point where the segment between the center of the
+// node and p crosses the node boundary.
+func (n *node) intersect(p Position) Position {
+	dx, dy := p.X-n.x, p.Y-n.y
+	if dx == 0 && dy == 0 {
+		return Position{n.x, n.y}
+	}
+	w, h := float64(n.width)/2, float64(n.height)/2
+	switch n.style.Shape {
+	case mdl.ShapeCircle, mdl.ShapeEllipse, mdl.ShapeHexagon:
+		rx, ry := w, h
+		if n.style.Shape != mdl.ShapeEllipse {
+			ry = w
+		}
+		t := 1 / math.Sqrt(dx*dx/(rx*rx)+dy*dy/(ry*ry))
+		return Position{n.x + dx*t, n.y + dy*t}
+	}
+	t := math.Min(math.Abs(w/dx), math.Abs(h/dy))
+	if math.IsInf(t, 0) || math.IsNaN(t) {
+		t = 1
+	}
+	return Position{n.x + dx*t, n.y + dy*t}
+}
+
+// along returns the point at the given fraction of the length of the
+// polyline.
+func along(pts []Position, frac float64) Position {
+	var total float64
+	for i := 1; i < len(pts); i++ {
+		total += math.Hypot(pts[i].X-pts[i-1].X, pts[i].Y-pts[i-1].Y)
+	}
+	target := total * frac
+	for i := 1; i < len(pts); i++ {
+		l := math.Hypot(pts[i].X-pts[i-1].X, pts[i].Y-pts[i-1].Y)
+		if l >= target && l > 0 {
+			t := target / l
+			return Position{pts[i-1].X + (pts[i].X-pts[i-1].X)*t, pts[i-1].Y + (pts[i].Y-pts[i-1].Y)*t}
+		}
+		target -= l
+	}
+	return pts[len(pts)-1]
+}
+
+// wrap splits text into lines that fit in the given width.
+func wrap(text string, width, fontSize float64, bold bool) []string {
+	var (
+		lines []string
+		cur   string
+	)
+	for _, w := range strings.Fields(text) {
+		candidate := w
+		if cur != "" {
+			candidate = cur + " " + w
+		}
+		if cur != "" && textWidth(candidate, fontSize, bold) > width {
+			lines = append(lines, cur)
+			cur = w
+			continue
+		}
+		cur = candidate
+	}
+	if cur != "" {
+		lines = append(lines, cur)
+	}
+	return lines
+}
+
+// textWidth approximates the width of the given text rendered with a sans
+// serif font of the given size.
+func textWidth(text string, fontSize float64, bold bool) float64 {
+	factor := 0.55
+	if bold {
+		factor = 0.6
+	}
+	return float64(len([]rune(text))) * fontSize * factor
+}
+
+// isInternal returns true if e is a person or software system that is not
+// external to the enterprise.
+func isInternal(e interface{}) bool {
+	switch a := e.(type) {
+	case *mdl.Person:
+		return a.Location != mdl.LocationExternal
+	case *mdl.SoftwareSystem:
+		return a.Location != mdl.LocationExternal
+	}
+	return false
+}
+
+// idOf returns the ID of the given element.
+func idOf(e interface{}) string {
+	switch a := e.(type) {
+	case *mdl.Person:
+		return a.ID
+	case *mdl.SoftwareSystem:
+		return a.ID
+	case *mdl.Container:
+		return a.ID
+	case *mdl.Component:
+		return a.ID
+	case *mdl.CodeElement:
+		return a.ID
+	case *mdl.DeploymentNode:
+		return a.ID
+	case *mdl.InfrastructureNode:
+		return a.ID
+	case *mdl.ContainerInstance:
+		return a.ID
+	}
+	return ""
+}
+
+// esc escapes s for use in SVG text and attribute values.
+func esc(s string) string {
+	return html.EscapeString(s)
+}
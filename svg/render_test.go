@@ -0,0 +1,167 @@
+package svg
+
+import (
+	"strings"
+	"testing"
+
+	"goa.design/model/dsl"
+	"goa.design/model/internal/testutil"
+)
+
+// fleet defines the design used to test the SVG renderer.
+func fleet() {
+	dsl.Design("Fleet", "Manages a fleet of buses.", func() {
+		dsl.Enterprise("Transit")
+		dsl.Team("Ops")
+		var Maps = dsl.SoftwareSystem("Maps", "Geocodes <addresses> & routes.", func() {
+			dsl.External()
+			dsl.Tag("External")
+		})
+		var Dispatch = dsl.SoftwareSystem("Dispatch", "Dispatches buses.", func() {
+			dsl.Owner("Ops")
+			dsl.Uses(Maps, "Looks up addresses")
+			dsl.Container("Console", "Lets operators dispatch buses.", "React", func() {
+				dsl.Tag("Browser")
+				dsl.Uses("Router", "Sends commands", "HTTPS")
+				dsl.Uses("Router", "Polls status", "HTTPS")
+			})
+			dsl.Container("Router", "Routes commands to buses.", "Go", func() {
+				dsl.Tag("Service")
+				dsl.Prop("tier", "1")
+				dsl.Uses("Bus", "Publishes positions", dsl.Asynchronous, func() {
+					dsl.Tag("Event")
+				})
+				dsl.Uses(Maps, "Geocodes", "HTTPS", func() {
+					dsl.Tag("Lookup")
+				})
+			})
+			dsl.Container("Bus", "Carries events.", "Kafka", func() {
+				dsl.Tag("Stream")
+			})
+			dsl.Container("Ledger", "Records trips.", "PostgreSQL", func() {
+				dsl.Tag("Database")
+			})
+		})
+		dsl.Person("Operator", "Dispatches buses.", func() {
+			dsl.Uses(Dispatch, "Dispatches buses with")
+			dsl.Uses("Dispatch/Console", "Uses", "HTTPS")
+		})
+		dsl.DeploymentEnvironment("Production", func() {
+			dsl.DeploymentNode("Region", "", "AWS", func() {
+				dsl.DeploymentNode("Cluster", "", "Kubernetes", func() {
+					dsl.Instances(2)
+					dsl.ContainerInstance("Dispatch/Console")
+					dsl.ContainerInstance("Dispatch/Router")
+				})
+				dsl.DeploymentNode("MSK", "", "Kafka", func() {
+					dsl.ContainerInstance("Dispatch/Bus")
+				})
+			})
+		})
+		dsl.Views(func() {
+			dsl.SystemLandscapeView("landscape", "Transit landscape.", func() {
+				dsl.AddAll()
+				dsl.AutoLayout(dsl.RankLeftRight)
+			})
+			dsl.ContainerView(Dispatch, "containers", "Dispatch containers.", func() {
+				dsl.AddAll()
+				dsl.Add("Dispatch/Console", func() {
+					dsl.Coord(100, 100)
+				})
+				dsl.Title("Dispatch <containers>")
+			})
+			dsl.ContainerView(Dispatch, "pinned", "Containers at fixed positions.", func() {
+				dsl.Add("Dispatch/Console")
+				dsl.Add("Dispatch/Router")
+			})
+			dsl.DeploymentView(Dispatch, "Production", "production", "Production deployment.", func() {
+				dsl.AddAll()
+			})
+			dsl.Styles(func() {
+				dsl.ElementStyle("Browser", func() {
+					dsl.Shape(dsl.ShapeWebBrowser)
+				})
+				dsl.ElementStyle("Service", func() {
+					dsl.Shape(dsl.ShapeHexagon)
+					dsl.Border(dsl.BorderDotted)
+				})
+				dsl.ElementStyle("Stream", func() {
+					dsl.Shape(dsl.ShapePipe)
+					dsl.Opacity(50)
+				})
+				dsl.ElementStyle("Database", func() {
+					dsl.Shape(dsl.ShapeCylinder)
+					dsl.Width(400)
+					dsl.Height(200)
+					dsl.FontSize(30)
+				})
+				dsl.ElementStyle("External", func() {
+					dsl.Background("#999999")
+					dsl.Color("#ffffff")
+				})
+				dsl.RelationshipStyle("Event", func() {
+					dsl.Solid()
+					dsl.Color("#ff0000")
+					dsl.Thickness(6)
+					dsl.Position(25)
+				})
+				dsl.RelationshipStyle("Lookup", func() {
+					dsl.Routing(dsl.RoutingOrthogonal)
+				})
+			})
+		})
+	})
+}
+
+func TestRender(t *testing.T) {
+	layouts := map[string]Layout{
+		"pinned": {
+			"yz0cd9": {X: 200, Y: 200},
+			"wf9tpd": {X: 800, Y: 200},
+		},
+	}
+	views := Render(testutil.RunDSL(t, fleet), layouts)
+	keys := []string{"containers", "landscape", "pinned", "production"}
+	if len(views) != len(keys) {
+		t.Errorf("got %d views, want %d", len(views), len(keys))
+	}
+	for _, key := range keys {
+		key := key
+		t.Run(key, func(t *testing.T) {
+			src, ok := views[key]
+			if !ok {
+				t.Fatalf("view %q not rendered", key)
+			}
+			testutil.AssertGolden(t, key+".svg", string(src))
+		})
+	}
+}
+
+func TestRenderEdgeCases(t *testing.T) {
+	views := Render(testutil.RunDSL(t, fleet), nil)
+	tests := []struct {
+		name, view, want string
+		absent           bool
+	}{
+		{"enterprise boundary", "landscape", `<g class="group" id="__enterprise__">`, false},
+		{"escaped title", "containers", `>Dispatch &lt;containers&gt;</text>`, false},
+		{"escaped description", "landscape", `&amp; routes.</text>`, false},
+		{"element coordinates", "containers", `<g class="node" id="yz0cd9" transform="translate(250.0,250.0)">`, false},
+		{"dotted border", "containers", `stroke-dasharray="2 3"`, false},
+		{"opacity", "containers", `opacity="0.50"`, false},
+		{"element size", "containers", `font-size="30px" font-weight="bold">Ledger</text>`, false},
+		{"solid relationship", "containers", `stroke="#ff0000" stroke-width="6" opacity="1.00" marker-end="url(#arrow)"/>`, false},
+		{"dashed relationship", "containers", `stroke-width="6" opacity="1.00" marker-end="url(#arrow)" stroke-dasharray`, true},
+		{"owner metadata", "containers", `"owner": "Ops"`, false},
+		{"properties metadata", "containers", `"tier": "1"`, false},
+		{"deployment instances", "production", `>Cluster [Kubernetes] x2</text>`, false},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			if got := string(views[tt.view]); strings.Contains(got, tt.want) == tt.absent {
+				t.Errorf("%s view: got contains %q = %v, want %v:\n%s", tt.view, tt.want, tt.absent, !tt.absent, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package svg
+
+import (
+	"fmt"
+	"strings"
+
+	"goa.design/model/mdl"
+)
+
+// shapeElements returns the SVG element used to render the given shape
+// centered on the origin, the additional elements used to decorate it and the
+// vertical offset of the label relative to the center. All elements are
+// self-closing so that attributes can be appended.
+func shapeElements(shape mdl.ShapeKind, w, h float64) (string, []string, float64) {
+	switch shape {
+	case mdl.ShapeRoundedBox:
+		return rect(w, h, w/8), nil, 0
+	case mdl.ShapeCircle:
+		return fmt.Sprintf(`<circle cx="0" cy="0" r="%.1f"/>`, w/2), nil, 0
+	case mdl.ShapeEllipse:
+		return fmt.Sprintf(`<ellipse cx="0" cy="0" rx="%.1f" ry="%.1f"/>`, w/2, h/2), nil, 0
+	case mdl.ShapeHexagon:
+		sz := w / 2
+		coords := []float64{0.5, 0.866, 1, 0, 0.5, -0.866, -0.5, -0.866, -1, 0, -0.5, 0.866}
+		pts := make([]string, len(coords))
+		for i, c := range coords {
+			pts[i] = fmt.Sprintf("%.1f", c*sz)
+		}
+		return fmt.Sprintf(`<polygon points="%s"/>`, strings.Join(pts, ",")), nil, 0
+	case mdl.ShapeCylinder:
+		rx := w / 2
+		ry := rx / (2.5 + w/70)
+		d := fmt.Sprintf("M %.1f,%.1f a %.1f,%.1f 0,0,0 %.1f 0 a %.1f,%.1f 0,0,0 %.1f 0 l 0,%.1f a %.1f,%.1f 0,0,0 %.1f 0 l 0,%.1f",
+			-w/2, -h/2+ry, rx, ry, w, rx, ry, -w, h-2*ry, rx, ry, w, -h+2*ry)
+		return path(d), nil, 2 * ry
+	case mdl.ShapePipe:
+		ry := h / 2
+		rx := ry / (2.5 + h/70)
+		d := fmt.Sprintf("M %.1f,%.1f a %.1f,%.1f 0,0,0 0 %.1f a %.1f,%.1f 0,0,0 0 %.1f l %.1f,0 a %.1f,%.1f 0,0,0 0 %.1f l %.1f,0",
+			-w/2+rx, -h/2, rx, ry, h, rx, ry, -h, w-2*rx, rx, ry, h, -w+2*rx)
+		return path(d), nil, 0
+	case mdl.ShapePerson:
+		d := fmt.Sprintf("M %.1f,%.1f A %.1f,%.1f 0,0,0 0 %.1f L %.1f,%.1f L %.1f,%.1f L %.1f,%.1f A %.1f,%.1f 0,0,0 %.1f %.1f A %.1f,%.1f 0,1,0 %.1f %.1f",
+			.38*w, h/3, w/2, h/2, h/2, w/11, h, w-w/11, h, w, h/2, w/2, h/2, w-.38*w, h/3, w/6, w/6, .38*w, h/3)
+		return fmt.Sprintf(`<path d="%s" transform="translate(%.1f,%.1f)"/>`, d, -w/2, -h/2), nil, h * .4
+	case mdl.ShapeComponent:
+		return rect(w, h, 3), []string{
+			fmt.Sprintf(`<rect x="%.1f" y="%.1f" width="40" height="20"/>`, -w/2-20, -h/4-10),
+			fmt.Sprintf(`<rect x="%.1f" y="%.1f" width="40" height="20"/>`, -w/2-20, h/4-10),
+		}, 0
+	case mdl.ShapeFolder:
+		tab := w / 3
+		d := fmt.Sprintf("M %.1f,%.1f l 0,%.1f l %.1f,0 l 0,%.1f l %.1f,0 l -20,-20 l %.1f,0 z",
+			-w/2, -h/2+20, h-20, w, -h+20, -(w - tab), -(tab - 20))
+		return path(d), nil, 20
+	case mdl.ShapeWebBrowser:
+		return rect(w, h, 3), []string{
+			fmt.Sprintf(`<path d="M %.1f,%.1f l %.1f,0"/>`, -w/2, -h/2+40, w),
+			fmt.Sprintf(`<circle cx="%.1f" cy="%.1f" r="8"/>`, -w/2+20, -h/2+20),
+			fmt.Sprintf(`<circle cx="%.1f" cy="%.1f" r="8"/>`, -w/2+45, -h/2+20),
+			fmt.Sprintf(`<circle cx="%.1f" cy="%.1f" r="8"/>`, -w/2+70, -h/2+20),
+		}, 40
+	case mdl.ShapeMobileDevicePortrait:
+		return rect(w, h, 20), []string{
+			fmt.Sprintf(`<rect x="%.1f" y="%.1f" width="%.1f" height="%.1f"/>`, -w/2+10, -h/2+40, w-20, h-80),
+		}, 0
+	case mdl.ShapeMobileDeviceLandscape:
+		return rect(w, h, 20), []string{
+			fmt.Sprintf(`<rect x="%.1f" y="%.1f" width="%.1f" height="%.1f"/>`, -w/2+40, -h/2+10, w-80, h-20),
+		}, 0
+	default:
+		return rect(w, h, 3), nil, 0
+	}
+}
+
+func rect(w, h, radius float64) string {
+	return fmt.Sprintf(`<rect x="%.1f" y="%.1f" width="%.1f" height="%.1f" rx="%.1f" ry="%.1f"/>`, -w/2, -h/2, w, h, radius, radius)
+}
+
+func path(d string) string {
+	return fmt.Sprintf(`<path d="%s"/>`, d)
+}
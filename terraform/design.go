@@ -0,0 +1,561 @@
+package terraform
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"goa.design/model/mdl"
+)
+
+type (
+	// service describes how resources of a given type are mapped.
+	service struct {
+		// technology is the name of the cloud service.
+		technology string
+		// category is the resource category.
+		category category
+	}
+
+	// category describes the role of a resource in the deployment model.
+	category int
+
+	// builder builds a design from Terraform resources.
+	builder struct {
+		// m is the model being built.
+		m *mdl.Model
+		// env is the name of the deployment environment.
+		env string
+		// nodes indexes the provider and region nodes by provider and
+		// region names.
+		nodes map[string]*mdl.DeploymentNode
+		// refs indexes the network nodes by the ID, name, self link and ARN
+		// of the corresponding resources.
+		refs map[string]*mdl.DeploymentNode
+		// parents indexes the parent of each deployment node by node ID.
+		parents map[string]*mdl.DeploymentNode
+		// subnetGroups indexes the subnet IDs of subnet groups by group
+		// name.
+		subnetGroups map[string][]string
+		// seq is used to generate element IDs.
+		seq int
+	}
+
+	// group lists the resources created by a single resource block.
+	group struct {
+		// address of resource block.
+		address string
+		// resources created by the block.
+		resources []*Resource
+		// parent is the deployment node that contains the node
+		// corresponding to the group once split, see builder.split.
+		parent *mdl.DeploymentNode
+	}
+)
+
+const (
+	// network is a VPC or virtual network.
+	network category = iota + 1
+	// subnet is a subnet of a network.
+	subnet
+	// node is a resource that runs software or stores data (compute,
+	// databases, caches, queues, buckets etc.)
+	node
+	// infrastructure is a supporting resource (load balancers, gateways, DNS
+	// zones etc.)
+	infrastructure
+)
+
+// services lists the supported resource types.
+var services = map[string]*service{
+	// Amazon Web Services
+	"aws_vpc":                           {"Amazon VPC", network},
+	"aws_subnet":                        {"Subnet", subnet},
+	"aws_instance":                      {"Amazon EC2", node},
+	"aws_autoscaling_group":             {"Amazon EC2 Auto Scaling", node},
+	"aws_ecs_cluster":                   {"Amazon ECS", node},
+	"aws_eks_cluster":                   {"Amazon EKS", node},
+	"aws_lambda_function":               {"AWS Lambda", node},
+	"aws_db_instance":                   {"Amazon RDS", node},
+	"aws_rds_cluster":                   {"Amazon Aurora", node},
+	"aws_elasticache_cluster":           {"Amazon ElastiCache", node},
+	"aws_elasticache_replication_group": {"Amazon ElastiCache", node},
+	"aws_dynamodb_table":                {"Amazon DynamoDB", node},
+	"aws_msk_cluster":                   {"Amazon MSK", node},
+	"aws_lb":                            {"Elastic Load Balancing", infrastructure},
+	"aws_alb":                           {"Elastic Load Balancing", infrastructure},
+	"aws_elb":                           {"Elastic Load Balancing", infrastructure},
+	"aws_sqs_queue":                     {"Amazon SQS", node},
+	"aws_sns_topic":                     {"Amazon SNS", node},
+	"aws_kinesis_stream":                {"Amazon Kinesis", node},
+	"aws_s3_bucket":                     {"Amazon S3", node},
+	"aws_cloudfront_distribution":       {"Amazon CloudFront", infrastructure},
+	"aws_route53_zone":                  {"Amazon Route 53", infrastructure},
+	"aws_api_gateway_rest_api":          {"Amazon API Gateway", infrastructure},
+	"aws_apigatewayv2_api":              {"Amazon API Gateway", infrastructure},
+	"aws_nat_gateway":                   {"NAT Gateway", infrastructure},
+	"aws_internet_gateway":              {"Internet Gateway", infrastructure},
+
+	// Google Cloud Platform
+	"google_compute_network":                {"Google VPC", network},
+	"google_compute_subnetwork":             {"Subnet", subnet},
+	"google_compute_instance":               {"Compute Engine", node},
+	"google_container_cluster":              {"Google Kubernetes Engine", node},
+	"google_cloud_run_service":              {"Cloud Run", node},
+	"google_cloud_run_v2_service":           {"Cloud Run", node},
+	"google_sql_database_instance":          {"Cloud SQL", node},
+	"google_redis_instance":                 {"Memorystore", node},
+	"google_compute_forwarding_rule":        {"Cloud Load Balancing", infrastructure},
+	"google_compute_global_forwarding_rule": {"Cloud Load Balancing", infrastructure},
+	"google_pubsub_topic":                   {"Pub/Sub", node},
+	"google_storage_bucket":                 {"Cloud Storage", node},
+	"google_dns_managed_zone":               {"Cloud DNS", infrastructure},
+
+	// Microsoft Azure
+	"azurerm_virtual_network":            {"Azure Virtual Network", network},
+	"azurerm_subnet":                     {"Subnet", subnet},
+	"azurerm_linux_virtual_machine":      {"Azure Virtual Machines", node},
+	"azurerm_windows_virtual_machine":    {"Azure Virtual Machines", node},
+	"azurerm_kubernetes_cluster":         {"Azure Kubernetes Service", node},
+	"azurerm_linux_web_app":              {"Azure App Service", node},
+	"azurerm_postgresql_flexible_server": {"Azure Database for PostgreSQL", node},
+	"azurerm_postgresql_server":          {"Azure Database for PostgreSQL", node},
+	"azurerm_mssql_server":               {"Azure SQL Database", node},
+	"azurerm_redis_cache":                {"Azure Cache for Redis", node},
+	"azurerm_lb":                         {"Azure Load Balancer", infrastructure},
+	"azurerm_application_gateway":        {"Azure Application Gateway", infrastructure},
+	"azurerm_servicebus_queue":           {"Azure Service Bus", node},
+	"azurerm_servicebus_topic":           {"Azure Service Bus", node},
+	"azurerm_storage_account":            {"Azure Storage", node},
+	"azurerm_dns_zone":                   {"Azure DNS", infrastructure},
+}
+
+// providers maps the provider names to the names of the cloud platforms.
+var providers = map[string][2]string{
+	"aws":         {"Amazon Web Services", "AWS"},
+	"google":      {"Google Cloud Platform", "GCP"},
+	"google-beta": {"Google Cloud Platform", "GCP"},
+	"azurerm":     {"Microsoft Azure", "Azure"},
+}
+
+// attributes lists the resource attributes mapped to element properties.
+var attributes = []string{
+	"cidr_block", "address_space", "address_prefixes", "ip_cidr_range",
+	"availability_zone", "zone", "instance_type", "instance_class",
+	"machine_type", "size", "sku_name", "tier", "node_type", "engine",
+	"engine_version", "database_version", "kubernetes_version", "version",
+	"runtime", "memory_size", "allocated_storage", "multi_az",
+	"load_balancer_type", "internal", "fifo_queue", "billing_mode",
+}
+
+// Attributes that reference subnets, subnet groups and networks.
+var (
+	subnetAttrs      = []string{"subnet_id", "subnetwork", "subnet_ids", "subnets", "vpc_zone_identifier"}
+	subnetGroupAttrs = []string{"db_subnet_group_name", "subnet_group_name"}
+	networkAttrs     = []string{"vpc_id", "network", "virtual_network_name"}
+)
+
+// zoneRegexp matches availability zone suffixes.
+var zoneRegexp = regexp.MustCompile(`-?[a-z]$`)
+
+// Design returns the design with the given name that describes the
+// resources of the given Terraform state deployed in the deployment
+// environment env.
+func Design(name, env string, s *State) *mdl.Design {
+	b := &builder{
+		m:            &mdl.Model{},
+		env:          env,
+		nodes:        make(map[string]*mdl.DeploymentNode),
+		refs:         make(map[string]*mdl.DeploymentNode),
+		parents:      make(map[string]*mdl.DeploymentNode),
+		subnetGroups: make(map[string][]string),
+	}
+	groups := groups(s.Resources())
+	for _, g := range groups {
+		switch g.resources[0].Type {
+		case "aws_db_subnet_group", "aws_elasticache_subnet_group":
+			v := g.resources[0].Values
+			b.subnetGroups[str(v["name"])] = strs(v["subnet_ids"])
+		}
+	}
+	for _, cat := range []category{network, subnet, node, infrastructure} {
+		for _, g := range groups {
+			if svc, ok := services[g.resources[0].Type]; ok && svc.category == cat {
+				b.add(g, svc)
+			}
+		}
+	}
+	disambiguate(b.m.DeploymentNodes, nil)
+	return &mdl.Design{Name: name, Model: b.m, Views: b.views()}
+}
+
+// add adds the deployment or infrastructure nodes corresponding to the given
+// resource group.
+func (b *builder) add(g *group, svc *service) {
+	for _, g := range b.split(g, svc.category) {
+		r := g.resources[0]
+		props := properties(g)
+		if svc.category == infrastructure {
+			inf := &mdl.InfrastructureNode{
+				ID:          b.newID(),
+				Name:        displayName(r),
+				Technology:  svc.technology,
+				Tags:        "Element,Infrastructure Node",
+				Properties:  props,
+				Environment: b.env,
+			}
+			if len(g.resources) > 1 {
+				props["instances"] = strconv.Itoa(len(g.resources))
+			}
+			g.parent.InfrastructureNodes = append(g.parent.InfrastructureNodes, inf)
+			continue
+		}
+		n := b.newNode(g.parent, displayName(r), svc.technology)
+		n.Properties = props
+		if c := instances(g); c > 1 {
+			n.Instances = &c
+		}
+		if svc.category == network || svc.category == subnet {
+			for _, r := range g.resources {
+				for _, attr := range []string{"id", "name", "self_link", "arn"} {
+					if v := str(r.Values[attr]); v != "" {
+						b.refs[v] = n
+					}
+				}
+			}
+		}
+	}
+}
+
+// split splits the resources of g into groups of resources that have the
+// same display name and the same parent node, each group is mapped to a single
+// node.
+func (b *builder) split(g *group, cat category) []*group {
+	var res []*group
+	for _, r := range g.resources {
+		parent, name := b.parent(r, cat), displayName(r)
+		var sub *group
+		for _, s := range res {
+			if s.parent == parent && displayName(s.resources[0]) == name {
+				sub = s
+				break
+			}
+		}
+		if sub == nil {
+			sub = &group{parent: parent}
+			res = append(res, sub)
+		}
+		sub.resources = append(sub.resources, r)
+	}
+	for _, sub := range res {
+		sub.address = g.address
+		if len(sub.resources) < len(g.resources) {
+			addrs := make([]string, len(sub.resources))
+			for i, r := range sub.resources {
+				addrs[i] = r.Address
+			}
+			sub.address = strings.Join(addrs, ", ")
+		}
+	}
+	return res
+}
+
+// parent returns the deployment node that contains the node corresponding to
+// the given resource.
+func (b *builder) parent(r *Resource, cat category) *mdl.DeploymentNode {
+	if cat != network && cat != subnet {
+		var subnets []*mdl.DeploymentNode
+		for _, id := range b.subnetRefs(r) {
+			if n, ok := b.refs[id]; ok {
+				subnets = append(subnets, n)
+			}
+		}
+		if len(subnets) > 0 {
+			same := true
+			for _, n := range subnets[1:] {
+				if n != subnets[0] {
+					same = false
+				}
+			}
+			if same {
+				return subnets[0]
+			}
+			// Resource spans multiple subnets, use network.
+			return b.parents[subnets[0].ID]
+		}
+	}
+	if cat != network {
+		for _, attr := range networkAttrs {
+			if n, ok := b.refs[str(r.Values[attr])]; ok {
+				return n
+			}
+		}
+	}
+	return b.regionNode(r)
+}
+
+// subnetRefs returns the values of the attributes of r that reference
+// subnets.
+func (b *builder) subnetRefs(r *Resource) []string {
+	var ids []string
+	for _, attr := range subnetAttrs {
+		ids = append(ids, strs(r.Values[attr])...)
+	}
+	if cfgs, ok := r.Values["vpc_config"].([]interface{}); ok && len(cfgs) > 0 {
+		if cfg, ok := cfgs[0].(map[string]interface{}); ok {
+			ids = append(ids, strs(cfg["subnet_ids"])...)
+		}
+	}
+	for _, attr := range subnetGroupAttrs {
+		ids = append(ids, b.subnetGroups[str(r.Values[attr])]...)
+	}
+	return ids
+}
+
+// regionNode returns the deployment node of the region of r or of the
+// provider of r if the region cannot be determined creating it if needed.
+func (b *builder) regionNode(r *Resource) *mdl.DeploymentNode {
+	pname := r.ProviderName
+	if i := strings.LastIndex(pname, "/"); i >= 0 {
+		pname = pname[i+1:]
+	}
+	if pname == "" {
+		pname = strings.SplitN(r.Type, "_", 2)[0]
+	}
+	p, ok := b.nodes[pname]
+	if !ok {
+		name, tech := pname, ""
+		if names, ok := providers[pname]; ok {
+			name, tech = names[0], names[1]
+		}
+		p = b.newNode(nil, name, tech)
+		b.nodes[pname] = p
+	}
+	region := region(r)
+	if region == "" {
+		return p
+	}
+	key := pname + "/" + region
+	n, ok := b.nodes[key]
+	if !ok {
+		n = b.newNode(p, region, "Region")
+		b.nodes[key] = n
+	}
+	return n
+}
+
+// newNode creates a deployment node child of parent or a top level
+// deployment node if parent is nil.
+func (b *builder) newNode(parent *mdl.DeploymentNode, name, tech string) *mdl.DeploymentNode {
+	n := &mdl.DeploymentNode{
+		ID:          b.newID(),
+		Name:        name,
+		Technology:  tech,
+		Environment: b.env,
+		Tags:        "Element,Deployment Node",
+	}
+	if parent == nil {
+		b.m.DeploymentNodes = append(b.m.DeploymentNodes, n)
+	} else {
+		parent.Children = append(parent.Children, n)
+		b.parents[n.ID] = parent
+	}
+	return n
+}
+
+// disambiguate makes the names of the given sibling deployment and
+// infrastructure nodes and of their descendants unique. Names shared by
+// multiple nodes are qualified with the addresses of the corresponding
+// resources, names that remain ambiguous get a numeric suffix.
+func disambiguate(nodes []*mdl.DeploymentNode, infs []*mdl.InfrastructureNode) {
+	count := make(map[string]int)
+	for _, n := range nodes {
+		count[n.Name]++
+	}
+	for _, n := range infs {
+		count[n.Name]++
+	}
+	taken := make(map[string]bool)
+	unique := func(name string, props map[string]string) string {
+		if addr := props["terraform"]; count[name] > 1 && addr != "" {
+			name = fmt.Sprintf("%s (%s)", name, addr)
+		}
+		res := name
+		for i := 2; taken[res]; i++ {
+			res = fmt.Sprintf("%s %d", name, i)
+		}
+		taken[res] = true
+		return res
+	}
+	for _, n := range nodes {
+		n.Name = unique(n.Name, n.Properties)
+		disambiguate(n.Children, n.InfrastructureNodes)
+	}
+	for _, n := range infs {
+		n.Name = unique(n.Name, n.Properties)
+	}
+}
+
+// views returns the deployment view listing all the elements of the
+// environment.
+func (b *builder) views() *mdl.Views {
+	props := &mdl.ViewProps{
+		Key:         strings.Join(strings.Fields(b.env), "") + "Deployment",
+		Description: "Infrastructure of the " + b.env + " environment.",
+	}
+	var add func(n *mdl.DeploymentNode)
+	add = func(n *mdl.DeploymentNode) {
+		props.ElementViews = append(props.ElementViews, &mdl.ElementView{ID: n.ID})
+		for _, inf := range n.InfrastructureNodes {
+			props.ElementViews = append(props.ElementViews, &mdl.ElementView{ID: inf.ID})
+		}
+		for _, c := range n.Children {
+			add(c)
+		}
+	}
+	for _, n := range b.m.DeploymentNodes {
+		add(n)
+	}
+	return &mdl.Views{
+		DeploymentViews: []*mdl.DeploymentView{{ViewProps: props, Environment: b.env}},
+	}
+}
+
+// newID returns a new unique element ID.
+func (b *builder) newID() string {
+	b.seq++
+	return strconv.Itoa(b.seq)
+}
+
+// groups groups the resources created by the same resource block (using
+// count or for_each) preserving the order of the resources.
+func groups(rs []*Resource) []*group {
+	var (
+		res   []*group
+		index = make(map[string]*group)
+	)
+	for _, r := range rs {
+		addr := r.Address
+		if r.Index != nil {
+			if i := strings.LastIndex(addr, "["); i >= 0 {
+				addr = addr[:i]
+			}
+		}
+		g, ok := index[addr]
+		if !ok {
+			g = &group{address: addr}
+			index[addr] = g
+			res = append(res, g)
+		}
+		g.resources = append(g.resources, r)
+	}
+	return res
+}
+
+// instances returns the number of instances of the deployment node
+// corresponding to g.
+func instances(g *group) int {
+	v := g.resources[0].Values
+	for _, attr := range []string{"desired_capacity", "num_cache_nodes"} {
+		if n, ok := v[attr].(float64); ok && n > 0 {
+			return int(n) * len(g.resources)
+		}
+	}
+	return len(g.resources)
+}
+
+// displayName returns the name of the node corresponding to r: the value of
+// its Name tag, name or identifier attribute or the name of the resource.
+func displayName(r *Resource) string {
+	if tags, ok := r.Values["tags"].(map[string]interface{}); ok {
+		if n := str(tags["Name"]); n != "" {
+			return n
+		}
+	}
+	for _, attr := range []string{"name", "identifier", "cluster_identifier", "cluster_id", "replication_group_id", "function_name", "bucket"} {
+		if n := str(r.Values[attr]); n != "" {
+			return n
+		}
+	}
+	return r.Name
+}
+
+// region returns the region of r if it can be determined.
+func region(r *Resource) string {
+	v := r.Values
+	if reg := str(v["region"]); reg != "" {
+		return reg
+	}
+	if loc := str(v["location"]); loc != "" && !strings.EqualFold(loc, "global") {
+		return loc
+	}
+	if arn := strings.Split(str(v["arn"]), ":"); len(arn) > 3 && arn[3] != "" {
+		return arn[3]
+	}
+	for _, attr := range []string{"availability_zone", "zone"} {
+		if z := str(v[attr]); z != "" {
+			return zoneRegexp.ReplaceAllString(z, "")
+		}
+	}
+	return ""
+}
+
+// properties returns the element properties of the node corresponding to g.
+// Distinct values of the resources of the group are joined with commas.
+func properties(g *group) map[string]string {
+	props := map[string]string{"terraform": g.address}
+	for _, attr := range attributes {
+		var (
+			vals []string
+			seen = make(map[string]bool)
+		)
+		for _, r := range g.resources {
+			for _, v := range strs(r.Values[attr]) {
+				if !seen[v] {
+					seen[v] = true
+					vals = append(vals, v)
+				}
+			}
+		}
+		if len(vals) > 0 {
+			props[attr] = strings.Join(vals, ", ")
+		}
+	}
+	return props
+}
+
+// str returns the string representation of the scalar value v, the empty
+// string if v is not a scalar.
+func str(v interface{}) string {
+	switch val := v.(type) {
+	case string:
+		return val
+	case float64:
+		return strconv.FormatFloat(val, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(val)
+	}
+	return ""
+}
+
+// strs returns the string representations of the scalar values in v if it
+// is a list or of v itself if it is a scalar.
+func strs(v interface{}) []string {
+	list, ok := v.([]interface{})
+	if !ok {
+		if s := str(v); s != "" {
+			return []string{s}
+		}
+		return nil
+	}
+	var res []string
+	for _, e := range list {
+		if s := str(e); s != "" {
+			res = append(res, s)
+		}
+	}
+	sort.Strings(res)
+	return res
+}
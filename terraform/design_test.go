@@ -0,0 +1,137 @@
+package terraform
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/kylelemons/godebug/diff"
+	"goa.design/model/mdl"
+)
+
+func TestDesign(t *testing.T) {
+	s, err := Load(filepath.Join("testdata", "state.json"))
+	if err != nil {
+		t.Fatalf("failed to load state: %s", err)
+	}
+	d := Design("Infra", "Production", s)
+	want := strings.Join([]string{
+		"Amazon Web Services [AWS] ",
+		"  us-east-1 [Region] ",
+		"    main [Amazon VPC] cidr_block=10.0.0.0/16 terraform=aws_vpc.main",
+		"      orders (aws_lb.orders) (Elastic Load Balancing) load_balancer_type=application terraform=aws_lb.orders",
+		"      private-a [Subnet] cidr_block=10.0.1.0/24 terraform=aws_subnet.private[0]",
+		"        web [Amazon EC2] x2 instance_type=t3.micro, t3.small terraform=aws_instance.web",
+		"        api [Amazon EC2] instance_type=t3.medium terraform=aws_instance.api[0]",
+		"      private-b [Subnet] cidr_block=10.0.2.0/24 terraform=aws_subnet.private[1]",
+		"        api [Amazon EC2] x2 instance_type=t3.medium terraform=aws_instance.api[1], aws_instance.api[2]",
+		"      private [Subnet] cidr_block=10.0.3.0/24 terraform=aws_subnet.public",
+		"      orders (aws_db_instance.db) [Amazon RDS] engine=postgres terraform=aws_db_instance.db",
+		"    jobs (aws_instance.batch) [Amazon EC2] availability_zone=us-east-1a instance_type=c5.large terraform=aws_instance.batch",
+		"    jobs (aws_sqs_queue.jobs) [Amazon SQS] terraform=aws_sqs_queue.jobs",
+		"    jobs (module.worker.aws_sqs_queue.jobs) [Amazon SQS] fifo_queue=true terraform=module.worker.aws_sqs_queue.jobs",
+		"Google Cloud Platform [GCP] ",
+		"  US [Region] ",
+		"    assets [Cloud Storage] terraform=module.worker.google_storage_bucket.assets",
+		"",
+	}, "\n")
+	if got := dump(d.Model.DeploymentNodes, ""); got != want {
+		t.Errorf("unexpected deployment nodes:\n%s", diff.Diff(want, got))
+	}
+	if len(d.Views.DeploymentViews) != 1 {
+		t.Fatalf("got %d deployment views, want 1", len(d.Views.DeploymentViews))
+	}
+	v := d.Views.DeploymentViews[0]
+	if v.Key != "ProductionDeployment" || v.Environment != "Production" || len(v.ElementViews) != 17 {
+		t.Errorf("got view %q of environment %q with %d elements, want ProductionDeployment of Production with 17", v.Key, v.Environment, len(v.ElementViews))
+	}
+}
+
+func TestDisambiguate(t *testing.T) {
+	nodes := []*mdl.DeploymentNode{
+		{Name: "Google Cloud Platform"},
+		{Name: "Google Cloud Platform"},
+		{Name: "db", Properties: map[string]string{"terraform": "aws_db_instance.db"}, Children: []*mdl.DeploymentNode{
+			{Name: "db", Properties: map[string]string{"terraform": "aws_instance.db"}},
+		}, InfrastructureNodes: []*mdl.InfrastructureNode{
+			{Name: "db", Properties: map[string]string{"terraform": "aws_lb.db"}},
+		}},
+		{Name: "db", Properties: map[string]string{"terraform": "aws_sqs_queue.db"}},
+	}
+	disambiguate(nodes, nil)
+	want := []string{
+		"Google Cloud Platform",
+		"Google Cloud Platform 2",
+		"db (aws_db_instance.db)",
+		"db (aws_sqs_queue.db)",
+		"db (aws_instance.db)",
+		"db (aws_lb.db)",
+	}
+	got := []string{
+		nodes[0].Name,
+		nodes[1].Name,
+		nodes[2].Name,
+		nodes[3].Name,
+		nodes[2].Children[0].Name,
+		nodes[2].InfrastructureNodes[0].Name,
+	}
+	for i, name := range want {
+		if got[i] != name {
+			t.Errorf("got name %q, want %q", got[i], name)
+		}
+	}
+}
+
+func TestLoad(t *testing.T) {
+	s, err := Load(filepath.Join("testdata", "plan.json"))
+	if err != nil {
+		t.Fatalf("failed to load plan: %s", err)
+	}
+	rs := s.Resources()
+	if len(rs) != 1 || rs[0].Address != "aws_s3_bucket.assets" {
+		t.Errorf("got resources %+v, want the planned S3 bucket", rs)
+	}
+
+	s, err = Load(filepath.Join("testdata", "state.json"))
+	if err != nil {
+		t.Fatalf("failed to load state: %s", err)
+	}
+	for _, r := range s.Resources() {
+		if r.Mode == "data" {
+			t.Errorf("got data source %q, want managed resources only", r.Address)
+		}
+	}
+
+	if _, err := Load(filepath.Join("testdata", "empty.json")); err == nil || !strings.Contains(err.Error(), "no resource found") {
+		t.Errorf("got error %v, want no resource found error", err)
+	}
+}
+
+// dump returns a description of the given nodes and their children.
+func dump(nodes []*mdl.DeploymentNode, indent string) string {
+	var b strings.Builder
+	for _, n := range nodes {
+		inst := ""
+		if n.Instances != nil {
+			inst = fmt.Sprintf(" x%d", *n.Instances)
+		}
+		fmt.Fprintf(&b, "%s%s [%s]%s %s\n", indent, n.Name, n.Technology, inst, props(n.Properties))
+		for _, inf := range n.InfrastructureNodes {
+			fmt.Fprintf(&b, "%s  %s (%s) %s\n", indent, inf.Name, inf.Technology, props(inf.Properties))
+		}
+		b.WriteString(dump(n.Children, indent+"  "))
+	}
+	return b.String()
+}
+
+// props returns the sorted list of properties.
+func props(p map[string]string) string {
+	var res []string
+	for k, v := range p {
+		res = append(res, k+"="+v)
+	}
+	sort.Strings(res)
+	return strings.Join(res, " ")
+}
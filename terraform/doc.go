@@ -0,0 +1,29 @@
+/*
+Package terraform builds the deployment model of a software architecture
+design from the JSON representation of a Terraform state or plan as produced
+by "terraform show -json".
+
+Load reads the JSON file and Design maps the managed cloud resources to
+deployment nodes and infrastructure nodes. Resources are nested using the
+references found in their attributes:
+
+	Provider                      -> Deployment Node (e.g. "Amazon Web Services")
+	Region                        -> Deployment Node
+	VPC and virtual network       -> Deployment Node
+	Subnet                        -> Deployment Node
+	Compute, database, cache,     -> Deployment Node
+	queue, topic and bucket
+	Load balancer, gateway, CDN   -> Infrastructure Node
+	and DNS zone
+
+Resources created with count or for_each that have the same name and are
+nested in the same node are mapped to a single deployment node whose number of
+instances is the number of resources. The node names are read from the Name
+tags or name attributes of the resources, names shared by sibling nodes are
+qualified with the resource addresses. The technology of the nodes is the name
+of the cloud service and their properties are read from a set of well known
+resource attributes (CIDR blocks, instance types, engines etc.). Other
+resources are ignored. The design also contains a deployment view
+that includes all the elements of the environment.
+*/
+package terraform
@@ -0,0 +1,101 @@
+package terraform
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+)
+
+type (
+	// State is the JSON representation of a Terraform state or plan.
+	State struct {
+		// Values describes the resources of a state.
+		Values *Values `json:"values"`
+		// PlannedValues describes the resources of a plan.
+		PlannedValues *Values `json:"planned_values"`
+	}
+
+	// Values describes the resources of the root module.
+	Values struct {
+		// RootModule is the root module.
+		RootModule *Module `json:"root_module"`
+	}
+
+	// Module lists the resources of a module.
+	Module struct {
+		// Address of module, empty for the root module.
+		Address string `json:"address"`
+		// Resources of module.
+		Resources []*Resource `json:"resources"`
+		// ChildModules lists the modules called by the module.
+		ChildModules []*Module `json:"child_modules"`
+	}
+
+	// Resource is a Terraform resource.
+	Resource struct {
+		// Address of resource, e.g. "aws_instance.web[0]".
+		Address string `json:"address"`
+		// Mode is "managed" for resources and "data" for data sources.
+		Mode string `json:"mode"`
+		// Type of resource, e.g. "aws_instance".
+		Type string `json:"type"`
+		// Name of resource.
+		Name string `json:"name"`
+		// Index of resource created with count or for_each if any.
+		Index interface{} `json:"index"`
+		// ProviderName is the address of the provider.
+		ProviderName string `json:"provider_name"`
+		// Values contains the resource attributes.
+		Values map[string]interface{} `json:"values"`
+	}
+)
+
+// Load reads the output of "terraform show -json" from the file at the given
+// path.
+func Load(path string) (*State, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var s State
+	if err := json.Unmarshal(b, &s); err != nil {
+		return nil, fmt.Errorf("%s: %s", path, err)
+	}
+	if s.root() == nil {
+		return nil, fmt.Errorf("%s: no resource found, file must be produced with 'terraform show -json'", path)
+	}
+	return &s, nil
+}
+
+// Resources returns the managed resources of all the modules.
+func (s *State) Resources() []*Resource {
+	var (
+		res  []*Resource
+		walk func(m *Module)
+	)
+	walk = func(m *Module) {
+		for _, r := range m.Resources {
+			if r.Mode == "" || r.Mode == "managed" {
+				res = append(res, r)
+			}
+		}
+		for _, c := range m.ChildModules {
+			walk(c)
+		}
+	}
+	if root := s.root(); root != nil {
+		walk(root)
+	}
+	return res
+}
+
+// root returns the root module of the state or plan.
+func (s *State) root() *Module {
+	if s.Values != nil && s.Values.RootModule != nil {
+		return s.Values.RootModule
+	}
+	if s.PlannedValues != nil {
+		return s.PlannedValues.RootModule
+	}
+	return nil
+}